@@ -60,6 +60,7 @@ func init() {
 	rootCmd.AddCommand(commands.VarInitCmd)
 	rootCmd.AddCommand(commands.VarDepsCmd)
 	rootCmd.AddCommand(commands.VarGraphCmd)
+	rootCmd.AddCommand(commands.VarConflictCmd)
 	rootCmd.AddCommand(commands.VarDoctorCmd)
 	rootCmd.AddCommand(commands.VarPlaybookCmd)
 	rootCmd.AddCommand(commands.VarAuthCmd)
@@ -73,6 +74,8 @@ func init() {
 	rootCmd.AddCommand(commands.VarCommentCmd)
 	rootCmd.AddCommand(commands.VarCodeCmd)
 	rootCmd.AddCommand(commands.VarSkillCmd)
+	rootCmd.AddCommand(commands.VarRunCmd)
+	rootCmd.AddCommand(commands.VarMCPCmd)
 
 	// Add version command
 	rootCmd.AddCommand(&cobra.Command{
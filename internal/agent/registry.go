@@ -14,6 +14,19 @@ type Agent struct {
 	APIKeyEnvVar  string // e.g., "ANTHROPIC_API_KEY" for Claude
 	BaseURLEnvVar string // e.g., "ANTHROPIC_BASE_URL" for Claude
 	ModelEnvVar   string // e.g., "ANTHROPIC_MODEL" for Claude
+
+	// MCPConfigFile is the project file the agent reads MCP servers from,
+	// relative to the project root. Empty if the agent has no project-level
+	// MCP config.
+	MCPConfigFile string
+
+	// HeadlessArgs run the agent without a terminal and with tool permissions
+	// granted, for unattended runs such as 'sl run'. They come before any
+	// configured arguments.
+	HeadlessArgs []string
+	// PromptFlag, if set, is passed immediately before the prompt in
+	// headless runs, for agents that take the prompt as a flag value.
+	PromptFlag string
 }
 
 type Registry struct {
@@ -36,6 +49,8 @@ func NewRegistry() *Registry {
 			APIKeyEnvVar:   "ANTHROPIC_AUTH_TOKEN",
 			BaseURLEnvVar:  "ANTHROPIC_BASE_URL",
 			ModelEnvVar:    "ANTHROPIC_MODEL",
+			MCPConfigFile:  ".mcp.json",
+			HeadlessArgs:   []string{"-p", "--dangerously-skip-permissions"},
 		},
 		{
 			Name:           "OpenCode",
@@ -45,6 +60,8 @@ func NewRegistry() *Registry {
 			APIKeyEnvVar:   "",
 			BaseURLEnvVar:  "",
 			ModelEnvVar:    "", // OpenCode uses config file for model
+			MCPConfigFile:  ".opencode.json",
+			HeadlessArgs:   []string{"run"},
 		},
 		{
 			Name:           "Copilot CLI",
@@ -54,6 +71,9 @@ func NewRegistry() *Registry {
 			APIKeyEnvVar:   "GITHUB_TOKEN",
 			BaseURLEnvVar:  "",
 			ModelEnvVar:    "", // Copilot uses config file for model
+			MCPConfigFile:  "", // Copilot CLI reads MCP servers from user config only
+			HeadlessArgs:   []string{"--allow-all-tools"},
+			PromptFlag:     "-p",
 		},
 		{
 			Name:           "Codex",
@@ -63,6 +83,8 @@ func NewRegistry() *Registry {
 			APIKeyEnvVar:   "OPENAI_API_KEY",
 			BaseURLEnvVar:  "OPENAI_BASE_URL",
 			ModelEnvVar:    "", // Codex uses config file for model
+			MCPConfigFile:  ".codex/config.toml",
+			HeadlessArgs:   []string{"exec", "--dangerously-bypass-approvals-and-sandbox"},
 		},
 	}
 
@@ -233,6 +233,15 @@ func setupSpecLedgerProject(projectPath, projectName, shortCode, playbookName st
 		}
 	}
 
+	// Register the issues.jsonl merge driver (no-op outside a git repository)
+	registerIssueMergeDriver(projectPath)
+
+	// Install the hooks linking commits to issues (no-op outside a git repository)
+	registerIssueCommitHooks(projectPath)
+
+	// Register 'sl mcp serve' with the selected and already configured agents
+	registerMCPServer(projectPath, selectedAgents)
+
 	return selectedPlaybookName, playbookVersion, playbookStructure, nil
 }
 
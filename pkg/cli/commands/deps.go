@@ -1,12 +1,13 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/go-git/go-git/v5"
 	"github.com/specledger/specledger/pkg/cli/framework"
 	"github.com/specledger/specledger/pkg/cli/metadata"
 	"github.com/specledger/specledger/pkg/cli/ui"
@@ -30,15 +31,19 @@ Examples:
 
 // VarAddCmd represents the add command
 var VarAddCmd = &cobra.Command{
-	Use:   "add <repo-url> [branch] --alias <name> [--artifact-path <path>]",
+	Use:   "add <repo-url>[@version] [branch] --alias <name> [--artifact-path <path>]",
 	Short: "Add a dependency",
 	Long: `Add an external specification dependency to your project. The dependency will be tracked in specledger.yaml and cached locally for offline use.
 
 The --alias flag is required and will be used as the reference path when accessing artifacts from this dependency.
 
+Without a version the dependency tracks the head of the branch. Append @<version> to track tags instead: @^1.2 (any 1.x from 1.2.0), @~1.4 (1.4.x), @v1.4.0 (exactly that tag) or comparisons such as @">=1.2 <2". The highest matching tag is recorded as resolved_tag next to resolved_commit, and 'sl deps update' stays within the constraint. @<commit> pins a commit.
+
 For SpecLedger repositories, the artifact_path will be auto-detected from the dependency's specledger.yaml. For non-SpecLedger repositories, use --artifact-path to manually specify where artifacts are located.`,
 	Example: `  sl deps add git@github.com:org/api-spec --alias api
   sl deps add git@github.com:org/api-spec develop --alias api
+  sl deps add git@github.com:org/api-spec@^1.2 --alias api
+  sl deps add https://github.com/org/api-spec@3f5d8a1 --alias api
   sl deps add https://github.com/org/api-docs --alias docs --artifact-path docs/openapi/`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runAddDependency,
@@ -65,20 +70,28 @@ var VarRemoveCmd = &cobra.Command{
 
 // VarResolveCmd represents the resolve command
 var VarResolveCmd = &cobra.Command{
-	Use:     "resolve",
-	Short:   "Download and cache dependencies",
-	Long:    `Download all dependencies from specledger.yaml and cache them locally at ~/.specledger/cache/.`,
-	Example: `  sl deps resolve`,
-	RunE:    runResolveDependencies,
+	Use:   "resolve",
+	Short: "Download and cache dependencies",
+	Long: `Download all dependencies from specledger.yaml into the shared cache at ~/.specledger/cache/. Each repository is cloned once, and each locked commit is extracted into its own read-only worktree, so projects locked to different commits of one repository never overwrite each other. See 'sl deps cache'.
+
+Once every dependency is cached, their own dependencies are resolved too, from each one's specledger.yaml at its locked commit. Each repository is resolved once, to the lowest commit satisfying everything that requires it, and the result is recorded under transitive: in specledger.yaml. Conflicting requirements leave the lock unchanged and fail the command; see 'sl conflict check'.`,
+	Example: `  sl deps resolve
+  sl deps resolve --link`,
+	RunE: runResolveDependencies,
 }
 
 // VarDepsUpdateCmd represents the update command
 var VarDepsUpdateCmd = &cobra.Command{
 	Use:   "update [repo-url]",
 	Short: "Update dependencies to latest versions",
-	Long:  `Update dependencies to their latest versions. If no URL is given, updates all dependencies.`,
+	Long: `Update dependencies to their latest versions. If no URL is given, updates all dependencies.
+
+Branch dependencies move to the head of their branch. Versioned dependencies move to the highest tag matching their constraint; use --major to move to the highest release tag even outside it, which rewrites the constraint (e.g. ^1.2 becomes ^2.0). Commit pins are never updated.
+
+After an update, transitive dependencies are resolved again for the new commits; conflicts are reported as by 'sl deps resolve'.`,
 	Example: `  sl deps update                    # Update all
-  sl deps update git@github.com:org/spec # Update one`,
+  sl deps update git@github.com:org/spec # Update one
+  sl deps update api --major             # Allow a new major version`,
 	RunE: runUpdateDependencies,
 }
 
@@ -88,7 +101,7 @@ var VarLinkCmd = &cobra.Command{
 	Short: "Create symlinks from cached dependencies to project artifacts directory",
 	Long: `Create symlinks from cached dependencies to the project's artifacts directory, making them available for Claude Code and other tools.
 
-This command creates symlinks from ~/.specledger/cache/<alias>/ to <project.artifact_path>/deps/<alias>/, allowing reference paths like "alias:artifact.md" to resolve to actual files.
+This command creates symlinks from the cached worktree of each dependency's locked commit to <project.artifact_path>/deps/<alias>/, allowing reference paths like "alias:artifact.md" to resolve to actual files.
 
 Example:  sl deps link`,
 	RunE: runLinkDependencies,
@@ -116,8 +129,10 @@ func init() {
 	VarAddCmd.Flags().String("artifact-path", "", "Path to artifacts within dependency repository (auto-detected for SpecLedger repos)")
 	VarAddCmd.Flags().Bool("link", false, "Create symlinks after adding dependency")
 
-	VarResolveCmd.Flags().BoolP("no-cache", "n", false, "Ignore cached specifications")
+	VarResolveCmd.Flags().BoolP("no-cache", "n", false, "Ignore locked commits and resolve every dependency afresh")
 	VarResolveCmd.Flags().Bool("link", false, "Create symlinks after resolving dependencies")
+
+	VarDepsUpdateCmd.Flags().Bool("major", false, "Update versioned dependencies past their constraint to the latest release")
 }
 
 func runAddDependency(cmd *cobra.Command, args []string) error {
@@ -138,7 +153,7 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 	artifactPath, _ := cmd.Flags().GetString("artifact-path")
 
 	// Parse arguments
-	repoURL := args[0]
+	repoURL, version := splitDependencyVersion(args[0])
 	branch := "main" // default
 
 	if len(args) >= 2 {
@@ -157,6 +172,12 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if version != "" && !deps.IsCommitPin(version) {
+		if _, err := deps.ParseConstraint(version); err != nil {
+			return err
+		}
+	}
+
 	// Detect framework type
 	frameworkType := metadata.FrameworkNone
 	ui.PrintSection("Detecting Framework")
@@ -213,6 +234,7 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 		Branch:       branch,
 		Alias:        alias,
 		ArtifactPath: artifactPath,
+		Version:      version,
 		Framework:    frameworkType,
 	}
 
@@ -241,40 +263,33 @@ func runAddDependency(cmd *cobra.Command, args []string) error {
 
 	// Auto-download the dependency
 	ui.PrintSection("Downloading Dependency")
-	dirName := alias
-	homeDir, _ := os.UserHomeDir()
-	cacheDir := filepath.Join(homeDir, ".specledger", "cache", dirName)
-
-	fmt.Printf("Cache: %s\n", ui.Cyan(cacheDir))
+	cache, err := openDependencyCache(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency cache: %w", err)
+	}
+	fmt.Printf("Cache: %s\n", ui.Cyan(cache.RepoPath(repoURL)))
 	fmt.Printf("Status: %s...\n", ui.Yellow("cloning"))
 
-	if err := cloneOrUpdateRepository(dep, cacheDir); err != nil {
-		ui.PrintWarning(fmt.Sprintf("Failed to clone repository: %v", err))
+	if _, err := cacheDependency(cache, &meta.Dependencies[dependencyIndex], false); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to download dependency: %v", err))
 		ui.PrintWarning("Dependency was added but not downloaded. Run 'sl deps resolve' to retry.")
 		fmt.Println()
 		return nil
 	}
-
-	// Resolve current commit SHA
-	gitCmd := exec.Command("git", "-C", cacheDir, "rev-parse", "HEAD")
-	output, err := gitCmd.CombinedOutput()
-	if err != nil {
-		ui.PrintWarning(fmt.Sprintf("Failed to resolve commit: %v", err))
-	} else {
-		commitSHA := strings.TrimSpace(string(output))
-		meta.Dependencies[dependencyIndex].ResolvedCommit = commitSHA
-		// Save updated metadata with commit SHA
-		if err := metadata.SaveToProject(meta, projectDir); err != nil {
-			ui.PrintWarning(fmt.Sprintf("Failed to save commit SHA: %v", err))
-		}
-		fmt.Printf("Status: %s %s\n", ui.Green("✓"), ui.Gray(commitSHA[:8]))
+	dep = meta.Dependencies[dependencyIndex]
+	if err := metadata.SaveToProject(meta, projectDir); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to save commit SHA: %v", err))
 	}
+	fmt.Printf("Status: %s %s\n", ui.Green("✓"), ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)))
 	fmt.Println()
 
 	ui.PrintSuccess("Dependency added")
 	fmt.Printf("  Repository:  %s\n", ui.Bold(repoURL))
 	fmt.Printf("  Alias:       %s\n", ui.Bold(alias))
 	fmt.Printf("  Branch:      %s\n", ui.Bold(branch))
+	if dep.Version != "" {
+		fmt.Printf("  Version:     %s\n", ui.Bold(dep.Version))
+	}
 	if dep.ArtifactPath != "" {
 		fmt.Printf("  Artifact Path: %s\n", ui.Bold(dep.ArtifactPath))
 	}
@@ -317,6 +332,8 @@ func runListDependencies(cmd *cobra.Command, args []string) error {
 
 	ui.PrintHeader("Dependencies", fmt.Sprintf("%d total", len(meta.Dependencies)), 70)
 	fmt.Println()
+	warnReplacements(projectDir)
+	replace := deps.Replacements(projectDir)
 
 	for i, dep := range meta.Dependencies {
 		fmt.Printf("%s. %s\n", ui.Bold(fmt.Sprintf("%d", i+1)), ui.Bold(dep.URL))
@@ -326,6 +343,9 @@ func runListDependencies(cmd *cobra.Command, args []string) error {
 		if dep.Alias != "" {
 			fmt.Printf("   Alias:   %s\n", ui.Cyan(dep.Alias))
 		}
+		if dep.Version != "" {
+			fmt.Printf("   Version: %s\n", ui.Cyan(dep.Version))
+		}
 		if dep.ArtifactPath != "" {
 			fmt.Printf("   Artifact Path: %s\n", ui.Cyan(dep.ArtifactPath))
 		}
@@ -337,10 +357,25 @@ func runListDependencies(cmd *cobra.Command, args []string) error {
 			fmt.Printf("   Import:    %s\n", ui.Yellow(dep.ImportPath))
 		}
 		if dep.ResolvedCommit != "" {
-			fmt.Printf("   Status:  %s %s\n", ui.Green("✓"), ui.Gray(dep.ResolvedCommit[:8]))
+			fmt.Printf("   Status:  %s %s\n", ui.Green("✓"), ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)))
 		} else {
 			fmt.Printf("   Status:  %s (run %s)\n", ui.Yellow("not resolved"), ui.Cyan("sl deps resolve"))
 		}
+		if local, ok := replace[dep.Alias]; ok {
+			fmt.Printf("   Replaced: %s %s\n", ui.WarningIcon(), ui.Yellow(local))
+		}
+		fmt.Println()
+	}
+
+	if len(meta.Transitive) > 0 {
+		ui.PrintSection("Transitive Dependencies")
+		for _, dep := range meta.Transitive {
+			fmt.Printf("  %s %s %s\n", ui.Bold(dep.Alias), ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)), dep.URL)
+			fmt.Printf("    required by %s\n", ui.Cyan(strings.Join(dep.RequiredBy, ", ")))
+			if local, ok := replace[dep.Alias]; ok {
+				fmt.Printf("    replaced by %s\n", ui.Yellow(local))
+			}
+		}
 		fmt.Println()
 	}
 
@@ -411,78 +446,51 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Resolving %s dependencies...\n", ui.Bold(fmt.Sprintf("%d", len(meta.Dependencies))))
 	fmt.Println()
 
-	// Check for --no-cache flag
+	// --no-cache ignores locked commits and resolves every dependency afresh
 	noCache, _ := cmd.Flags().GetBool("no-cache")
 
+	cache, err := openDependencyCache(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency cache: %w", err)
+	}
+
 	// Resolve each dependency
 	resolvedCount := 0
 	for i, dep := range meta.Dependencies {
-		// Use alias as directory name if available, otherwise generate from URL
-		dirName := dep.Alias
-		if dirName == "" {
-			dirName = generateDirName(dep.URL)
-		}
-
-		// Determine cache location: project-local github.com/specledger/specledger/deps/ or global cache
-		var cacheDir string
-		if noCache {
-			// Use project-local cache
-			cacheDir = filepath.Join(projectDir, "specledger", "deps", dirName)
-		} else {
-			// Use global cache in user home directory
-			homeDir, _ := os.UserHomeDir()
-			cacheDir = filepath.Join(homeDir, ".specledger", "cache", dirName)
-		}
-
 		fmt.Printf("%s. %s\n", ui.Bold(fmt.Sprintf("%d", i+1)), ui.Bold(dep.URL))
 		if dep.Alias != "" {
 			fmt.Printf("   Alias:  %s\n", ui.Cyan(dep.Alias))
 		}
 		fmt.Printf("   Branch: %s\n", ui.Cyan(dep.Branch))
-
-		// Check if already resolved (skip if --no-cache not set and commit exists)
-		if dep.ResolvedCommit != "" && !noCache {
-			// Verify the commit still exists in the cloned repo
-			if _, err := os.Stat(cacheDir); err == nil {
-				// Repo exists, verify commit
-				cmd := exec.Command("git", "-C", cacheDir, "rev-parse", dep.ResolvedCommit+"^{commit}")
-				if output, err := cmd.CombinedOutput(); err == nil {
-					// Commit still valid
-					resolvedCount++
-					fmt.Printf("   Status: %s %s\n", ui.Green("✓"), ui.Gray(strings.TrimSpace(string(output))[:8]))
-					fmt.Println()
-					continue
-				}
-			}
+		if dep.Version != "" {
+			fmt.Printf("   Version: %s\n", ui.Cyan(dep.Version))
 		}
+		fmt.Printf("   Cache:  %s\n", ui.Cyan(cache.RepoPath(dep.URL)))
 
-		// Clone or update the repository
-		fmt.Printf("   Cache:  %s\n", ui.Cyan(cacheDir))
-		fmt.Printf("   Status: %s...\n", ui.Yellow("cloning"))
-
-		if err := cloneOrUpdateRepository(dep, cacheDir); err != nil {
-			ui.PrintWarning(fmt.Sprintf("Failed to clone %s: %v", dep.URL, err))
+		if _, err := cacheDependency(cache, &meta.Dependencies[i], noCache); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to resolve %s: %v", dep.URL, err))
 			fmt.Println()
 			continue
 		}
-
-		// Resolve current commit SHA
-		cmd := exec.Command("git", "-C", cacheDir, "rev-parse", "HEAD")
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			ui.PrintWarning(fmt.Sprintf("Failed to resolve commit: %v", err))
-			fmt.Println()
-			continue
-		}
-
-		commitSHA := strings.TrimSpace(string(output))
-		meta.Dependencies[i].ResolvedCommit = commitSHA
+		dep = meta.Dependencies[i]
 		resolvedCount++
 
-		fmt.Printf("   Status: %s %s\n", ui.Green("✓"), ui.Gray(commitSHA[:8]))
+		fmt.Printf("   Status: %s %s\n", ui.Green("✓"), ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)))
 		fmt.Println()
 	}
 
+	// Follow the dependencies' own dependencies once all of them are cached
+	var conflictErr error
+	if resolvedCount == len(meta.Dependencies) {
+		if err := resolveTransitiveDependencies(projectDir, cache, meta); errors.Is(err, errDependencyConflicts) {
+			conflictErr = err
+			fmt.Println()
+		} else if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to resolve transitive dependencies: %v", err))
+			fmt.Println()
+		}
+	}
+
 	// Save updated metadata
 	if err := metadata.SaveToProject(meta, projectDir); err != nil {
 		return fmt.Errorf("failed to save metadata: %w", err)
@@ -495,12 +503,20 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	// Link all resolved dependencies if --link flag is set
+	// Link all resolved dependencies if --link flag is set, otherwise keep
+	// existing links pointing at the locked commits
 	linkFlag, _ := cmd.Flags().GetBool("link")
+	if !linkFlag {
+		for _, dep := range lockedDependencies(meta) {
+			if dep.ResolvedCommit != "" {
+				relinkDependency(projectDir, meta, dep)
+			}
+		}
+	}
 	if linkFlag && resolvedCount > 0 {
 		ui.PrintSection("Linking Dependencies")
 		linkedCount := 0
-		for _, dep := range meta.Dependencies {
+		for _, dep := range lockedDependencies(meta) {
 			if dep.ResolvedCommit == "" {
 				continue // Skip unresolved dependencies
 			}
@@ -517,58 +533,25 @@ func runResolveDependencies(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
-	return nil
+	return conflictErr
 }
 
-// cloneOrUpdateRepository clones a Git repository if it doesn't exist, or updates it if it does
-func cloneOrUpdateRepository(dep metadata.Dependency, targetDir string) error {
-	// Check if directory already exists
-	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-		// Clone the repository using go-git/v5
-		cloneOpts := deps.CloneOptions{
-			URL:       dep.URL,
-			Branch:    dep.Branch,
-			TargetDir: targetDir,
-			Shallow:   false, // Do full clone for easier updates
-		}
-
-		_, _, err := deps.Clone(cloneOpts)
-		if err != nil {
-			return fmt.Errorf("git clone failed: %w", err)
-		}
-	} else {
-		// Repository exists, open and update it
-		repo, err := deps.OpenRepository(targetDir)
-		if err != nil {
-			return fmt.Errorf("failed to open repository: %w", err)
-		}
-
-		// Pull latest changes
-		_, err = deps.Pull(repo, dep.Branch)
-		if err != nil {
-			// Pull might fail if no tracking branch, that's okay for read-only access
-			return fmt.Errorf("git pull failed: %w", err)
-		}
+// splitDependencyVersion splits repo@version into the URL and the version.
+// The @ of an scp-style URL such as git@github.com:org/spec is not a separator.
+func splitDependencyVersion(arg string) (string, string) {
+	i := strings.LastIndex(arg, "@")
+	if i <= 0 || strings.ContainsAny(arg[i+1:], "/:") {
+		return arg, ""
 	}
-
-	return nil
+	return arg[:i], arg[i+1:]
 }
 
-// generateDirName generates a directory name from a Git URL
-func generateDirName(url string) string {
-	// Remove protocol and domain, extract repo name
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "http://")
-	url = strings.TrimPrefix(url, "git@")
-
-	// Replace : and / with -
-	url = strings.ReplaceAll(url, ":", "-")
-	url = strings.ReplaceAll(url, "/", "-")
-
-	// Remove .git suffix if present
-	url = strings.TrimSuffix(url, ".git")
-
-	return url
+// describeResolved formats a resolved commit with its tag, if any
+func describeResolved(tag, commit string) string {
+	if tag == "" {
+		return commit[:8]
+	}
+	return tag + " " + commit[:8]
 }
 
 func runUpdateDependencies(cmd *cobra.Command, args []string) error {
@@ -590,7 +573,13 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Checking %s dependencies for updates...\n", ui.Bold(fmt.Sprintf("%d", len(meta.Dependencies))))
 	fmt.Println()
 
+	cache, err := openDependencyCache(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency cache: %w", err)
+	}
+
 	updatesAvailable := 0
+	major, _ := cmd.Flags().GetBool("major")
 
 	for i, dep := range meta.Dependencies {
 		// Filter to specific dependency if URL provided
@@ -603,14 +592,6 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 			fmt.Printf("   Alias:  %s\n", ui.Cyan(dep.Alias))
 		}
 
-		// Get cache directory for this dependency
-		dirName := dep.Alias
-		if dirName == "" {
-			dirName = generateDirName(dep.URL)
-		}
-		homeDir, _ := os.UserHomeDir()
-		cacheDir := filepath.Join(homeDir, ".specledger", "cache", dirName)
-
 		// If dependency hasn't been resolved yet, skip
 		if dep.ResolvedCommit == "" {
 			fmt.Printf("   Status: %s\n", ui.Yellow("not resolved yet (run 'sl deps resolve' first)"))
@@ -619,17 +600,26 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 		}
 
 		// Fetch latest changes from remote
-		fmt.Printf("   Current: %s\n", ui.Gray(dep.ResolvedCommit[:8]))
+		fmt.Printf("   Current: %s\n", ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)))
 		fmt.Printf("   Checking: %s...\n", ui.Yellow("fetching latest"))
 
-		// Open the repository
-		repo, err := deps.OpenRepository(cacheDir)
+		// Open the cached repository
+		repo, err := cache.Repository(dep.URL)
 		if err != nil {
 			ui.PrintWarning(fmt.Sprintf("Failed to open repository: %v", err))
 			fmt.Println()
 			continue
 		}
 
+		if dep.Version != "" {
+			if updateDependencyVersion(cache, repo, &meta.Dependencies[i], major) {
+				updatesAvailable++
+				relinkDependency(projectDir, meta, meta.Dependencies[i])
+			}
+			fmt.Println()
+			continue
+		}
+
 		// Get the latest commit from remote
 		latestCommit, err := deps.ResolveRemoteCommit(repo, dep.Branch)
 		if err != nil {
@@ -663,20 +653,36 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 		// For now, automatically apply updates
 		fmt.Printf("   Status: %s\n", ui.Yellow("updating"))
 
-		// Checkout the latest commit
-		if err := deps.Checkout(repo, latestCommit); err != nil {
-			ui.PrintWarning(fmt.Sprintf("Failed to checkout latest commit: %v", err))
+		// Extract the latest commit into the cache
+		if _, err := cache.Worktree(dep.URL, latestCommit); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to extract latest commit: %v", err))
 			fmt.Println()
 			continue
 		}
 
 		// Update the resolved commit in metadata
 		meta.Dependencies[i].ResolvedCommit = latestCommit
+		relinkDependency(projectDir, meta, meta.Dependencies[i])
 
 		fmt.Printf("   Status: %s\n", ui.Green("updated"))
 		fmt.Println()
 	}
 
+	// The updated commits may require other transitive dependencies
+	var conflictErr error
+	if updatesAvailable > 0 && allDependenciesResolved(meta) {
+		if err := resolveTransitiveDependencies(projectDir, cache, meta); errors.Is(err, errDependencyConflicts) {
+			conflictErr = err
+			fmt.Println()
+		} else if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to resolve transitive dependencies: %v", err))
+			fmt.Println()
+		}
+		for _, dep := range lockedDependencies(meta) {
+			relinkDependency(projectDir, meta, dep)
+		}
+	}
+
 	// Save updated metadata
 	if updatesAvailable > 0 {
 		if err := metadata.SaveToProject(meta, projectDir); err != nil {
@@ -689,7 +695,93 @@ func runUpdateDependencies(cmd *cobra.Command, args []string) error {
 	}
 	fmt.Println()
 
-	return nil
+	return conflictErr
+}
+
+// allDependenciesResolved reports whether every direct dependency is locked
+// to a commit, which resolving transitive dependencies requires
+func allDependenciesResolved(meta *metadata.ProjectMetadata) bool {
+	for _, dep := range meta.Dependencies {
+		if dep.ResolvedCommit == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// updateDependencyVersion moves a versioned dependency to the highest tag
+// matching its constraint, or with major to the highest release tag,
+// rewriting the constraint. It reports whether the dependency changed.
+func updateDependencyVersion(cache *deps.Cache, repo *git.Repository, dep *metadata.Dependency, major bool) bool {
+	if deps.IsCommitPin(dep.Version) {
+		fmt.Printf("   Status: %s\n", ui.Green("pinned to commit "+dep.Version))
+		return false
+	}
+	constraint, err := deps.ParseConstraint(dep.Version)
+	if err != nil {
+		ui.PrintWarning(err.Error())
+		return false
+	}
+	tags, err := deps.ListRemoteTags(dep.URL)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to list remote tags: %v", err))
+		return false
+	}
+
+	target, err := deps.SelectTag(tags, constraint)
+	if major {
+		target, err = deps.LatestTag(tags)
+	}
+	if err != nil {
+		ui.PrintWarning(err.Error())
+		return false
+	}
+	if !major {
+		if latest, err := deps.LatestTag(tags); err == nil && latest.Version.Compare(target.Version) > 0 {
+			fmt.Printf("   Note:    %s is outside %s (use --major)\n", ui.Cyan(latest.Name), dep.Version)
+		}
+	}
+
+	if target.Commit == dep.ResolvedCommit && target.Name == dep.ResolvedTag {
+		fmt.Printf("   Status: %s\n", ui.Green("already up to date"))
+		return false
+	}
+	if v, ok := deps.ParseVersion(dep.ResolvedTag); ok && !major && target.Version.Compare(v) < 0 {
+		// The resolved tag is newer than anything matching, e.g. after the
+		// constraint was narrowed by hand; don't downgrade silently
+		fmt.Printf("   Status: %s\n", ui.Yellow(fmt.Sprintf("%s is newer than the highest tag matching %s", dep.ResolvedTag, dep.Version)))
+		return false
+	}
+
+	fmt.Printf("   Latest:  %s\n", ui.Green(describeResolved(target.Name, target.Commit)))
+	fmt.Printf("   Status: %s\n", ui.Yellow("updating"))
+	hash, err := deps.EnsureCommit(repo, target.Name, target.Commit)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to fetch %s: %v", target.Name, err))
+		return false
+	}
+	commit := hash.String()
+	if _, err := cache.Worktree(dep.URL, commit); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to extract %s: %v", target.Name, err))
+		return false
+	}
+
+	commits, err := deps.Log(repo, dep.ResolvedCommit, commit, 5)
+	if err == nil && commits != "" {
+		fmt.Printf("   Changes:\n")
+		for _, line := range strings.Split(commits, "\n") {
+			fmt.Printf("     %s\n", line)
+		}
+	}
+
+	dep.ResolvedCommit = commit
+	dep.ResolvedTag = target.Name
+	if !constraint.Match(target.Version) {
+		dep.Version = constraint.Rebase(target.Name)
+		fmt.Printf("   Version: %s -> %s\n", constraint, ui.Cyan(dep.Version))
+	}
+	fmt.Printf("   Status: %s\n", ui.Green("updated"))
+	return true
 }
 
 func runLinkDependencies(cmd *cobra.Command, args []string) error {
@@ -717,36 +809,37 @@ func runLinkDependencies(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Creating symlinks from cache to %s/deps/\n", ui.Bold(projectArtifactPath))
 	fmt.Println()
 
-	homeDir, _ := os.UserHomeDir()
+	cache, err := openDependencyCache(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency cache: %w", err)
+	}
 	linkedCount := 0
 
-	for _, dep := range meta.Dependencies {
+	for _, dep := range lockedDependencies(meta) {
 		if dep.Alias == "" {
 			continue
 		}
 
-		// Get cache directory
-		cacheDir := filepath.Join(homeDir, ".specledger", "cache", dep.Alias)
+		// Get dependency's artifact path
+		if dep.ArtifactPath == "" {
+			ui.PrintWarning(fmt.Sprintf("Dependency %s has no artifact_path", dep.Alias))
+			continue
+		}
 
-		// Check if dependency is cached
-		if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
+		// Source: the local replacement, or cache_dir/dep_artifact_path
+		sourceDir, err := dependencySource(cache, projectDir, dep)
+		if errors.Is(err, deps.ErrNotCached) {
 			ui.PrintWarning(fmt.Sprintf("Dependency %s is not cached (run 'sl deps resolve' first)", dep.Alias))
 			continue
 		}
-
-		// Get dependency's artifact path
-		depArtifactPath := dep.ArtifactPath
-		if depArtifactPath == "" {
-			ui.PrintWarning(fmt.Sprintf("Dependency %s has no artifact_path", dep.Alias))
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Dependency %s is not available: %v", dep.Alias, err))
 			continue
 		}
 
-		// Source: cache_dir/dep_artifact_path
-		sourceDir := filepath.Join(cacheDir, depArtifactPath)
-
 		// Check if source exists
 		if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-			ui.PrintWarning(fmt.Sprintf("Artifact path not found in cache: %s", sourceDir))
+			ui.PrintWarning(fmt.Sprintf("Artifact path not found: %s", sourceDir))
 			continue
 		}
 
@@ -805,22 +898,20 @@ func linkDependency(projectDir string, meta *metadata.ProjectMetadata, dep metad
 		return fmt.Errorf("project artifact_path is not set")
 	}
 
-	homeDir, _ := os.UserHomeDir()
-
-	// Get cache directory
-	cacheDir := filepath.Join(homeDir, ".specledger", "cache", dep.Alias)
-
-	// Check if dependency is cached
-	if _, err := os.Stat(cacheDir); os.IsNotExist(err) {
-		return fmt.Errorf("dependency is not cached")
+	cache, err := deps.OpenCache()
+	if err != nil {
+		return err
 	}
 
-	// Source: cache_dir/dep_artifact_path
-	sourceDir := filepath.Join(cacheDir, dep.ArtifactPath)
+	// Source: the local replacement, or cache_dir/dep_artifact_path
+	sourceDir, err := dependencySource(cache, projectDir, dep)
+	if err != nil {
+		return err
+	}
 
 	// Check if source exists
 	if _, err := os.Stat(sourceDir); os.IsNotExist(err) {
-		return fmt.Errorf("artifact path not found in cache: %s", sourceDir)
+		return fmt.Errorf("artifact path not found: %s", sourceDir)
 	}
 
 	// Target: project_dir/project_artifact_path/deps/alias
@@ -905,7 +996,7 @@ func runUnlinkDependencies(cmd *cobra.Command, args []string) error {
 	ui.PrintSection("Unlinking Dependencies")
 
 	unlinkedCount := 0
-	for _, dep := range meta.Dependencies {
+	for _, dep := range lockedDependencies(meta) {
 		// Skip if targeting specific alias and this doesn't match
 		if targetAlias != "" && dep.Alias != targetAlias {
 			continue
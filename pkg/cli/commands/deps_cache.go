@@ -0,0 +1,280 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/spf13/cobra"
+)
+
+// VarDepsCacheCmd represents the deps cache command
+var VarDepsCacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and clean the dependency cache",
+	Long: `Inspect and clean the dependency cache shared by all projects (~/.specledger/cache,
+or $SPECLEDGER_CACHE_DIR).
+
+Each dependency repository is cloned once as a bare repository, keyed by its
+normalized URL, and every commit a project is locked to gets its own read-only
+worktree. Projects locked to different commits of the same repository share
+the clone and never overwrite each other's files.
+
+Projects register themselves with the cache whenever they run 'sl deps'
+commands; prune keeps everything a registered project's specledger.yaml
+is locked to.`,
+}
+
+// VarDepsCacheListCmd represents the deps cache list command
+var VarDepsCacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached repositories and worktrees with their sizes",
+	Example: `  sl deps cache list
+  sl deps cache list --json`,
+	Args: cobra.NoArgs,
+	RunE: runDepsCacheList,
+}
+
+// VarDepsCachePruneCmd represents the deps cache prune command
+var VarDepsCachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove cache entries no registered project uses",
+	Long: `Remove worktrees of commits no registered project is locked to, repositories
+no registered project depends on, leftovers of the old per-alias cache layout,
+and registrations of projects that no longer exist. Old checkouts a registered
+project still links to are kept until it runs 'sl deps resolve', and anything
+fetched within the last hour is kept in case a project is still resolving.`,
+	Example: `  sl deps cache prune --dry-run
+  sl deps cache prune`,
+	Args: cobra.NoArgs,
+	RunE: runDepsCachePrune,
+}
+
+// VarDepsCacheVerifyCmd represents the deps cache verify command
+var VarDepsCacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cached worktrees against their commits",
+	Long: `Check that every cached repository opens and every worktree matches its commit
+file for file. With --fix, damaged worktrees are extracted again and damaged
+repositories removed, to be cloned again by the next 'sl deps resolve'.`,
+	Example: `  sl deps cache verify
+  sl deps cache verify --fix`,
+	Args: cobra.NoArgs,
+	RunE: runDepsCacheVerify,
+}
+
+func init() {
+	VarDepsCmd.AddCommand(VarDepsCacheCmd)
+	VarDepsCacheCmd.AddCommand(VarDepsCacheListCmd, VarDepsCachePruneCmd, VarDepsCacheVerifyCmd)
+
+	VarDepsCacheListCmd.Flags().Bool("json", false, "Output as JSON")
+	VarDepsCachePruneCmd.Flags().Bool("dry-run", false, "Show what would be removed without removing it")
+	VarDepsCachePruneCmd.Flags().Bool("json", false, "Output as JSON")
+	VarDepsCacheVerifyCmd.Flags().Bool("fix", false, "Repair damaged entries")
+	VarDepsCacheVerifyCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// openDependencyCache opens the dependency cache and registers the project
+// as one of its users, so that pruning keeps what it is locked to
+func openDependencyCache(projectDir string) (*deps.Cache, error) {
+	cache, err := deps.OpenCache()
+	if err != nil {
+		return nil, err
+	}
+	if err := cache.RegisterProject(projectDir); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to register project with the dependency cache: %v", err))
+	}
+	return cache, nil
+}
+
+// cacheDependency makes a dependency available in the cache and records the
+// commit it is locked to. It returns the commit's worktree.
+func cacheDependency(cache *deps.Cache, dep *metadata.Dependency, update bool) (string, error) {
+	cached, err := cache.Fetch(*dep, update)
+	if err != nil {
+		return "", err
+	}
+	dep.ResolvedCommit, dep.ResolvedTag = cached.Commit, cached.Tag
+	return cached.Worktree, nil
+}
+
+// dependencyWorktree returns the worktree of the commit a dependency is locked
+// to, extracting it from the cached repository if needed
+func dependencyWorktree(cache *deps.Cache, dep metadata.Dependency) (string, error) {
+	if dep.ResolvedCommit == "" {
+		return "", fmt.Errorf("%w: %s is not resolved", deps.ErrNotCached, dep.Alias)
+	}
+	return cache.Worktree(dep.URL, dep.ResolvedCommit)
+}
+
+// relinkDependency points an existing link of a dependency at the worktree of
+// its current commit. Dependencies that are not linked are left alone.
+func relinkDependency(projectDir string, meta *metadata.ProjectMetadata, dep metadata.Dependency) {
+	target := filepath.Join(projectDir, meta.GetArtifactPath(), "deps", dep.Alias)
+	if info, err := os.Lstat(target); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return
+	}
+	if err := linkDependency(projectDir, meta, dep); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Failed to relink %s: %v", dep.Alias, err))
+	}
+}
+
+func runDepsCacheList(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cache, err := deps.OpenCache()
+	if err != nil {
+		return err
+	}
+	report, err := cache.List()
+	if err != nil {
+		return fmt.Errorf("failed to read dependency cache: %w", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	ui.PrintSection("Dependency Cache")
+	fmt.Printf("Location: %s\n", ui.Cyan(report.Root))
+	fmt.Printf("Projects: %d registered\n\n", len(report.Projects)+len(report.Stale))
+
+	if len(report.Repos) == 0 && len(report.Leftovers) == 0 && len(report.Linked) == 0 {
+		fmt.Println("The cache is empty.")
+		return nil
+	}
+	for _, r := range report.Repos {
+		url := r.URL
+		if url == "" {
+			url = ui.Yellow("(repository missing)")
+		}
+		fmt.Printf("%s %s\n", ui.Bold(url), ui.Gray(formatSize(r.Size)))
+		if len(r.Projects) == 0 {
+			fmt.Printf("  %s\n", ui.Yellow("not used by any registered project"))
+		}
+		for _, wt := range r.Worktrees {
+			users := ui.Yellow("unused")
+			if len(wt.Projects) > 0 {
+				users = fmt.Sprintf("%d project(s)", len(wt.Projects))
+			}
+			fmt.Printf("  %s  %8s  %s\n", cligit.ShortHash(wt.Commit), formatSize(wt.Size), users)
+		}
+	}
+	for _, path := range report.Leftovers {
+		fmt.Printf("%s %s\n", ui.Gray(path), ui.Yellow("(leftover)"))
+	}
+	for _, path := range report.Linked {
+		fmt.Printf("%s %s\n", ui.Gray(path), ui.Yellow("(old layout, still linked; kept until the project runs 'sl deps resolve')"))
+	}
+	if len(report.Stale) > 0 {
+		fmt.Printf("\n%d registered project(s) no longer exist\n", len(report.Stale))
+	}
+	fmt.Printf("\nTotal: %s\n", ui.Bold(formatSize(report.Size)))
+	return nil
+}
+
+func runDepsCachePrune(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	var cache *deps.Cache
+	var err error
+	// The current project counts even if it never registered
+	if projectDir, findErr := metadata.FindProjectRoot(); findErr == nil {
+		cache, err = openDependencyCache(projectDir)
+	} else {
+		cache, err = deps.OpenCache()
+	}
+	if err != nil {
+		return err
+	}
+	result, err := cache.Prune(dryRun)
+	if err != nil {
+		return fmt.Errorf("failed to prune dependency cache: %w", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, r := range result.Repos {
+		fmt.Printf("  %s %s\n", verb, r.URL)
+	}
+	for _, wt := range result.Worktrees {
+		fmt.Printf("  %s %s\n", verb, ui.Gray(wt.Path))
+	}
+	for _, path := range result.Leftovers {
+		fmt.Printf("  %s %s\n", verb, ui.Gray(path))
+	}
+	for _, path := range result.Stale {
+		fmt.Printf("  Forgot project %s\n", ui.Gray(path))
+	}
+	if len(result.Repos)+len(result.Worktrees)+len(result.Leftovers)+len(result.Stale) == 0 {
+		fmt.Println("Nothing to prune.")
+		return nil
+	}
+	fmt.Println()
+	if dryRun {
+		fmt.Printf("%s would be freed (dry run)\n", formatSize(result.Freed))
+		return nil
+	}
+	ui.PrintSuccess(fmt.Sprintf("Freed %s", formatSize(result.Freed)))
+	return nil
+}
+
+func runDepsCacheVerify(cmd *cobra.Command, args []string) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	cache, err := deps.OpenCache()
+	if err != nil {
+		return err
+	}
+	problems, err := cache.Verify(fix)
+	if err != nil {
+		return fmt.Errorf("failed to verify dependency cache: %w", err)
+	}
+
+	unfixed := 0
+	for _, p := range problems {
+		if !p.Fixed {
+			unfixed++
+		}
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(problems, "", "  ")
+		fmt.Println(string(data))
+	} else {
+		for _, p := range problems {
+			status := ui.Yellow("damaged")
+			if p.Fixed {
+				status = ui.Green("fixed")
+			}
+			fmt.Printf("  %s %s: %s (%s)\n", ui.WarningIcon(), p.Path, p.Problem, status)
+		}
+		if len(problems) == 0 {
+			ui.PrintSuccess("Dependency cache is intact")
+		} else if unfixed > 0 && !fix {
+			fmt.Println("\nRun 'sl deps cache verify --fix' to repair.")
+		}
+	}
+
+	if unfixed > 0 {
+		return fmt.Errorf("%d damaged cache entry(ies)", unfixed)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/spf13/cobra"
+)
+
+// VarDepsDiffCmd represents the deps diff command
+var VarDepsDiffCmd = &cobra.Command{
+	Use:   "diff <alias>",
+	Short: "Show upstream changes to a dependency's specs",
+	Long: `Show a unified diff of a dependency's artifact path between the commit it is
+locked to and a newer one, to review upstream spec changes before updating.
+
+By default the target is what 'sl deps update' would move to: the head of the
+dependency's branch, or the highest tag matching its version constraint. --to
+accepts a branch, tag or commit of the dependency's repository.`,
+	Example: `  sl deps diff api
+  sl deps diff api --stat
+  sl deps diff api --to v2.0.0 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDepsDiff,
+}
+
+func init() {
+	VarDepsCmd.AddCommand(VarDepsDiffCmd)
+
+	VarDepsDiffCmd.Flags().String("to", "", "Branch, tag or commit to compare with (default: latest)")
+	VarDepsDiffCmd.Flags().Bool("stat", false, "Show changed files with line counts instead of the diff")
+	VarDepsDiffCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// dependencyDiff is the change to a dependency's artifacts between two commits
+type dependencyDiff struct {
+	Alias        string            `json:"alias"`
+	URL          string            `json:"url"`
+	ArtifactPath string            `json:"artifact_path"`
+	From         string            `json:"from"`
+	FromTag      string            `json:"from_tag,omitempty"`
+	To           string            `json:"to"`
+	ToRef        string            `json:"to_ref,omitempty"`
+	Commits      int               `json:"commits"`
+	Files        []deps.FileChange `json:"files"`
+}
+
+func runDepsDiff(cmd *cobra.Command, args []string) error {
+	to, _ := cmd.Flags().GetString("to")
+	stat, _ := cmd.Flags().GetBool("stat")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	projectDir, err := metadata.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	var dep *metadata.Dependency
+	for _, d := range lockedDependencies(meta) {
+		if d.Alias == args[0] {
+			dep = &d
+			break
+		}
+	}
+	if dep == nil {
+		return fmt.Errorf("dependency not found: %s", args[0])
+	}
+	if dep.ResolvedCommit == "" {
+		return fmt.Errorf("dependency %s is not resolved (run 'sl deps resolve' first)", dep.Alias)
+	}
+
+	cache, err := openDependencyCache(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency cache: %w", err)
+	}
+	repo, err := cache.Repository(dep.URL)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", dep.URL, err)
+	}
+	from, err := deps.EnsureCommit(repo, dep.ResolvedTag, dep.ResolvedCommit)
+	if err != nil {
+		return fmt.Errorf("failed to fetch locked commit: %w", err)
+	}
+	toRef, toCommit, err := resolveDiffTarget(repo, *dep, to)
+	if err != nil {
+		return err
+	}
+
+	changes, err := deps.DiffPath(repo, from.String(), toCommit, dep.ArtifactPath)
+	if err != nil {
+		return err
+	}
+	commits, err := deps.CommitsBetween(repo, from.String(), toCommit, 0)
+	if err != nil {
+		return err
+	}
+	diff := dependencyDiff{
+		Alias:        dep.Alias,
+		URL:          dep.URL,
+		ArtifactPath: dep.ArtifactPath,
+		From:         from.String(),
+		FromTag:      dep.ResolvedTag,
+		To:           toCommit,
+		ToRef:        toRef,
+		Commits:      len(commits),
+		Files:        changes,
+	}
+
+	if jsonOutput {
+		if stat {
+			for i := range diff.Files {
+				diff.Files[i].Patch = ""
+			}
+		}
+		data, _ := json.MarshalIndent(diff, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	target := cligit.ShortHash(toCommit)
+	if toRef != "" {
+		target = toRef + " " + target
+	}
+	if len(changes) == 0 {
+		scope := dep.Alias
+		if dep.ArtifactPath != "" {
+			scope += " (" + dep.ArtifactPath + ")"
+		}
+		fmt.Printf("No changes to %s between %s and %s\n", scope, describeResolved(dep.ResolvedTag, from.String()), target)
+		return nil
+	}
+	if stat {
+		printDiffStat(diff)
+		return nil
+	}
+	for _, fc := range changes {
+		printPatch(fc.Patch)
+	}
+	return nil
+}
+
+// resolveDiffTarget returns the ref and commit to compare a dependency with:
+// the latest commit by default, or the given branch, tag or commit
+func resolveDiffTarget(repo *git.Repository, dep metadata.Dependency, ref string) (string, string, error) {
+	if ref == "" {
+		tag, commit, err := latestDependencyCommit(repo, dep)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to resolve latest commit: %w", err)
+		}
+		return tag, commit, nil
+	}
+	if deps.IsCommitPin(ref) {
+		if hash, err := deps.EnsureCommit(repo, "", ref); err == nil {
+			return "", hash.String(), nil
+		}
+	}
+	if commit, err := deps.ResolveRemoteCommit(repo, ref); err == nil {
+		return ref, commit, nil
+	}
+	if hash, err := deps.EnsureCommit(repo, ref, "refs/tags/"+ref); err == nil {
+		return ref, hash.String(), nil
+	}
+	return "", "", fmt.Errorf("%s is not a branch, tag or commit of %s", ref, dep.URL)
+}
+
+func printDiffStat(diff dependencyDiff) {
+	width := 0
+	for _, fc := range diff.Files {
+		width = max(width, len(fc.Path))
+	}
+	additions, deletions := 0, 0
+	for _, fc := range diff.Files {
+		additions += fc.Additions
+		deletions += fc.Deletions
+		change := fmt.Sprintf("%d ", fc.Additions+fc.Deletions)
+		if fc.Additions > 0 {
+			change += ui.Green(strings.Repeat("+", min(fc.Additions, 40)))
+		}
+		if fc.Deletions > 0 {
+			change += ui.Red(strings.Repeat("-", min(fc.Deletions, 40)))
+		}
+		if fc.Binary {
+			change = "Bin"
+		}
+		fmt.Printf(" %-*s | %s\n", width, fc.Path, change)
+	}
+	summary := fmt.Sprintf(" %d file(s) changed, %d insertion(s)(+), %d deletion(s)(-)", len(diff.Files), additions, deletions)
+	if diff.Commits > 0 {
+		summary += fmt.Sprintf(" in %d commit(s)", diff.Commits)
+	}
+	fmt.Println(summary)
+}
+
+// printPatch prints a unified diff, colored by line kind
+func printPatch(patch string) {
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff "):
+			fmt.Print(ui.Bold(strings.TrimSuffix(line, "\n")) + "\n")
+		case strings.HasPrefix(line, "@@"):
+			fmt.Print(ui.Cyan(strings.TrimSuffix(line, "\n")) + "\n")
+		case strings.HasPrefix(line, "+"):
+			fmt.Print(ui.Green(strings.TrimSuffix(line, "\n")) + "\n")
+		case strings.HasPrefix(line, "-"):
+			fmt.Print(ui.Red(strings.TrimSuffix(line, "\n")) + "\n")
+		default:
+			fmt.Print(line)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/spf13/cobra"
+)
+
+// VarDepsOutdatedCmd represents the deps outdated command
+var VarDepsOutdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show dependencies with upstream changes",
+	Long: `Show how far each dependency's locked commit is behind upstream.
+
+The latest commit is the head of the dependency's branch, or for versioned
+dependencies the highest tag matching the version constraint, i.e. what
+'sl deps update' would move to. Commit pins are never outdated. Nothing is
+changed; use 'sl deps diff <alias>' to review the changes first.`,
+	Example: `  sl deps outdated
+  sl deps outdated --json`,
+	Args: cobra.NoArgs,
+	RunE: runDepsOutdated,
+}
+
+func init() {
+	VarDepsCmd.AddCommand(VarDepsOutdatedCmd)
+
+	VarDepsOutdatedCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// outdatedDependency is how far a dependency is behind upstream
+type outdatedDependency struct {
+	Alias      string     `json:"alias"`
+	URL        string     `json:"url"`
+	Version    string     `json:"version,omitempty"`
+	Locked     string     `json:"locked,omitempty"`
+	LockedTag  string     `json:"locked_tag,omitempty"`
+	Latest     string     `json:"latest,omitempty"`
+	LatestTag  string     `json:"latest_tag,omitempty"`
+	Behind     int        `json:"behind"`
+	LastChange *time.Time `json:"last_change,omitempty"`
+	Pinned     bool       `json:"pinned,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+func runDepsOutdated(cmd *cobra.Command, args []string) error {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	projectDir, err := metadata.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+	if len(meta.Dependencies) == 0 {
+		fmt.Println("No dependencies found")
+		return nil
+	}
+	cache, err := openDependencyCache(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency cache: %w", err)
+	}
+
+	results := make([]outdatedDependency, 0, len(meta.Dependencies))
+	for _, dep := range meta.Dependencies {
+		results = append(results, checkOutdated(cache, dep))
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	behind := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ALIAS\tLOCKED\tLATEST\tBEHIND\tLAST CHANGE")
+	for _, r := range results {
+		locked := "-"
+		if r.Locked != "" {
+			locked = describeResolved(r.LockedTag, r.Locked)
+		}
+		latest, count, changed := "-", "-", "-"
+		switch {
+		case r.Error != "":
+			latest = "error: " + r.Error
+		case r.Pinned:
+			latest = "pinned"
+		default:
+			latest = describeResolved(r.LatestTag, r.Latest)
+			count = fmt.Sprintf("%d", r.Behind)
+		}
+		if r.LastChange != nil {
+			changed = r.LastChange.Format("2006-01-02")
+		}
+		if r.Behind > 0 {
+			behind++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Alias, locked, latest, count, changed)
+	}
+	w.Flush()
+	fmt.Println()
+
+	if behind == 0 {
+		ui.PrintSuccess("All dependencies are up to date")
+	} else {
+		fmt.Printf("%d of %d dependencies are behind (see 'sl deps diff <alias>', 'sl deps update')\n", behind, len(results))
+	}
+	return nil
+}
+
+// checkOutdated compares a dependency's locked commit with the latest one
+func checkOutdated(cache *deps.Cache, dep metadata.Dependency) outdatedDependency {
+	r := outdatedDependency{
+		Alias:     dep.Alias,
+		URL:       dep.URL,
+		Version:   dep.Version,
+		Locked:    dep.ResolvedCommit,
+		LockedTag: dep.ResolvedTag,
+		Pinned:    deps.IsCommitPin(dep.Version),
+	}
+	repo, err := cache.Repository(dep.URL)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	tag, latest, err := latestDependencyCommit(repo, dep)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Latest, r.LatestTag = latest, tag
+	if c, err := repo.CommitObject(plumbing.NewHash(latest)); err == nil {
+		when := c.Committer.When
+		r.LastChange = &when
+	}
+	if r.Pinned || latest == dep.ResolvedCommit {
+		return r
+	}
+
+	from := dep.ResolvedCommit
+	if from != "" {
+		if hash, err := deps.EnsureCommit(repo, dep.ResolvedTag, from); err == nil {
+			from = hash.String()
+		}
+	}
+	commits, err := deps.CommitsBetween(repo, from, latest, 0)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Behind = len(commits)
+	return r
+}
+
+// latestDependencyCommit returns the commit 'sl deps update' would move a
+// dependency to: the highest tag matching its version constraint or the head
+// of its branch. A commit pin stays at its locked commit.
+func latestDependencyCommit(repo *git.Repository, dep metadata.Dependency) (string, string, error) {
+	var tag, commit string
+	var err error
+	switch {
+	case deps.IsCommitPin(dep.Version):
+		tag, commit = dep.ResolvedTag, dep.ResolvedCommit
+		if commit == "" {
+			commit = dep.Version
+		}
+	case dep.Version != "":
+		tag, commit, err = deps.ResolveVersion(dep.URL, dep.Version)
+	default:
+		commit, err = deps.ResolveRemoteCommit(repo, dep.Branch)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := deps.EnsureCommit(repo, tag, commit)
+	if err != nil {
+		return "", "", err
+	}
+	return tag, hash.String(), nil
+}
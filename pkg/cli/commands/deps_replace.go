@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/specledger/specledger/pkg/cli/config"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/spf13/cobra"
+)
+
+// VarDepsReplaceCmd represents the deps replace command
+var VarDepsReplaceCmd = &cobra.Command{
+	Use:   "replace [<alias> <local-path>]",
+	Short: "Use a local working copy instead of a cached dependency",
+	Long: `Point a dependency at a local working copy instead of the cache, like replace
+in go.mod, to co-develop two spec repositories.
+
+The replacement is stored in the personal, git-ignored config
+(specledger/specledger.local.yaml), so it never reaches the team. Links
+('sl deps link'), alias:artifact references and 'sl doctor' use the working
+copy; the commit locked in specledger.yaml is left as it is. Without
+arguments, the active replacements are listed.`,
+	Example: `  sl deps replace api ../api-specs
+  sl deps replace
+  sl deps replace --drop api`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runDepsReplace,
+}
+
+func init() {
+	VarDepsCmd.AddCommand(VarDepsReplaceCmd)
+
+	VarDepsReplaceCmd.Flags().Bool("drop", false, "Remove the replacement of <alias>")
+}
+
+func runDepsReplace(cmd *cobra.Command, args []string) error {
+	drop, _ := cmd.Flags().GetBool("drop")
+
+	projectDir, err := metadata.FindProjectRoot()
+	if err != nil {
+		return fmt.Errorf("failed to find project root: %w", err)
+	}
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return fmt.Errorf("failed to load metadata: %w", err)
+	}
+	personal, err := config.LoadPersonal(projectDir)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case drop && len(args) != 1:
+		return fmt.Errorf("--drop takes exactly one alias")
+	case !drop && len(args) == 1:
+		return fmt.Errorf("missing local path for %s", args[0])
+	case len(args) == 0:
+		printReplacements(personal.Replace)
+		return nil
+	}
+
+	alias := args[0]
+	var dep *metadata.Dependency
+	for _, d := range lockedDependencies(meta) {
+		if d.Alias == alias {
+			dep = &d
+			break
+		}
+	}
+
+	if drop {
+		if _, ok := personal.Replace[alias]; !ok {
+			return fmt.Errorf("%s is not replaced", alias)
+		}
+		delete(personal.Replace, alias)
+		if err := personal.Save(projectDir); err != nil {
+			return err
+		}
+		ui.PrintSuccess(fmt.Sprintf("%s uses the cache again", alias))
+		if dep != nil {
+			relinkDependency(projectDir, meta, *dep)
+		}
+		return nil
+	}
+
+	if dep == nil {
+		return fmt.Errorf("dependency not found: %s", alias)
+	}
+	local, err := filepath.Abs(args[1])
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(local); err != nil || !info.IsDir() {
+		return fmt.Errorf("local path is not a directory: %s", local)
+	}
+	if _, err := os.Stat(filepath.Join(local, dep.ArtifactPath)); err != nil {
+		ui.PrintWarning(fmt.Sprintf("%s has no artifact path %s", local, dep.ArtifactPath))
+	}
+	if remote := projectRemoteURL(local); remote != "" && deps.NormalizeURL(remote) != deps.NormalizeURL(dep.URL) {
+		ui.PrintWarning(fmt.Sprintf("%s is a working copy of %s, not %s", local, remote, dep.URL))
+	}
+
+	if personal.Replace == nil {
+		personal.Replace = make(map[string]string)
+	}
+	personal.Replace[alias] = local
+	if err := personal.Save(projectDir); err != nil {
+		return err
+	}
+	ui.PrintSuccess(fmt.Sprintf("%s replaced by %s", alias, local))
+	personalPath := config.GetPersonalConfigPath(projectDir)
+	fmt.Printf("  Stored in %s (not committed)\n", ui.Gray(personalPath))
+	if ignored, err := cligit.IsIgnored(projectDir, personalPath); err == nil && !ignored {
+		ui.PrintWarning(fmt.Sprintf("%s is not git-ignored; add it to .gitignore so the replacement is not shared", personalPath))
+	}
+	relinkDependency(projectDir, meta, *dep)
+	return nil
+}
+
+func printReplacements(replace map[string]string) {
+	if len(replace) == 0 {
+		fmt.Println("No dependencies are replaced.")
+		return
+	}
+	aliases := make([]string, 0, len(replace))
+	for alias := range replace {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		fmt.Printf("  %s => %s\n", ui.Bold(alias), replace[alias])
+	}
+}
+
+// dependencySource returns the directory holding a dependency's artifacts: its
+// local replacement if it has one, otherwise the artifact path within the
+// cached worktree of its locked commit
+func dependencySource(cache *deps.Cache, projectDir string, dep metadata.Dependency) (string, error) {
+	if dir, ok := deps.ReplacementDir(projectDir, dep); ok {
+		if _, err := os.Stat(dir); err != nil {
+			return "", fmt.Errorf("replacement of %s not found: %s", dep.Alias, dir)
+		}
+		return dir, nil
+	}
+	worktree, err := dependencyWorktree(cache, dep)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(worktree, dep.ArtifactPath), nil
+}
+
+// warnReplacements warns that some of the dependencies are replaced by local
+// working copies
+func warnReplacements(projectDir string) {
+	replace := deps.Replacements(projectDir)
+	if len(replace) == 0 {
+		return
+	}
+	ui.PrintWarning(fmt.Sprintf("%d dependency(ies) replaced by local working copies (see 'sl deps replace')", len(replace)))
+	printReplacements(replace)
+	fmt.Println()
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
+)
+
+// errDependencyConflicts is returned when requirements on a dependency cannot
+// all be satisfied
+var errDependencyConflicts = errors.New("dependency conflicts")
+
+// resolveDependencyGraph resolves the project's transitive dependencies,
+// cloning any that are missing into the cache
+func resolveDependencyGraph(projectDir string, cache *deps.Cache, meta *metadata.ProjectMetadata) (*deps.Resolution, error) {
+	open := func(dep metadata.Dependency) (*git.Repository, error) {
+		return cache.Repository(dep.URL)
+	}
+	return deps.ResolveTransitive(meta, deps.TransitiveOptions{RootURL: projectRemoteURL(projectDir), Open: open})
+}
+
+// projectRemoteURL returns the origin URL of the project, or "" if it has none
+func projectRemoteURL(projectDir string) string {
+	output, err := exec.Command("git", "-C", projectDir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// resolveTransitiveDependencies resolves the dependencies of the project's
+// dependencies, extracts the selected commits in the cache and records them
+// in the lock. Nothing is changed when there are unresolved conflicts.
+func resolveTransitiveDependencies(projectDir string, cache *deps.Cache, meta *metadata.ProjectMetadata) error {
+	ui.PrintSection("Transitive Dependencies")
+
+	res, err := resolveDependencyGraph(projectDir, cache, meta)
+	if err != nil {
+		return err
+	}
+	printDependencyCycles(res)
+	if unresolved := res.Unresolved(); len(unresolved) > 0 {
+		printDependencyConflicts(res)
+		return fmt.Errorf("%w: %d found, lock left unchanged (see 'sl conflict check')", errDependencyConflicts, len(unresolved))
+	}
+
+	// Another dependency may require a newer commit of a direct dependency
+	for i, dep := range res.Direct {
+		old := meta.Dependencies[i]
+		if dep.ResolvedCommit == old.ResolvedCommit {
+			continue
+		}
+		if _, err := dependencyWorktree(cache, dep); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", dep.Alias, err)
+		}
+		fmt.Printf("  %s %s raised to %s\n", ui.WarningIcon(), ui.Bold(dep.Alias), ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)))
+		meta.Dependencies[i] = dep
+	}
+
+	for _, dep := range res.Transitive {
+		if _, err := dependencyWorktree(cache, dep.Dependency); err != nil {
+			return fmt.Errorf("failed to extract %s: %w", dep.Alias, err)
+		}
+		fmt.Printf("  %s %s %s %s\n", ui.Green("✓"), ui.Bold(dep.Alias), ui.Gray(describeResolved(dep.ResolvedTag, dep.ResolvedCommit)),
+			ui.Gray("(required by "+strings.Join(dep.RequiredBy, ", ")+")"))
+	}
+	if len(res.Transitive) == 0 {
+		fmt.Println("  No transitive dependencies.")
+	}
+	printDependencyConflicts(res)
+	fmt.Println()
+
+	meta.Transitive = res.Transitive
+	return nil
+}
+
+func printDependencyCycles(res *deps.Resolution) {
+	for _, cycle := range res.Cycles {
+		fmt.Printf("  %s Dependency cycle: %s\n", ui.WarningIcon(), strings.Join(cycle, " -> "))
+	}
+}
+
+func printDependencyConflicts(res *deps.Resolution) {
+	for _, conflict := range res.Conflicts {
+		status := ui.Yellow("conflict")
+		if conflict.Overridden {
+			status = ui.Gray("overridden by the project")
+		}
+		fmt.Printf("  %s %s: %s (%s)\n", ui.WarningIcon(), ui.Bold(conflict.Alias), conflict.Reason, status)
+		for _, req := range conflict.Requirements {
+			want := req.Branch
+			if req.Version != "" {
+				want = req.Version
+			}
+			if want == "" {
+				want = "main"
+			}
+			fmt.Printf("      %s wants %s at %s\n", req.RequiredBy, want, ui.Gray(describeResolved(req.Tag, req.Commit)))
+		}
+	}
+}
+
+// lockedDependencies returns the project's direct and transitive dependencies
+func lockedDependencies(meta *metadata.ProjectMetadata) []metadata.Dependency {
+	all := append([]metadata.Dependency{}, meta.Dependencies...)
+	for _, dep := range meta.Transitive {
+		all = append(all, dep.Dependency)
+	}
+	return all
+}
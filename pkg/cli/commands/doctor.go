@@ -12,6 +12,7 @@ import (
 	"github.com/specledger/specledger/pkg/cli/prerequisites"
 	"github.com/specledger/specledger/pkg/cli/tui"
 	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
 	"github.com/specledger/specledger/pkg/templates"
 	"github.com/specledger/specledger/pkg/version"
 	"github.com/spf13/cobra"
@@ -33,6 +34,7 @@ This command verifies that:
 - Core tools (mise) are installed and accessible
 - CLI version is up to date (prompts to update if not)
 - Project templates match the CLI version (prompts to apply if not)
+- Dependencies are cached, or replaced by local working copies that exist
 
 Use --update to update the CLI binary without prompting.
 Use --template to apply embedded templates without prompting.
@@ -70,6 +72,9 @@ type DoctorOutput struct {
 	TemplateVersion         string   `json:"template_version,omitempty"`
 	TemplateUpdateAvailable bool     `json:"template_update_available"`
 	TemplateCustomizedFiles []string `json:"template_customized_files,omitempty"`
+
+	// Dependency info
+	Dependencies []DoctorDependencyStatus `json:"dependencies,omitempty"`
 }
 
 // DoctorToolStatus represents a tool's status in JSON output
@@ -81,6 +86,14 @@ type DoctorToolStatus struct {
 	Category  string `json:"category"`
 }
 
+// DoctorDependencyStatus represents a dependency's status in JSON output
+type DoctorDependencyStatus struct {
+	Alias  string `json:"alias"`
+	URL    string `json:"url"`
+	Status string `json:"status"` // cached, not_cached, replaced or replacement_missing
+	Path   string `json:"path,omitempty"`
+}
+
 func runDoctor(cmd *cobra.Command, args []string) error {
 	// Flag-only mode: skip full doctor output, just do the requested action(s)
 	if doctorUpdateFlag || doctorTemplateFlag {
@@ -224,6 +237,9 @@ func outputDoctorJSON(check prerequisites.PrerequisiteCheck) error {
 		output.TemplateCustomizedFiles = templateStatus.CustomizedFiles
 	}
 
+	// Add dependency info
+	output.Dependencies = checkDependencies(projectDir)
+
 	// Marshal and print JSON
 	jsonBytes, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
@@ -341,6 +357,27 @@ func outputDoctorHuman(check prerequisites.PrerequisiteCheck) error {
 		fmt.Println()
 	}
 
+	// Dependency section (only if the project has dependencies)
+	if statuses := checkDependencies(projectDir); len(statuses) > 0 {
+		fmt.Println(ui.Bold("Dependencies"))
+		fmt.Println(ui.Cyan("────────────"))
+		fmt.Println()
+
+		for _, dep := range statuses {
+			switch dep.Status {
+			case "replaced":
+				fmt.Printf("  ⚠  %s %s\n", ui.Bold(dep.Alias), ui.Yellow(fmt.Sprintf("(replaced by %s)", dep.Path)))
+			case "replacement_missing":
+				fmt.Printf("  %s %s %s\n", ui.Crossmark(), ui.Bold(dep.Alias), ui.Red(fmt.Sprintf("(replacement not found: %s)", dep.Path)))
+			case "not_cached":
+				fmt.Printf("  ⚠  %s %s\n", ui.Bold(dep.Alias), ui.Yellow("(not cached, run 'sl deps resolve')"))
+			default:
+				fmt.Printf("  %s %s\n", ui.Checkmark(), ui.Bold(dep.Alias))
+			}
+		}
+		fmt.Println()
+	}
+
 	// Overall status
 	if check.AllCoreInstalled {
 		ui.PrintBox("All core tools installed", ui.Green, 54)
@@ -356,6 +393,41 @@ func outputDoctorHuman(check prerequisites.PrerequisiteCheck) error {
 	return fmt.Errorf("missing required tools")
 }
 
+// checkDependencies reports whether each locked dependency of the project is
+// cached or replaced by a local working copy. Nothing is fetched or extracted.
+func checkDependencies(projectDir string) []DoctorDependencyStatus {
+	if projectDir == "" {
+		return nil
+	}
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return nil
+	}
+	cache, err := deps.OpenCache()
+	if err != nil {
+		return nil
+	}
+
+	var statuses []DoctorDependencyStatus
+	for _, dep := range lockedDependencies(meta) {
+		status := DoctorDependencyStatus{Alias: dep.Alias, URL: dep.URL, Status: "not_cached"}
+		if dir, ok := deps.ReplacementDir(projectDir, dep); ok {
+			status.Status, status.Path = "replaced", dir
+			if _, err := os.Stat(dir); err != nil {
+				status.Status = "replacement_missing"
+			}
+		} else if dep.ResolvedCommit != "" {
+			if _, err := os.Stat(cache.WorktreePath(dep.URL, dep.ResolvedCommit)); err == nil {
+				status.Status = "cached"
+			} else if _, err := os.Stat(cache.RepoPath(dep.URL)); err == nil {
+				status.Status = "cached"
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
 // hasUncommittedChanges checks if there are uncommitted changes in .claude/ directory
 func hasUncommittedChanges(projectDir string) bool {
 	// Run git status to check for uncommitted changes in .claude/
@@ -2,7 +2,12 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/deps"
 	"github.com/spf13/cobra"
 )
 
@@ -12,35 +17,45 @@ var VarGraphCmd = &cobra.Command{
 	Short: "Display dependency graphs",
 	Long: `Visualize dependencies and their relationships.
 
-NOTE: This feature is coming soon. For now, use 'sl deps list' to see dependencies.`,
+The graph starts from the dependencies declared in specledger.yaml and follows
+each cached dependency's own specledger.yaml at its locked commit (under
+~/.specledger/cache) to discover transitive dependencies. Run 'sl deps resolve'
+first so the cache is populated; uncached dependencies are shown as leaves.
+
+Supported formats: text, json, dot (Graphviz), mermaid, svg`,
 }
 
 // VarShowCmd represents the show command
 var VarShowCmd = &cobra.Command{
 	Use:   "show",
-	Short: "Show dependency graph (coming soon)",
-	Long: `Display the complete dependency graph with all nodes and edges.
+	Short: "Show dependency graph",
+	Long: `Display the dependency graph with all nodes and edges.
 
-This will show how specifications depend on each other.`,
-	Example: "  sl graph show",
-	RunE:    runShowGraph,
+By default only direct dependencies are shown. Use --include-transitive to
+follow cached dependencies' own dependencies.`,
+	Example: `  sl graph show
+  sl graph show --include-transitive
+  sl graph show --format mermaid`,
+	RunE: runShowGraph,
 }
 
 // VarExportCmd represents the export command
 var VarExportCmd = &cobra.Command{
 	Use:   "export --format <format> --output <file>",
-	Short: "Export graph to file (coming soon)",
-	Long: `Export the dependency graph to a file for visualization.
+	Short: "Export graph to file",
+	Long: `Export the full (transitive) dependency graph to a file for visualization.
 
-Supported formats will include: JSON, SVG, DOT (Graphviz)`,
-	Example: "  sl graph export --format svg --output deps.svg",
-	RunE:    runExportGraph,
+Supported formats: json, svg, dot (Graphviz), mermaid, text.
+When --output is omitted the file is named deps.<ext> after the format.`,
+	Example: `  sl graph export --format svg --output deps.svg
+  sl graph export --format dot --depth 2`,
+	RunE: runExportGraph,
 }
 
 // VarTransitiveCmd represents the transitive command
 var VarTransitiveCmd = &cobra.Command{
 	Use:   "transitive",
-	Short: "Show transitive dependencies (coming soon)",
+	Short: "Show transitive dependencies",
 	Long: `Show all transitive dependencies up to a specified depth.
 
 This helps understand the full dependency tree.`,
@@ -51,34 +66,148 @@ This helps understand the full dependency tree.`,
 func init() {
 	VarGraphCmd.AddCommand(VarShowCmd, VarExportCmd, VarTransitiveCmd)
 
-	VarShowCmd.Flags().StringP("format", "f", "text", "Output format: text, json, svg")
+	VarShowCmd.Flags().StringP("format", "f", "text", "Output format: text, json, dot, mermaid, svg")
 	VarShowCmd.Flags().BoolP("include-transitive", "t", false, "Include transitive dependencies")
-	VarExportCmd.Flags().StringP("format", "f", "json", "Export format: json, svg, text")
-	VarExportCmd.Flags().StringP("output", "o", "deps.svg", "Output file path")
+	VarShowCmd.Flags().IntP("depth", "d", 0, "Maximum depth when including transitive dependencies (0 = unlimited)")
+	VarExportCmd.Flags().StringP("format", "f", "json", "Export format: json, svg, dot, mermaid, text")
+	VarExportCmd.Flags().StringP("output", "o", "", "Output file path (default deps.<ext>)")
+	VarExportCmd.Flags().IntP("depth", "d", 0, "Maximum depth (0 = unlimited)")
 	VarTransitiveCmd.Flags().IntP("depth", "d", 0, "Maximum depth (0 = unlimited)")
+	VarTransitiveCmd.Flags().Bool("json", false, "Output as JSON")
+}
+
+// loadProjectGraph builds the dependency graph for the current project.
+func loadProjectGraph(maxDepth int) (*deps.Graph, error) {
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("--depth must be >= 0")
+	}
+
+	projectDir, err := metadata.FindProjectRoot()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find project root: %w", err)
+	}
+
+	meta, err := metadata.LoadFromProject(projectDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+
+	graph, err := deps.BuildGraph(meta, deps.GraphOptions{MaxDepth: maxDepth})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dependency graph: %w", err)
+	}
+	return graph, nil
 }
 
 func runShowGraph(cmd *cobra.Command, args []string) error {
 	format, _ := cmd.Flags().GetString("format")
-	fmt.Printf("Graph visualization is not yet implemented.\n")
-	fmt.Printf("Requested format: %s\n", format)
-	fmt.Println("\nTODO: Implement dependency graph visualization")
+	includeTransitive, _ := cmd.Flags().GetBool("include-transitive")
+	depth, _ := cmd.Flags().GetInt("depth")
+
+	if !includeTransitive {
+		depth = 1
+	}
+
+	graph, err := loadProjectGraph(depth)
+	if err != nil {
+		return err
+	}
+
+	out, err := deps.RenderGraph(graph, format)
+	if err != nil {
+		return err
+	}
+	fmt.Print(out)
 	return nil
 }
 
 func runExportGraph(cmd *cobra.Command, args []string) error {
 	format, _ := cmd.Flags().GetString("format")
 	output, _ := cmd.Flags().GetString("output")
-	fmt.Printf("Graph export is not yet implemented.\n")
-	fmt.Printf("Requested format: %s, output: %s\n", format, output)
-	fmt.Println("\nTODO: Implement graph export functionality")
+	depth, _ := cmd.Flags().GetInt("depth")
+
+	graph, err := loadProjectGraph(depth)
+	if err != nil {
+		return err
+	}
+
+	out, err := deps.RenderGraph(graph, format)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = "deps" + deps.GraphFileExtension(format)
+	}
+
+	// #nosec G306 -- exported graphs are meant to be shared, 0644 is appropriate
+	if err := os.WriteFile(output, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+
+	fmt.Printf("%s Exported %d nodes, %d edges to %s\n",
+		ui.Checkmark(), len(graph.Nodes), len(graph.Edges), ui.Cyan(output))
 	return nil
 }
 
 func runTransitiveDependencies(cmd *cobra.Command, args []string) error {
 	depth, _ := cmd.Flags().GetInt("depth")
-	fmt.Printf("Transitive dependency visualization is not yet implemented.\n")
-	fmt.Printf("Requested depth: %d\n", depth)
-	fmt.Println("\nTODO: Implement transitive dependency analysis")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	graph, err := loadProjectGraph(depth)
+	if err != nil {
+		return err
+	}
+
+	nodes := graph.Transitive()
+
+	if jsonOutput {
+		out, err := deps.RenderGraphJSON(graph)
+		if err != nil {
+			return err
+		}
+		fmt.Print(out)
+		return nil
+	}
+
+	if len(nodes) == 0 {
+		ui.PrintSection("Transitive Dependencies")
+		fmt.Println("No dependencies declared.")
+		return nil
+	}
+
+	depthLabel := "unlimited depth"
+	if depth > 0 {
+		depthLabel = fmt.Sprintf("depth ≤ %d", depth)
+	}
+	ui.PrintHeader("Transitive Dependencies", fmt.Sprintf("%d total, %s", len(nodes), depthLabel), 70)
+	fmt.Println()
+
+	var uncached, truncated int
+	for _, n := range nodes {
+		indent := strings.Repeat("  ", n.Depth-1)
+		line := fmt.Sprintf("%s%s %s", indent, ui.Gray(fmt.Sprintf("[%d]", n.Depth)), ui.Bold(n.Alias))
+		if n.URL != "" {
+			line += " " + ui.Gray(n.URL)
+		}
+		if !n.Cached {
+			line += " " + ui.Yellow("(not cached)")
+			uncached++
+		}
+		if n.Truncated {
+			line += " " + ui.Gray("(more beyond depth limit)")
+			truncated++
+		}
+		fmt.Println(line)
+	}
+	fmt.Println()
+
+	if uncached > 0 {
+		ui.PrintWarning(fmt.Sprintf("%d dependencies are not cached; run 'sl deps resolve' to discover their dependencies", uncached))
+	}
+	if truncated > 0 {
+		fmt.Printf("%s %d dependencies have further dependencies beyond the depth limit\n", ui.InfoIcon(), truncated)
+	}
+
 	return nil
 }
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	cligit "github.com/specledger/specledger/pkg/cli/git"
 	"github.com/specledger/specledger/pkg/cli/metadata"
 	"github.com/specledger/specledger/pkg/cli/ui"
 	"github.com/specledger/specledger/pkg/issues"
@@ -43,6 +47,9 @@ var (
 	issueCheckDoDFlag    string   // Mark DoD item as checked
 	issueUncheckDoDFlag  string   // Mark DoD item as unchecked
 	issueParentFlag      string   // Parent issue ID
+	issueAsOfFlag        string   // Replay issue state at a point in time
+	issueMigrateToFlag   string   // Target storage backend for migrate
+	issueEstimateFlag    string   // Estimated effort, e.g. 2, 1.5d, 4h, 1w
 )
 
 // getArtifactPath loads the artifact_path from specledger.yaml
@@ -61,19 +68,37 @@ var VarIssueCmd = &cobra.Command{
 	Short: "Manage issues for the current spec",
 	Long: `Manage issues for tracking work within a spec.
 
-Issues are stored in JSONL format at specledger/<spec>/issues.jsonl.
+Issues are stored in JSONL format at specledger/<spec>/issues.jsonl, or in
+specledger/issues.db when task_tracker.storage is sqlite.
 Each issue has a globally unique ID (SL-xxxxxx format).
 
 Commands:
   sl issue create    Create a new issue
   sl issue list      List issues
+  sl issue search    Search issues across specs with a query
+  sl issue board     Open an interactive kanban board
   sl issue show      Show issue details
   sl issue update    Update an issue
   sl issue close     Close an issue
+  sl issue claim     Claim an issue with a lease
+  sl issue heartbeat Renew the lease on a claimed issue
+  sl issue release   Release a claimed issue back to ready
   sl issue link      Link issues with dependencies
   sl issue unlink    Remove dependency links
-  sl issue migrate   Migrate from Beads format
+  sl issue move      Move issues and their children to another spec
+  sl issue migrate   Migrate from Beads format or between storage backends
+  sl issue export    Export issues to CSV, Markdown, GitHub or Jira files
+  sl issue import    Import issues from CSV, Markdown, GitHub or Jira files
   sl issue repair    Repair corrupted issues.jsonl
+  sl issue archive   Archive closed issues to keep issue files small
+  sl issue history   Show the change history of an issue
+  sl issue stats     Show cycle-time and throughput metrics
+  sl issue sync-commits  Link issues to the commits that reference them
+  sl issue plan      Plan an epic: critical path, slack and agent schedule
+  sl issue templates List issue templates and their custom fields
+  sl issue view      Save and run named issue list views
+  sl issue workflow  Show the issue statuses and transition rules
+  sl issue merge-driver  Git merge driver for issues.jsonl
 
 Examples:
   sl issue create --title "Add validation" --type task
@@ -99,15 +124,19 @@ derived from SHA-256 hash of (spec_context + title + created_at).`,
 
 // issueListCmd lists issues
 var issueListCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [@view]",
 	Short: "List issues",
 	Long: `List issues for the current spec or across all specs.
 
-Supports various filters and output formats.`,
+Supports various filters and output formats. Pass @<name> to run a saved view
+(see 'sl issue view'); flags given alongside override the view.`,
 	Example: `  sl issue list
   sl issue list --status open
-  sl issue list --all
-  sl issue list --spec 010-my-feature`,
+  sl issue list --all --sort priority,-updated
+  sl issue list --spec 010-my-feature
+  sl issue list -q 'label:component:api "rate limit"'
+  sl issue list @my-api-work`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runIssueList,
 }
 
@@ -117,7 +146,8 @@ var issueShowCmd = &cobra.Command{
 	Short: "Show issue details",
 	Long:  `Display full details of an issue including all fields.`,
 	Example: `  sl issue show SL-a3f5d8
-  sl issue show SL-a3f5d8 --json`,
+  sl issue show SL-a3f5d8 --json
+  sl issue show SL-a3f5d8 --as-of 2026-01-15`,
 	Args: cobra.ExactArgs(1),
 	RunE: runIssueShow,
 }
@@ -147,18 +177,29 @@ before closing (use --force to bypass).`,
 	RunE: runIssueClose,
 }
 
-// issueMigrateCmd migrates from Beads
+// issueMigrateCmd migrates from Beads or between storage backends
 var issueMigrateCmd = &cobra.Command{
 	Use:   "migrate",
-	Short: "Migrate from Beads format",
+	Short: "Migrate from Beads format or between storage backends",
 	Long: `Migrate existing Beads issues to the new per-spec format.
 
 This command reads .beads/issues.jsonl and distributes issues to their
 respective spec directories. After successful migration, it removes the
-.beads directory and cleans up mise.toml.`,
+.beads directory and cleans up mise.toml.
+
+With --to, moves all issues between storage backends instead and records
+the choice as task_tracker.storage in specledger.yaml:
+  jsonl   one issues.jsonl per spec directory (default, git-friendly)
+  sqlite  a single embedded database at specledger/issues.db, faster for
+          cross-spec queries in large repositories
+
+Issues keep their IDs, timestamps and dependencies; the source files are
+removed once every spec has been copied.`,
 	Example: `  sl issue migrate
   sl issue migrate --dry-run
-  sl issue migrate --keep-beads`,
+  sl issue migrate --keep-beads
+  sl issue migrate --to sqlite
+  sl issue migrate --to jsonl`,
 	RunE: runIssueMigrate,
 }
 
@@ -182,10 +223,16 @@ var issueLinkCmd = &cobra.Command{
 Link types:
   blocks  - from blocks to (from must complete before to can start)
   related - from and to are related (soft link)
-  parent  - set to as the parent of from (hierarchy, not dependency)`,
+  parent  - set to as the parent of from (hierarchy, not dependency)
+
+An issue in a spec dependency can block local issues: name it as
+@<alias>/SL-xxxxxx, using the alias from 'sl deps add'. It is looked up in the
+dependency's cached checkout, and the local issue stays blocked until it is
+closed there; run 'sl deps update' to refresh the checkout.`,
 	Example: `  sl issue link SL-a3f5d8 blocks SL-b4e6f9
   sl issue link SL-a3f5d8 related SL-c7e1a2
-  sl issue link SL-child1 parent SL-epic1`,
+  sl issue link SL-child1 parent SL-epic1
+  sl issue link @platform/SL-1a2b3c blocks SL-b4e6f9`,
 	Args: cobra.ExactArgs(3),
 	RunE: runIssueLink,
 }
@@ -243,15 +290,18 @@ func init() {
 	issueCreateCmd.Flags().StringVar(&issueDesignFlag, "design", "", "Design notes/approach")
 	issueCreateCmd.Flags().StringVar(&issueNotesFlag, "notes", "", "Implementation notes")
 	issueCreateCmd.Flags().StringVar(&issueParentFlag, "parent", "", "Parent issue ID")
+	issueCreateCmd.Flags().StringVar(&issueEstimateFlag, "estimate", "", "Estimated effort in working days (e.g. 2, 1.5d, 4h, 1w)")
+	issueCreateCmd.Flags().StringVar(&issueTemplateFlag, "template", "", "Preset the issue from a template (see 'sl issue templates')")
+	issueCreateCmd.Flags().StringArrayVar(&issueFieldFlag, "field", []string{}, "Set a custom field declared by the template, name=value (can be repeated)")
 	if err := issueCreateCmd.MarkFlagRequired("title"); err != nil {
 		panic(fmt.Sprintf("failed to mark title flag as required: %v", err))
 	}
 
 	// List command flags
-	issueListCmd.Flags().StringVar(&issueStatusFlag, "status", "", "Filter by status (open, in_progress, closed)")
+	issueListCmd.Flags().StringVar(&issueStatusFlag, "status", "", "Filter by status (e.g. open, in_progress, closed)")
 	issueListCmd.Flags().StringVar(&issueTypeFlag, "type", "", "Filter by type")
 	issueListCmd.Flags().IntVarP(&issuePriorityFlag, "priority", "p", -1, "Filter by priority")
-	issueListCmd.Flags().StringVar(&issueLabelsFlag, "label", "", "Filter by label")
+	issueListCmd.Flags().StringVar(&issueLabelsFlag, "label", "", "Filter by labels (comma-separated, all must match)")
 	issueListCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Filter by spec context")
 	issueListCmd.Flags().BoolVar(&issueAllFlag, "all", false, "List across all specs")
 	issueListCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
@@ -259,10 +309,13 @@ func init() {
 	issueListCmd.Flags().BoolVar(&issueGraphFlag, "graph", false, "Show blocking dependency graph")
 	issueListCmd.Flags().BoolVar(&issueBlockedFlag, "blocked", false, "Show only blocked issues")
 	issueListCmd.Flags().BoolVar(&issueOrphanedFlag, "orphaned", false, "Show only non-epic issues without a parent")
+	issueListCmd.Flags().BoolVar(&issueIncludeArchivedFlag, "include-archived", false, "Also list archived issues (see 'sl issue archive')")
+	issueListCmd.Flags().StringArrayVar(&issueFieldFlag, "field", []string{}, "Filter by custom field value, name=value (can be repeated)")
 
 	// Show command flags
 	issueShowCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
 	issueShowCmd.Flags().BoolVar(&issueTreeFlag, "tree", false, "Show dependency tree")
+	issueShowCmd.Flags().StringVar(&issueAsOfFlag, "as-of", "", "Show the issue as it was at a time (RFC3339, YYYY-MM-DD, or relative like 2h, 3d)")
 
 	// Update command flags
 	issueUpdateCmd.Flags().StringVar(&issueTitleFlag, "title", "", "Update title")
@@ -279,6 +332,8 @@ func init() {
 	issueUpdateCmd.Flags().StringVar(&issueCheckDoDFlag, "check-dod", "", "Mark DoD item as checked (exact match)")
 	issueUpdateCmd.Flags().StringVar(&issueUncheckDoDFlag, "uncheck-dod", "", "Mark DoD item as unchecked (exact match)")
 	issueUpdateCmd.Flags().StringVar(&issueParentFlag, "parent", "", "Set parent issue ID (empty string to clear)")
+	issueUpdateCmd.Flags().StringVar(&issueEstimateFlag, "estimate", "", "Update estimated effort (e.g. 2, 1.5d, 4h, 1w; 0 to clear)")
+	issueUpdateCmd.Flags().StringArrayVar(&issueFieldFlag, "field", []string{}, "Set a custom field, name=value; an empty value removes it (can be repeated)")
 
 	// Close command flags
 	issueCloseCmd.Flags().StringVar(&issueReasonFlag, "reason", "", "Close reason")
@@ -287,6 +342,7 @@ func init() {
 	// Migrate command flags
 	issueMigrateCmd.Flags().BoolVar(&issueDryRunFlag, "dry-run", false, "Show what would be migrated")
 	issueMigrateCmd.Flags().BoolVar(&issueKeepBeadsFlag, "keep-beads", false, "Keep .beads folder after migration")
+	issueMigrateCmd.Flags().StringVar(&issueMigrateToFlag, "to", "", "Move issues to another storage backend (jsonl, sqlite)")
 
 	// Ready command flags
 	issueReadyCmd.Flags().BoolVar(&issueAllFlag, "all", false, "List ready issues across all specs")
@@ -306,18 +362,53 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create issue
-	issueType := issues.IssueType(issueTypeFlag)
+	issueType, priority := issues.IssueType(issueTypeFlag), issuePriorityFlag
+	if issueTemplateFlag != "" {
+		// Start from the flag defaults; the template presets come next
+		if !cmd.Flags().Changed("type") {
+			issueType = issues.IssueType(cmd.Flags().Lookup("type").DefValue)
+		}
+		if !cmd.Flags().Changed("priority") {
+			priority, _ = strconv.Atoi(cmd.Flags().Lookup("priority").DefValue)
+		}
+	}
 	if !issues.IsValidIssueType(issueType) {
-		return fmt.Errorf("invalid issue type: %s (must be epic, feature, task, or bug)", issueTypeFlag)
+		return fmt.Errorf("invalid issue type: %s (must be epic, feature, task, or bug)", issueType)
 	}
 
-	issue := issues.NewIssue(issueTitleFlag, issueDescFlag, specContext, issueType, issuePriorityFlag)
+	issue := issues.NewIssue(issueTitleFlag, issueDescFlag, specContext, issueType, priority)
+
+	// Template presets apply first so explicit flags override them
+	if issueTemplateFlag != "" {
+		template, err := lookupTemplate(issueTemplateFlag)
+		if err != nil {
+			return err
+		}
+		template.Apply(issue)
+		if cmd.Flags().Changed("type") {
+			issue.IssueType = issueType
+		}
+		if cmd.Flags().Changed("priority") {
+			issue.Priority = priority
+		}
+	}
+	fields, err := parseFieldFlags(issueFieldFlag)
+	if err != nil {
+		return err
+	}
+	for name, value := range fields {
+		if issue.Fields == nil {
+			issue.Fields = make(map[string]string)
+		}
+		issue.Fields[name] = value
+	}
 
 	// Add labels
 	if issueLabelsFlag != "" {
-		issue.Labels = strings.Split(issueLabelsFlag, ",")
-		for i, l := range issue.Labels {
-			issue.Labels[i] = strings.TrimSpace(l)
+		for _, l := range strings.Split(issueLabelsFlag, ",") {
+			if l = strings.TrimSpace(l); !slices.Contains(issue.Labels, l) {
+				issue.Labels = append(issue.Labels, l)
+			}
 		}
 	}
 
@@ -333,6 +424,9 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 				Checked: false,
 			}
 		}
+		if issue.DefinitionOfDone != nil {
+			items = append(items, issue.DefinitionOfDone.Items...)
+		}
 		issue.DefinitionOfDone = &issues.DefinitionOfDone{Items: items}
 	}
 	if issueDesignFlag != "" {
@@ -344,6 +438,13 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 	if issueParentFlag != "" {
 		issue.ParentID = &issueParentFlag
 	}
+	if issueEstimateFlag != "" {
+		estimate, err := issues.ParseEstimate(issueEstimateFlag)
+		if err != nil {
+			return err
+		}
+		issue.Estimate = estimate
+	}
 
 	// Create store and save
 	store, err := issues.NewStore(issues.StoreOptions{
@@ -375,6 +476,12 @@ func runIssueCreate(cmd *cobra.Command, args []string) error {
 }
 
 func runIssueList(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		if err := useIssueView(cmd, args[0]); err != nil {
+			return err
+		}
+	}
+
 	// Determine spec context
 	specContext := issueSpecFlag
 	if specContext == "" && !issueAllFlag {
@@ -399,16 +506,23 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 	if issuePriorityFlag >= 0 {
 		filter.Priority = &issuePriorityFlag
 	}
-	if issueLabelsFlag != "" {
-		filter.Labels = []string{issueLabelsFlag}
+	for _, l := range strings.Split(issueLabelsFlag, ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			filter.Labels = append(filter.Labels, l)
+		}
 	}
 	filter.SpecContext = specContext
 	filter.All = issueAllFlag
 	filter.Blocked = issueBlockedFlag
 	filter.Orphaned = issueOrphanedFlag
+	filter.IncludeArchived = issueIncludeArchivedFlag
+	fields, err := parseFieldFlags(issueFieldFlag)
+	if err != nil {
+		return err
+	}
+	filter.Fields = fields
 
 	var issueList []issues.Issue
-	var err error
 
 	// Get issues - use cross-spec listing if --all flag is set
 	artifactPath := getArtifactPath()
@@ -432,6 +546,21 @@ func runIssueList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if issueQueryFlag != "" {
+		query, err := issues.ParseQuery(issueQueryFlag, issues.WorkflowFunc(getArtifactPath()))
+		if err != nil {
+			return err
+		}
+		issueList = query.Filter(issueList)
+	}
+	if issueSortFlag != "" {
+		sortKeys, err := issues.ParseSortKeys(issueSortFlag)
+		if err != nil {
+			return err
+		}
+		issues.SortIssues(issueList, sortKeys)
+	}
+
 	if issueJSONFlag {
 		data, _ := json.MarshalIndent(issueList, "", "  ")
 		fmt.Println(string(data))
@@ -497,7 +626,7 @@ func renderSingleSpecTree(issueList []issues.Issue, store *issues.Store, specCon
 	}
 
 	// Create renderer
-	renderer := issues.NewTreeRenderer(issues.DefaultTreeRenderOptions())
+	renderer := issues.NewTreeRenderer(treeRenderOptions())
 
 	// Render hierarchy tree
 	output := renderer.RenderHierarchyForest(specContext, trees, len(issueList))
@@ -561,7 +690,7 @@ func renderSingleSpecGraph(issueList []issues.Issue, store *issues.Store, specCo
 		return nil
 	}
 
-	renderer := issues.NewTreeRenderer(issues.DefaultTreeRenderOptions())
+	renderer := issues.NewTreeRenderer(treeRenderOptions())
 
 	// Summary line
 	parts := []string{}
@@ -723,7 +852,7 @@ func renderCrossSpecGraph(issueList []issues.Issue, artifactPath string) error {
 	fmt.Println("All Specs (dependency graph)")
 	fmt.Println()
 
-	renderer := issues.NewTreeRenderer(issues.DefaultTreeRenderOptions())
+	renderer := issues.NewTreeRenderer(treeRenderOptions())
 
 	for _, spec := range specNames {
 		issuesInSpec := specIssues[spec]
@@ -921,7 +1050,7 @@ func renderCrossSpecTree(issueList []issues.Issue, artifactPath string) error {
 	}
 
 	// Create renderer (no spec context since we're grouping by spec)
-	opts := issues.DefaultTreeRenderOptions()
+	opts := treeRenderOptions()
 	opts.ShowSpec = false
 	renderer := issues.NewTreeRenderer(opts)
 
@@ -1009,17 +1138,6 @@ func renderHierarchyTreeWithPrefix(renderer *issues.TreeRenderer, tree *issues.D
 	return sb.String()
 }
 
-// truncateTitle truncates a title to maxLen characters
-func truncateTitle(title string, maxLen int) string {
-	if len(title) <= maxLen {
-		return title
-	}
-	if maxLen <= 3 {
-		return title[:maxLen]
-	}
-	return title[:maxLen-3] + "..."
-}
-
 func runIssueReady(cmd *cobra.Command, args []string) error {
 	// Determine spec context
 	specContext := issueSpecFlag
@@ -1094,9 +1212,9 @@ func runIssueReady(cmd *cobra.Command, args []string) error {
 			fmt.Println()
 			fmt.Println("Blocked issues:")
 			for _, bi := range blockedIssues {
-				fmt.Printf("  %s \"%s\" is blocked by:\n", bi.Issue.ID, truncateTitle(bi.Issue.Title, 40))
+				fmt.Printf("  %s \"%s\" is blocked by:\n", bi.Issue.ID, issues.Truncate(bi.Issue.Title, 40))
 				for _, blocker := range bi.BlockedBy {
-					fmt.Printf("    - %s \"%s\" (%s)\n", blocker.ID, truncateTitle(blocker.Title, 30), blocker.Status)
+					fmt.Printf("    - %s \"%s\" (%s)\n", blocker.ID, issues.Truncate(blocker.Title, 30), blocker.Status)
 				}
 			}
 		}
@@ -1109,13 +1227,13 @@ func runIssueReady(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tPRIORITY\tSPEC")
 		for _, ri := range readyIssues {
 			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n",
-				ri.Issue.ID, truncateTitle(ri.Issue.Title, 40), ri.Issue.Status, ri.Issue.Priority, ri.Issue.SpecContext)
+				ri.Issue.ID, issues.Truncate(ri.Issue.Title, 40), ri.Issue.Status, ri.Issue.Priority, ri.Issue.SpecContext)
 		}
 	} else {
 		fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tPRIORITY")
 		for _, ri := range readyIssues {
 			fmt.Fprintf(w, "%s\t%s\t%s\t%d\n",
-				ri.Issue.ID, truncateTitle(ri.Issue.Title, 40), ri.Issue.Status, ri.Issue.Priority)
+				ri.Issue.ID, issues.Truncate(ri.Issue.Title, 40), ri.Issue.Status, ri.Issue.Priority)
 		}
 	}
 	w.Flush()
@@ -1147,9 +1265,37 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create store: %w", err)
 	}
 
-	issue, err := store.Get(issueID)
-	if err != nil {
-		return fmt.Errorf("failed to get issue: %w", err)
+	var issue *issues.Issue
+	if issueAsOfFlag != "" {
+		asOf, err := parseAsOf(issueAsOfFlag)
+		if err != nil {
+			return err
+		}
+		issue, err = store.GetAsOf(issueID, asOf)
+		if err != nil {
+			return fmt.Errorf("failed to replay issue history: %w", err)
+		}
+		if !issueJSONFlag {
+			fmt.Printf("%s\n\n", ui.Gray(fmt.Sprintf("As of %s (replayed from %s)", asOf.Format("2006-01-02 15:04:05"), issues.EventsFileName)))
+		}
+	} else {
+		issue, err = store.Get(issueID)
+		if errors.Is(err, issues.ErrIssueNotFound) {
+			// Moved issues resolve through the redirect left in their old spec
+			if redirect, rerr := issues.ResolveRedirect(getArtifactPath(), issueID); rerr == nil {
+				store, err = issues.NewStore(issues.StoreOptions{BasePath: getArtifactPath(), SpecContext: redirect.SpecContext})
+				if err != nil {
+					return fmt.Errorf("failed to create store: %w", err)
+				}
+				issue, err = store.Get(redirect.MovedTo)
+				if err == nil && !issueJSONFlag {
+					fmt.Printf("%s\n\n", ui.Gray(fmt.Sprintf("%s was moved to %s in %s", issueID, redirect.MovedTo, redirect.SpecContext)))
+				}
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get issue: %w", err)
+		}
 	}
 
 	if issueJSONFlag {
@@ -1181,8 +1327,27 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 	if issue.ParentID != nil && *issue.ParentID != "" {
 		fmt.Printf("  Parent: %s\n", *issue.ParentID)
 	}
+	if issue.Assignee != "" {
+		fmt.Printf("  Assignee: %s\n", issue.Assignee)
+	}
+	if issue.Estimate > 0 {
+		fmt.Printf("  Estimate: %s\n", issues.FormatEstimate(issue.Estimate))
+	}
+	if issue.Template != "" {
+		fmt.Printf("  Template: %s\n", issue.Template)
+	}
+	if issue.Lease != nil {
+		expires := issue.Lease.ExpiresAt.Local().Format("2006-01-02 15:04:05")
+		if issue.IsClaimed(time.Now()) {
+			fmt.Printf("  Lease: held by %s until %s\n", issue.Lease.Holder, expires)
+		} else {
+			fmt.Printf("  Lease: %s\n", ui.Yellow(fmt.Sprintf("expired %s (was %s)", expires, issue.Lease.Holder)))
+		}
+	}
 	fmt.Println()
 
+	printCustomFields(issue, store.Template(issue.Template))
+
 	if issue.Description != "" {
 		fmt.Println("Description:")
 		fmt.Printf("  %s\n", strings.ReplaceAll(issue.Description, "\n", "\n  "))
@@ -1231,6 +1396,19 @@ func runIssueShow(cmd *cobra.Command, args []string) error {
 		fmt.Println()
 	}
 
+	if len(issue.Commits) > 0 {
+		fmt.Println("Commits:")
+		for _, hash := range issue.Commits {
+			// The commit may not exist locally (e.g. unfetched branch)
+			if commit, err := issues.LookupCommit(".", hash); err == nil {
+				fmt.Printf("  %s %s\n", commit.ShortHash(), commit.Subject())
+			} else {
+				fmt.Printf("  %s\n", cligit.ShortHash(hash))
+			}
+		}
+		fmt.Println()
+	}
+
 	// Get and display children
 	children, err := store.GetChildren(issue.ID)
 	if err == nil && len(children) > 0 {
@@ -1258,7 +1436,7 @@ func renderIssueShowTree(store *issues.Store, issue *issues.Issue) error {
 		return fmt.Errorf("failed to get dependency tree: %w", err)
 	}
 
-	renderer := issues.NewTreeRenderer(issues.DefaultTreeRenderOptions())
+	renderer := issues.NewTreeRenderer(treeRenderOptions())
 
 	// Show parent hierarchy (if any)
 	if issue.ParentID != nil && *issue.ParentID != "" {
@@ -1387,8 +1565,8 @@ func runIssueUpdate(cmd *cobra.Command, args []string) error {
 	}
 	if cmd.Flags().Changed("status") {
 		status := issues.IssueStatus(issueStatusFlag)
-		if !issues.IsValidStatus(status) {
-			return fmt.Errorf("invalid status: %s", issueStatusFlag)
+		if !store.Workflow().IsValidStatus(status) {
+			return fmt.Errorf("invalid status: %s (statuses: %s)", issueStatusFlag, joinIssueStatuses(store.Workflow().Names()))
 		}
 		update.Status = &status
 	}
@@ -1407,6 +1585,20 @@ func runIssueUpdate(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("acceptance-criteria") {
 		update.AcceptanceCriteria = &issueAcceptFlag
 	}
+	if cmd.Flags().Changed("estimate") {
+		estimate, err := issues.ParseEstimate(issueEstimateFlag)
+		if err != nil {
+			return err
+		}
+		update.Estimate = &estimate
+	}
+	if len(issueFieldFlag) > 0 {
+		fields, err := parseFieldFlags(issueFieldFlag)
+		if err != nil {
+			return err
+		}
+		update.Fields = fields
+	}
 	if issueAddLabelFlag != "" {
 		update.AddLabels = []string{issueAddLabelFlag}
 	}
@@ -1481,20 +1673,25 @@ func runIssueClose(cmd *cobra.Command, args []string) error {
 	}
 
 	// Check definition of done
-	if issue.DefinitionOfDone != nil && !issue.DefinitionOfDone.IsComplete() && !issueForceFlag {
+	workflow := store.Workflow()
+	if issue.DefinitionOfDone != nil && !issue.DefinitionOfDone.IsComplete() && (!issueForceFlag || workflow.RequireCompleteDoD) {
 		unchecked := issue.DefinitionOfDone.GetUncheckedItems()
 		fmt.Println("Definition of done not met:")
 		for _, item := range unchecked {
 			fmt.Printf("  [ ] %s\n", item)
 		}
 		fmt.Println()
+		if workflow.RequireCompleteDoD {
+			return fmt.Errorf("the project workflow requires a complete definition of done to close")
+		}
 		return fmt.Errorf("use --force to close anyway")
 	}
 
 	// Close the issue
-	status := issues.StatusClosed
+	status := workflow.StatusFor(issues.CategoryDone)
 	update := issues.IssueUpdate{
 		Status: &status,
+		Reason: issueReasonFlag,
 	}
 
 	_, err = store.Update(issueID, update)
@@ -1510,6 +1707,10 @@ func runIssueClose(cmd *cobra.Command, args []string) error {
 }
 
 func runIssueMigrate(cmd *cobra.Command, args []string) error {
+	if issueMigrateToFlag != "" {
+		return runIssueMigrateStorage(issueMigrateToFlag)
+	}
+
 	ui.PrintSection("Migrating Beads Issues")
 
 	// Create migrator
@@ -1597,11 +1798,11 @@ func runIssueLink(cmd *cobra.Command, args []string) error {
 	linkTypeStr := args[1]
 	toID := args[2]
 
-	// Validate IDs
-	if _, err := issues.ParseIssueID(fromID); err != nil {
+	// Validate IDs; either may name an issue in a dependency (@alias/SL-xxxxxx)
+	if _, err := issues.ParseIssueRef(fromID); err != nil {
 		return fmt.Errorf("invalid from issue ID: %w", err)
 	}
-	if _, err := issues.ParseIssueID(toID); err != nil {
+	if _, err := issues.ParseIssueRef(toID); err != nil {
 		return fmt.Errorf("invalid to issue ID: %w", err)
 	}
 
@@ -1653,11 +1854,11 @@ func runIssueUnlink(cmd *cobra.Command, args []string) error {
 	linkTypeStr := args[1]
 	toID := args[2]
 
-	// Validate IDs
-	if _, err := issues.ParseIssueID(fromID); err != nil {
+	// Validate IDs; either may name an issue in a dependency (@alias/SL-xxxxxx)
+	if _, err := issues.ParseIssueRef(fromID); err != nil {
 		return fmt.Errorf("invalid from issue ID: %w", err)
 	}
-	if _, err := issues.ParseIssueID(toID); err != nil {
+	if _, err := issues.ParseIssueRef(toID); err != nil {
 		return fmt.Errorf("invalid to issue ID: %w", err)
 	}
 
@@ -1698,8 +1899,6 @@ func runIssueRepair(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%w", err)
 	}
 
-	ui.PrintSection("Repairing Issues File")
-
 	store, err := issues.NewStore(issues.StoreOptions{
 		BasePath:    getArtifactPath(),
 		SpecContext: specContext,
@@ -1707,6 +1906,11 @@ func runIssueRepair(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to create store: %w", err)
 	}
+	if store.Storage() != issues.StorageJSONL {
+		return fmt.Errorf("repair only applies to JSONL storage; issues are stored in %s", store.Path())
+	}
+
+	ui.PrintSection("Repairing Issues File")
 
 	// Repair the issues file
 	result, err := issues.RepairIssuesFile(store.Path())
@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueArchiveOlderThanFlag string
+	issueIncludeArchivedFlag  bool
+)
+
+// issueArchiveCmd moves old closed issues into the spec's archive
+var issueArchiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Archive closed issues to keep issue files small",
+	Long: `Move issues closed longer ago than --older-than out of issues.jsonl into a
+compressed issues.archive.jsonl.gz next to it.
+
+Archived issues are no longer read by list, ready, board and the other
+everyday commands, but 'sl issue show' still finds them, 'sl issue list
+--include-archived' lists them and 'sl issue stats' counts them. They can no
+longer be changed.
+
+Closed issues that an open issue, in any spec, is still blocked by or a child
+of are kept and reported. Issues closed before closed_at was recorded are
+judged by their last update.`,
+	Example: `  sl issue archive
+  sl issue archive --older-than 90d --dry-run
+  sl issue archive --all --json`,
+	Args: cobra.NoArgs,
+	RunE: runIssueArchive,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueArchiveCmd)
+
+	issueArchiveCmd.Flags().StringVar(&issueArchiveOlderThanFlag, "older-than", "30d", "Archive issues closed longer ago than this (e.g. 30d, 12w)")
+	issueArchiveCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueArchiveCmd.Flags().BoolVar(&issueAllFlag, "all", false, "Archive across all specs")
+	issueArchiveCmd.Flags().BoolVar(&issueDryRunFlag, "dry-run", false, "Show what would be archived")
+	issueArchiveCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+}
+
+func runIssueArchive(cmd *cobra.Command, args []string) error {
+	age, err := issues.ParseRelativeDuration(issueArchiveOlderThanFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --older-than: %w", err)
+	}
+	cutoff := time.Now().Add(-age)
+
+	artifactPath := getArtifactPath()
+	var specs []string
+	if issueAllFlag {
+		specs, err = issues.ListSpecs(artifactPath)
+		if err != nil {
+			return fmt.Errorf("failed to list specs: %w", err)
+		}
+	} else {
+		specContext := issueSpecFlag
+		if specContext == "" {
+			detector := issues.NewContextDetector(".")
+			specContext, err = detector.DetectSpecContext()
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+		}
+		specs = []string{specContext}
+	}
+
+	var results []*issues.ArchiveResult
+	for _, spec := range specs {
+		store, err := issues.NewStore(issues.StoreOptions{BasePath: artifactPath, SpecContext: spec})
+		if err != nil {
+			return fmt.Errorf("failed to create store: %w", err)
+		}
+		result, err := store.Archive(cutoff, issueDryRunFlag)
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", spec, err)
+		}
+		results = append(results, result)
+	}
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	verb := "Archived"
+	if issueDryRunFlag {
+		verb = "Would archive"
+	}
+	archived, skipped := 0, 0
+	for _, result := range results {
+		if len(result.Archived) == 0 && len(result.Skipped) == 0 {
+			continue
+		}
+		ui.PrintSection(result.SpecContext)
+		for _, id := range result.Archived {
+			fmt.Printf("  %s %s\n", ui.Checkmark(), id)
+		}
+		for _, skip := range result.Skipped {
+			fmt.Printf("  %s %s kept: %s\n", ui.WarningIcon(), skip.ID, skip.Reason)
+		}
+		archived += len(result.Archived)
+		skipped += len(result.Skipped)
+	}
+	if archived+skipped > 0 {
+		fmt.Println()
+	}
+	fmt.Printf("%s %d issue(s) closed before %s", verb, archived, cutoff.Local().Format("2006-01-02"))
+	if skipped > 0 {
+		fmt.Printf(", kept %d still referenced by open issues", skipped)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/tui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+// issueBoardCmd opens the interactive kanban board
+var issueBoardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Open an interactive kanban board of issues",
+	Long: `Open a full-screen board with a column per workflow status (open,
+in_progress and closed unless specledger.yaml declares a workflow) for the
+current spec, or for all specs with --all.
+
+Keys:
+  ←/→ or h/l      move between columns
+  ↑/↓ or j/k      select an issue
+  H/L or </>      move the selected issue to the previous/next status
+  enter           show details and the Definition of Done
+  space or x      toggle the highlighted DoD item (in the detail pane)
+  / or f          filter with a search query (see 'sl issue search --help')
+  r               reload
+  q or ctrl+c     quit
+
+Changes are written through the issue store, so validation, locking and
+the workflow's transition rules apply as for 'sl issue update'. The board
+reloads when the issue files change on disk, e.g. when an agent closes an
+issue.`,
+	Example: `  sl issue board
+  sl issue board --all --assignee alice
+  sl issue board --label component:api`,
+	RunE: runIssueBoard,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueBoardCmd)
+
+	issueBoardCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueBoardCmd.Flags().BoolVar(&issueAllFlag, "all", false, "Show issues across all specs")
+	issueBoardCmd.Flags().StringVar(&issueLabelsFlag, "label", "", "Only show issues with this label")
+	issueBoardCmd.Flags().StringVar(&issueAssigneeFlag, "assignee", "", "Only show issues assigned to this user (me, none or a name)")
+}
+
+func runIssueBoard(cmd *cobra.Command, args []string) error {
+	source := &issueBoardSource{basePath: getArtifactPath()}
+	title := "SpecLedger Board: all specs"
+	if !issueAllFlag {
+		source.specContext = issueSpecFlag
+		if source.specContext == "" {
+			specContext, err := issues.NewContextDetector(".").DetectSpecContext()
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+			source.specContext = specContext
+		}
+		title = "SpecLedger Board: " + source.specContext
+	}
+
+	var terms []string
+	if issueLabelsFlag != "" {
+		terms = append(terms, "label:"+quoteQueryValue(issueLabelsFlag))
+	}
+	if issueAssigneeFlag != "" {
+		terms = append(terms, "assignee:"+quoteQueryValue(issueAssigneeFlag))
+	}
+
+	return tui.RunBoard(source, issues.WorkflowFunc(source.basePath), title, strings.Join(terms, " "))
+}
+
+// quoteQueryValue quotes a search query value that contains spaces
+func quoteQueryValue(value string) string {
+	if strings.ContainsAny(value, " \t\"") {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+// issueBoardSource feeds the board from one spec, or all specs when
+// specContext is empty
+type issueBoardSource struct {
+	basePath    string
+	specContext string
+}
+
+func (s *issueBoardSource) Load() ([]issues.Issue, error) {
+	if s.specContext == "" {
+		return issues.ListAllSpecs(s.basePath, issues.ListFilter{})
+	}
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: s.basePath, SpecContext: s.specContext})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return store.List(issues.ListFilter{})
+}
+
+func (s *issueBoardSource) Update(issue issues.Issue, update issues.IssueUpdate) error {
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: s.basePath, SpecContext: issue.SpecContext})
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	_, err = store.Update(issue.ID, update)
+	return err
+}
+
+func (s *issueBoardSource) Modified() (time.Time, error) {
+	return issues.LastModified(s.basePath, s.specContext)
+}
@@ -0,0 +1,234 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+// Lock contention handling for concurrent claims
+const (
+	claimLockRetries = 40
+	claimLockBackoff = 50 * time.Millisecond
+)
+
+var (
+	issueClaimNextFlag   bool
+	issueClaimLeaseFlag  time.Duration
+	issueClaimHolderFlag string
+)
+
+// issueClaimCmd claims an issue for the current agent or user
+var issueClaimCmd = &cobra.Command{
+	Use:   "claim [issue-id]",
+	Short: "Claim an issue with a lease",
+	Long: `Atomically claim an issue: assign it to you, set it to in_progress and
+record a lease. While the lease is active the issue is hidden from
+'sl issue ready' and cannot be claimed by anyone else.
+
+Renew the lease with 'sl issue heartbeat' and give it up with 'sl issue release'.
+Leases that are not renewed expire and the issue returns to ready.
+
+The holder defaults to $SL_ACTOR, then git user.name. Agents running in
+parallel should set SL_ACTOR (or --as) to a unique name.`,
+	Example: `  sl issue claim --next
+  sl issue claim SL-a3f5d8 --lease 1h
+  SL_ACTOR=agent-2 sl issue claim --next --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIssueClaim,
+}
+
+// issueHeartbeatCmd renews a lease
+var issueHeartbeatCmd = &cobra.Command{
+	Use:   "heartbeat <issue-id>",
+	Short: "Renew the lease on a claimed issue",
+	Example: `  sl issue heartbeat SL-a3f5d8
+  sl issue heartbeat SL-a3f5d8 --lease 1h`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueHeartbeat,
+}
+
+// issueReleaseCmd gives up a claim
+var issueReleaseCmd = &cobra.Command{
+	Use:   "release [issue-id]",
+	Short: "Release a claimed issue back to ready",
+	Long: `Release a claim, returning the issue to open and clearing the assignee.
+
+Without an issue ID, releases every expired lease in the spec.`,
+	Example: `  sl issue release SL-a3f5d8
+  sl issue release SL-a3f5d8 --force
+  sl issue release`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIssueRelease,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueClaimCmd, issueHeartbeatCmd, issueReleaseCmd)
+
+	issueClaimCmd.Flags().BoolVar(&issueClaimNextFlag, "next", false, "Claim the highest-priority ready issue")
+	issueClaimCmd.Flags().DurationVar(&issueClaimLeaseFlag, "lease", issues.DefaultLeaseDuration, "Lease duration")
+	issueClaimCmd.Flags().StringVar(&issueClaimHolderFlag, "as", "", "Claim holder (default: $SL_ACTOR or git user.name)")
+	issueClaimCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueClaimCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+
+	issueHeartbeatCmd.Flags().DurationVar(&issueClaimLeaseFlag, "lease", issues.DefaultLeaseDuration, "Lease duration from now")
+	issueHeartbeatCmd.Flags().StringVar(&issueClaimHolderFlag, "as", "", "Claim holder (default: $SL_ACTOR or git user.name)")
+	issueHeartbeatCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+
+	issueReleaseCmd.Flags().StringVar(&issueClaimHolderFlag, "as", "", "Claim holder (default: $SL_ACTOR or git user.name)")
+	issueReleaseCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueReleaseCmd.Flags().BoolVar(&issueForceFlag, "force", false, "Release even if another holder owns the lease")
+}
+
+func runIssueClaim(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 && !issueClaimNextFlag {
+		return fmt.Errorf("specify an issue ID or use --next")
+	}
+	if len(args) > 0 && issueClaimNextFlag {
+		return fmt.Errorf("cannot combine an issue ID with --next")
+	}
+
+	var store *issues.Store
+	var issueID string
+	var err error
+	if len(args) > 0 {
+		issueID = args[0]
+		if _, err := issues.ParseIssueID(issueID); err != nil {
+			return fmt.Errorf("invalid issue ID: %w", err)
+		}
+		store, err = storeForIssue(issueID)
+	} else {
+		store, err = specStore()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Other agents may hold the lock briefly while claiming; retry for a moment
+	var issue *issues.Issue
+	for attempt := 0; ; attempt++ {
+		issue, err = store.Claim(issueID, claimHolder(), issueClaimLeaseFlag)
+		if !errors.Is(err, issues.ErrStoreLocked) || attempt >= claimLockRetries {
+			break
+		}
+		time.Sleep(claimLockBackoff)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to claim issue: %w", err)
+	}
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(issue, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s Claimed %s \"%s\"\n", ui.Checkmark(), issue.ID, issue.Title)
+	fmt.Printf("  Holder: %s\n", issue.Lease.Holder)
+	fmt.Printf("  Lease expires: %s\n", issue.Lease.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+	fmt.Println(ui.Gray(fmt.Sprintf("  Renew with 'sl issue heartbeat %s', release with 'sl issue release %s'", issue.ID, issue.ID)))
+	return nil
+}
+
+func runIssueHeartbeat(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+	if _, err := issues.ParseIssueID(issueID); err != nil {
+		return fmt.Errorf("invalid issue ID: %w", err)
+	}
+
+	store, err := storeForIssue(issueID)
+	if err != nil {
+		return err
+	}
+
+	issue, err := store.Heartbeat(issueID, claimHolder(), issueClaimLeaseFlag)
+	if err != nil {
+		return fmt.Errorf("failed to renew lease: %w", err)
+	}
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(issue, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s Lease on %s renewed until %s\n", ui.Checkmark(), issue.ID,
+		issue.Lease.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+func runIssueRelease(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		store, err := specStore()
+		if err != nil {
+			return err
+		}
+		released, err := store.ReleaseExpiredLeases()
+		if err != nil {
+			return fmt.Errorf("failed to release expired leases: %w", err)
+		}
+		if len(released) == 0 {
+			fmt.Println("No expired leases")
+			return nil
+		}
+		for _, id := range released {
+			fmt.Printf("%s Released expired lease on %s\n", ui.Checkmark(), id)
+		}
+		return nil
+	}
+
+	issueID := args[0]
+	if _, err := issues.ParseIssueID(issueID); err != nil {
+		return fmt.Errorf("invalid issue ID: %w", err)
+	}
+
+	store, err := storeForIssue(issueID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := store.Release(issueID, claimHolder(), issueForceFlag); err != nil {
+		if errors.Is(err, issues.ErrNotLeaseHolder) {
+			return fmt.Errorf("failed to release issue: %w (use --force to override)", err)
+		}
+		return fmt.Errorf("failed to release issue: %w", err)
+	}
+
+	fmt.Printf("%s Released %s\n", ui.Checkmark(), issueID)
+	return nil
+}
+
+// claimHolder returns the identity used for claims
+func claimHolder() string {
+	if issueClaimHolderFlag != "" {
+		return issueClaimHolderFlag
+	}
+	return issues.ActorFunc()
+}
+
+// specStore opens the store for --spec or the spec detected from the current branch.
+func specStore() (*issues.Store, error) {
+	specContext := issueSpecFlag
+	if specContext == "" {
+		detector := issues.NewContextDetector(".")
+		var err error
+		specContext, err = detector.DetectSpecContext()
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	store, err := issues.NewStore(issues.StoreOptions{
+		BasePath:    getArtifactPath(),
+		SpecContext: specContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return store, nil
+}
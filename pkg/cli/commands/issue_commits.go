@@ -0,0 +1,231 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/playbooks"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueSyncRevFlag     string
+	issueSyncLimitFlag   int
+	issueSyncMessageFlag string
+	issueSyncQuietFlag   bool
+)
+
+// issueSyncCommitsCmd links issues to the commits that reference them
+var issueSyncCommitsCmd = &cobra.Command{
+	Use:   "sync-commits",
+	Short: "Link issues to commits and close issues fixed by commits",
+	Long: `Scan git history for issue IDs (SL-xxxxxx) in commit messages.
+
+Each referenced issue records the commit hash in its commits field, shown by
+'sl issue show'. An ID after a closing keyword closes the issue with the
+commit as the reason:
+
+  close, closes, closed, fix, fixes, fixed, resolve, resolves, resolved
+
+e.g. "Handle empty input, fixes SL-ab12cd". Commits already recorded on an
+issue are skipped, so syncing again does not reopen or re-close anything.
+Closing still follows the project workflow; a commit that may not close an
+issue (e.g. its Definition of Done is incomplete) only links it.
+
+'sl init' installs git hooks that run this automatically:
+
+  commit-msg   warns about IDs that match no issue (--message-file)
+  post-commit  links the new commit (--rev HEAD --limit 1)
+
+Issue files changed by the post-commit hook are picked up by the next commit.
+Run without flags to link the whole history of the current branch.`,
+	Example: `  sl issue sync-commits
+  sl issue sync-commits --dry-run
+  sl issue sync-commits --rev main --limit 100 --json`,
+	Args: cobra.NoArgs,
+	RunE: runIssueSyncCommits,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueSyncCommitsCmd)
+
+	issueSyncCommitsCmd.Flags().StringVar(&issueSyncRevFlag, "rev", "HEAD", "Revision to scan history from")
+	issueSyncCommitsCmd.Flags().IntVar(&issueSyncLimitFlag, "limit", 0, "Maximum number of commits to scan (0 = all)")
+	issueSyncCommitsCmd.Flags().BoolVar(&issueDryRunFlag, "dry-run", false, "Show what would be linked and closed")
+	issueSyncCommitsCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+	issueSyncCommitsCmd.Flags().StringVar(&issueSyncMessageFlag, "message-file", "", "Check a commit message file instead of scanning history (commit-msg hook)")
+	issueSyncCommitsCmd.Flags().BoolVar(&issueSyncQuietFlag, "quiet", false, "Only report links and closures (post-commit hook)")
+}
+
+func runIssueSyncCommits(cmd *cobra.Command, args []string) error {
+	basePath := getArtifactPath()
+	if issueSyncMessageFlag != "" {
+		return checkCommitMessage(basePath, issueSyncMessageFlag)
+	}
+
+	commits, err := issues.ReadCommits(".", issueSyncRevFlag, issueSyncLimitFlag)
+	if err != nil {
+		return fmt.Errorf("failed to read commits: %w", err)
+	}
+	result, err := issues.SyncCommits(basePath, commits, issueDryRunFlag)
+	if err != nil {
+		return err
+	}
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if issueSyncQuietFlag {
+		// Hook output: one line per change, nothing otherwise
+		for _, link := range result.Links {
+			action := "linked to"
+			if link.Closed {
+				action = "closed"
+			}
+			fmt.Fprintf(os.Stderr, "sl: %s %s %s\n", cligit.ShortHash(link.Commit), action, link.IssueID)
+			if link.CloseError != "" {
+				fmt.Fprintf(os.Stderr, "sl: %s left open: %s\n", link.IssueID, link.CloseError)
+			}
+		}
+		return nil
+	}
+
+	title := "Syncing Commits"
+	if issueDryRunFlag {
+		title += " (dry run)"
+	}
+	ui.PrintSection(title)
+	for _, link := range result.Links {
+		action := "linked"
+		if link.Closed {
+			action = ui.Green("closed")
+		} else if link.CloseError != "" {
+			action = ui.Yellow("linked, left open: " + link.CloseError)
+		}
+		fmt.Printf("  %s  %s  %s  %s\n", link.IssueID, cligit.ShortHash(link.Commit), issues.Truncate(link.Subject, 50), action)
+	}
+	if len(result.Links) > 0 {
+		fmt.Println()
+	}
+
+	closed := 0
+	for _, link := range result.Links {
+		if link.Closed {
+			closed++
+		}
+	}
+	verb := "Linked"
+	if issueDryRunFlag {
+		verb = "Would link"
+	}
+	fmt.Printf("%s %s %d reference(s) from %d commit(s), %d closing\n", ui.Checkmark(), verb, len(result.Links), result.Scanned, closed)
+	if len(result.Unknown) > 0 {
+		fmt.Printf("%s No issue found for: %s\n", ui.WarningIcon(), strings.Join(result.Unknown, ", "))
+	}
+	if len(result.Archived) > 0 {
+		fmt.Printf("%s Archived, left unchanged: %s\n", ui.InfoIcon(), strings.Join(result.Archived, ", "))
+	}
+	return nil
+}
+
+// checkCommitMessage warns about issue references in a commit message that
+// match no issue. It never fails, so it cannot block a commit.
+func checkCommitMessage(basePath, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sl: could not read commit message: %v\n", err)
+		return nil
+	}
+
+	// git drops comment lines from the final message
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+
+	for _, ref := range issues.ParseCommitRefs(strings.Join(lines, "\n")) {
+		issue, _, err := issues.GetIssueAcrossSpecs(ref.IssueID, basePath)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "sl: %s does not match any issue\n", ref.IssueID)
+		case ref.Closes:
+			fmt.Fprintf(os.Stderr, "sl: this commit will close %s (%s)\n", issue.ID, issue.Title)
+		}
+	}
+	return nil
+}
+
+// issueCommitHooks are the managed sections of the git hooks 'sl init' installs
+var issueCommitHooks = map[string]string{
+	"commit-msg": `if command -v sl >/dev/null 2>&1; then
+	sl issue sync-commits --message-file "$1" || true
+fi`,
+	"post-commit": `if command -v sl >/dev/null 2>&1; then
+	sl issue sync-commits --rev HEAD --limit 1 --quiet || true
+fi`,
+}
+
+// registerIssueCommitHooks installs the commit-msg and post-commit hooks that
+// link commits to issues, as sentinel-managed sections so existing hook
+// scripts are kept. Hooks that are not shell scripts are left alone. This is
+// a non-fatal operation — it is skipped when projectPath is not a git
+// repository.
+func registerIssueCommitHooks(projectPath string) {
+	out, err := exec.Command("git", "-C", projectPath, "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return
+	}
+
+	hooksDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(projectPath, hooksDir)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Could not install issue commit hooks: %v", err))
+		return
+	}
+
+	var installed []string
+	for _, name := range []string{"commit-msg", "post-commit"} {
+		path := filepath.Join(hooksDir, name)
+		existing := ""
+		if data, err := os.ReadFile(path); err == nil {
+			existing = string(data)
+		}
+
+		if strings.TrimSpace(existing) == "" {
+			existing = "#!/bin/sh\n"
+		} else if shebang, _, _ := strings.Cut(existing, "\n"); !strings.HasPrefix(shebang, "#!") || !strings.HasSuffix(shebang, "sh") {
+			ui.PrintWarning(fmt.Sprintf("Skipped the %s hook: %s is not a shell script", name, path))
+			continue
+		}
+
+		merged := playbooks.MergeSentinelSection(existing, issueCommitHooks[name])
+		if merged == existing {
+			continue
+		}
+		// #nosec G306 -- hooks must be executable
+		if err := os.WriteFile(path, []byte(merged), 0755); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Could not install the %s hook: %v", name, err))
+			continue
+		}
+		_ = os.Chmod(path, 0755) // #nosec G302 -- hooks must be executable
+		installed = append(installed, name)
+	}
+
+	if len(installed) > 0 {
+		fmt.Printf("%s Installed git hooks linking commits to issues (%s)\n", ui.Checkmark(), strings.Join(installed, ", "))
+	}
+}
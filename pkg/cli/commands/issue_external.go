@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// dependencyIssues caches the issues of each dependency checkout read during
+// this run, keyed by the checkout's artifact directory
+var dependencyIssues = struct {
+	sync.Mutex
+	byBase map[string]map[string]*issues.Issue
+}{byBase: make(map[string]map[string]*issues.Issue)}
+
+func init() {
+	issues.ExternalIssueFunc = resolveExternalIssue
+}
+
+// resolveExternalIssue finds issue id in the cached checkout of the
+// dependency declared with alias in the specledger.yaml of the project owning
+// basePath. The checkout is the one 'sl deps resolve' and 'sl deps update'
+// maintain, so upstream status changes are seen after an update; a local
+// replacement (see 'sl deps replace') is read instead when there is one.
+// Archived upstream issues are found too, and issues moved upstream are
+// followed to their new ID.
+func resolveExternalIssue(basePath, alias, id string) (*issues.Issue, bool, error) {
+	root, err := metadata.FindProjectRootFrom(basePath)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s (no specledger.yaml)", issues.ErrUnknownDependency, alias)
+	}
+	meta, err := metadata.LoadFromProject(root)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load specledger.yaml: %w", err)
+	}
+
+	var dep *metadata.Dependency
+	for i := range meta.Dependencies {
+		if meta.Dependencies[i].Alias == alias {
+			dep = &meta.Dependencies[i]
+		}
+	}
+	if dep == nil {
+		return nil, false, fmt.Errorf("%w: %s", issues.ErrUnknownDependency, alias)
+	}
+
+	cache, err := deps.OpenCache()
+	if err != nil {
+		return nil, false, err
+	}
+	source := *dep
+	if source.ArtifactPath == "" {
+		source.ArtifactPath = "specledger"
+	}
+	depBase, err := dependencySource(cache, root, source)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %s (run 'sl deps resolve')", issues.ErrDependencyMissing, alias)
+	}
+
+	dependencyIssues.Lock()
+	defer dependencyIssues.Unlock()
+	byID, ok := dependencyIssues.byBase[depBase]
+	if !ok {
+		list, err := issues.ListAllSpecs(depBase, issues.ListFilter{IncludeArchived: true})
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read issues of %s: %w", alias, err)
+		}
+		byID = make(map[string]*issues.Issue, len(list))
+		for i := range list {
+			byID[list[i].ID] = &list[i]
+		}
+		dependencyIssues.byBase[depBase] = byID
+	}
+
+	issue, ok := byID[id]
+	if !ok {
+		if redirect, err := issues.ResolveRedirect(depBase, id); err == nil {
+			issue, ok = byID[redirect.MovedTo]
+		}
+	}
+	if !ok {
+		return nil, false, fmt.Errorf("%w: %s in %s", issues.ErrIssueNotFound, id, alias)
+	}
+	return issue, issues.WorkflowFunc(depBase).IsDone(issue.Status), nil
+}
@@ -0,0 +1,215 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+// issueHistoryCmd shows the event log for an issue
+var issueHistoryCmd = &cobra.Command{
+	Use:   "history <issue-id>",
+	Short: "Show the change history of an issue",
+	Long: `Show every recorded change to an issue: who made it, when, and which
+fields changed from what to what.
+
+Events are stored append-only in specledger/<spec>/issues.events.jsonl.
+Use 'sl issue show <id> --as-of <time>' to see the issue as it was at a point in time.`,
+	Example: `  sl issue history SL-a3f5d8
+  sl issue history SL-a3f5d8 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueHistory,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueHistoryCmd)
+
+	issueHistoryCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+}
+
+func runIssueHistory(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+
+	if _, err := issues.ParseIssueID(issueID); err != nil {
+		return fmt.Errorf("invalid issue ID: %w", err)
+	}
+
+	store, err := storeForIssue(issueID)
+	if err != nil {
+		return err
+	}
+
+	events, err := store.History(issueID)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	if issueJSONFlag {
+		if events == nil {
+			events = []issues.Event{}
+		}
+		data, _ := json.MarshalIndent(events, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(events) == 0 {
+		fmt.Printf("No recorded history for %s\n", issueID)
+		fmt.Println(ui.Gray("Changes are recorded from the first update made with this version of sl."))
+		return nil
+	}
+
+	ui.PrintHeader("History: "+issueID, fmt.Sprintf("%d events", len(events)), 70)
+	fmt.Println()
+
+	for _, e := range events {
+		fmt.Printf("%s  %s  %s\n",
+			ui.Gray(e.Timestamp.Local().Format("2006-01-02 15:04:05")),
+			ui.Cyan(e.Actor),
+			formatEventType(e.Type))
+		if e.Reason != "" {
+			fmt.Printf("    reason: %s\n", e.Reason)
+		}
+		for _, c := range e.Changes {
+			switch e.Type {
+			case issues.EventCreated:
+				fmt.Printf("    %s: %s\n", c.Field, formatEventValue(c.New))
+			case issues.EventDeleted:
+				fmt.Printf("    %s: %s\n", c.Field, formatEventValue(c.Old))
+			default:
+				fmt.Printf("    %s: %s → %s\n", c.Field, formatEventValue(c.Old), formatEventValue(c.New))
+			}
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// storeForIssue opens the store for the spec containing issueID, preferring the
+// current spec context and falling back to a cross-spec lookup.
+func storeForIssue(issueID string) (*issues.Store, error) {
+	basePath := getArtifactPath()
+
+	detector := issues.NewContextDetector(".")
+	specContext, _ := detector.DetectSpecContext()
+
+	if specContext != "" {
+		store, err := issues.NewStore(issues.StoreOptions{BasePath: basePath, SpecContext: specContext})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create store: %w", err)
+		}
+		if _, err := store.Get(issueID); err == nil {
+			return store, nil
+		}
+	}
+
+	_, spec, err := issues.GetIssueAcrossSpecs(issueID, basePath)
+	if err != nil {
+		if specContext == "" {
+			return nil, fmt.Errorf("failed to find issue: %w", err)
+		}
+		// Possibly deleted; its history can still live in the current spec.
+		spec = specContext
+	}
+
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: basePath, SpecContext: spec})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return store, nil
+}
+
+func formatEventType(t issues.EventType) string {
+	switch t {
+	case issues.EventCreated:
+		return ui.Green(string(t))
+	case issues.EventClosed:
+		return ui.Gray(string(t))
+	case issues.EventDeleted:
+		return ui.Red(string(t))
+	default:
+		return ui.Bold(string(t))
+	}
+}
+
+// formatEventValue renders a JSON-encoded field value compactly for display.
+func formatEventValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ui.Gray("(none)")
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return string(raw)
+	}
+
+	var s string
+	switch val := v.(type) {
+	case string:
+		if val == "" {
+			return ui.Gray("(empty)")
+		}
+		s = strconv.Quote(val)
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			if str, ok := item.(string); ok {
+				parts = append(parts, str)
+			} else {
+				b, _ := json.Marshal(item)
+				parts = append(parts, string(b))
+			}
+		}
+		s = "[" + strings.Join(parts, ", ") + "]"
+	case map[string]interface{}:
+		// Definition of done: render as a checklist rather than raw JSON
+		items, ok := val["items"].([]interface{})
+		if !ok {
+			s = string(raw)
+			break
+		}
+		parts := make([]string, 0, len(items))
+		for _, item := range items {
+			entry, _ := item.(map[string]interface{})
+			mark := "[ ]"
+			if checked, _ := entry["checked"].(bool); checked {
+				mark = "[x]"
+			}
+			parts = append(parts, fmt.Sprintf("%s %v", mark, entry["item"]))
+		}
+		s = strings.Join(parts, ", ")
+	default:
+		s = string(raw)
+	}
+
+	return issues.Truncate(s, 80)
+}
+
+// parseAsOf parses an absolute time (RFC3339, "YYYY-MM-DD HH:MM:SS", "YYYY-MM-DD")
+// or a relative duration in the past (e.g. "90m", "2h", "3d", "1w").
+func parseAsOf(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			if layout == "2006-01-02" {
+				// A bare date means the end of that day
+				t = t.Add(24*time.Hour - time.Nanosecond)
+			}
+			return t, nil
+		}
+	}
+
+	if d, err := issues.ParseRelativeDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC3339, YYYY-MM-DD, or a duration like 2h or 3d", value)
+}
@@ -0,0 +1,208 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueFormatFlag string
+	issueOutputFlag string
+)
+
+// issueExportCmd writes issues to another tracker's file format
+var issueExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export issues to CSV, Markdown, GitHub or Jira files",
+	Long: `Export issues for use in other trackers or documents.
+
+Formats:
+  csv          one column per field; lossless, re-importable with sl issue import
+  markdown     nested task list per spec, children under their parents
+  github-json  array of GitHub issues; type/priority/progress become labels,
+               parents and blockers become "Parent: #n" / "Blocked by: #n" lines
+  jira-csv     Jira CSV layout (Issue key, Summary, Issue Type, Status, Priority,
+               repeated Labels and "Inward issue link (Blocks)" columns)
+
+Use -q to export only issues matching a search query.`,
+	Example: `  sl issue export --format csv -o backlog.csv
+  sl issue export --all --format markdown
+  sl issue export --format jira-csv -q 'status:open,in_progress' -o jira.csv`,
+	RunE: runIssueExport,
+}
+
+// issueImportCmd reads issues from another tracker's file format
+var issueImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import issues from CSV, Markdown, GitHub or Jira files",
+	Long: `Import issues into the current spec (or --spec) from a file written by
+another tracker. Use "-" to read from stdin.
+
+Statuses, types, priorities, labels, assignees, parents and blocking links are
+mapped to SpecLedger equivalents. Each imported issue keeps its original ID
+(e.g. "#42" or "PROJ-17") in its migration metadata, so importing the same
+file again skips issues that were already imported.
+
+Issues whose title is similar to an existing issue in the spec are skipped
+as likely duplicates; use --force to import them anyway.
+
+The csv format also accepts common headers from other tools (Summary, State,
+Tags, Parent issue, ...), which covers Linear and spreadsheet exports. For
+GitHub, use: gh issue list --state all --limit 1000 --json number,title,body,state,labels,assignees,createdAt,updatedAt,closedAt,url`,
+	Example: `  sl issue import backlog.csv --format csv
+  gh issue list --state all --json number,title,body,state,labels,assignees,createdAt,updatedAt,closedAt,url | sl issue import - --format github-json
+  sl issue import jira.csv --format jira-csv --spec 010-my-feature --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueImport,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueExportCmd)
+	VarIssueCmd.AddCommand(issueImportCmd)
+
+	formats := make([]string, len(issues.ExchangeFormats))
+	for i, f := range issues.ExchangeFormats {
+		formats[i] = string(f)
+	}
+	formatUsage := "File format (" + strings.Join(formats, ", ") + ")"
+
+	issueExportCmd.Flags().StringVar(&issueFormatFlag, "format", "csv", formatUsage)
+	issueExportCmd.Flags().StringVarP(&issueOutputFlag, "output", "o", "", "Write to a file instead of stdout")
+	issueExportCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueExportCmd.Flags().BoolVar(&issueAllFlag, "all", false, "Export issues across all specs")
+	issueExportCmd.Flags().StringVarP(&issueQueryFlag, "query", "q", "", "Only export issues matching a search query")
+
+	issueImportCmd.Flags().StringVar(&issueFormatFlag, "format", "csv", formatUsage)
+	issueImportCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueImportCmd.Flags().BoolVar(&issueForceFlag, "force", false, "Import issues even if a similar title exists")
+	issueImportCmd.Flags().BoolVar(&issueDryRunFlag, "dry-run", false, "Show what would be imported")
+}
+
+func runIssueExport(cmd *cobra.Command, args []string) error {
+	format, err := issues.ParseExchangeFormat(issueFormatFlag)
+	if err != nil {
+		return err
+	}
+	query, err := issues.ParseQuery(issueQueryFlag, issues.WorkflowFunc(getArtifactPath()))
+	if err != nil {
+		return err
+	}
+
+	var issueList []issues.Issue
+	artifactPath := getArtifactPath()
+	if issueAllFlag {
+		issueList, err = issues.ListAllSpecs(artifactPath, issues.ListFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list issues across specs: %w", err)
+		}
+	} else {
+		specContext := issueSpecFlag
+		if specContext == "" {
+			specContext, err = issues.NewContextDetector(".").DetectSpecContext()
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+		}
+		store, err := issues.NewStore(issues.StoreOptions{BasePath: artifactPath, SpecContext: specContext})
+		if err != nil {
+			return fmt.Errorf("failed to create store: %w", err)
+		}
+		issueList, err = store.List(issues.ListFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list issues: %w", err)
+		}
+	}
+	issueList = query.Filter(issueList)
+
+	var out io.Writer = os.Stdout
+	if issueOutputFlag != "" {
+		f, err := os.Create(issueOutputFlag)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", issueOutputFlag, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := issues.ExportIssues(out, format, issueList); err != nil {
+		return fmt.Errorf("export failed: %w", err)
+	}
+	if issueOutputFlag != "" {
+		fmt.Printf("%s Exported %d issues to %s\n", ui.Checkmark(), len(issueList), issueOutputFlag)
+	}
+	return nil
+}
+
+func runIssueImport(cmd *cobra.Command, args []string) error {
+	format, err := issues.ParseExchangeFormat(issueFormatFlag)
+	if err != nil {
+		return err
+	}
+
+	specContext := issueSpecFlag
+	if specContext == "" {
+		specContext, err = issues.NewContextDetector(".").DetectSpecContext()
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	var in io.Reader = os.Stdin
+	if args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", args[0], err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	ui.PrintSection(fmt.Sprintf("Importing %s Issues into %s", format, specContext))
+
+	result, err := issues.ImportIssues(in, issues.ImportOptions{
+		BasePath:    getArtifactPath(),
+		SpecContext: specContext,
+		Format:      format,
+		Force:       issueForceFlag,
+		DryRun:      issueDryRunFlag,
+	})
+	if err != nil {
+		return fmt.Errorf("import failed: %w", err)
+	}
+
+	fmt.Printf("Total issues found: %d\n", result.TotalIssues)
+
+	if len(result.Skipped) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %d issues skipped\n", ui.WarningIcon(), len(result.Skipped))
+		for _, s := range result.Skipped {
+			fmt.Printf("  - %s %s: %s\n", s.OriginalID, s.Title, s.Reason)
+		}
+	}
+
+	if len(result.Warnings) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %d warnings during import\n", ui.WarningIcon(), len(result.Warnings))
+		for _, w := range result.Warnings {
+			fmt.Printf("  - %s\n", w)
+		}
+	}
+
+	fmt.Println()
+	if issueDryRunFlag {
+		fmt.Printf("%d issues and %d links would be imported.\n", result.ImportedIssues, result.Links)
+		fmt.Println("Dry run complete. No changes were made.")
+		return nil
+	}
+
+	fmt.Printf("%s Import complete!\n", ui.Checkmark())
+	fmt.Printf("  %d issues imported\n", result.ImportedIssues)
+	fmt.Printf("  %d parent and blocking links created\n", result.Links)
+	return nil
+}
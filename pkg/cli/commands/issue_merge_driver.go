@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/playbooks"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+// issueMergeDriverName is the git merge driver name referenced from .gitattributes.
+const issueMergeDriverName = "sl-issues"
+
+// issueMergeDriverCmd is invoked by git to merge issues.jsonl files
+var issueMergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs>",
+	Short: "Git merge driver for issues.jsonl",
+	Long: `Three-way, record-level merge of issues.jsonl files, keyed by issue ID.
+
+Git invokes this as a custom merge driver with the ancestor (%O), current (%A)
+and other (%B) versions of the file. The merged result is written to %A.
+
+Fields changed on only one side are taken from that side. Fields changed on
+both sides take the value from the side with the newer updated_at and are
+reported as conflicts. blocked_by, blocks, labels and commits are merged as
+sets.
+
+The command exits non-zero only when a field was changed on both sides with an
+identical updated_at, so git leaves the file marked as conflicted for review.
+
+'sl init' registers the driver automatically:
+
+  .gitattributes:  specledger/*/issues.jsonl merge=sl-issues
+  .git/config:     [merge "sl-issues"] driver = sl issue merge-driver %O %A %B`,
+	Example: `  sl issue merge-driver %O %A %B`,
+	Args:    cobra.ExactArgs(3),
+	RunE:    runIssueMergeDriver,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueMergeDriverCmd)
+}
+
+func runIssueMergeDriver(cmd *cobra.Command, args []string) error {
+	result, err := issues.MergeFiles(args[0], args[1], args[2])
+	if err != nil {
+		return fmt.Errorf("issue merge failed: %w", err)
+	}
+
+	// git shows driver output during the merge; keep stdout clean.
+	for _, c := range result.Conflicts {
+		fmt.Fprintf(os.Stderr, "sl issue merge-driver: %s\n", c)
+	}
+
+	if result.HasUnresolved() {
+		return fmt.Errorf("unresolved issue conflicts in %s", args[1])
+	}
+	return nil
+}
+
+// issueMergeDriverConfig is the managed .git/config section registering the driver.
+var issueMergeDriverConfig = `[merge "` + issueMergeDriverName + `"]
+	name = SpecLedger issues.jsonl record-level merge
+	driver = sl issue merge-driver %O %A %B`
+
+// registerIssueMergeDriver adds the issues.jsonl merge driver to the repository's
+// git config using a sentinel-managed section. The matching .gitattributes entry
+// ships with the embedded playbook. This is a non-fatal operation — it is skipped
+// when projectPath is not a git repository.
+func registerIssueMergeDriver(projectPath string) {
+	out, err := exec.Command("git", "-C", projectPath, "rev-parse", "--git-path", "config").Output()
+	if err != nil {
+		return
+	}
+
+	configPath := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(configPath) {
+		configPath = filepath.Join(projectPath, configPath)
+	}
+
+	existing := ""
+	if data, err := os.ReadFile(configPath); err == nil {
+		existing = string(data)
+	}
+
+	merged := playbooks.MergeSentinelSection(existing, issueMergeDriverConfig)
+	if merged == existing {
+		return
+	}
+
+	// #nosec G306 -- git config is not sensitive, 0644 matches git's default
+	if err := os.WriteFile(configPath, []byte(merged), 0644); err != nil {
+		ui.PrintWarning(fmt.Sprintf("Could not register issues merge driver: %v", err))
+		return
+	}
+
+	fmt.Printf("%s Registered git merge driver for issues.jsonl\n", ui.Checkmark())
+}
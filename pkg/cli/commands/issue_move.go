@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var issueMoveToFlag string
+
+// issueMoveCmd moves issues to another spec
+var issueMoveCmd = &cobra.Command{
+	Use:   "move <id...> --to <spec>",
+	Short: "Move issues and their children to another spec",
+	Long: `Move issues, with all of their children, to another spec.
+
+Issue IDs are derived from the spec, so moved issues get new IDs. Every
+blocks, blocked-by and parent link to them, in any spec, is updated to the new
+IDs. The old spec keeps a redirect (issues.redirects.jsonl), so
+'sl issue show <old-id>' still finds a moved issue.
+
+All issues to move must be in the same spec. Moving some children of an epic
+splits them off: a moved issue whose parent stays behind is detached from it,
+since parents must be in the same spec. Blocking links between moved issues and
+issues that stay behind are removed in both directions as well, as readiness
+only considers blockers in the issue's own spec. The target spec directory must
+exist.`,
+	Example: `  sl issue move SL-a3f5d8 --to 012-billing
+  sl issue move SL-a3f5d8 SL-b4e6f9 --to 012-billing --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runIssueMove,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueMoveCmd)
+
+	issueMoveCmd.Flags().StringVar(&issueMoveToFlag, "to", "", "Spec to move the issues to (e.g. 012-billing)")
+	issueMoveCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+	_ = issueMoveCmd.MarkFlagRequired("to")
+}
+
+func runIssueMove(cmd *cobra.Command, args []string) error {
+	for _, id := range args {
+		if _, err := issues.ParseIssueID(id); err != nil {
+			return fmt.Errorf("invalid issue ID %s: %w", id, err)
+		}
+	}
+
+	result, err := issues.MoveIssues(getArtifactPath(), args, issueMoveToFlag)
+	if err != nil && result == nil {
+		return fmt.Errorf("failed to move issues: %w", err)
+	}
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(data))
+		return err
+	}
+
+	ui.PrintSection(fmt.Sprintf("Moved %s → %s", result.From, result.To))
+	for _, moved := range result.Moved {
+		fmt.Printf("  %s → %s\n", moved.ID, moved.MovedTo)
+	}
+	fmt.Println()
+	fmt.Printf("%s Moved %d issue(s), updated references in %d other issue(s)\n", ui.Checkmark(), len(result.Moved), len(result.Rewritten))
+	if len(result.Detached) > 0 {
+		fmt.Printf("%s Detached from their parent in %s: %s\n", ui.WarningIcon(), result.From, strings.Join(result.Detached, ", "))
+	}
+	if len(result.Unblocked) > 0 {
+		fmt.Printf("%s Unlinked from their blockers in %s: %s\n", ui.WarningIcon(), result.From, strings.Join(result.Unblocked, ", "))
+	}
+	if len(result.Released) > 0 {
+		fmt.Printf("%s No longer blocked by the moved issues: %s\n", ui.WarningIcon(), strings.Join(result.Released, ", "))
+	}
+	if len(result.Stale) > 0 {
+		fmt.Printf("%s References to the old IDs remain in: %s\n", ui.WarningIcon(), strings.Join(result.Stale, ", "))
+	}
+	return err
+}
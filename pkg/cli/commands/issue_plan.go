@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issuePlanAgentsFlag  int
+	issuePlanDefaultFlag string
+	issuePlanWidthFlag   int
+)
+
+// issuePlanCmd computes the critical path and schedule of an epic
+var issuePlanCmd = &cobra.Command{
+	Use:   "plan <epic-id>",
+	Short: "Plan an epic: critical path, slack and agent schedule",
+	Long: `Plan the open work below an epic from issue estimates and blocking links.
+
+For every task the plan shows its earliest and latest start and finish in
+working days and its slack: how long it can slip without delaying the epic.
+Tasks without slack form the critical path (marked * in the chart). Closed
+tasks take no time; issues with children are planned as their leaf tasks, and
+unfinished blockers from outside the epic are planned as external work.
+
+With --agents N the plan also simulates N agents working in parallel, giving
+each free agent the ready task with the least slack, and forecasts the finish
+date from that schedule.
+
+Set estimates with 'sl issue create/update --estimate' (e.g. 2, 1.5d, 4h, 1w).
+Tasks without one are assumed to take --default-estimate.`,
+	Example: `  sl issue plan SL-a3f5d8
+  sl issue plan SL-a3f5d8 --agents 3
+  sl issue plan SL-a3f5d8 --default-estimate 4h --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssuePlan,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issuePlanCmd)
+
+	issuePlanCmd.Flags().IntVar(&issuePlanAgentsFlag, "agents", 0, "Simulate a schedule with this many parallel agents")
+	issuePlanCmd.Flags().StringVar(&issuePlanDefaultFlag, "default-estimate", "1d", "Effort assumed for tasks without an estimate")
+	issuePlanCmd.Flags().IntVar(&issuePlanWidthFlag, "width", 50, "Width of the Gantt chart bars")
+	issuePlanCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+}
+
+func runIssuePlan(cmd *cobra.Command, args []string) error {
+	if issuePlanAgentsFlag < 0 {
+		return fmt.Errorf("--agents must not be negative")
+	}
+	defaultEstimate, err := issues.ParseEstimate(issuePlanDefaultFlag)
+	if err != nil {
+		return fmt.Errorf("invalid --default-estimate: %w", err)
+	}
+
+	basePath := getArtifactPath()
+	_, specContext, err := issues.GetIssueAcrossSpecs(args[0], basePath)
+	if err != nil {
+		return fmt.Errorf("failed to find issue: %w", err)
+	}
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: basePath, SpecContext: specContext})
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	issueList, err := store.List(issues.ListFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	plan, err := issues.BuildPlan(args[0], issueList, issues.PlanOptions{
+		Agents:          issuePlanAgentsFlag,
+		DefaultEstimate: defaultEstimate,
+		Workflow:        store.Workflow(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(plan, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	ui.PrintSection(fmt.Sprintf("Plan: %s %s", plan.EpicID, plan.EpicTitle))
+	fmt.Printf("Critical path: %s (%s)\n", strings.Join(plan.CriticalPath, " → "), issues.FormatEstimate(plan.Duration))
+	if plan.Agents > 0 {
+		fmt.Printf("Schedule with %d agent(s): %s\n", plan.Agents, issues.FormatEstimate(plan.Makespan))
+	}
+	fmt.Printf("Forecast finish: %s\n\n", plan.Forecast.Local().Format("Mon 2006-01-02"))
+
+	fmt.Print(plan.Gantt(issuePlanWidthFlag))
+
+	for _, warning := range plan.Warnings {
+		fmt.Printf("\n%s %s", ui.WarningIcon(), warning)
+	}
+	if len(plan.Warnings) > 0 {
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueQueryFlag  string
+	issueSortFlag   string
+	issueFieldsFlag string
+	issueLimitFlag  int
+)
+
+// defaultSearchFields are the columns shown when --fields is not given
+var defaultSearchFields = []string{"id", "title", "status", "type", "priority", "spec"}
+
+// issueSearchCmd searches issues across specs with the query language
+var issueSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search issues across specs",
+	Long: `Search issues across all specs with a query.
+
+Bare words and "quoted phrases" match title, description, notes, design and
+acceptance criteria (case-insensitive). Qualifiers narrow by field:
+
+  status:open,in_progress   type:bug          priority:<=1
+  label:component:api       label:area:*      spec:010-my-feature
+  assignee:me               assignee:none     parent:SL-a3f5d8
+  is:blocked                is:orphaned       is:claimed
+  title:"rate limit"        notes:retry       id:SL-a3f5d8
+  created:>2026-01-01       updated:>7d       closed:<=2w
+
+Terms are ANDed. Combine with OR, NOT (or a leading -) and parentheses.
+Comma-separated values match any of them.
+
+Dates take YYYY-MM-DD, RFC3339 or a duration ago (90m, 12h, 7d, 2w):
+updated:>7d is "updated in the last 7 days", updated:<7d "not updated for
+7 days". "me" is the actor recorded in the event log (SL_ACTOR, then git
+user.name).
+
+Sort with --sort (prefix a field with - for descending) and choose columns
+with --fields. Fields: ` + strings.Join(issues.SearchFields, ", ") + `.`,
+	Example: `  sl issue search 'status:open label:component:api assignee:me "rate limit" updated:>7d'
+  sl issue search 'type:bug (priority:0 OR label:security) -is:blocked'
+  sl issue search 'status:open' --sort priority,-updated --fields id,title,status
+  sl issue search 'retry' --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIssueSearch,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueSearchCmd)
+
+	issueSearchCmd.Flags().StringVar(&issueSortFlag, "sort", "", "Sort by fields, e.g. priority,-updated")
+	issueSearchCmd.Flags().StringVar(&issueFieldsFlag, "fields", "", "Fields to show, e.g. id,title,status")
+	issueSearchCmd.Flags().IntVar(&issueLimitFlag, "limit", 0, "Show at most this many issues (0 = no limit)")
+	issueSearchCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+
+	issueListCmd.Flags().StringVarP(&issueQueryFlag, "query", "q", "", "Filter with a search query (see 'sl issue search --help')")
+}
+
+func runIssueSearch(cmd *cobra.Command, args []string) error {
+	input := ""
+	if len(args) == 1 {
+		input = args[0]
+	}
+	query, err := issues.ParseQuery(input, issues.WorkflowFunc(getArtifactPath()))
+	if err != nil {
+		return err
+	}
+	sortKeys, err := issues.ParseSortKeys(issueSortFlag)
+	if err != nil {
+		return err
+	}
+
+	var fields []string
+	if issueFieldsFlag != "" {
+		fields, err = issues.ParseFieldList(issueFieldsFlag)
+		if err != nil {
+			return err
+		}
+	}
+
+	all, err := issues.ListAllSpecs(getArtifactPath(), issues.ListFilter{})
+	if err != nil {
+		return fmt.Errorf("failed to list issues across specs: %w", err)
+	}
+	results := query.Filter(all)
+	issues.SortIssues(results, sortKeys)
+	if issueLimitFlag > 0 && len(results) > issueLimitFlag {
+		results = results[:issueLimitFlag]
+	}
+
+	if issueJSONFlag {
+		var data []byte
+		if fields == nil {
+			data, _ = json.MarshalIndent(results, "", "  ")
+		} else {
+			rows := make([]map[string]interface{}, 0, len(results))
+			for _, issue := range results {
+				row := make(map[string]interface{}, len(fields))
+				for _, field := range fields {
+					row[field] = issues.IssueField(issue, field)
+				}
+				rows = append(rows, row)
+			}
+			data, _ = json.MarshalIndent(rows, "", "  ")
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+	if fields == nil {
+		fields = defaultSearchFields
+	}
+	printIssueFields(results, fields)
+	return nil
+}
+
+// printIssueFields prints issues as a table with the given SearchFields as columns.
+func printIssueFields(issueList []issues.Issue, fields []string) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.ToUpper(strings.Join(fields, "\t")))
+	for _, issue := range issueList {
+		cells := make([]string, len(fields))
+		for i, field := range fields {
+			cells[i] = issues.FormatIssueField(issue, field)
+			if field == "title" {
+				cells[i] = issues.Truncate(cells[i], 40)
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+	w.Flush()
+}
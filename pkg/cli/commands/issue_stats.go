@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var issueStatsWeeksFlag int
+
+// issueStatsCmd reports flow metrics for issues
+var issueStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cycle-time and throughput metrics",
+	Long: `Show flow metrics computed from issue timestamps and the event log:
+
+  Lead time    created → closed
+  Cycle time   first in_progress → closed
+  WIP          issues in progress at the end of each week
+  Throughput   issues closed per week
+  Blocked      time an issue spent with at least one open blocker
+
+With a custom workflow, "in progress" means any doing status (e.g. review)
+and "closed" any done status. Issues changed before the event log existed fall back to their created, updated
+and closed timestamps. Use --json to feed dashboards.`,
+	Example: `  sl issue stats
+  sl issue stats --all --weeks 12
+  sl issue stats --json`,
+	RunE: runIssueStats,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueStatsCmd)
+
+	issueStatsCmd.Flags().BoolVar(&issueAllFlag, "all", false, "Include issues across all specs")
+	issueStatsCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	issueStatsCmd.Flags().IntVar(&issueStatsWeeksFlag, "weeks", 8, "Number of weeks to chart")
+	issueStatsCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+}
+
+func runIssueStats(cmd *cobra.Command, args []string) error {
+	specContext := issueSpecFlag
+	if specContext == "" && !issueAllFlag {
+		detector := issues.NewContextDetector(".")
+		var err error
+		specContext, err = detector.DetectSpecContext()
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	var issueList []issues.Issue
+	var events []issues.Event
+	var err error
+
+	// Archived issues still count towards throughput and cycle times
+	artifactPath := getArtifactPath()
+	if issueAllFlag {
+		issueList, err = issues.ListAllSpecs(artifactPath, issues.ListFilter{IncludeArchived: true})
+		if err != nil {
+			return fmt.Errorf("failed to list issues across specs: %w", err)
+		}
+		events, err = issues.ListAllEvents(artifactPath)
+		if err != nil {
+			return fmt.Errorf("failed to read event logs: %w", err)
+		}
+	} else {
+		store, storeErr := issues.NewStore(issues.StoreOptions{
+			BasePath:    artifactPath,
+			SpecContext: specContext,
+		})
+		if storeErr != nil {
+			return fmt.Errorf("failed to create store: %w", storeErr)
+		}
+		issueList, err = store.List(issues.ListFilter{IncludeArchived: true})
+		if err != nil {
+			return fmt.Errorf("failed to list issues: %w", err)
+		}
+		events, err = issues.ReadEvents(store.EventsPath())
+		if err != nil {
+			return fmt.Errorf("failed to read event log: %w", err)
+		}
+	}
+
+	stats := issues.ComputeStats(issueList, events, issues.StatsOptions{
+		Weeks:    issueStatsWeeksFlag,
+		Workflow: issues.WorkflowFunc(artifactPath),
+	})
+
+	if issueJSONFlag {
+		data, _ := json.MarshalIndent(stats, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	scope := specContext
+	if issueAllFlag {
+		scope = "all specs"
+	}
+	ui.PrintHeader("Issue Stats", scope, 70)
+
+	if stats.Total == 0 {
+		fmt.Println("No issues found")
+		return nil
+	}
+
+	fmt.Printf("%d issues: %d open, %d in progress, %d closed\n",
+		stats.Total, stats.Open, stats.InProgress, stats.Closed)
+	ui.PrintSection("Flow Times")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "METRIC\tCOUNT\tMEAN\tMEDIAN\tP85\tMAX")
+	for _, row := range []struct {
+		name    string
+		summary issues.DurationSummary
+	}{
+		{"Lead time", stats.LeadTime},
+		{"Cycle time", stats.CycleTime},
+	} {
+		s := row.summary
+		if s.Count == 0 {
+			fmt.Fprintf(w, "%s\t0\t-\t-\t-\t-\n", row.name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", row.name, s.Count,
+			formatHours(s.MeanHours), formatHours(s.MedianHours), formatHours(s.P85Hours), formatHours(s.MaxHours))
+	}
+	w.Flush()
+
+	ui.PrintSection("Weekly")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "WEEK\tOPENED\tCLOSED\tWIP\tOPEN")
+	var throughput, wip, burnup, burndown []int
+	for _, p := range stats.Weeks {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", p.WeekStart.Format("2006-01-02"), p.Opened, p.Closed, p.WIP, p.Open)
+		throughput = append(throughput, p.Closed)
+		wip = append(wip, p.WIP)
+		burnup = append(burnup, p.Done)
+		burndown = append(burndown, p.Open)
+	}
+	w.Flush()
+
+	ui.PrintSection("Trends")
+	printSparkRow("Throughput", throughput)
+	printSparkRow("WIP", wip)
+	printSparkRow("Burnup", burnup)
+	printSparkRow("Burndown", burndown)
+
+	var blocked []issues.IssueFlow
+	for _, f := range stats.Issues {
+		if f.BlockedHours > 0 {
+			blocked = append(blocked, f)
+		}
+	}
+	if len(blocked) > 0 {
+		sort.Slice(blocked, func(i, j int) bool { return blocked[i].BlockedHours > blocked[j].BlockedHours })
+		if len(blocked) > 10 {
+			blocked = blocked[:10]
+		}
+
+		ui.PrintSection("Most Blocked")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tBLOCKED\tSTATUS\tTITLE")
+		for _, f := range blocked {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", f.ID, formatHours(f.BlockedHours), f.Status, issues.Truncate(f.Title, 40))
+		}
+		w.Flush()
+	}
+
+	return nil
+}
+
+func printSparkRow(label string, values []int) {
+	last := 0
+	if len(values) > 0 {
+		last = values[len(values)-1]
+	}
+	fmt.Printf("  %-11s %s  %s\n", label, ui.Cyan(ui.SparklineInts(values)), ui.Gray(strconv.Itoa(last)))
+}
+
+// formatHours renders a duration in hours as a compact "3.5h" or "2.1d".
+func formatHours(hours float64) string {
+	if hours >= 48 {
+		return strconv.FormatFloat(hours/24, 'f', 1, 64) + "d"
+	}
+	return strconv.FormatFloat(hours, 'f', 1, 64) + "h"
+}
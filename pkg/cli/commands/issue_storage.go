@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+func init() {
+	issues.StorageFunc = configuredIssueStorage
+}
+
+// configuredIssueStorage returns task_tracker.storage from the specledger.yaml
+// of the project containing basePath. Unknown or missing values mean JSONL.
+func configuredIssueStorage(basePath string) issues.StorageKind {
+	root, err := metadata.FindProjectRootFrom(basePath)
+	if err != nil {
+		return issues.StorageJSONL
+	}
+	meta, err := metadata.LoadFromProject(root)
+	if err != nil {
+		return issues.StorageJSONL
+	}
+	kind, err := issues.ParseStorageKind(meta.TaskTracker.Storage)
+	if err != nil {
+		return issues.StorageJSONL
+	}
+	return kind
+}
+
+// runIssueMigrateStorage moves all issues to the storage named by --to and
+// records the choice in specledger.yaml.
+func runIssueMigrateStorage(to string) error {
+	toKind, err := issues.ParseStorageKind(to)
+	if err != nil {
+		return err
+	}
+
+	meta, err := metadata.LoadFromProject(".")
+	if err != nil {
+		return fmt.Errorf("failed to load specledger.yaml: %w", err)
+	}
+	fromKind, err := issues.ParseStorageKind(meta.TaskTracker.Storage)
+	if err != nil {
+		return err
+	}
+	if fromKind == toKind {
+		fmt.Printf("%s Issues are already stored as %s\n", ui.Checkmark(), toKind)
+		return nil
+	}
+
+	ui.PrintSection(fmt.Sprintf("Migrating Issue Storage: %s → %s", fromKind, toKind))
+
+	if issueDryRunFlag {
+		all, err := issues.ListAllSpecs(getArtifactPath(), issues.ListFilter{})
+		if err != nil {
+			return fmt.Errorf("failed to list issues: %w", err)
+		}
+		fmt.Printf("%d issues would be moved to %s\n", len(all), toKind)
+		fmt.Println("Dry run complete. No changes were made.")
+		return nil
+	}
+
+	result, err := issues.MigrateStorage(getArtifactPath(), fromKind, toKind)
+	if err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+
+	meta.TaskTracker.Storage = string(toKind)
+	if err := metadata.SaveToProject(meta, "."); err != nil {
+		return fmt.Errorf("issues were moved to %s but specledger.yaml could not be updated (set task_tracker.storage: %s): %w", toKind, toKind, err)
+	}
+
+	specs := make([]string, 0, len(result.SpecDistribution))
+	for spec := range result.SpecDistribution {
+		specs = append(specs, spec)
+	}
+	sort.Strings(specs)
+
+	fmt.Println("Issues by spec:")
+	for _, spec := range specs {
+		fmt.Printf("  %s: %d issues\n", spec, result.SpecDistribution[spec])
+	}
+	fmt.Println()
+	fmt.Printf("%s Migrated %d issues to %s\n", ui.Checkmark(), result.TotalIssues, toKind)
+	fmt.Printf("  task_tracker.storage set to %s in specledger.yaml\n", toKind)
+	return nil
+}
@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueTemplateFlag string
+	issueFieldFlag    []string // Custom field assignments, name=value (repeatable)
+)
+
+// templatesWarning reports invalid issue templates once per run
+var templatesWarning sync.Once
+
+// issueTemplatesCmd lists the project's issue templates
+var issueTemplatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "List issue templates and their custom fields",
+	Long: `List the issue templates of the project and the custom fields they declare.
+
+Templates live in .specledger/templates/issues/<name>.yaml:
+
+  type: bug
+  priority: 1
+  labels: [bug]
+  definition_of_done:
+    - Regression test added
+  fields:
+    - name: steps-to-reproduce
+      type: string
+      required: true
+    - name: severity
+      type: enum
+      values: [low, medium, high, critical]
+      default: medium
+    - name: found-on
+      type: date
+
+Field types are string, enum (one of values), number and date (YYYY-MM-DD).
+'sl issue create --template bug' presets the issue from the template; set
+fields with --field name=value on create and update, and filter with
+'sl issue list --field name=value'. Values are checked against the template
+whenever the issue is written.`,
+	Example: `  sl issue templates
+  sl issue create --title "Login fails" --template bug --field severity=high --field steps-to-reproduce="..."
+  sl issue list --field severity=high`,
+	Args: cobra.NoArgs,
+	RunE: runIssueTemplates,
+}
+
+func init() {
+	issues.TemplatesFunc = configuredTemplates
+
+	VarIssueCmd.AddCommand(issueTemplatesCmd)
+	issueTemplatesCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+}
+
+// configuredTemplates loads the issue templates of the project containing
+// basePath. Invalid templates are reported and ignored.
+func configuredTemplates(basePath string) map[string]*issues.Template {
+	root, err := metadata.FindProjectRootFrom(basePath)
+	if err != nil {
+		return nil
+	}
+	templates, err := issues.LoadTemplates(filepath.Join(root, issues.TemplatesDir))
+	if err != nil {
+		templatesWarning.Do(func() {
+			fmt.Fprintf(os.Stderr, "%s Ignoring issue templates: %v\n", ui.WarningIcon(), err)
+		})
+		return nil
+	}
+	return templates
+}
+
+// lookupTemplate returns the named template of the current project
+func lookupTemplate(name string) (*issues.Template, error) {
+	templates := issues.TemplatesFunc(getArtifactPath())
+	if t, ok := templates[name]; ok {
+		return t, nil
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("%w: %s (no templates in %s)", issues.ErrUnknownTemplate, name, issues.TemplatesDir)
+	}
+	return nil, fmt.Errorf("%w: %s (available: %s)", issues.ErrUnknownTemplate, name, strings.Join(templateNames(templates), ", "))
+}
+
+// parseFieldFlags parses --field name=value assignments
+func parseFieldFlags(assignments []string) (map[string]string, error) {
+	if len(assignments) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]string, len(assignments))
+	for _, assignment := range assignments {
+		name, value, err := issues.ParseFieldAssignment(assignment)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// printCustomFields prints custom field values, in the template's order when
+// the template is known
+func printCustomFields(issue *issues.Issue, template *issues.Template) {
+	if len(issue.Fields) == 0 {
+		return
+	}
+	var names []string
+	if template != nil {
+		for _, f := range template.Fields {
+			if _, ok := issue.Fields[f.Name]; ok {
+				names = append(names, f.Name)
+			}
+		}
+	}
+	for name := range issue.Fields {
+		if template == nil || template.Field(name) == nil {
+			names = append(names, name)
+		}
+	}
+	if template == nil {
+		sort.Strings(names)
+	}
+
+	fmt.Println("Fields:")
+	for _, name := range names {
+		fmt.Printf("  %s: %s\n", name, issue.Fields[name])
+	}
+	fmt.Println()
+}
+
+func templateNames(templates map[string]*issues.Template) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runIssueTemplates(cmd *cobra.Command, args []string) error {
+	templates := issues.TemplatesFunc(getArtifactPath())
+	names := templateNames(templates)
+
+	if issueJSONFlag {
+		list := make([]*issues.Template, 0, len(names))
+		for _, name := range names {
+			list = append(list, templates[name])
+		}
+		data, _ := json.MarshalIndent(list, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(names) == 0 {
+		fmt.Printf("No issue templates. Add them to %s/<name>.yaml (see 'sl issue templates --help').\n", issues.TemplatesDir)
+		return nil
+	}
+
+	for _, name := range names {
+		t := templates[name]
+		ui.PrintSection(t.Name)
+		if t.Description != "" {
+			fmt.Printf("  %s\n", t.Description)
+		}
+		if t.IssueType != "" {
+			fmt.Printf("  Type: %s\n", t.IssueType)
+		}
+		if t.Priority != nil {
+			fmt.Printf("  Priority: %d\n", *t.Priority)
+		}
+		if len(t.Labels) > 0 {
+			fmt.Printf("  Labels: %s\n", strings.Join(t.Labels, ", "))
+		}
+		for _, item := range t.DefinitionOfDone {
+			fmt.Printf("  DoD: %s\n", item)
+		}
+		for _, f := range t.Fields {
+			kind := string(f.Type)
+			if f.Type == issues.FieldEnum {
+				kind += " (" + strings.Join(f.Values, "|") + ")"
+			}
+			var notes []string
+			if f.Required {
+				notes = append(notes, "required")
+			}
+			if f.Default != "" {
+				notes = append(notes, "default "+f.Default)
+			}
+			if f.Description != "" {
+				notes = append(notes, f.Description)
+			}
+			line := fmt.Sprintf("  %s: %s", f.Name, kind)
+			if len(notes) > 0 {
+				line += "  " + ui.Gray(strings.Join(notes, "; "))
+			}
+			fmt.Println(line)
+		}
+		fmt.Println()
+	}
+	return nil
+}
@@ -0,0 +1,528 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/config"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	issueViewTeamFlag        bool
+	issueViewDescriptionFlag string
+)
+
+// viewNamePattern restricts view names to what reads well after @
+var viewNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// viewFormats are the output formats a view can select
+var viewFormats = []string{"table", "json", "tree", "graph"}
+
+const (
+	viewScopeTeam    = "team"
+	viewScopePrivate = "private"
+)
+
+// savedView is a view with where it is stored
+type savedView struct {
+	Name     string            `json:"name"`
+	Scope    string            `json:"scope"`
+	Shadowed bool              `json:"shadowed,omitempty"` // A private view of the same name wins
+	View     *config.IssueView `json:"view"`
+}
+
+// issueViewCmd groups the saved view commands
+var issueViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Save and run named issue list views",
+	Long: `Save 'sl issue list' filters, sort order and output format under a name.
+
+Team views are stored under task_tracker.views in specledger.yaml and shared
+through git. Private views (the default) are stored in
+specledger/specledger.local.yaml. A private view hides a team view with the
+same name.
+
+Run a view with 'sl issue list @<name>'; list flags given alongside override
+the view's settings.
+
+Commands:
+  sl issue view save <name> [list flags]    Save a view
+  sl issue view list                        List saved views
+  sl issue view run <name>                  Run a view
+  sl issue view delete <name>               Delete a view`,
+	Example: `  sl issue view save my-api-work --status open --label component:api --all --sort priority,-updated
+  sl issue view save triage --team --status open --type bug --json
+  sl issue list @my-api-work
+  sl issue list @my-api-work --status in_progress`,
+}
+
+var issueViewSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save a view",
+	Long: `Save the given list flags as a named view, replacing any view of that name
+in the same scope. Only the flags given are stored.`,
+	Example: `  sl issue view save my-api-work --status open --label component:api --all
+  sl issue view save stale --team --all -q 'updated:<30d' --sort updated
+  sl issue view save roadmap --type epic --tree --description "Epics by spec"`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueViewSave,
+}
+
+var issueViewListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List saved views",
+	Example: `  sl issue view list`,
+	Args:    cobra.NoArgs,
+	RunE:    runIssueViewList,
+}
+
+var issueViewRunCmd = &cobra.Command{
+	Use:   "run <name>",
+	Short: "Run a view",
+	Long:  `Run a saved view. Same as 'sl issue list @<name>'.`,
+	Example: `  sl issue view run my-api-work
+  sl issue list @my-api-work`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueViewRun,
+}
+
+var issueViewDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a view",
+	Long: `Delete a saved view. The private view is deleted when both a private and a
+team view have the name; use --team to delete the team view.`,
+	Example: `  sl issue view delete my-api-work
+  sl issue view delete triage --team`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIssueViewDelete,
+}
+
+func init() {
+	VarIssueCmd.AddCommand(issueViewCmd)
+	issueViewCmd.AddCommand(issueViewSaveCmd)
+	issueViewCmd.AddCommand(issueViewListCmd)
+	issueViewCmd.AddCommand(issueViewRunCmd)
+	issueViewCmd.AddCommand(issueViewDeleteCmd)
+
+	issueListCmd.Flags().StringVar(&issueSortFlag, "sort", "", "Sort by fields, e.g. priority,-updated")
+
+	issueViewSaveCmd.Flags().StringVar(&issueStatusFlag, "status", "", "Filter by status (e.g. open, in_progress, closed)")
+	issueViewSaveCmd.Flags().StringVar(&issueTypeFlag, "type", "", "Filter by type")
+	issueViewSaveCmd.Flags().IntVarP(&issuePriorityFlag, "priority", "p", -1, "Filter by priority")
+	issueViewSaveCmd.Flags().StringVar(&issueLabelsFlag, "label", "", "Filter by labels (comma-separated, all must match)")
+	issueViewSaveCmd.Flags().StringVar(&issueSpecFlag, "spec", "", "Filter by spec context")
+	issueViewSaveCmd.Flags().BoolVar(&issueAllFlag, "all", false, "List across all specs")
+	issueViewSaveCmd.Flags().BoolVar(&issueBlockedFlag, "blocked", false, "Show only blocked issues")
+	issueViewSaveCmd.Flags().BoolVar(&issueOrphanedFlag, "orphaned", false, "Show only non-epic issues without a parent")
+	issueViewSaveCmd.Flags().BoolVar(&issueIncludeArchivedFlag, "include-archived", false, "Also list archived issues")
+	issueViewSaveCmd.Flags().StringArrayVar(&issueFieldFlag, "field", []string{}, "Filter by custom field value, name=value (can be repeated)")
+	issueViewSaveCmd.Flags().StringVarP(&issueQueryFlag, "query", "q", "", "Filter with a search query")
+	issueViewSaveCmd.Flags().StringVar(&issueSortFlag, "sort", "", "Sort by fields, e.g. priority,-updated")
+	issueViewSaveCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+	issueViewSaveCmd.Flags().BoolVar(&issueTreeFlag, "tree", false, "Show parent-child hierarchy tree")
+	issueViewSaveCmd.Flags().BoolVar(&issueGraphFlag, "graph", false, "Show blocking dependency graph")
+	issueViewSaveCmd.Flags().StringVar(&issueViewDescriptionFlag, "description", "", "What the view is for")
+	issueViewSaveCmd.Flags().BoolVar(&issueViewTeamFlag, "team", false, "Save as a team view in specledger.yaml")
+
+	issueViewListCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+	issueViewDeleteCmd.Flags().BoolVar(&issueViewTeamFlag, "team", false, "Delete the team view")
+}
+
+// loadIssueViews returns the team and private views of the project at root
+func loadIssueViews(root string) (*metadata.ProjectMetadata, *config.PersonalConfig, error) {
+	meta, err := metadata.LoadFromProject(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load metadata: %w", err)
+	}
+	personal, err := config.LoadPersonal(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return meta, personal, nil
+}
+
+// listIssueViews returns all saved views ordered by name, private first
+func listIssueViews(meta *metadata.ProjectMetadata, personal *config.PersonalConfig) []savedView {
+	var views []savedView
+	// An entry written as "name:" with nothing under it lists everything
+	for name, view := range personal.IssueViews {
+		if view == nil {
+			view = &config.IssueView{}
+		}
+		views = append(views, savedView{Name: name, Scope: viewScopePrivate, View: view})
+	}
+	for name, view := range meta.TaskTracker.Views {
+		if view == nil {
+			view = &config.IssueView{}
+		}
+		_, shadowed := personal.IssueViews[name]
+		views = append(views, savedView{Name: name, Scope: viewScopeTeam, Shadowed: shadowed, View: view})
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		if views[i].Name != views[j].Name {
+			return views[i].Name < views[j].Name
+		}
+		return views[i].Scope == viewScopePrivate
+	})
+	return views
+}
+
+// findIssueView looks a view up by name, private views first
+func findIssueView(name string) (*config.IssueView, error) {
+	root, err := metadata.FindProjectRoot()
+	if err != nil {
+		return nil, err
+	}
+	meta, personal, err := loadIssueViews(root)
+	if err != nil {
+		return nil, err
+	}
+	view, ok := personal.IssueViews[name]
+	if !ok {
+		view, ok = meta.TaskTracker.Views[name]
+	}
+	if ok {
+		if view == nil {
+			view = &config.IssueView{}
+		}
+		return view, nil
+	}
+
+	var names []string
+	for _, v := range listIssueViews(meta, personal) {
+		if !v.Shadowed {
+			names = append(names, v.Name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("unknown view %q (no saved views; see 'sl issue view save --help')", name)
+	}
+	return nil, fmt.Errorf("unknown view %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// useIssueView applies the view named by an @name argument of 'sl issue
+// list' to the list flags
+func useIssueView(cmd *cobra.Command, arg string) error {
+	name, ok := strings.CutPrefix(arg, "@")
+	if !ok {
+		return fmt.Errorf("unexpected argument %q (run a saved view with @<name>)", arg)
+	}
+	view, err := findIssueView(name)
+	if err != nil {
+		return err
+	}
+	return applyIssueView(cmd, view)
+}
+
+// applyIssueView sets the list flags from a view. Flags given on the command
+// line keep their values; every other flag is reset, so settings shared with
+// other commands cannot leak into the view.
+func applyIssueView(cmd *cobra.Command, view *config.IssueView) error {
+	set := func(flag string, apply func()) {
+		if !cmd.Flags().Changed(flag) {
+			apply()
+		}
+	}
+	set("status", func() { issueStatusFlag = view.Status })
+	set("type", func() { issueTypeFlag = view.Type })
+	set("priority", func() {
+		issuePriorityFlag = -1
+		if view.Priority != nil {
+			issuePriorityFlag = *view.Priority
+		}
+	})
+	set("label", func() { issueLabelsFlag = strings.Join(view.Labels, ",") })
+	set("spec", func() { issueSpecFlag = view.Spec })
+	set("all", func() { issueAllFlag = view.All })
+	set("blocked", func() { issueBlockedFlag = view.Blocked })
+	set("orphaned", func() { issueOrphanedFlag = view.Orphaned })
+	set("include-archived", func() { issueIncludeArchivedFlag = view.IncludeArchived })
+	set("field", func() { issueFieldFlag = fieldAssignments(view.Fields) })
+	set("query", func() { issueQueryFlag = view.Query })
+	set("sort", func() { issueSortFlag = view.Sort })
+
+	if cmd.Flags().Changed("json") || cmd.Flags().Changed("tree") || cmd.Flags().Changed("graph") {
+		return nil
+	}
+	format := view.Format
+	if format == "" {
+		format = "table"
+	}
+	if !slices.Contains(viewFormats, format) {
+		return fmt.Errorf("invalid view format %q (use %s)", view.Format, strings.Join(viewFormats, ", "))
+	}
+	issueJSONFlag = format == "json"
+	issueTreeFlag = format == "tree"
+	issueGraphFlag = format == "graph"
+	return nil
+}
+
+// viewFromFlags builds a view from the list flags given on the command line
+func viewFromFlags(cmd *cobra.Command) (*config.IssueView, error) {
+	changed := cmd.Flags().Changed
+	view := &config.IssueView{Description: issueViewDescriptionFlag}
+	if changed("status") {
+		view.Status = issueStatusFlag
+	}
+	if changed("type") {
+		view.Type = issueTypeFlag
+	}
+	if changed("priority") {
+		priority := issuePriorityFlag
+		view.Priority = &priority
+	}
+	if changed("label") {
+		for _, l := range strings.Split(issueLabelsFlag, ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				view.Labels = append(view.Labels, l)
+			}
+		}
+	}
+	if changed("spec") {
+		view.Spec = issueSpecFlag
+	}
+	view.All = issueAllFlag
+	view.Blocked = issueBlockedFlag
+	view.Orphaned = issueOrphanedFlag
+	view.IncludeArchived = issueIncludeArchivedFlag
+
+	fields, err := parseFieldFlags(issueFieldFlag)
+	if err != nil {
+		return nil, err
+	}
+	view.Fields = fields
+	if issueQueryFlag != "" {
+		if _, err := issues.ParseQuery(issueQueryFlag, issues.WorkflowFunc(getArtifactPath())); err != nil {
+			return nil, err
+		}
+		view.Query = issueQueryFlag
+	}
+	if issueSortFlag != "" {
+		if _, err := issues.ParseSortKeys(issueSortFlag); err != nil {
+			return nil, err
+		}
+		view.Sort = issueSortFlag
+	}
+
+	var formats []string
+	for _, f := range []struct {
+		set  bool
+		name string
+	}{{issueJSONFlag, "json"}, {issueTreeFlag, "tree"}, {issueGraphFlag, "graph"}} {
+		if f.set {
+			formats = append(formats, f.name)
+		}
+	}
+	if len(formats) > 1 {
+		return nil, fmt.Errorf("choose one output format, got --%s", strings.Join(formats, " and --"))
+	}
+	if len(formats) == 1 {
+		view.Format = formats[0]
+	}
+	return view, nil
+}
+
+// fieldAssignments formats custom field values as sorted name=value pairs
+func fieldAssignments(fields map[string]string) []string {
+	assignments := make([]string, 0, len(fields))
+	for name, value := range fields {
+		assignments = append(assignments, name+"="+value)
+	}
+	sort.Strings(assignments)
+	return assignments
+}
+
+// describeView returns the list flags equivalent to a view
+func describeView(view *config.IssueView) string {
+	var parts []string
+	add := func(flag, value string) {
+		if strings.ContainsAny(value, " \t\"'") {
+			value = strconv.Quote(value)
+		}
+		parts = append(parts, flag+" "+value)
+	}
+	if view.Status != "" {
+		add("--status", view.Status)
+	}
+	if view.Type != "" {
+		add("--type", view.Type)
+	}
+	if view.Priority != nil {
+		add("--priority", strconv.Itoa(*view.Priority))
+	}
+	if len(view.Labels) > 0 {
+		add("--label", strings.Join(view.Labels, ","))
+	}
+	if view.Spec != "" {
+		add("--spec", view.Spec)
+	}
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{{view.All, "--all"}, {view.Blocked, "--blocked"}, {view.Orphaned, "--orphaned"}, {view.IncludeArchived, "--include-archived"}} {
+		if flag.set {
+			parts = append(parts, flag.name)
+		}
+	}
+	for _, assignment := range fieldAssignments(view.Fields) {
+		add("--field", assignment)
+	}
+	if view.Query != "" {
+		add("-q", view.Query)
+	}
+	if view.Sort != "" {
+		add("--sort", view.Sort)
+	}
+	if view.Format != "" && view.Format != "table" {
+		parts = append(parts, "--"+view.Format)
+	}
+	return strings.Join(parts, " ")
+}
+
+func runIssueViewSave(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], "@")
+	if !viewNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid view name %q (use letters, digits, - and _)", name)
+	}
+	view, err := viewFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	root, err := metadata.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+	meta, personal, err := loadIssueViews(root)
+	if err != nil {
+		return err
+	}
+
+	var existed bool
+	scope := viewScopePrivate
+	if issueViewTeamFlag {
+		scope = viewScopeTeam
+		if meta.TaskTracker.Views == nil {
+			meta.TaskTracker.Views = make(map[string]*config.IssueView)
+		}
+		_, existed = meta.TaskTracker.Views[name]
+		meta.TaskTracker.Views[name] = view
+		if err := metadata.SaveToProject(meta, root); err != nil {
+			return fmt.Errorf("failed to save metadata: %w", err)
+		}
+	} else {
+		if personal.IssueViews == nil {
+			personal.IssueViews = make(map[string]*config.IssueView)
+		}
+		_, existed = personal.IssueViews[name]
+		personal.IssueViews[name] = view
+		if err := personal.Save(root); err != nil {
+			return err
+		}
+	}
+
+	verb := "Saved"
+	if existed {
+		verb = "Updated"
+	}
+	ui.PrintSuccess(fmt.Sprintf("%s %s view '%s'", verb, scope, name))
+	if desc := describeView(view); desc != "" {
+		fmt.Printf("  sl issue list %s\n", desc)
+	}
+	fmt.Printf("Run it with: sl issue list @%s\n", name)
+	if scope == viewScopeTeam {
+		if _, ok := personal.IssueViews[name]; ok {
+			fmt.Printf("%s Your private view '%s' still takes precedence\n", ui.WarningIcon(), name)
+		}
+	}
+	return nil
+}
+
+func runIssueViewList(cmd *cobra.Command, args []string) error {
+	root, err := metadata.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+	meta, personal, err := loadIssueViews(root)
+	if err != nil {
+		return err
+	}
+	views := listIssueViews(meta, personal)
+
+	if issueJSONFlag {
+		if views == nil {
+			views = []savedView{}
+		}
+		data, _ := json.MarshalIndent(views, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(views) == 0 {
+		fmt.Println("No saved views. Save one with 'sl issue view save <name> [list flags]'.")
+		return nil
+	}
+
+	for _, v := range views {
+		scope := v.Scope
+		if v.Shadowed {
+			scope += ", hidden by private view"
+		}
+		fmt.Printf("  @%s %s\n", v.Name, ui.Gray("("+scope+")"))
+		if v.View.Description != "" {
+			fmt.Printf("    %s\n", v.View.Description)
+		}
+		desc := describeView(v.View)
+		if desc == "" {
+			desc = "(current spec, no filters)"
+		}
+		fmt.Printf("    %s\n", ui.Gray(desc))
+	}
+	return nil
+}
+
+func runIssueViewRun(cmd *cobra.Command, args []string) error {
+	return runIssueList(cmd, []string{"@" + strings.TrimPrefix(args[0], "@")})
+}
+
+func runIssueViewDelete(cmd *cobra.Command, args []string) error {
+	name := strings.TrimPrefix(args[0], "@")
+	root, err := metadata.FindProjectRoot()
+	if err != nil {
+		return err
+	}
+	meta, personal, err := loadIssueViews(root)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := personal.IssueViews[name]; ok && !issueViewTeamFlag {
+		delete(personal.IssueViews, name)
+		if err := personal.Save(root); err != nil {
+			return err
+		}
+		ui.PrintSuccess(fmt.Sprintf("Deleted private view '%s'", name))
+		return nil
+	}
+	if _, ok := meta.TaskTracker.Views[name]; ok {
+		delete(meta.TaskTracker.Views, name)
+		if err := metadata.SaveToProject(meta, root); err != nil {
+			return fmt.Errorf("failed to save metadata: %w", err)
+		}
+		ui.PrintSuccess(fmt.Sprintf("Deleted team view '%s'", name))
+		return nil
+	}
+	if issueViewTeamFlag {
+		return fmt.Errorf("unknown team view %q", name)
+	}
+	return fmt.Errorf("unknown view %q", name)
+}
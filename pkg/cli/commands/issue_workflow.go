@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+// workflowWarning reports an invalid workflow configuration once per run
+var workflowWarning sync.Once
+
+// issueWorkflowCmd shows the statuses and transition rules in effect
+var issueWorkflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "Show the issue statuses and transition rules",
+	Long: `Show the issue workflow: its statuses, their categories and the statuses
+each one may move to.
+
+Projects declare a workflow under task_tracker.workflow in specledger.yaml:
+
+  task_tracker:
+    workflow:
+      statuses:
+        - {name: open, category: todo}
+        - {name: in_progress, category: doing}
+        - {name: blocked, category: doing, waiting: true}
+        - {name: review, category: doing, waiting: true}
+        - {name: closed, category: done}
+      transitions:
+        open: [in_progress, blocked]
+        in_progress: [review, blocked, open]
+        blocked: [open, in_progress]
+        review: [closed, in_progress]
+        closed: [open]
+      require_complete_dod: true
+
+Categories are todo, doing and done. Done statuses resolve blockers and set
+closed_at; 'sl issue close' moves to the done status. Waiting statuses are
+left out of 'sl issue ready'. A status without a transitions entry may move
+to any status. With require_complete_dod, no issue moves to a done status
+while its Definition of Done has unchecked items, even with --force.
+
+Without a workflow, issues use open (todo), in_progress (doing) and closed
+(done) with no transition rules.`,
+	RunE: runIssueWorkflow,
+}
+
+func init() {
+	issues.WorkflowFunc = configuredWorkflow
+
+	VarIssueCmd.AddCommand(issueWorkflowCmd)
+	issueWorkflowCmd.Flags().BoolVar(&issueJSONFlag, "json", false, "Output as JSON")
+}
+
+// configuredWorkflow returns task_tracker.workflow from the specledger.yaml of
+// the project containing basePath, or the default workflow if none is
+// declared. An invalid workflow is reported and ignored.
+func configuredWorkflow(basePath string) *issues.Workflow {
+	root, err := metadata.FindProjectRootFrom(basePath)
+	if err != nil {
+		return issues.DefaultWorkflow()
+	}
+	meta, err := metadata.LoadFromProject(root)
+	if err != nil || meta.TaskTracker.Workflow == nil {
+		return issues.DefaultWorkflow()
+	}
+	workflow, err := workflowFromConfig(meta.TaskTracker.Workflow)
+	if err != nil {
+		workflowWarning.Do(func() {
+			fmt.Fprintf(os.Stderr, "%s Ignoring task_tracker.workflow in specledger.yaml: %v\n", ui.WarningIcon(), err)
+		})
+		return issues.DefaultWorkflow()
+	}
+	return workflow
+}
+
+// workflowFromConfig converts and checks a workflow declared in specledger.yaml
+func workflowFromConfig(cfg *metadata.WorkflowConfig) (*issues.Workflow, error) {
+	workflow := &issues.Workflow{RequireCompleteDoD: cfg.RequireCompleteDoD}
+	for _, s := range cfg.Statuses {
+		workflow.Statuses = append(workflow.Statuses, issues.WorkflowStatus{
+			Name:     issues.IssueStatus(s.Name),
+			Category: issues.StatusCategory(s.Category),
+			Waiting:  s.Waiting,
+		})
+	}
+	if len(cfg.Transitions) > 0 {
+		workflow.Transitions = make(map[issues.IssueStatus][]issues.IssueStatus, len(cfg.Transitions))
+		for from, targets := range cfg.Transitions {
+			// An empty list makes a final status, so keep the entry
+			next := []issues.IssueStatus{}
+			for _, to := range targets {
+				next = append(next, issues.IssueStatus(to))
+			}
+			workflow.Transitions[issues.IssueStatus(from)] = next
+		}
+	}
+	if err := workflow.Check(); err != nil {
+		return nil, err
+	}
+	return workflow, nil
+}
+
+// treeRenderOptions returns the default tree options with the project workflow
+func treeRenderOptions() issues.TreeRenderOptions {
+	opts := issues.DefaultTreeRenderOptions()
+	opts.Workflow = issues.WorkflowFunc(getArtifactPath())
+	return opts
+}
+
+// joinIssueStatuses formats statuses as a comma-separated list
+func joinIssueStatuses(statuses []issues.IssueStatus) string {
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
+
+func runIssueWorkflow(cmd *cobra.Command, args []string) error {
+	workflow := issues.WorkflowFunc(getArtifactPath())
+
+	if issueJSONFlag {
+		type statusJSON struct {
+			Name        issues.IssueStatus    `json:"name"`
+			Category    issues.StatusCategory `json:"category"`
+			Waiting     bool                  `json:"waiting,omitempty"`
+			Transitions []issues.IssueStatus  `json:"transitions"`
+		}
+		out := struct {
+			Statuses           []statusJSON `json:"statuses"`
+			RequireCompleteDoD bool         `json:"require_complete_dod"`
+		}{RequireCompleteDoD: workflow.RequireCompleteDoD}
+		for _, s := range workflow.Statuses {
+			out.Statuses = append(out.Statuses, statusJSON{
+				Name:        s.Name,
+				Category:    s.Category,
+				Waiting:     s.Waiting,
+				Transitions: workflow.AllowedTransitions(s.Name),
+			})
+		}
+		data, _ := json.MarshalIndent(out, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	ui.PrintSection("Issue Workflow")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tCATEGORY\tREADY\tMAY MOVE TO")
+	for _, s := range workflow.Statuses {
+		ready := "yes"
+		if s.Waiting || s.Category == issues.CategoryDone {
+			ready = "no"
+		}
+		next := joinIssueStatuses(workflow.AllowedTransitions(s.Name))
+		if next == "" {
+			next = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", s.Name, s.Category, ready, next)
+	}
+	w.Flush()
+
+	fmt.Println()
+	if workflow.RequireCompleteDoD {
+		fmt.Println("Closing requires a complete Definition of Done.")
+	} else {
+		fmt.Println("Closing with unchecked Definition of Done items needs --force.")
+	}
+	return nil
+}
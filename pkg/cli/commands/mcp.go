@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/auth"
+	"github.com/specledger/specledger/pkg/cli/comment"
+	"github.com/specledger/specledger/pkg/cli/mcp"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/spf13/cobra"
+)
+
+var mcpRegisterAgentsFlag []string
+
+// VarMCPCmd is the mcp command group
+var VarMCPCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Model Context Protocol server for coding agents",
+	Long: `Expose specs, issues, dependency artifacts and review comments to coding
+agents over the Model Context Protocol (MCP).
+
+Commands:
+  sl mcp serve       Run the MCP server over stdio
+  sl mcp register    Add the server to the agents' project MCP config`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+}
+
+var mcpServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the MCP server over stdio",
+	Long: `Run a Model Context Protocol server on stdin/stdout for the project in the
+current directory. Agents start it themselves from their MCP config; see
+'sl mcp register'.
+
+Tools:
+  issue_list, issue_ready, issue_show, issue_create, issue_update,
+  issue_close, issue_link     Issue tracking (same store as 'sl issue')
+  spec_info                   Feature context of the current branch
+  deps_resolve                Dependency artifact lookup (<alias>:<artifact>)
+  comment_list, comment_reply,
+  comment_resolve             Review comments (requires 'sl auth login')
+
+Resources:
+  specledger://specs/<spec>/spec.md, plan.md and tasks.md`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runMCPServe,
+}
+
+var mcpRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Add the MCP server to the agents' project config",
+	Long: `Add 'sl mcp serve' to the project MCP config of each agent that is set up in
+this project (its config directory exists) or named with --agent.
+
+This is done automatically by 'sl init'.`,
+	Example: `  sl mcp register
+  sl mcp register --agent claude --agent codex`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		changed, err := mcp.Register(".", mcpRegisterAgentsFlag)
+		if err != nil {
+			return err
+		}
+		if len(changed) == 0 {
+			fmt.Println("MCP server already registered (or no agents configured).")
+			return nil
+		}
+		fmt.Printf("%s Registered MCP server in %s\n", ui.Checkmark(), strings.Join(changed, ", "))
+		return nil
+	},
+}
+
+func init() {
+	mcpRegisterCmd.Flags().StringSliceVar(&mcpRegisterAgentsFlag, "agent", nil, "Agent to register with even if not set up yet (repeatable)")
+
+	VarMCPCmd.AddCommand(mcpServeCmd)
+	VarMCPCmd.AddCommand(mcpRegisterCmd)
+}
+
+func runMCPServe(cmd *cobra.Command, args []string) error {
+	repoRoot, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	server := mcp.NewServer(mcp.Options{
+		RepoRoot:     repoRoot,
+		ArtifactPath: getArtifactPath(),
+		CommentClient: func() (*comment.Client, error) {
+			accessToken, err := auth.GetValidAccessToken()
+			if err != nil {
+				return nil, fmt.Errorf("authentication required: %w", err)
+			}
+			return comment.NewClient(accessToken), nil
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// stdout carries the protocol; everything else must go to stderr
+	return server.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// registerMCPServer adds 'sl mcp serve' to the MCP config of the selected and
+// already configured agents. This is a non-fatal operation.
+func registerMCPServer(projectPath, selectedAgents string) {
+	var agentNames []string
+	if selectedAgents != "" && selectedAgents != "None" {
+		agentNames = strings.Split(selectedAgents, ",")
+	}
+
+	changed, err := mcp.Register(projectPath, agentNames)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Could not register MCP server: %v", err))
+	}
+	if len(changed) > 0 {
+		fmt.Printf("%s Registered MCP server in %s\n", ui.Checkmark(), strings.Join(changed, ", "))
+	}
+}
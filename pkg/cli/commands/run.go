@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/specledger/specledger/internal/agent"
+	"github.com/specledger/specledger/pkg/cli/config"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/launcher"
+	"github.com/specledger/specledger/pkg/cli/orchestrator"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runAgentsFlag        int
+	runAgentFlag         string
+	runSpecFlag          string
+	runBaseFlag          string
+	runWorktreeDirFlag   string
+	runLeaseFlag         time.Duration
+	runTimeoutFlag       time.Duration
+	runLimitFlag         int
+	runKeepWorktreesFlag bool
+	runJSONFlag          bool
+)
+
+// VarRunCmd runs coding agents over the ready issue queue
+var VarRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run coding agents in parallel over ready issues",
+	Long: `Work through the ready issues of a spec with one or more coding agents.
+
+Each issue gets its own git worktree on the branch sl-run/<spec>/<issue-id>,
+started from the current branch. The issue is claimed (see 'sl issue claim'),
+an agent is launched in the worktree with a prompt built from the issue's
+description, design, acceptance criteria and definition of done, and the issue
+is closed as soon as the agent commits. Issues blocked by open issues wait until
+their blockers are done; the blockers' branches are merged into the dependent
+issue's worktree.
+
+Agents run headless with tool permissions granted, so they can work without
+anyone at the terminal. Agents that exit without committing, or that are still
+running after --timeout, release their issue back to ready. Their worktree and
+log are kept for inspection.
+
+Use --agent stub to try the flow without an AI agent: the stub writes the prompt
+to .sl-stub/<issue-id>.md and commits it.`,
+	Example: `  sl run --agents 3
+  sl run --agents 2 --agent opencode --limit 4
+  sl run --agent stub --keep-worktrees
+  sl run --agents 2 --timeout 30m`,
+	Args: cobra.NoArgs,
+	RunE: runRun,
+}
+
+func init() {
+	VarRunCmd.Flags().IntVar(&runAgentsFlag, "agents", 1, "Number of agents to run in parallel")
+	VarRunCmd.Flags().StringVar(&runAgentFlag, "agent", "", "Agent to launch (claude, opencode, github-copilot, codex, stub; default from config)")
+	VarRunCmd.Flags().StringVar(&runSpecFlag, "spec", "", "Spec context (auto-detected from branch if not specified)")
+	VarRunCmd.Flags().StringVar(&runBaseFlag, "base", "", "Branch or commit worktrees start from (default: current branch)")
+	VarRunCmd.Flags().StringVar(&runWorktreeDirFlag, "worktree-dir", "", "Directory for agent worktrees (default: ../<repo>.worktrees)")
+	VarRunCmd.Flags().DurationVar(&runLeaseFlag, "lease", issues.DefaultLeaseDuration, "Claim lease, renewed while the agent runs")
+	VarRunCmd.Flags().DurationVar(&runTimeoutFlag, "timeout", 0, "Stop an agent that runs longer than this and release its issue (0 = no limit)")
+	VarRunCmd.Flags().IntVar(&runLimitFlag, "limit", 0, "Maximum number of issues to start (0 = all ready issues)")
+	VarRunCmd.Flags().BoolVar(&runKeepWorktreesFlag, "keep-worktrees", false, "Keep worktrees of completed issues")
+	VarRunCmd.Flags().BoolVar(&runJSONFlag, "json", false, "Output results as JSON")
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	repoDir, err := filepath.Abs(".")
+	if err != nil {
+		return fmt.Errorf("failed to resolve working directory: %w", err)
+	}
+
+	specContext := runSpecFlag
+	if specContext == "" {
+		specContext, err = issues.NewContextDetector(repoDir).DetectSpecContext()
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	store, err := issues.NewStore(issues.StoreOptions{
+		BasePath:    filepath.Join(repoDir, getArtifactPath()),
+		SpecContext: specContext,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+
+	runAgent, err := resolveRunAgent(runAgentFlag)
+	if err != nil {
+		return err
+	}
+
+	base := runBaseFlag
+	if base == "" {
+		base, err = cligit.GetCurrentBranch(repoDir)
+		if err != nil {
+			return fmt.Errorf("failed to determine base branch: %w", err)
+		}
+	}
+
+	worktreeDir := runWorktreeDirFlag
+	if worktreeDir == "" {
+		worktreeDir = filepath.Join(filepath.Dir(repoDir), filepath.Base(repoDir)+".worktrees")
+	}
+	worktreeDir, err = filepath.Abs(worktreeDir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve worktree directory: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if !runJSONFlag {
+		ui.PrintHeader("SpecLedger Run", fmt.Sprintf("%s · %d × %s", specContext, runAgentsFlag, runAgent.Name()), 70)
+		fmt.Printf("  Base:      %s\n", base)
+		fmt.Printf("  Worktrees: %s\n\n", worktreeDir)
+	}
+
+	var mu sync.Mutex
+	results, runErr := orchestrator.Run(ctx, orchestrator.Options{
+		RepoDir:       repoDir,
+		Store:         store,
+		SpecContext:   specContext,
+		Agent:         runAgent,
+		Agents:        runAgentsFlag,
+		BaseRef:       base,
+		WorktreeDir:   worktreeDir,
+		Lease:         runLeaseFlag,
+		Timeout:       runTimeoutFlag,
+		Limit:         runLimitFlag,
+		KeepWorktrees: runKeepWorktreesFlag,
+		Progress: func(u orchestrator.Update) {
+			if runJSONFlag {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			printRunUpdate(u)
+		},
+	})
+
+	if runJSONFlag {
+		if results == nil {
+			results = []orchestrator.Result{}
+		}
+		data, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(data))
+		return runErr
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No ready issues to run.")
+		return runErr
+	}
+
+	ui.PrintSection("Summary")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tAGENT\tRESULT\tDETAIL")
+	closed := 0
+	for _, r := range results {
+		if r.Closed {
+			closed++
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", r.IssueID, r.Slot, ui.Green("closed"), r.Branch)
+		} else {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s (log: %s)\n", r.IssueID, r.Slot, ui.Yellow("released"), r.Error, r.LogPath)
+		}
+	}
+	w.Flush()
+	fmt.Printf("\n%d of %d issues closed\n", closed, len(results))
+	if closed > 0 {
+		fmt.Println(ui.Gray("Review and merge the sl-run/ branches into your spec branch."))
+	}
+
+	return runErr
+}
+
+func printRunUpdate(u orchestrator.Update) {
+	prefix := ui.Gray(fmt.Sprintf("[agent-%d]", u.Slot))
+	switch u.Stage {
+	case orchestrator.StageStarted:
+		fmt.Printf("%s %s started: %s\n", prefix, ui.Cyan(u.IssueID), u.Message)
+	case orchestrator.StageCommitted:
+		fmt.Printf("%s %s committed %s\n", prefix, ui.Cyan(u.IssueID), u.Message)
+	case orchestrator.StageFinished:
+		fmt.Printf("%s %s %s %s\n", prefix, ui.Checkmark(), ui.Cyan(u.IssueID), u.Message)
+	case orchestrator.StageFailed:
+		fmt.Printf("%s %s %s %s\n", prefix, ui.Crossmark(), ui.Cyan(u.IssueID), u.Message)
+	default:
+		fmt.Printf("%s %s %s %s\n", prefix, ui.WarningIcon(), u.IssueID, u.Message)
+	}
+}
+
+// resolveRunAgent returns the stub agent or a launcher-backed agent configured
+// the same way as 'sl code'.
+func resolveRunAgent(name string) (orchestrator.Agent, error) {
+	if name == orchestrator.StubAgentName {
+		return &orchestrator.StubAgent{}, nil
+	}
+
+	if name == "" {
+		name = "claude"
+		if cfg, _ := config.Load(); cfg != nil && cfg.Agents != nil && cfg.Agents.Default != "" {
+			name = cfg.Agents.Default
+		}
+	}
+
+	ag, found := agent.Lookup(name)
+	if !found {
+		return nil, fmt.Errorf("unknown agent: %s\nValid agents: claude, opencode, github-copilot, codex, stub", name)
+	}
+	if err := launcher.NewAgentFromDefinition(ag).CheckInstalled(); err != nil {
+		return nil, err
+	}
+
+	return newRunLauncherAgent(ag), nil
+}
+
+// newRunLauncherAgent returns a launcher-backed agent that runs headless, with
+// the agent's configured arguments and env vars applied as in 'sl code'.
+func newRunLauncherAgent(ag agent.Agent) *orchestrator.LauncherAgent {
+	return &orchestrator.LauncherAgent{
+		Definition: ag,
+		Configure: func(l *launcher.AgentLauncher) {
+			flags := append([]string{}, ag.HeadlessArgs...)
+			settings := config.ResolveAgentSettings(ag.Command)
+			if settings != nil {
+				flags = append(flags, settings.Arguments...)
+			}
+			if ag.PromptFlag != "" {
+				flags = append(flags, ag.PromptFlag)
+			}
+			l.SetFlags(flags)
+			if settings == nil {
+				return
+			}
+
+			envVars := make(map[string]string)
+			if settings.APIKey != "" && ag.APIKeyEnvVar != "" {
+				envVars[ag.APIKeyEnvVar] = settings.APIKey
+			}
+			if settings.BaseURL != "" && ag.BaseURLEnvVar != "" {
+				envVars[ag.BaseURLEnvVar] = settings.BaseURL
+			}
+			if settings.Model != "" && ag.ModelEnvVar != "" {
+				envVars[ag.ModelEnvVar] = settings.Model
+			}
+			for k, v := range settings.EnvVars {
+				envVars[k] = v
+			}
+			l.SetEnv(envVars)
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/specledger/specledger/internal/agent"
+	"github.com/specledger/specledger/pkg/cli/launcher"
+)
+
+func TestRunLauncherAgentArgs(t *testing.T) {
+	// Isolate from the user's real config; codex gets project arguments
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, "specledger"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	projectConfig := `agents:
+  codex:
+    arguments:
+      - --model
+      - o3
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "specledger", "specledger.yaml"), []byte(projectConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(tmpDir)
+
+	const prompt = "Work on SL-abc123"
+	tests := []struct {
+		agent string
+		want  []string
+	}{
+		{"claude", []string{"-p", "--dangerously-skip-permissions", prompt}},
+		{"opencode", []string{"run", prompt}},
+		{"github-copilot", []string{"--allow-all-tools", "-p", prompt}},
+		{"codex", []string{"exec", "--dangerously-bypass-approvals-and-sandbox", "--model", "o3", prompt}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent, func(t *testing.T) {
+			ag, found := agent.Lookup(tt.agent)
+			if !found {
+				t.Fatalf("agent %q not in registry", tt.agent)
+			}
+			runAgent := newRunLauncherAgent(ag)
+			l := launcher.NewLauncherForAgent(ag, tmpDir)
+			runAgent.Configure(l)
+
+			if got := l.Args(prompt); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("argv = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
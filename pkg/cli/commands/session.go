@@ -9,6 +9,7 @@ import (
 
 	"github.com/specledger/specledger/pkg/cli/auth"
 	"github.com/specledger/specledger/pkg/cli/session"
+	"github.com/specledger/specledger/pkg/issues"
 	"github.com/spf13/cobra"
 )
 
@@ -85,7 +86,8 @@ var VarSessionGetCmd = &cobra.Command{
 You can look up a session by:
   - Session ID (UUID)
   - Commit hash (full or partial, e.g., "abc1234" or full 40-char hash)
-  - Task ID (e.g., SL-42)
+  - Task ID (e.g., SL-42), also matching sessions captured for the commits
+    linked to the issue (see 'sl issue sync-commits')
 
 Examples:
   sl session get abc1234               # Get by partial commit hash
@@ -298,6 +300,15 @@ func runSessionGet(cmd *cobra.Command, args []string) error {
 		sessionMeta, _ = metaClient.GetByTaskID(accessToken, projectID, identifier)
 	}
 
+	// Try the commits linked to the task, newest first
+	if sessionMeta == nil {
+		if issue, _, err := issues.GetIssueAcrossSpecs(identifier, getArtifactPath()); err == nil {
+			for i := len(issue.Commits) - 1; i >= 0 && sessionMeta == nil; i-- {
+				sessionMeta, _ = metaClient.GetByCommitHash(accessToken, projectID, issue.Commits[i])
+			}
+		}
+	}
+
 	if sessionMeta == nil {
 		return fmt.Errorf("session not found: %s", identifier)
 	}
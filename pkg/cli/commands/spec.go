@@ -13,11 +13,13 @@ Commands:
   info        Get feature paths and prerequisite validation
   create      Create a new feature branch and spec directory
   setup-plan  Copy plan template to feature directory
+  trace       Trace spec requirements to issues and tests
 
 Examples:
   sl spec info --json                    # Get feature info as JSON
   sl spec create --number 600 --short-name "test-feature"  # Create new feature
-  sl spec setup-plan                     # Setup plan.md from template`,
+  sl spec setup-plan                     # Setup plan.md from template
+  sl spec trace --strict                 # Fail if a requirement has no task`,
 }
 
 func NewSpecCmd() *cobra.Command {
@@ -0,0 +1,223 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/specledger/specledger/pkg/cli/spec"
+	"github.com/specledger/specledger/pkg/cli/ui"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/spf13/cobra"
+)
+
+var specTraceCmd = &cobra.Command{
+	Use:   "trace",
+	Short: "Trace spec requirements to issues and tests",
+	Long: `Build a requirements traceability matrix for the current spec.
+
+Functional requirements (- **FR-001**: ...) and success criteria
+(- **SC-001**: ...) are read from spec.md and joined with the
+requirement:<id> labels of the spec's issues, archived ones included.
+With --tests, test files in the repository (*_test.go, *.test.*, *.spec.*,
+test_*.py, *_test.py, *_spec.rb) are searched for the IDs as well.
+
+The report lists, per requirement, its tasks and how many are open and
+closed, then the functional requirements without a task and the tasks
+labeled with a requirement spec.md does not declare. Success criteria are
+shown but not required to have tasks.
+
+With --strict the command fails when there are untasked functional
+requirements or unknown requirement labels, for use in CI.`,
+	Example: `  sl spec trace
+  sl spec trace --tests --format markdown > trace.md
+  sl spec trace --spec 010-my-feature --format json
+  sl spec trace --strict`,
+	Args: cobra.NoArgs,
+	RunE: runSpecTrace,
+}
+
+func init() {
+	VarSpecCmd.AddCommand(specTraceCmd)
+
+	specTraceCmd.Flags().String("spec", "", "Override feature spec name (bypasses detection)")
+	specTraceCmd.Flags().String("format", "table", "Output format: table, markdown or json")
+	specTraceCmd.Flags().Bool("tests", false, "Also search test files for requirement IDs")
+	specTraceCmd.Flags().Bool("strict", false, "Exit non-zero on untasked requirements or unknown requirement labels")
+}
+
+func runSpecTrace(cmd *cobra.Command, args []string) error {
+	specOverride, _ := cmd.Flags().GetString("spec")
+	format, _ := cmd.Flags().GetString("format")
+	withTests, _ := cmd.Flags().GetBool("tests")
+	strict, _ := cmd.Flags().GetBool("strict")
+
+	if format != "table" && format != "markdown" && format != "json" {
+		return fmt.Errorf("invalid format %q (use table, markdown or json)", format)
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+	ctx, err := spec.DetectFeatureContextWithOptions(workDir, spec.DetectionOptions{SpecOverride: specOverride})
+	if err != nil {
+		return fmt.Errorf("failed to detect feature context: %w", err)
+	}
+
+	content, err := os.ReadFile(ctx.SpecFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("spec.md not found at: %s", ctx.SpecFile)
+		}
+		return fmt.Errorf("failed to read spec file: %w", err)
+	}
+
+	artifactPath := getArtifactPath()
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: artifactPath, SpecContext: ctx.Branch})
+	if err != nil {
+		return fmt.Errorf("failed to create store: %w", err)
+	}
+	issueList, err := store.List(issues.ListFilter{IncludeArchived: true})
+	if err != nil {
+		return fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	report := spec.Trace(ctx.Branch, spec.ParseRequirements(string(content)), issueList, issues.WorkflowFunc(artifactPath))
+	if withTests {
+		if err := report.AddTestMentions(ctx.RepoRoot); err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "json":
+		data, _ := json.MarshalIndent(report, "", "  ")
+		fmt.Println(string(data))
+	case "markdown":
+		printTraceMarkdown(report)
+	default:
+		printTraceTable(report)
+	}
+
+	if strict && report.Problems() > 0 {
+		return fmt.Errorf("%d traceability problem(s) found (strict mode)", report.Problems())
+	}
+	return nil
+}
+
+// traceCoverage summarizes how many functional requirements have tasks
+func traceCoverage(report *spec.TraceReport) (tasked, functional int) {
+	for _, req := range report.Requirements {
+		if req.Functional() {
+			functional++
+			if len(req.Tasks) > 0 {
+				tasked++
+			}
+		}
+	}
+	return tasked, functional
+}
+
+func printTraceTable(report *spec.TraceReport) {
+	if len(report.Requirements) == 0 {
+		fmt.Printf("No requirements (- **FR-###**: ...) found in the spec of %s.\n", report.SpecContext)
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		header := "REQUIREMENT\tTASKS\tOPEN\tCLOSED"
+		if report.TestsScanned {
+			header += "\tTESTS"
+		}
+		fmt.Fprintln(w, header+"\tDESCRIPTION")
+		for _, req := range report.Requirements {
+			text := req.Text
+			if len(text) > 50 {
+				text = text[:47] + "..."
+			}
+			row := fmt.Sprintf("%s\t%d\t%d\t%d", req.ID, len(req.Tasks), req.Open, req.Closed)
+			if report.TestsScanned {
+				row += "\t" + strconv.Itoa(len(req.Tests))
+			}
+			fmt.Fprintln(w, row+"\t"+text)
+		}
+		w.Flush()
+	}
+
+	if untasked := report.Untasked(); len(untasked) > 0 {
+		ui.PrintSection("Requirements without tasks")
+		for _, req := range untasked {
+			fmt.Printf("  %s %s %s\n", ui.WarningIcon(), req.ID, ui.Gray(req.Text))
+		}
+	}
+	if len(report.Unknown) > 0 {
+		ui.PrintSection("Tasks with unknown requirements")
+		for _, u := range report.Unknown {
+			fmt.Printf("  %s %s is labeled %s%s\n", ui.WarningIcon(), u.IssueID, spec.RequirementLabelPrefix, u.Requirement)
+		}
+	}
+
+	tasked, functional := traceCoverage(report)
+	fmt.Println()
+	fmt.Printf("%d/%d functional requirement(s) have tasks", tasked, functional)
+	if len(report.Unknown) > 0 {
+		fmt.Printf(", %d unknown requirement label(s)", len(report.Unknown))
+	}
+	fmt.Println()
+}
+
+func printTraceMarkdown(report *spec.TraceReport) {
+	fmt.Printf("# Requirements Traceability: %s\n\n", report.SpecContext)
+
+	if len(report.Requirements) > 0 {
+		header, rule := "| Requirement | Description | Tasks | Open | Closed |", "|---|---|---|---|---|"
+		if report.TestsScanned {
+			header, rule = header+" Tests |", rule+"---|"
+		}
+		fmt.Println(header)
+		fmt.Println(rule)
+		for _, req := range report.Requirements {
+			tasks := strings.Join(req.Tasks, ", ")
+			if tasks == "" {
+				tasks = "-"
+			}
+			row := fmt.Sprintf("| %s | %s | %s | %d | %d |", req.ID, markdownCell(req.Text), tasks, req.Open, req.Closed)
+			if report.TestsScanned {
+				tests := "-"
+				if len(req.Tests) > 0 {
+					tests = "`" + strings.Join(req.Tests, "`, `") + "`"
+				}
+				row += " " + tests + " |"
+			}
+			fmt.Println(row)
+		}
+		fmt.Println()
+	}
+
+	if untasked := report.Untasked(); len(untasked) > 0 {
+		fmt.Println("## Requirements Without Tasks")
+		fmt.Println()
+		for _, req := range untasked {
+			fmt.Printf("- **%s**: %s\n", req.ID, req.Text)
+		}
+		fmt.Println()
+	}
+	if len(report.Unknown) > 0 {
+		fmt.Println("## Tasks With Unknown Requirements")
+		fmt.Println()
+		for _, u := range report.Unknown {
+			fmt.Printf("- %s: `%s%s`\n", u.IssueID, spec.RequirementLabelPrefix, u.Requirement)
+		}
+		fmt.Println()
+	}
+
+	tasked, functional := traceCoverage(report)
+	fmt.Printf("%d/%d functional requirement(s) have tasks.\n", tasked, functional)
+}
+
+// markdownCell escapes text for a markdown table cell
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -501,6 +502,49 @@ func TestPersonalConfig(t *testing.T) {
 	}
 }
 
+func TestPersonalConfigIssueViews(t *testing.T) {
+	tmpDir := t.TempDir()
+	personalPath := GetPersonalConfigPath(tmpDir)
+	if err := os.MkdirAll(filepath.Dir(personalPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(personalPath, []byte("agents:\n  claude:\n    arguments: --verbose\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	personal, err := LoadPersonal(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadPersonal failed: %v", err)
+	}
+	priority := 1
+	personal.IssueViews = map[string]*IssueView{
+		"my-api-work": {Status: "open", Priority: &priority, Labels: []string{"component:api"}, All: true, Sort: "-updated"},
+	}
+	if err := personal.Save(tmpDir); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(personalPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "arguments: --verbose") {
+		t.Errorf("Save dropped other keys:\n%s", data)
+	}
+	if strings.Contains(string(data), "agent:") {
+		t.Errorf("Save wrote an empty agent section:\n%s", data)
+	}
+
+	loaded, err := LoadPersonal(tmpDir)
+	if err != nil {
+		t.Fatalf("LoadPersonal failed: %v", err)
+	}
+	view := loaded.IssueViews["my-api-work"]
+	if view == nil || view.Status != "open" || view.Priority == nil || *view.Priority != 1 || !view.All || view.Sort != "-updated" {
+		t.Errorf("loaded view = %+v", view)
+	}
+}
+
 func TestParseArguments(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,21 @@
+package config
+
+// IssueView is a saved 'sl issue list' invocation. Team views are stored
+// under task_tracker.views in specledger.yaml, private views under
+// issue-views in specledger.local.yaml. Unset fields do not filter.
+type IssueView struct {
+	Description     string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Status          string            `yaml:"status,omitempty" json:"status,omitempty"`
+	Type            string            `yaml:"type,omitempty" json:"type,omitempty"`
+	Priority        *int              `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Labels          []string          `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Spec            string            `yaml:"spec,omitempty" json:"spec,omitempty"`
+	All             bool              `yaml:"all,omitempty" json:"all,omitempty"`
+	Blocked         bool              `yaml:"blocked,omitempty" json:"blocked,omitempty"`
+	Orphaned        bool              `yaml:"orphaned,omitempty" json:"orphaned,omitempty"`
+	Fields          map[string]string `yaml:"fields,omitempty" json:"fields,omitempty"`
+	IncludeArchived bool              `yaml:"include_archived,omitempty" json:"include_archived,omitempty"`
+	Query           string            `yaml:"query,omitempty" json:"query,omitempty"`
+	Sort            string            `yaml:"sort,omitempty" json:"sort,omitempty"`     // e.g. "priority,-updated"
+	Format          string            `yaml:"format,omitempty" json:"format,omitempty"` // table (default), json, tree or graph
+}
@@ -9,8 +9,14 @@ import (
 )
 
 type PersonalConfig struct {
-	Agent         *AgentConfig `yaml:"agent,omitempty"`
-	ActiveProfile string       `yaml:"active-profile,omitempty"`
+	Agent         *AgentConfig          `yaml:"agent,omitempty"`
+	ActiveProfile string                `yaml:"active-profile,omitempty"`
+	IssueViews    map[string]*IssueView `yaml:"issue-views,omitempty"`
+	// Replace points dependencies, by alias, at local working copies
+	// instead of the cache (see 'sl deps replace')
+	Replace map[string]string `yaml:"replace,omitempty"`
+	// Other keys (e.g. agents) are kept as they are when saving
+	Other map[string]interface{} `yaml:",inline"`
 }
 
 func LoadPersonal(projectPath string) (*PersonalConfig, error) {
@@ -46,7 +52,12 @@ func (c *PersonalConfig) Save(projectPath string) error {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(c)
+	out := *c
+	if !c.HasAgentConfig() {
+		// LoadPersonal fills in an empty agent section; don't write it back
+		out.Agent = nil
+	}
+	data, err := yaml.Marshal(&out)
 	if err != nil {
 		return fmt.Errorf("failed to marshal personal config: %w", err)
 	}
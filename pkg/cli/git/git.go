@@ -221,6 +221,22 @@ func CheckoutBranch(repoPath, name string) error {
 	return nil
 }
 
+// IsIgnored reports whether path is ignored by the repository's gitignore rules.
+// Uses exec so global excludes and core.excludesFile are honored as well.
+func IsIgnored(repoPath, path string) (bool, error) {
+	// #nosec G204 — path is a CLI-managed file, not user input
+	cmd := exec.Command("git", "check-ignore", "-q", path)
+	cmd.Dir = repoPath
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return false, fmt.Errorf("git check-ignore failed: %w", err)
+}
+
 // StashChanges stashes all uncommitted changes.
 // Uses exec because go-git does not implement stash (see go-git issue #606).
 func StashChanges(repoPath string) error {
@@ -278,6 +294,14 @@ func AddFiles(repoPath string, paths []string) error {
 	return nil
 }
 
+// ShortHash abbreviates a commit hash to 7 characters for display
+func ShortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
 // CommitChanges creates a commit with the staged changes and returns the short (8-char) commit hash.
 // Author info is read from the repository's global git config, with fallbacks to "SpecLedger".
 func CommitChanges(repoPath, message string) (string, error) {
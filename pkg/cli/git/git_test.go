@@ -405,3 +405,17 @@ func TestParseRepoFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestShortHash(t *testing.T) {
+	tests := map[string]string{
+		"0123456789abcdef0123456789abcdef01234567": "0123456",
+		"0123456": "0123456",
+		"abc":     "abc",
+		"":        "",
+	}
+	for in, want := range tests {
+		if got := ShortHash(in); got != want {
+			t.Errorf("ShortHash(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
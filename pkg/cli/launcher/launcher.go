@@ -1,10 +1,13 @@
 package launcher
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 // AgentOption represents an available AI coding agent choice.
@@ -140,22 +143,51 @@ type LaunchOptions struct {
 	SkipPermissions bool   // Add --dangerously-skip-permissions flag
 	Model           string // Set ANTHROPIC_MODEL env var (empty = use default)
 	MaxOutputTokens int    // Set CLAUDE_CODE_MAX_OUTPUT_TOKENS env var (0 = use default)
+
+	// Stdio overrides for non-interactive runs (nil = inherit the terminal)
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // LaunchWithPromptAndOptions starts the agent with custom options.
 func (l *AgentLauncher) LaunchWithPromptAndOptions(prompt string, opts LaunchOptions) error {
+	return l.LaunchWithPromptContext(context.Background(), prompt, opts)
+}
+
+// Args returns the arguments the agent is started with: the configured flags
+// followed by the prompt.
+func (l *AgentLauncher) Args(prompt string) []string {
+	args := make([]string, 0, len(l.flags)+1)
+	args = append(args, l.flags...)
+	return append(args, prompt)
+}
+
+// LaunchWithPromptContext starts the agent with custom options and kills it
+// when ctx is done.
+func (l *AgentLauncher) LaunchWithPromptContext(ctx context.Context, prompt string, opts LaunchOptions) error {
 	if l.Command == "" {
 		return fmt.Errorf("no agent command configured")
 	}
 
-	// Combine flags with prompt as final argument
-	args := append(l.flags, prompt)
 	// #nosec G204 -- l.Command is from a controlled DefaultAgents list, prompt is internal
-	cmd := exec.Command(l.Command, args...)
+	cmd := exec.CommandContext(ctx, l.Command, l.Args(prompt)...)
+	// Don't wait forever on output held open by the agent's children once
+	// it has been killed.
+	cmd.WaitDelay = 5 * time.Second
 	cmd.Dir = l.Dir
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
+	if opts.Stdin != nil {
+		cmd.Stdin = opts.Stdin
+	}
+	if opts.Stdout != nil {
+		cmd.Stdout = opts.Stdout
+	}
+	if opts.Stderr != nil {
+		cmd.Stderr = opts.Stderr
+	}
 
 	// Start with merged env (os.Environ + launcher's configured env vars from config)
 	cmd.Env = l.BuildEnv()
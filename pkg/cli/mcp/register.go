@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/specledger/specledger/internal/agent"
+	"github.com/specledger/specledger/pkg/cli/playbooks"
+)
+
+// ServeCommand is the command agents run to start the server.
+var ServeCommand = []string{"sl", "mcp", "serve"}
+
+// codexServerConfig is the managed config.toml section for Codex.
+var codexServerConfig = `[mcp_servers.` + ServerName + `]
+command = "sl"
+args = ["mcp", "serve"]`
+
+// Register adds the server to the project MCP config of every agent that is
+// selected or already set up in projectPath (its config dir exists). It
+// returns the config files that were changed, relative to projectPath.
+// Existing servers and settings in those files are preserved.
+func Register(projectPath string, agentNames []string) ([]string, error) {
+	selected := make(map[string]bool)
+	for _, name := range agentNames {
+		selected[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	var changed []string
+	for _, ag := range agent.All() {
+		if ag.MCPConfigFile == "" {
+			continue
+		}
+		isSelected := selected[strings.ToLower(ag.Name)] || selected[strings.ToLower(ag.Command)]
+		if !isSelected && (ag.ConfigDir == "" || !dirExists(filepath.Join(projectPath, ag.ConfigDir))) {
+			continue
+		}
+
+		path := filepath.Join(projectPath, ag.MCPConfigFile)
+		var updated bool
+		var err error
+		if strings.HasSuffix(path, ".toml") {
+			updated, err = registerTOML(path)
+		} else {
+			updated, err = registerJSON(path)
+		}
+		if err != nil {
+			return changed, fmt.Errorf("failed to register MCP server for %s: %w", ag.Name, err)
+		}
+		if updated {
+			changed = append(changed, ag.MCPConfigFile)
+		}
+	}
+	return changed, nil
+}
+
+// registerJSON adds the server under "mcpServers" in a JSON config file.
+func registerJSON(path string) (bool, error) {
+	config := make(map[string]interface{})
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return false, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	servers, _ := config["mcpServers"].(map[string]interface{})
+	if servers == nil {
+		servers = make(map[string]interface{})
+	}
+	entry := map[string]interface{}{
+		"type":    "stdio",
+		"command": ServeCommand[0],
+		"args":    ServeCommand[1:],
+	}
+	if existing, err := json.Marshal(servers[ServerName]); err == nil {
+		if want, _ := json.Marshal(entry); string(existing) == string(want) {
+			return false, nil
+		}
+	}
+	servers[ServerName] = entry
+	config["mcpServers"] = servers
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	// #nosec G306 -- agent config is committed with the project
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// registerTOML adds the server as a sentinel-managed section of a TOML config
+// file. A server table the user wrote by hand is left alone.
+func registerTOML(path string) (bool, error) {
+	existing := ""
+	if data, err := os.ReadFile(path); err == nil {
+		existing = string(data)
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	header := "[mcp_servers." + ServerName + "]"
+	if strings.Contains(existing, header) && !strings.Contains(existing, playbooks.SentinelBegin) {
+		return false, nil
+	}
+
+	merged := playbooks.MergeSentinelSection(existing, codexServerConfig)
+	if merged == existing {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	// #nosec G306 -- agent config is committed with the project
+	if err := os.WriteFile(path, []byte(merged), 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,141 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// specDocuments are the spec artifacts exposed as resources.
+var specDocuments = []string{"spec.md", "plan.md", "tasks.md"}
+
+// documentTitles describe the spec documents in resource listings.
+var documentTitles = map[string]string{
+	"spec.md":  "Feature specification",
+	"plan.md":  "Implementation plan",
+	"tasks.md": "Task breakdown",
+}
+
+// resourcePrefix is the URI prefix of spec resources:
+// specledger://specs/<spec>/<document>
+const resourcePrefix = "specledger://specs/"
+
+type resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType"`
+}
+
+type resourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
+type resourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// ResourceURI returns the resource URI of a spec document.
+func ResourceURI(specContext, document string) string {
+	return resourcePrefix + specContext + "/" + document
+}
+
+func (s *Server) listResources() (interface{}, error) {
+	entries, err := os.ReadDir(s.basePath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+	}
+
+	list := []resource{}
+	var specs []string
+	for _, e := range entries {
+		if e.IsDir() && issues.ValidateSpecContext(e.Name()) == nil {
+			specs = append(specs, e.Name())
+		}
+	}
+	sort.Strings(specs)
+
+	for _, specContext := range specs {
+		for _, doc := range specDocuments {
+			if _, err := os.Stat(filepath.Join(s.basePath(), specContext, doc)); err != nil {
+				continue
+			}
+			list = append(list, resource{
+				URI:         ResourceURI(specContext, doc),
+				Name:        specContext + "/" + doc,
+				Description: fmt.Sprintf("%s for %s", documentTitles[doc], specContext),
+				MimeType:    "text/markdown",
+			})
+		}
+	}
+
+	return map[string]interface{}{"resources": list}, nil
+}
+
+func (s *Server) listResourceTemplates() map[string]interface{} {
+	templates := make([]resourceTemplate, 0, len(specDocuments))
+	for _, doc := range specDocuments {
+		templates = append(templates, resourceTemplate{
+			URITemplate: resourcePrefix + "{spec}/" + doc,
+			Name:        doc,
+			Description: documentTitles[doc] + " of a spec",
+			MimeType:    "text/markdown",
+		})
+	}
+	return map[string]interface{}{"resourceTemplates": templates}
+}
+
+func (s *Server) readResource(params json.RawMessage) (interface{}, error) {
+	var req struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(params, &req); err != nil {
+		return nil, err
+	}
+
+	specContext, doc, err := parseResourceURI(req.URI)
+	if err != nil {
+		return nil, &rpcError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.basePath(), specContext, doc))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "resource not found: " + req.URI}
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", req.URI, err)
+	}
+
+	return map[string]interface{}{
+		"contents": []resourceContents{{URI: req.URI, MimeType: "text/markdown", Text: string(data)}},
+	}, nil
+}
+
+// parseResourceURI splits a spec resource URI into spec and document,
+// rejecting anything outside the known documents.
+func parseResourceURI(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, resourcePrefix)
+	if !ok {
+		return "", "", fmt.Errorf("unknown resource: %s", uri)
+	}
+	specContext, doc, ok := strings.Cut(rest, "/")
+	if !ok || issues.ValidateSpecContext(specContext) != nil {
+		return "", "", fmt.Errorf("unknown resource: %s", uri)
+	}
+	for _, known := range specDocuments {
+		if doc == known {
+			return specContext, doc, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown resource: %s", uri)
+}
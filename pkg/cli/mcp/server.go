@@ -0,0 +1,253 @@
+// Package mcp implements a Model Context Protocol server that exposes specs,
+// issues, dependency artifacts and review comments to coding agents.
+//
+// The server speaks JSON-RPC 2.0 over newline-delimited stdio, as described by
+// the MCP stdio transport. Only the tools and resources capabilities are
+// implemented.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+
+	"github.com/specledger/specledger/pkg/cli/comment"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+	"github.com/specledger/specledger/pkg/issues"
+	"github.com/specledger/specledger/pkg/version"
+)
+
+// ProtocolVersion is the MCP revision this server implements. Clients asking
+// for a different revision are answered with this one, per the spec.
+const ProtocolVersion = "2025-06-18"
+
+// ServerName identifies the server in the initialize handshake and in agent
+// config files.
+const ServerName = "specledger"
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// maxMessageSize bounds a single JSON-RPC message read from the client.
+const maxMessageSize = 16 * 1024 * 1024
+
+// Options configures a Server.
+type Options struct {
+	// RepoRoot is the project root; specs and issues are resolved under its
+	// artifact path.
+	RepoRoot string
+
+	// ArtifactPath overrides the artifact path from specledger.yaml.
+	ArtifactPath string
+
+	// CommentClient returns an authenticated review comment client. Comment
+	// tools report an error when it is nil or fails.
+	CommentClient func() (*comment.Client, error)
+}
+
+// Server is an MCP server bound to one project.
+type Server struct {
+	opts  Options
+	tools map[string]*tool
+	order []string
+
+	writeMu sync.Mutex
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// NewServer creates a server for the project at opts.RepoRoot.
+func NewServer(opts Options) *Server {
+	if opts.RepoRoot == "" {
+		opts.RepoRoot = "."
+	}
+	if opts.ArtifactPath == "" {
+		opts.ArtifactPath = "specledger/"
+		if meta, err := metadata.LoadFromProject(opts.RepoRoot); err == nil {
+			opts.ArtifactPath = meta.GetArtifactPath()
+		}
+	}
+
+	s := &Server{opts: opts, tools: make(map[string]*tool)}
+	var statuses []string
+	for _, name := range issues.WorkflowFunc(s.basePath()).Names() {
+		statuses = append(statuses, string(name))
+	}
+	for _, t := range builtinTools(statuses) {
+		s.tools[t.Name] = t
+		s.order = append(s.order, t.Name)
+	}
+	return s
+}
+
+// basePath returns the directory holding the spec folders.
+func (s *Server) basePath() string {
+	return filepath.Join(s.opts.RepoRoot, s.opts.ArtifactPath)
+}
+
+// Serve reads requests from in and writes responses to out until in is
+// exhausted or ctx is cancelled. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-scanErr:
+					if err != nil {
+						return fmt.Errorf("failed to read request: %w", err)
+					}
+				default:
+				}
+				return nil
+			}
+			if len(line) == 0 {
+				continue
+			}
+			if resp := s.handleMessage(ctx, line); resp != nil {
+				if err := s.write(out, resp); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// handleMessage processes one raw message. It returns nil for notifications.
+func (s *Server) handleMessage(ctx context.Context, data []byte) *response {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: codeParseError, Message: "parse error: " + err.Error()}}
+	}
+
+	if len(req.ID) == 0 {
+		// Notifications (initialized, cancelled, ...) need no reply
+		return nil
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: &rpcError{Code: codeInvalidRequest, Message: "invalid request"}}
+	}
+
+	result, err := s.dispatch(ctx, req.Method, req.Params)
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		return &response{JSONRPC: "2.0", ID: req.ID, Error: rerr}
+	}
+	return &response{JSONRPC: "2.0", ID: req.ID, Result: result}
+}
+
+func (s *Server) dispatch(ctx context.Context, method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "initialize":
+		return s.initialize(), nil
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, params)
+	case "resources/list":
+		return s.listResources()
+	case "resources/templates/list":
+		return s.listResourceTemplates(), nil
+	case "resources/read":
+		return s.readResource(params)
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+func (s *Server) initialize() map[string]interface{} {
+	return map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities": map[string]interface{}{
+			"tools":     map[string]interface{}{},
+			"resources": map[string]interface{}{},
+		},
+		"serverInfo": map[string]interface{}{
+			"name":    ServerName,
+			"version": version.GetVersion(),
+		},
+		"instructions": "SpecLedger project tools. Use issue_ready to find work, issue_show for details, " +
+			"issue_update/issue_close to record progress, and read spec.md/plan.md/tasks.md resources for context.",
+	}
+}
+
+func (s *Server) write(out io.Writer, resp *response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := out.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// decodeParams unmarshals params into dest, reporting failures as invalid params.
+func decodeParams(params json.RawMessage, dest interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, dest); err != nil {
+		return &rpcError{Code: codeInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
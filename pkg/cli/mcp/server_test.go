@@ -0,0 +1,280 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+const testSpec = "001-test"
+
+// setupProject creates a git repo on the spec branch with a spec.md.
+func setupProject(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found on PATH, skipping")
+	}
+
+	dir := t.TempDir()
+	specDir := filepath.Join(dir, "specledger", testSpec)
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "spec.md"), []byte("# Test spec\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q", "-b", testSpec},
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return dir
+}
+
+// session runs requests through a server and returns responses by ID.
+func session(t *testing.T, s *Server, requests ...string) map[string]response {
+	t.Helper()
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(strings.Join(requests, "\n")+"\n"), &out); err != nil {
+		t.Fatalf("Serve() error: %v", err)
+	}
+
+	responses := make(map[string]response)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp struct {
+			response
+			Result json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", line, err)
+		}
+		resp.response.Result = resp.Result
+		responses[string(resp.ID)] = resp.response
+	}
+	return responses
+}
+
+func call(id int, name string, args interface{}) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"method":  "tools/call",
+		"params":  map[string]interface{}{"name": name, "arguments": args},
+	})
+	return string(data)
+}
+
+// toolText decodes a tools/call result, failing if it is not a success.
+func toolText(t *testing.T, resp response, wantError bool) string {
+	t.Helper()
+	if resp.Error != nil {
+		t.Fatalf("unexpected protocol error: %v", resp.Error)
+	}
+	var result toolResult
+	if err := json.Unmarshal(resp.Result.(json.RawMessage), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.IsError != wantError {
+		t.Fatalf("isError = %v, want %v: %s", result.IsError, wantError, result.Content[0].Text)
+	}
+	return result.Content[0].Text
+}
+
+// issueIDs returns the comma-separated IDs of a JSON issue list.
+func issueIDs(t *testing.T, text string) string {
+	t.Helper()
+	var list []struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(text), &list); err != nil {
+		t.Fatalf("invalid issue list %s: %v", text, err)
+	}
+	ids := make([]string, 0, len(list))
+	for _, issue := range list {
+		ids = append(ids, issue.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestServeIssueTools(t *testing.T) {
+	dir := setupProject(t)
+	s := NewServer(Options{RepoRoot: dir})
+
+	responses := session(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		call(3, "issue_create", map[string]interface{}{"title": "Blocker", "definition_of_done": []string{"Tests pass"}}),
+		call(4, "issue_create", map[string]interface{}{"title": "Blocked work", "type": "bug", "priority": 1}),
+		`{"jsonrpc":"2.0","id":5,"method":"nope"}`,
+	)
+
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses (no reply to the notification), got %d", len(responses))
+	}
+	if !strings.Contains(string(responses["1"].Result.(json.RawMessage)), ProtocolVersion) {
+		t.Errorf("initialize result missing protocol version: %s", responses["1"].Result)
+	}
+	if !strings.Contains(string(responses["2"].Result.(json.RawMessage)), `"issue_ready"`) {
+		t.Errorf("tools/list missing issue_ready: %s", responses["2"].Result)
+	}
+	if responses["5"].Error == nil || responses["5"].Error.Code != codeMethodNotFound {
+		t.Errorf("expected method not found, got %+v", responses["5"])
+	}
+
+	var blocker, blocked map[string]interface{}
+	_ = json.Unmarshal([]byte(toolText(t, responses["3"], false)), &blocker)
+	_ = json.Unmarshal([]byte(toolText(t, responses["4"], false)), &blocked)
+	blockerID, blockedID := blocker["id"].(string), blocked["id"].(string)
+	if blocker["spec_context"] != testSpec || blocked["issue_type"] != "bug" {
+		t.Fatalf("unexpected created issues: %v, %v", blocker, blocked)
+	}
+
+	responses = session(t, s,
+		call(1, "issue_link", map[string]interface{}{"from": blockerID, "type": "blocks", "to": blockedID}),
+		call(2, "issue_ready", map[string]interface{}{}),
+		call(3, "issue_close", map[string]interface{}{"id": blockerID}),
+		call(4, "issue_update", map[string]interface{}{"id": blockerID, "check_dod": "Tests pass", "status": "in_progress"}),
+		call(5, "issue_close", map[string]interface{}{"id": blockerID, "reason": "done"}),
+		call(6, "issue_ready", map[string]interface{}{}),
+		call(7, "issue_show", map[string]interface{}{"id": "SL-000000"}),
+		call(8, "issue_list", map[string]interface{}{"bogus": true}),
+		call(9, "issue_create", map[string]interface{}{"title": "Blocked  work"}),
+		call(10, "issue_create", map[string]interface{}{"title": "Blocked work!", "force": true}),
+	)
+
+	toolText(t, responses["1"], false)
+	if ready := issueIDs(t, toolText(t, responses["2"], false)); ready != blockerID {
+		t.Errorf("expected only the blocker to be ready, got %s", ready)
+	}
+	if msg := toolText(t, responses["3"], true); !strings.Contains(msg, "Tests pass") {
+		t.Errorf("expected DoD error, got %s", msg)
+	}
+	toolText(t, responses["4"], false)
+	if closed := toolText(t, responses["5"], false); !strings.Contains(closed, `"status": "closed"`) {
+		t.Errorf("expected closed issue, got %s", closed)
+	}
+	if ready := issueIDs(t, toolText(t, responses["6"], false)); ready != blockedID {
+		t.Errorf("expected blocked issue to become ready, got %s", ready)
+	}
+	toolText(t, responses["7"], true)
+	toolText(t, responses["8"], true)
+
+	var dup, forced map[string]interface{}
+	_ = json.Unmarshal([]byte(toolText(t, responses["9"], false)), &dup)
+	_ = json.Unmarshal([]byte(toolText(t, responses["10"], false)), &forced)
+	if warning, _ := dup["warning"].(string); dup["id"] == nil || !strings.Contains(warning, blockedID) {
+		t.Errorf("expected created issue with a duplicate warning naming %s, got %v", blockedID, dup)
+	}
+	if forced["id"] == nil || forced["warning"] != nil {
+		t.Errorf("expected forced create without a warning, got %v", forced)
+	}
+}
+
+func TestToolStatusesFollowWorkflow(t *testing.T) {
+	defer func(orig func(string) *issues.Workflow) { issues.WorkflowFunc = orig }(issues.WorkflowFunc)
+	issues.WorkflowFunc = func(string) *issues.Workflow {
+		return &issues.Workflow{Statuses: []issues.WorkflowStatus{
+			{Name: "open", Category: issues.CategoryTodo},
+			{Name: "review", Category: issues.CategoryDoing, Waiting: true},
+			{Name: "closed", Category: issues.CategoryDone},
+		}}
+	}
+
+	s := NewServer(Options{RepoRoot: t.TempDir()})
+	for _, name := range []string{"issue_list", "issue_update"} {
+		status := s.tools[name].InputSchema["properties"].(map[string]interface{})["status"].(map[string]interface{})
+		if got := strings.Join(status["enum"].([]string), ","); got != "open,review,closed" {
+			t.Errorf("%s status enum = %s, want open,review,closed", name, got)
+		}
+	}
+}
+
+func TestServeResources(t *testing.T) {
+	dir := setupProject(t)
+	s := NewServer(Options{RepoRoot: dir})
+
+	responses := session(t, s,
+		`{"jsonrpc":"2.0","id":1,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"specledger://specs/001-test/spec.md"}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/read","params":{"uri":"specledger://specs/001-test/../../etc/passwd"}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"resources/read","params":{"uri":"specledger://specs/001-test/plan.md"}}`,
+	)
+
+	list := string(responses["1"].Result.(json.RawMessage))
+	if !strings.Contains(list, ResourceURI(testSpec, "spec.md")) || strings.Contains(list, "plan.md") {
+		t.Errorf("expected only existing documents to be listed, got %s", list)
+	}
+	if read := string(responses["2"].Result.(json.RawMessage)); !strings.Contains(read, "# Test spec") {
+		t.Errorf("expected spec.md content, got %s", read)
+	}
+	for _, id := range []string{"3", "4"} {
+		if responses[id].Error == nil {
+			t.Errorf("request %s: expected an error, got %s", id, responses[id].Result)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	dir := t.TempDir()
+
+	// An existing server entry in .mcp.json must survive registration
+	existing := `{"mcpServers": {"other": {"command": "other"}}}`
+	if err := os.WriteFile(filepath.Join(dir, ".mcp.json"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Codex is already set up in the project
+	if err := os.MkdirAll(filepath.Join(dir, ".codex"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := Register(dir, []string{"claude"})
+	if err != nil {
+		t.Fatalf("Register() error: %v", err)
+	}
+	if strings.Join(changed, ",") != ".mcp.json,.codex/config.toml" && strings.Join(changed, ",") != ".codex/config.toml,.mcp.json" {
+		t.Errorf("unexpected changed files: %v", changed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".opencode.json")); !os.IsNotExist(err) {
+		t.Error("expected unselected agent without config dir to be skipped")
+	}
+
+	data, _ := os.ReadFile(filepath.Join(dir, ".mcp.json"))
+	var config struct {
+		MCPServers map[string]struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.MCPServers["other"].Command != "other" || config.MCPServers[ServerName].Command != "sl" {
+		t.Errorf("unexpected .mcp.json: %s", data)
+	}
+
+	toml, _ := os.ReadFile(filepath.Join(dir, ".codex", "config.toml"))
+	if !strings.Contains(string(toml), "[mcp_servers.specledger]") {
+		t.Errorf("unexpected config.toml: %s", toml)
+	}
+
+	// Registration is idempotent
+	changed, err = Register(dir, []string{"claude"})
+	if err != nil || len(changed) != 0 {
+		t.Errorf("expected no changes on second run, got %v, %v", changed, err)
+	}
+}
@@ -0,0 +1,839 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/cli/comment"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/cli/spec"
+	"github.com/specledger/specledger/pkg/deps"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// tool is an MCP tool backed by a handler. Handlers decode their own
+// arguments and return a JSON-serializable result.
+type tool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	handler func(s *Server, ctx context.Context, args json.RawMessage) (interface{}, error)
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type toolResult struct {
+	Content []textContent `json:"content"`
+	IsError bool          `json:"isError,omitempty"`
+}
+
+// prop builds a JSON schema property.
+func prop(typ, description string) map[string]interface{} {
+	return map[string]interface{}{"type": typ, "description": description}
+}
+
+// enumProp builds a string property restricted to values.
+func enumProp(description string, values ...string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description, "enum": values}
+}
+
+// listProp builds a string array property.
+func listProp(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": description}
+}
+
+// objectSchema builds a tool input schema.
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+var specProp = prop("string", "Spec context (e.g. 001-my-feature); defaults to the current branch")
+
+// builtinTools returns the tools of the server. statuses are the status names
+// of the project's workflow.
+func builtinTools(statuses []string) []*tool {
+	return []*tool{
+		{
+			Name:        "issue_list",
+			Description: "List issues of a spec, or of all specs, with optional filters.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"spec":     specProp,
+				"all":      prop("boolean", "List issues across all specs"),
+				"status":   enumProp("Filter by status", statuses...),
+				"type":     enumProp("Filter by type", "epic", "feature", "task", "bug"),
+				"priority": prop("integer", "Filter by priority (0-5, 0=highest)"),
+				"label":    prop("string", "Filter by label"),
+				"blocked":  prop("boolean", "Only blocked issues"),
+			}),
+			handler: (*Server).issueList,
+		},
+		{
+			Name:        "issue_ready",
+			Description: "List issues that are ready to work on: not blocked by open issues and not claimed.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"spec":     specProp,
+				"all":      prop("boolean", "List ready issues across all specs"),
+				"type":     enumProp("Filter by type", "epic", "feature", "task", "bug"),
+				"priority": prop("integer", "Filter by priority (0-5, 0=highest)"),
+			}),
+			handler: (*Server).issueReady,
+		},
+		{
+			Name:        "issue_show",
+			Description: "Show an issue with its description, design, acceptance criteria, definition of done and links.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id": prop("string", "Issue ID (SL-xxxxxx)"),
+			}, "id"),
+			handler: (*Server).issueShow,
+		},
+		{
+			Name:        "issue_create",
+			Description: "Create an issue in a spec.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"spec":                specProp,
+				"title":               prop("string", "Issue title"),
+				"description":         prop("string", "Issue description"),
+				"type":                enumProp("Issue type (default task)", "epic", "feature", "task", "bug"),
+				"priority":            prop("integer", "Priority (0-5, 0=highest; default 2)"),
+				"labels":              listProp("Labels"),
+				"design":              prop("string", "Design notes"),
+				"acceptance_criteria": prop("string", "Acceptance criteria"),
+				"notes":               prop("string", "Implementation notes"),
+				"definition_of_done":  listProp("Definition of done checklist items"),
+				"parent":              prop("string", "Parent issue ID"),
+				"force":               prop("boolean", "Skip duplicate detection"),
+			}, "title"),
+			handler: (*Server).issueCreate,
+		},
+		{
+			Name:        "issue_update",
+			Description: "Update fields of an issue. Only the given fields change.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":                  prop("string", "Issue ID (SL-xxxxxx)"),
+				"title":               prop("string", "New title"),
+				"description":         prop("string", "New description"),
+				"status":              enumProp("New status", statuses...),
+				"type":                enumProp("New type", "epic", "feature", "task", "bug"),
+				"priority":            prop("integer", "New priority (0-5)"),
+				"assignee":            prop("string", "New assignee"),
+				"design":              prop("string", "New design notes"),
+				"acceptance_criteria": prop("string", "New acceptance criteria"),
+				"notes":               prop("string", "New implementation notes"),
+				"add_labels":          listProp("Labels to add"),
+				"remove_labels":       listProp("Labels to remove"),
+				"check_dod":           prop("string", "Definition of done item to check"),
+				"uncheck_dod":         prop("string", "Definition of done item to uncheck"),
+				"parent":              prop("string", "Parent issue ID (empty string clears the parent)"),
+				"reason":              prop("string", "Reason recorded in the issue history"),
+			}, "id"),
+			handler: (*Server).issueUpdate,
+		},
+		{
+			Name:        "issue_close",
+			Description: "Close an issue. Fails while definition of done items are unchecked unless force is set.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":     prop("string", "Issue ID (SL-xxxxxx)"),
+				"reason": prop("string", "Close reason"),
+				"force":  prop("boolean", "Close even if the definition of done is incomplete"),
+			}, "id"),
+			handler: (*Server).issueClose,
+		},
+		{
+			Name:        "issue_link",
+			Description: "Link two issues. 'blocks' means from must be done before to; 'parent' makes to the parent of from.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"from": prop("string", "Source issue ID"),
+				"type": enumProp("Link type", "blocks", "related", "parent"),
+				"to":   prop("string", "Target issue ID"),
+			}, "from", "type", "to"),
+			handler: (*Server).issueLink,
+		},
+		{
+			Name:        "spec_info",
+			Description: "Show the feature context: spec directory, branch and which of spec.md, plan.md and tasks.md exist.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"spec": specProp,
+			}),
+			handler: (*Server).specInfo,
+		},
+		{
+			Name:        "deps_resolve",
+			Description: "Resolve a dependency artifact reference (<alias>:<artifact>) to its path and content.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"reference": prop("string", "Artifact reference, e.g. platform:api.md"),
+			}, "reference"),
+			handler: (*Server).depsResolve,
+		},
+		{
+			Name:        "comment_list",
+			Description: "List review comments for a spec. Requires 'sl auth login'.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"spec":   prop("string", "Spec branch; defaults to the current branch"),
+				"status": enumProp("Comment status (default open)", "open", "resolved", "all"),
+			}),
+			handler: (*Server).commentList,
+		},
+		{
+			Name:        "comment_reply",
+			Description: "Reply to a review comment thread.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":      prop("string", "Comment ID or unique prefix"),
+				"message": prop("string", "Reply text"),
+			}, "id", "message"),
+			handler: (*Server).commentReply,
+		},
+		{
+			Name:        "comment_resolve",
+			Description: "Resolve a review comment. The reason is posted as a reply first.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":     prop("string", "Comment ID or unique prefix"),
+				"reason": prop("string", "Resolution reason"),
+			}, "id", "reason"),
+			handler: (*Server).commentResolve,
+		},
+	}
+}
+
+func (s *Server) listTools() map[string]interface{} {
+	list := make([]*tool, 0, len(s.order))
+	for _, name := range s.order {
+		list = append(list, s.tools[name])
+	}
+	return map[string]interface{}{"tools": list}
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(params, &call); err != nil {
+		return nil, err
+	}
+
+	t, ok := s.tools[call.Name]
+	if !ok {
+		return nil, &rpcError{Code: codeInvalidParams, Message: "unknown tool: " + call.Name}
+	}
+
+	args := call.Arguments
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	// Tool failures are reported in the result so the agent can see and
+	// react to them; protocol errors are reserved for malformed calls.
+	result, err := t.handler(s, ctx, args)
+	if err != nil {
+		return toolResult{Content: []textContent{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s result: %w", call.Name, err)
+	}
+	return toolResult{Content: []textContent{{Type: "text", Text: string(data)}}}, nil
+}
+
+// decodeArgs unmarshals tool arguments, rejecting unknown fields so typos
+// surface instead of being silently ignored.
+func decodeArgs(args json.RawMessage, dest interface{}) error {
+	dec := json.NewDecoder(strings.NewReader(string(args)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dest); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// resolveSpec returns the given spec or the one detected from the current branch.
+func (s *Server) resolveSpec(specContext string) (string, error) {
+	if specContext != "" {
+		if err := issues.ValidateSpecContext(specContext); err != nil {
+			return "", err
+		}
+		return specContext, nil
+	}
+	detected, err := issues.NewContextDetector(s.opts.RepoRoot).DetectSpecContext()
+	if err != nil {
+		return "", fmt.Errorf("%w (pass 'spec' explicitly)", err)
+	}
+	return detected, nil
+}
+
+func (s *Server) store(specContext string) (*issues.Store, error) {
+	store, err := issues.NewStore(issues.StoreOptions{
+		BasePath:    s.basePath(),
+		SpecContext: specContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	return store, nil
+}
+
+// storeForIssue finds the spec holding id and returns its store.
+func (s *Server) storeForIssue(id string) (*issues.Store, error) {
+	if _, err := issues.ParseIssueID(id); err != nil {
+		return nil, fmt.Errorf("invalid issue ID: %w", err)
+	}
+	_, specContext, err := issues.GetIssueAcrossSpecs(id, s.basePath())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", id, err)
+	}
+	return s.store(specContext)
+}
+
+type listArgs struct {
+	Spec     string `json:"spec"`
+	All      bool   `json:"all"`
+	Status   string `json:"status"`
+	Type     string `json:"type"`
+	Priority *int   `json:"priority"`
+	Label    string `json:"label"`
+	Blocked  bool   `json:"blocked"`
+}
+
+func (a listArgs) filter() issues.ListFilter {
+	filter := issues.ListFilter{All: a.All, Blocked: a.Blocked, Priority: a.Priority}
+	if a.Status != "" {
+		status := issues.IssueStatus(a.Status)
+		filter.Status = &status
+	}
+	if a.Type != "" {
+		issueType := issues.IssueType(a.Type)
+		filter.IssueType = &issueType
+	}
+	if a.Label != "" {
+		filter.Labels = []string{a.Label}
+	}
+	return filter
+}
+
+func (s *Server) issueList(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args listArgs
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	filter := args.filter()
+	var list []issues.Issue
+	if args.All && args.Spec == "" {
+		var err error
+		if list, err = issues.ListAllSpecs(s.basePath(), filter); err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+	} else {
+		specContext, err := s.resolveSpec(args.Spec)
+		if err != nil {
+			return nil, err
+		}
+		store, err := s.store(specContext)
+		if err != nil {
+			return nil, err
+		}
+		filter.SpecContext = specContext
+		if list, err = store.List(filter); err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+	}
+
+	if list == nil {
+		list = []issues.Issue{}
+	}
+	return list, nil
+}
+
+func (s *Server) issueReady(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args listArgs
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	filter := args.filter()
+	var ready []issues.ReadyIssue
+	if args.All && args.Spec == "" {
+		var err error
+		if ready, err = issues.ListReadyAcrossSpecs(s.basePath(), filter); err != nil {
+			return nil, fmt.Errorf("failed to list ready issues: %w", err)
+		}
+	} else {
+		specContext, err := s.resolveSpec(args.Spec)
+		if err != nil {
+			return nil, err
+		}
+		store, err := s.store(specContext)
+		if err != nil {
+			return nil, err
+		}
+		if ready, err = store.ListReady(filter); err != nil {
+			return nil, fmt.Errorf("failed to list ready issues: %w", err)
+		}
+	}
+
+	list := make([]issues.Issue, 0, len(ready))
+	for _, r := range ready {
+		list = append(list, r.Issue)
+	}
+	return list, nil
+}
+
+func (s *Server) issueShow(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	store, err := s.storeForIssue(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := store.Get(args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+
+	children, err := store.GetChildren(args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children: %w", err)
+	}
+	childIDs := make([]string, 0, len(children))
+	for _, child := range children {
+		childIDs = append(childIDs, child.ID)
+	}
+
+	return struct {
+		*issues.Issue
+		Children []string `json:"children,omitempty"`
+	}{issue, childIDs}, nil
+}
+
+func (s *Server) issueCreate(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Spec               string   `json:"spec"`
+		Title              string   `json:"title"`
+		Description        string   `json:"description"`
+		Type               string   `json:"type"`
+		Priority           *int     `json:"priority"`
+		Labels             []string `json:"labels"`
+		Design             string   `json:"design"`
+		AcceptanceCriteria string   `json:"acceptance_criteria"`
+		Notes              string   `json:"notes"`
+		DefinitionOfDone   []string `json:"definition_of_done"`
+		Parent             string   `json:"parent"`
+		Force              bool     `json:"force"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	specContext, err := s.resolveSpec(args.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	issueType := issues.TypeTask
+	if args.Type != "" {
+		issueType = issues.IssueType(args.Type)
+		if !issues.IsValidIssueType(issueType) {
+			return nil, fmt.Errorf("invalid issue type: %s (must be epic, feature, task, or bug)", args.Type)
+		}
+	}
+	priority := 2
+	if args.Priority != nil {
+		priority = *args.Priority
+	}
+
+	issue := issues.NewIssue(args.Title, args.Description, specContext, issueType, priority)
+	issue.Labels = args.Labels
+	issue.Design = args.Design
+	issue.AcceptanceCriteria = args.AcceptanceCriteria
+	issue.Notes = args.Notes
+	if len(args.DefinitionOfDone) > 0 {
+		items := make([]issues.ChecklistItem, len(args.DefinitionOfDone))
+		for i, item := range args.DefinitionOfDone {
+			items[i] = issues.ChecklistItem{Item: item}
+		}
+		issue.DefinitionOfDone = &issues.DefinitionOfDone{Items: items}
+	}
+	if args.Parent != "" {
+		issue.ParentID = &args.Parent
+	}
+
+	// Check before creating so the new issue does not match itself
+	var warning string
+	if !args.Force {
+		dup, err := issues.CheckDuplicatesForCreate(s.basePath(), issue.Title, specContext, issues.DefaultSimilarityThreshold)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check duplicates: %w", err)
+		}
+		warning = issues.FormatDuplicateWarning(dup.Duplicates)
+	}
+
+	store, err := s.store(specContext)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Create(issue); err != nil {
+		return nil, fmt.Errorf("failed to create issue: %w", err)
+	}
+	return struct {
+		*issues.Issue
+		Warning string `json:"warning,omitempty"`
+	}{issue, warning}, nil
+}
+
+func (s *Server) issueUpdate(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID                 string   `json:"id"`
+		Title              *string  `json:"title"`
+		Description        *string  `json:"description"`
+		Status             *string  `json:"status"`
+		Type               *string  `json:"type"`
+		Priority           *int     `json:"priority"`
+		Assignee           *string  `json:"assignee"`
+		Design             *string  `json:"design"`
+		AcceptanceCriteria *string  `json:"acceptance_criteria"`
+		Notes              *string  `json:"notes"`
+		AddLabels          []string `json:"add_labels"`
+		RemoveLabels       []string `json:"remove_labels"`
+		CheckDoD           string   `json:"check_dod"`
+		UncheckDoD         string   `json:"uncheck_dod"`
+		Parent             *string  `json:"parent"`
+		Reason             string   `json:"reason"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	update := issues.IssueUpdate{
+		Title:              args.Title,
+		Description:        args.Description,
+		Priority:           args.Priority,
+		Assignee:           args.Assignee,
+		Design:             args.Design,
+		AcceptanceCriteria: args.AcceptanceCriteria,
+		Notes:              args.Notes,
+		AddLabels:          args.AddLabels,
+		RemoveLabels:       args.RemoveLabels,
+		CheckDoDItem:       args.CheckDoD,
+		UncheckDoDItem:     args.UncheckDoD,
+		ParentID:           args.Parent,
+		Reason:             args.Reason,
+	}
+	if args.Status != nil {
+		status := issues.IssueStatus(*args.Status)
+		update.Status = &status
+	}
+	if args.Type != nil {
+		issueType := issues.IssueType(*args.Type)
+		update.IssueType = &issueType
+	}
+
+	store, err := s.storeForIssue(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := store.Update(args.ID, update)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update issue: %w", err)
+	}
+	return issue, nil
+}
+
+func (s *Server) issueClose(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID     string `json:"id"`
+		Reason string `json:"reason"`
+		Force  bool   `json:"force"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	store, err := s.storeForIssue(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := store.Get(args.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue: %w", err)
+	}
+	if issue.DefinitionOfDone != nil && !issue.DefinitionOfDone.IsComplete() && !args.Force {
+		return nil, fmt.Errorf("definition of done not met, unchecked: %s (check the items with issue_update or set force)",
+			strings.Join(issue.DefinitionOfDone.GetUncheckedItems(), "; "))
+	}
+
+	status := store.Workflow().StatusFor(issues.CategoryDone)
+	issue, err = store.Update(args.ID, issues.IssueUpdate{Status: &status, Reason: args.Reason})
+	if err != nil {
+		return nil, fmt.Errorf("failed to close issue: %w", err)
+	}
+	return issue, nil
+}
+
+func (s *Server) issueLink(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		From string `json:"from"`
+		Type string `json:"type"`
+		To   string `json:"to"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if _, err := issues.ParseIssueID(args.To); err != nil {
+		return nil, fmt.Errorf("invalid to issue ID: %w", err)
+	}
+
+	store, err := s.storeForIssue(args.From)
+	if err != nil {
+		return nil, err
+	}
+
+	if args.Type == "parent" {
+		parentID := args.To
+		if _, err := store.Update(args.From, issues.IssueUpdate{ParentID: &parentID}); err != nil {
+			return nil, fmt.Errorf("failed to set parent: %w", err)
+		}
+	} else {
+		linkType := issues.LinkType(args.Type)
+		if !issues.IsValidLinkType(linkType) {
+			return nil, fmt.Errorf("invalid link type: %s (must be 'blocks', 'related', or 'parent')", args.Type)
+		}
+		if err := store.AddDependency(args.From, args.To, linkType); err != nil {
+			return nil, fmt.Errorf("failed to create dependency: %w", err)
+		}
+	}
+
+	return map[string]string{"from": args.From, "type": args.Type, "to": args.To}, nil
+}
+
+func (s *Server) specInfo(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Spec string `json:"spec"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	fc, err := spec.DetectFeatureContextWithOptions(s.opts.RepoRoot, spec.DetectionOptions{SpecOverride: args.Spec})
+	if err != nil {
+		return nil, err
+	}
+
+	var docs []string
+	for _, doc := range specDocuments {
+		if _, err := os.Stat(filepath.Join(fc.FeatureDir, doc)); err == nil {
+			docs = append(docs, doc)
+		}
+	}
+
+	return map[string]interface{}{
+		"branch":         fc.Branch,
+		"feature_dir":    fc.FeatureDir,
+		"spec_file":      fc.SpecFile,
+		"plan_file":      fc.PlanFile,
+		"tasks_file":     fc.TasksFile,
+		"available_docs": docs,
+	}, nil
+}
+
+func (s *Server) depsResolve(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Reference string `json:"reference"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	alias, artifact, ok := strings.Cut(args.Reference, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid reference %q (expected <alias>:<artifact>)", args.Reference)
+	}
+
+	// Dependencies are linked under <artifact_path>/deps/<alias> (see 'sl deps link')
+	depsPath := path.Join(filepath.ToSlash(s.opts.ArtifactPath), "deps")
+	resolved, err := deps.ResolveReference(depsPath, alias, artifact, s.opts.RepoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w (run 'sl deps resolve' to fetch dependencies)", err)
+	}
+
+	fullPath := filepath.Join(s.opts.RepoRoot, resolved)
+	result := map[string]interface{}{"reference": args.Reference, "path": resolved}
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat artifact: %w", err)
+	}
+	if info.IsDir() {
+		entries, err := os.ReadDir(fullPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read artifact directory: %w", err)
+		}
+		names := make([]string, 0, len(entries))
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		result["entries"] = names
+		return result, nil
+	}
+
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact: %w", err)
+	}
+	result["content"] = string(content)
+	return result, nil
+}
+
+func (s *Server) commentClient() (*comment.Client, error) {
+	if s.opts.CommentClient == nil {
+		return nil, errors.New("review comments are not available")
+	}
+	return s.opts.CommentClient()
+}
+
+func (s *Server) commentList(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Spec   string `json:"spec"`
+		Status string `json:"status"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	client, err := s.commentClient()
+	if err != nil {
+		return nil, err
+	}
+
+	specKey := args.Spec
+	if specKey == "" {
+		if specKey, err = cligit.GetCurrentBranch(s.opts.RepoRoot); err != nil {
+			return nil, fmt.Errorf("failed to detect current branch: %w", err)
+		}
+	}
+	repoOwner, repoName, err := cligit.GetRepoOwnerName(s.opts.RepoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo info: %w", err)
+	}
+
+	project, err := client.GetProject(repoOwner, repoName)
+	if err != nil {
+		return nil, err
+	}
+	specRecord, err := client.GetSpec(project.ID, specKey)
+	if err != nil {
+		return nil, err
+	}
+	change, err := client.GetChange(specRecord.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []comment.ReviewComment
+	switch args.Status {
+	case "", "open":
+		comments, err = client.FetchComments(change.ID)
+	case "resolved":
+		comments, err = client.FetchResolvedComments(change.ID)
+	case "all":
+		if comments, err = client.FetchComments(change.ID); err == nil {
+			var resolved []comment.ReviewComment
+			resolved, err = client.FetchResolvedComments(change.ID)
+			comments = append(comments, resolved...)
+		}
+	default:
+		return nil, fmt.Errorf("invalid status filter: %s (use: open, resolved, all)", args.Status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comments: %w", err)
+	}
+
+	if comments == nil {
+		comments = []comment.ReviewComment{}
+	}
+	return comments, nil
+}
+
+func (s *Server) commentReply(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+
+	client, err := s.commentClient()
+	if err != nil {
+		return nil, err
+	}
+	commentID, err := client.ResolveIDPrefix(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := client.CreateReply(commentID, args.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to post reply: %w", err)
+	}
+	return reply, nil
+}
+
+func (s *Server) commentResolve(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID     string `json:"id"`
+		Reason string `json:"reason"`
+	}
+	if err := decodeArgs(raw, &args); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Reason) == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	client, err := s.commentClient()
+	if err != nil {
+		return nil, err
+	}
+	commentID, err := client.ResolveIDPrefix(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Post reason as a reply before resolving (audit trail), as 'sl comment resolve' does
+	if _, err := client.CreateReply(commentID, args.Reason); err != nil {
+		return nil, fmt.Errorf("failed to post resolution reason: %w", err)
+	}
+
+	resolved := []string{commentID}
+	replies, _ := client.FetchRepliesByParentID(commentID)
+	if len(replies) > 0 {
+		replyIDs := make([]string, 0, len(replies))
+		for _, r := range replies {
+			replyIDs = append(replyIDs, r.ID)
+		}
+		if err := client.ResolveCommentWithReplies(commentID, replyIDs); err != nil {
+			return nil, fmt.Errorf("failed to resolve comment: %w", err)
+		}
+		resolved = append(resolved, replyIDs...)
+	} else if err := client.ResolveComment(commentID); err != nil {
+		return nil, fmt.Errorf("failed to resolve comment: %w", err)
+	}
+
+	return map[string][]string{"resolved_ids": resolved}, nil
+}
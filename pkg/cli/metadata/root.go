@@ -18,7 +18,12 @@ func FindProjectRoot() (string, error) {
 
 // FindProjectRootFrom walks from the given directory upward
 // to find the nearest directory containing specledger/specledger.yaml.
+// A relative dir is resolved against the working directory first.
 func FindProjectRootFrom(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
 	if HasYAMLMetadata(dir) {
 		return dir, nil
 	}
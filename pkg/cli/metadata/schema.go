@@ -20,6 +20,7 @@ type ProjectMetadata struct {
 	TaskTracker     TaskTrackerInfo                `yaml:"task_tracker,omitempty"`
 	ArtifactPath    string                         `yaml:"artifact_path,omitempty"`
 	Dependencies    []Dependency                   `yaml:"dependencies,omitempty"`
+	Transitive      []LockedDependency             `yaml:"transitive,omitempty"` // Dependencies of dependencies, selected by sl deps resolve
 	Agent           *config.AgentConfig            `yaml:"agent,omitempty"`
 	Profiles        map[string]*config.AgentConfig `yaml:"profiles,omitempty"`
 	ActiveProfile   string                         `yaml:"active-profile,omitempty"`
@@ -41,6 +42,25 @@ type ProjectInfo struct {
 type TaskTrackerInfo struct {
 	Choice    TaskTrackerChoice `yaml:"choice"`
 	EnabledAt *time.Time        `yaml:"enabled_at,omitempty"`
+	Storage   string            `yaml:"storage,omitempty"` // Issue storage: jsonl (default) or sqlite
+	Workflow  *WorkflowConfig   `yaml:"workflow,omitempty"`
+	// Views are the team's saved issue list views (sl issue view)
+	Views map[string]*config.IssueView `yaml:"views,omitempty"`
+}
+
+// WorkflowConfig declares custom issue statuses and the rules for moving
+// between them. Without it issues use open, in_progress and closed.
+type WorkflowConfig struct {
+	Statuses           []WorkflowStatusConfig `yaml:"statuses"`
+	Transitions        map[string][]string    `yaml:"transitions,omitempty"`          // Allowed target statuses per status; unlisted statuses may move anywhere
+	RequireCompleteDoD bool                   `yaml:"require_complete_dod,omitempty"` // Closing requires every DoD item checked
+}
+
+// WorkflowStatusConfig declares one issue status
+type WorkflowStatusConfig struct {
+	Name     string `yaml:"name"`
+	Category string `yaml:"category"`          // todo, doing or done
+	Waiting  bool   `yaml:"waiting,omitempty"` // Waiting on others (e.g. review); left out of sl issue ready
 }
 
 // PlaybookInfo records the playbook applied to this project
@@ -82,9 +102,18 @@ type Dependency struct {
 	Branch         string          `yaml:"branch,omitempty"`
 	Alias          string          `yaml:"alias,omitempty"`
 	ArtifactPath   string          `yaml:"artifact_path,omitempty"` // Path to artifacts within dependency repo
+	Version        string          `yaml:"version,omitempty"`       // Tag constraint (^1.2, ~1.4, v1.4.0) or commit SHA to pin
 	ResolvedCommit string          `yaml:"resolved_commit,omitempty"`
-	Framework      FrameworkChoice `yaml:"framework,omitempty"`   // speckit, openspec, both, none
-	ImportPath     string          `yaml:"import_path,omitempty"` // @alias/spec format for AI imports
+	ResolvedTag    string          `yaml:"resolved_tag,omitempty"` // Tag the commit was resolved from, for versioned dependencies
+	Framework      FrameworkChoice `yaml:"framework,omitempty"`    // speckit, openspec, both, none
+	ImportPath     string          `yaml:"import_path,omitempty"`  // @alias/spec format for AI imports
+}
+
+// LockedDependency is a dependency of a dependency, recorded with the commit
+// 'sl deps resolve' selected for it and the projects that require it
+type LockedDependency struct {
+	Dependency `yaml:",inline"`
+	RequiredBy []string `yaml:"required_by,omitempty"`
 }
 
 // ToolStatus represents runtime tool detection (not persisted)
@@ -22,7 +22,11 @@ func Load(path string) (*ProjectMetadata, error) {
 	if err != nil {
 		return nil, err
 	}
+	return Parse(data)
+}
 
+// Parse parses and validates specledger.yaml content
+func Parse(data []byte) (*ProjectMetadata, error) {
 	var metadata ProjectMetadata
 	if err := yaml.Unmarshal(data, &metadata); err != nil {
 		return nil, err
@@ -0,0 +1,118 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/specledger/specledger/internal/agent"
+	"github.com/specledger/specledger/pkg/cli/launcher"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// StubAgentName selects the built-in stub agent.
+const StubAgentName = "stub"
+
+// Task is one unit of work handed to an agent.
+type Task struct {
+	Issue  issues.Issue
+	Dir    string            // Worktree the agent works in
+	Branch string            // Branch checked out in Dir
+	Prompt string            // Rendered prompt (see BuildPrompt)
+	Env    map[string]string // Extra environment for the agent process
+	Output io.Writer         // Agent stdout/stderr
+}
+
+// Agent runs a task to completion. An agent signals success by committing in
+// the task's worktree; returning nil without a commit counts as unfinished.
+type Agent interface {
+	Name() string
+	Run(ctx context.Context, task Task) error
+}
+
+// LauncherAgent runs a real coding agent CLI through launcher.AgentLauncher.
+type LauncherAgent struct {
+	Definition agent.Agent
+	Options    launcher.LaunchOptions
+
+	// Configure is called on each launcher before it starts, e.g. to apply
+	// headless flags and per-agent flags and env vars from config.
+	Configure func(l *launcher.AgentLauncher)
+}
+
+// Name returns the agent's display name.
+func (a *LauncherAgent) Name() string {
+	return a.Definition.Name
+}
+
+// Run launches the agent in the task worktree with the task prompt. The agent
+// process is killed when ctx is done.
+func (a *LauncherAgent) Run(ctx context.Context, task Task) error {
+	l := launcher.NewLauncherForAgent(a.Definition, task.Dir)
+	if a.Configure != nil {
+		a.Configure(l)
+	}
+	l.SetEnv(task.Env)
+
+	opts := a.Options
+	opts.Stdin = strings.NewReader("")
+	opts.Stdout = task.Output
+	opts.Stderr = task.Output
+
+	return l.LaunchWithPromptContext(ctx, task.Prompt, opts)
+}
+
+// StubAgent is a fake agent for exercising the orchestrator without an LLM
+// CLI. It writes the prompt to .sl-stub/<issue-id>.md in the worktree and
+// commits it.
+type StubAgent struct {
+	Delay      time.Duration // Simulated work time
+	SkipCommit bool          // Exit without committing (simulates an unfinished run)
+	Err        error         // Returned from Run, after any commit
+}
+
+// Name returns "stub".
+func (a *StubAgent) Name() string {
+	return StubAgentName
+}
+
+// Run simulates an agent working on the task.
+func (a *StubAgent) Run(ctx context.Context, task Task) error {
+	if a.Delay > 0 {
+		select {
+		case <-time.After(a.Delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if task.Output != nil {
+		fmt.Fprintf(task.Output, "stub agent working on %s in %s\n", task.Issue.ID, task.Dir)
+	}
+	if a.SkipCommit {
+		return a.Err
+	}
+
+	dir := filepath.Join(task.Dir, ".sl-stub")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create stub directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, task.Issue.ID+".md"), []byte(task.Prompt), 0644); err != nil {
+		return fmt.Errorf("failed to write stub output: %w", err)
+	}
+
+	message := fmt.Sprintf("%s: %s", task.Issue.ID, task.Issue.Title)
+	if _, err := git(ctx, task.Dir, "add", ".sl-stub"); err != nil {
+		return err
+	}
+	if _, err := git(ctx, task.Dir, "-c", "user.name=sl stub agent", "-c", "user.email=stub@specledger.invalid",
+		"commit", "-q", "-m", message); err != nil {
+		return err
+	}
+
+	return a.Err
+}
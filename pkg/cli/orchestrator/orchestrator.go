@@ -0,0 +1,392 @@
+// Package orchestrator runs several coding agents in parallel over the ready
+// issue queue, each in its own git worktree on a sub-branch.
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// Options configures a run.
+type Options struct {
+	RepoDir       string        // Main worktree of the repository
+	Store         *issues.Store // Issue store for SpecContext in RepoDir
+	SpecContext   string
+	Agent         Agent
+	Agents        int           // Number of agents running at once (default 1)
+	BaseRef       string        // Branch or commit new worktrees start from
+	WorktreeDir   string        // Parent directory for worktrees
+	Lease         time.Duration // Claim lease, renewed while the agent runs
+	Timeout       time.Duration // Per-issue agent time limit (0 = no limit)
+	PollInterval  time.Duration // How often worktrees are checked for commits
+	Limit         int           // Maximum issues to start (0 = no limit)
+	KeepWorktrees bool          // Keep worktrees of completed issues
+
+	// Progress receives status updates. It is called from multiple goroutines.
+	Progress func(Update)
+}
+
+// Stage identifies a progress update.
+type Stage string
+
+const (
+	StageStarted   Stage = "started"
+	StageCommitted Stage = "committed"
+	StageFinished  Stage = "finished"
+	StageFailed    Stage = "failed"
+	StageWarning   Stage = "warning"
+)
+
+// Update is a progress notification for one issue.
+type Update struct {
+	Slot    int
+	IssueID string
+	Stage   Stage
+	Message string
+}
+
+// Result describes what happened to one issue.
+type Result struct {
+	IssueID  string    `json:"issue_id"`
+	Title    string    `json:"title"`
+	Slot     int       `json:"agent"`
+	Branch   string    `json:"branch"`
+	Worktree string    `json:"worktree"`
+	Commit   string    `json:"commit,omitempty"`
+	Closed   bool      `json:"closed"`
+	Error    string    `json:"error,omitempty"`
+	LogPath  string    `json:"log"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// Holder returns the claim holder name used for an agent slot.
+func Holder(slot int) string {
+	return fmt.Sprintf("sl-run/agent-%d", slot)
+}
+
+// Run dispatches ready issues to agents until the queue is empty, the limit
+// is reached or ctx is cancelled. Issues are claimed before an agent starts
+// and closed once the agent commits; issues whose agent exits without a
+// commit are released back to ready and not retried in this run. Issues
+// unblocked by work completed during the run are picked up with their
+// blockers' branches merged into the new worktree.
+func Run(ctx context.Context, opts Options) ([]Result, error) {
+	if opts.Store == nil || opts.Agent == nil {
+		return nil, fmt.Errorf("orchestrator requires a store and an agent")
+	}
+	if opts.Agents <= 0 {
+		opts.Agents = 1
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = issues.DefaultLeaseDuration
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 2 * time.Second
+	}
+	if opts.BaseRef == "" {
+		opts.BaseRef = "HEAD"
+	}
+	if opts.Progress == nil {
+		opts.Progress = func(Update) {}
+	}
+	if err := os.MkdirAll(opts.WorktreeDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	var (
+		results   []Result
+		attempted = make(map[string]bool)
+		completed = make(map[string]bool)
+		running   = make(map[string]bool)
+		done      = make(chan Result)
+		freeSlots []int
+		active    int
+		started   int
+	)
+	for slot := opts.Agents; slot >= 1; slot-- {
+		freeSlots = append(freeSlots, slot)
+	}
+
+	for {
+		for ctx.Err() == nil && len(freeSlots) > 0 && (opts.Limit == 0 || started < opts.Limit) {
+			slot := freeSlots[len(freeSlots)-1]
+			issue, err := claimNext(opts.Store, Holder(slot), opts.Lease, func(issue issues.Issue) bool {
+				if attempted[issue.ID] {
+					return false
+				}
+				// A blocker is closed as soon as it commits, but its branch can
+				// only be merged once its agent has finished
+				for _, blocker := range issue.BlockedBy {
+					if running[blocker] {
+						return false
+					}
+				}
+				return true
+			})
+			if err != nil {
+				if active == 0 {
+					return results, err
+				}
+				opts.Progress(Update{Slot: slot, Stage: StageWarning, Message: err.Error()})
+				break
+			}
+			if issue == nil {
+				break
+			}
+
+			freeSlots = freeSlots[:len(freeSlots)-1]
+			attempted[issue.ID] = true
+			running[issue.ID] = true
+			active++
+			started++
+
+			// Blockers finished in this run only exist on their branches so far
+			var prerequisites []string
+			for _, blocker := range issue.BlockedBy {
+				if completed[blocker] {
+					prerequisites = append(prerequisites, blocker)
+				}
+			}
+			go func(issue issues.Issue, slot int, prerequisites []string) {
+				done <- runIssue(ctx, opts, issue, slot, prerequisites)
+			}(*issue, slot, prerequisites)
+		}
+
+		if active == 0 {
+			break
+		}
+
+		r := <-done
+		active--
+		delete(running, r.IssueID)
+		freeSlots = append(freeSlots, r.Slot)
+		if r.Closed {
+			completed[r.IssueID] = true
+		}
+		results = append(results, r)
+	}
+
+	return results, ctx.Err()
+}
+
+// claimNext claims the highest-priority ready open issue accepted by eligible.
+// It returns nil when nothing is left.
+func claimNext(store *issues.Store, holder string, lease time.Duration, eligible func(issues.Issue) bool) (*issues.Issue, error) {
+	ready, err := withRetry(func() ([]issues.ReadyIssue, error) {
+		return store.ListReady(issues.ListFilter{})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ready issues: %w", err)
+	}
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		a, b := ready[i].Issue, ready[j].Issue
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	for _, r := range ready {
+		issue := r.Issue
+		if store.Workflow().Category(issue.Status) != issues.CategoryTodo || issue.IssueType == issues.TypeEpic || !eligible(issue) {
+			continue
+		}
+		claimed, err := withRetry(func() (*issues.Issue, error) {
+			return store.Claim(issue.ID, holder, lease)
+		})
+		if err != nil {
+			// Claimed elsewhere in the meantime; try the next one
+			continue
+		}
+		return claimed, nil
+	}
+	return nil, nil
+}
+
+// runIssue prepares a worktree for one issue, runs the agent and closes or
+// releases the issue depending on whether the agent committed.
+func runIssue(ctx context.Context, opts Options, issue issues.Issue, slot int, prerequisites []string) Result {
+	holder := Holder(slot)
+	branch := BranchName(opts.SpecContext, issue.ID)
+	result := Result{
+		IssueID:  issue.ID,
+		Title:    issue.Title,
+		Slot:     slot,
+		Branch:   branch,
+		Worktree: filepath.Join(opts.WorktreeDir, issue.ID),
+		LogPath:  filepath.Join(opts.WorktreeDir, issue.ID+".log"),
+		Started:  time.Now(),
+	}
+
+	fail := func(err error) Result {
+		result.Error = err.Error()
+		result.Finished = time.Now()
+		if _, relErr := withRetry(func() (*issues.Issue, error) {
+			return opts.Store.Release(issue.ID, holder, false)
+		}); relErr != nil {
+			opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageWarning, Message: "failed to release claim: " + relErr.Error()})
+		}
+		opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageFailed, Message: result.Error})
+		return result
+	}
+
+	// Work continues in the background even if the run is interrupted so that
+	// git state stays consistent; cancellation only stops new dispatches.
+	gitCtx := context.WithoutCancel(ctx)
+
+	if err := addWorktree(gitCtx, opts.RepoDir, result.Worktree, branch, opts.BaseRef); err != nil {
+		return fail(err)
+	}
+	var merged []string
+	for _, id := range prerequisites {
+		if err := mergeBranch(gitCtx, result.Worktree, BranchName(opts.SpecContext, id)); err != nil {
+			opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageWarning, Message: err.Error()})
+			continue
+		}
+		merged = append(merged, id)
+	}
+
+	baseline, err := headCommit(gitCtx, result.Worktree)
+	if err != nil {
+		return fail(err)
+	}
+
+	prompt, err := BuildPrompt(PromptContext{
+		Issue:         issue,
+		SpecContext:   opts.SpecContext,
+		Branch:        branch,
+		Prerequisites: merged,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	logFile, err := os.Create(result.LogPath)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create agent log: %w", err))
+	}
+	defer logFile.Close()
+
+	opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageStarted,
+		Message: fmt.Sprintf("%s in %s", issue.Title, result.Worktree)})
+
+	agentCtx, cancelAgent := ctx, context.CancelFunc(func() {})
+	if opts.Timeout > 0 {
+		agentCtx, cancelAgent = context.WithTimeout(ctx, opts.Timeout)
+	}
+	defer cancelAgent()
+
+	agentDone := make(chan error, 1)
+	go func() {
+		agentDone <- opts.Agent.Run(agentCtx, Task{
+			Issue:  issue,
+			Dir:    result.Worktree,
+			Branch: branch,
+			Prompt: prompt,
+			Env: map[string]string{
+				"SL_ACTOR": holder,
+				"SL_ISSUE": issue.ID,
+				"SL_SPEC":  opts.SpecContext,
+			},
+			Output: io.Writer(logFile),
+		})
+	}()
+
+	poll := time.NewTicker(opts.PollInterval)
+	defer poll.Stop()
+	heartbeat := time.NewTicker(opts.Lease / 3)
+	defer heartbeat.Stop()
+
+	var agentErr error
+	for running := true; running; {
+		select {
+		case agentErr = <-agentDone:
+			running = false
+		case <-poll.C:
+			closeIfCommitted(gitCtx, opts, &result, baseline)
+		case <-heartbeat.C:
+			if !result.Closed {
+				if _, err := withRetry(func() (*issues.Issue, error) {
+					return opts.Store.Heartbeat(issue.ID, holder, opts.Lease)
+				}); err != nil {
+					opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageWarning, Message: "heartbeat failed: " + err.Error()})
+				}
+			}
+		}
+	}
+
+	closeIfCommitted(gitCtx, opts, &result, baseline)
+	if !result.Closed {
+		if errors.Is(agentCtx.Err(), context.DeadlineExceeded) {
+			agentErr = fmt.Errorf("agent timed out after %s", opts.Timeout)
+		}
+		if agentErr == nil {
+			agentErr = errors.New("agent exited without committing")
+		}
+		return fail(agentErr)
+	}
+
+	result.Finished = time.Now()
+	if agentErr != nil {
+		result.Error = agentErr.Error()
+	}
+	if !opts.KeepWorktrees {
+		if err := removeWorktree(gitCtx, opts.RepoDir, result.Worktree); err != nil {
+			opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageWarning, Message: "kept worktree: " + err.Error()})
+		}
+	}
+	opts.Progress(Update{Slot: slot, IssueID: issue.ID, Stage: StageFinished, Message: "closed on " + branch})
+	return result
+}
+
+// closeIfCommitted closes the issue once the worktree has moved past baseline.
+func closeIfCommitted(ctx context.Context, opts Options, result *Result, baseline string) {
+	if result.Closed {
+		return
+	}
+	head, err := headCommit(ctx, result.Worktree)
+	if err != nil || head == baseline {
+		return
+	}
+
+	result.Commit = head
+	opts.Progress(Update{Slot: result.Slot, IssueID: result.IssueID, Stage: StageCommitted, Message: cligit.ShortHash(head)})
+
+	status := opts.Store.Workflow().StatusFor(issues.CategoryDone)
+	_, err = withRetry(func() (*issues.Issue, error) {
+		return opts.Store.Update(result.IssueID, issues.IssueUpdate{
+			Status: &status,
+			Reason: fmt.Sprintf("committed %s on %s by %s", cligit.ShortHash(head), result.Branch, opts.Agent.Name()),
+		})
+	})
+	if err != nil {
+		opts.Progress(Update{Slot: result.Slot, IssueID: result.IssueID, Stage: StageWarning, Message: "failed to close issue: " + err.Error()})
+		return
+	}
+	result.Closed = true
+}
+
+// withRetry retries store operations that lost the file lock to another process.
+func withRetry[T any](fn func() (T, error)) (T, error) {
+	var v T
+	var err error
+	for attempt := 0; attempt < 100; attempt++ {
+		v, err = fn()
+		if !errors.Is(err, issues.ErrStoreLocked) {
+			return v, err
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return v, err
+}
@@ -0,0 +1,251 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+const testSpec = "001-test"
+
+// requireGit skips the test if git is not available on PATH.
+func requireGit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found on PATH, skipping")
+	}
+}
+
+// gitCmd runs a git command in dir, failing the test on error.
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\nOutput: %s", args, err, string(output))
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// setupRunRepo creates a repo on the spec branch with an issue store.
+func setupRunRepo(t *testing.T) (string, *issues.Store) {
+	t.Helper()
+	requireGit(t)
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-b", testSpec)
+	gitCmd(t, dir, "config", "user.email", "test@test.com")
+	gitCmd(t, dir, "config", "user.name", "Test")
+	if err := os.MkdirAll(filepath.Join(dir, "specledger", testSpec), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "init")
+
+	store, err := issues.NewStore(issues.StoreOptions{BasePath: filepath.Join(dir, "specledger"), SpecContext: testSpec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, store
+}
+
+func createIssue(t *testing.T, store *issues.Store, id, title string, priority int) {
+	t.Helper()
+	issue := issues.NewIssue(title, "Do "+title, testSpec, issues.TypeTask, priority)
+	issue.ID = id
+	issue.AcceptanceCriteria = title + " works"
+	if err := store.Create(issue); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+}
+
+func TestRunWithStubAgent(t *testing.T) {
+	dir, store := setupRunRepo(t)
+
+	createIssue(t, store, "SL-aaaaaa", "First", 1)
+	createIssue(t, store, "SL-bbbbbb", "Second", 2)
+	createIssue(t, store, "SL-cccccc", "Depends on first", 0)
+	if err := store.AddDependency("SL-aaaaaa", "SL-cccccc", issues.LinkBlocks); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var stages []string
+	results, err := Run(context.Background(), Options{
+		RepoDir:      dir,
+		Store:        store,
+		SpecContext:  testSpec,
+		Agent:        &StubAgent{Delay: 20 * time.Millisecond},
+		Agents:       2,
+		BaseRef:      testSpec,
+		WorktreeDir:  filepath.Join(t.TempDir(), "worktrees"),
+		PollInterval: 10 * time.Millisecond,
+		Progress: func(u Update) {
+			mu.Lock()
+			defer mu.Unlock()
+			stages = append(stages, u.IssueID+":"+string(u.Stage))
+		},
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d: %+v", len(results), results)
+	}
+	order := make(map[string]int)
+	for i, r := range results {
+		if !r.Closed || r.Commit == "" {
+			t.Errorf("expected %s to be closed with a commit, got %+v", r.IssueID, r)
+		}
+		order[r.IssueID] = i
+	}
+	if order["SL-cccccc"] < order["SL-aaaaaa"] {
+		t.Error("blocked issue finished before its blocker")
+	}
+
+	list, err := store.List(issues.ListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range list {
+		if issue.Status != issues.StatusClosed || issue.Lease != nil {
+			t.Errorf("expected %s closed without a lease, got status=%s lease=%v", issue.ID, issue.Status, issue.Lease)
+		}
+	}
+
+	// The dependent issue's branch includes its blocker's work
+	files := gitCmd(t, dir, "ls-tree", "-r", "--name-only", BranchName(testSpec, "SL-cccccc"))
+	if !strings.Contains(files, ".sl-stub/SL-aaaaaa.md") || !strings.Contains(files, ".sl-stub/SL-cccccc.md") {
+		t.Errorf("expected blocker work merged into dependent branch, got:\n%s", files)
+	}
+
+	// Completed worktrees are removed, branches are kept
+	if _, err := os.Stat(results[0].Worktree); !os.IsNotExist(err) {
+		t.Errorf("expected worktree %s to be removed", results[0].Worktree)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".sl-stub")); !os.IsNotExist(err) {
+		t.Error("agent output leaked into the main worktree")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !containsStage(stages, "SL-aaaaaa:started") || !containsStage(stages, "SL-aaaaaa:committed") {
+		t.Errorf("missing progress updates: %v", stages)
+	}
+}
+
+func TestRunReleasesUnfinishedIssues(t *testing.T) {
+	dir, store := setupRunRepo(t)
+	createIssue(t, store, "SL-aaaaaa", "Never finished", 1)
+
+	results, err := Run(context.Background(), Options{
+		RepoDir:      dir,
+		Store:        store,
+		SpecContext:  testSpec,
+		Agent:        &StubAgent{SkipCommit: true},
+		BaseRef:      testSpec,
+		WorktreeDir:  filepath.Join(t.TempDir(), "worktrees"),
+		PollInterval: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(results) != 1 || results[0].Closed || results[0].Error == "" {
+		t.Fatalf("expected one unfinished result, got %+v", results)
+	}
+
+	issue, err := store.Get("SL-aaaaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Status != issues.StatusOpen || issue.Lease != nil || issue.Assignee != "" {
+		t.Errorf("expected issue released back to ready, got %+v", issue)
+	}
+	if _, err := os.Stat(results[0].Worktree); err != nil {
+		t.Errorf("expected unfinished worktree to be kept for inspection: %v", err)
+	}
+}
+
+func TestRunTimesOutAgents(t *testing.T) {
+	dir, store := setupRunRepo(t)
+	createIssue(t, store, "SL-aaaaaa", "Takes too long", 1)
+
+	results, err := Run(context.Background(), Options{
+		RepoDir:      dir,
+		Store:        store,
+		SpecContext:  testSpec,
+		Agent:        &StubAgent{Delay: time.Minute},
+		BaseRef:      testSpec,
+		WorktreeDir:  filepath.Join(t.TempDir(), "worktrees"),
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if len(results) != 1 || results[0].Closed || !strings.Contains(results[0].Error, "timed out") {
+		t.Fatalf("expected one timed out result, got %+v", results)
+	}
+
+	issue, err := store.Get("SL-aaaaaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Status != issues.StatusOpen || issue.Lease != nil {
+		t.Errorf("expected issue released back to ready, got %+v", issue)
+	}
+}
+
+func TestBuildPrompt(t *testing.T) {
+	issue := issues.NewIssue("Add login", "Users sign in with email", testSpec, issues.TypeTask, 1)
+	issue.Design = "Use sessions"
+	issue.AcceptanceCriteria = "Login form validates input"
+	issue.DefinitionOfDone = &issues.DefinitionOfDone{Items: []issues.ChecklistItem{{Item: "Tests pass"}}}
+
+	prompt, err := BuildPrompt(PromptContext{Issue: *issue, SpecContext: testSpec, Branch: "sl-run/x", Prerequisites: []string{"SL-111111"}})
+	if err != nil {
+		t.Fatalf("BuildPrompt() error: %v", err)
+	}
+	for _, want := range []string{"Users sign in with email", "Use sessions", "Login form validates input", "- [ ] Tests pass", "SL-111111", "sl-run/x"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+}
+
+func containsStage(stages []string, want string) bool {
+	for _, s := range stages {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGitErrorNamesSubcommand(t *testing.T) {
+	requireGit(t)
+	dir := t.TempDir()
+
+	_, err := git(context.Background(), dir, "-c", "core.quotepath=off", "no-such-command")
+	if err == nil || !strings.Contains(err.Error(), "git no-such-command failed") {
+		t.Errorf("error = %v, want it to name the subcommand", err)
+	}
+
+	// A trailing -c with nothing after it must not index past the arguments.
+	if _, err := git(context.Background(), dir, "-c", "core.quotepath=off"); err == nil {
+		t.Error("expected an error for a command-less git invocation")
+	}
+}
@@ -0,0 +1,37 @@
+package orchestrator
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"text/template"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+//go:embed prompt.tmpl
+var promptTemplate string
+
+// PromptContext is the data rendered into the agent prompt.
+type PromptContext struct {
+	Issue         issues.Issue
+	SpecContext   string
+	Branch        string
+	Prerequisites []string // Completed blocker issues merged into the worktree
+}
+
+// BuildPrompt renders the prompt handed to an agent for one issue, built from
+// the issue's description, design, acceptance criteria and definition of done.
+func BuildPrompt(ctx PromptContext) (string, error) {
+	tmpl, err := template.New("run").Parse(promptTemplate)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, ctx); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,71 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// git runs a git command in dir and returns its trimmed stdout.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	// #nosec G204 -- args are built internally from branch names and paths
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		subcommand := args[0]
+		for i := 0; i+2 < len(args) && args[i] == "-c"; i += 2 {
+			subcommand = args[i+2]
+		}
+		return "", fmt.Errorf("git %s failed: %w: %s", subcommand, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// BranchName returns the sub-branch used for an issue's worktree. It lives
+// under sl-run/ so it cannot clash with the spec branch itself.
+func BranchName(specContext, issueID string) string {
+	return fmt.Sprintf("sl-run/%s/%s", specContext, issueID)
+}
+
+// addWorktree creates a worktree at dir on branch, starting from base. An
+// existing branch (from an earlier run) is checked out as-is so previous work
+// is kept.
+func addWorktree(ctx context.Context, repoDir, dir, branch, base string) error {
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("worktree directory already exists: %s (remove it with 'git worktree remove')", dir)
+	}
+
+	if _, err := git(ctx, repoDir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		_, err := git(ctx, repoDir, "worktree", "add", dir, branch)
+		return err
+	}
+	_, err := git(ctx, repoDir, "worktree", "add", "-b", branch, dir, base)
+	return err
+}
+
+// mergeBranch merges branch into the worktree, aborting the merge on conflict.
+func mergeBranch(ctx context.Context, dir, branch string) error {
+	if _, err := git(ctx, dir, "-c", "user.name=sl run", "-c", "user.email=run@specledger.invalid",
+		"merge", "--no-edit", "-q", branch); err != nil {
+		_, _ = git(ctx, dir, "merge", "--abort")
+		return fmt.Errorf("could not merge %s: %w", branch, err)
+	}
+	return nil
+}
+
+// headCommit returns the commit checked out in dir.
+func headCommit(ctx context.Context, dir string) (string, error) {
+	return git(ctx, dir, "rev-parse", "HEAD")
+}
+
+// removeWorktree removes a clean worktree. Worktrees with uncommitted changes
+// are left in place.
+func removeWorktree(ctx context.Context, repoDir, dir string) error {
+	_, err := git(ctx, repoDir, "worktree", "remove", dir)
+	return err
+}
@@ -0,0 +1,211 @@
+package spec
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// RequirementLabelPrefix is the label prefix linking a task to a requirement,
+// e.g. requirement:FR-001
+const RequirementLabelPrefix = "requirement:"
+
+var (
+	// requirementDefPattern matches requirement definitions in spec.md such as
+	// "- **FR-001**: System MUST ..." and "- **SC-002**: ..."
+	requirementDefPattern = regexp.MustCompile(`^\s*[-*]\s+\*\*((?:FR|SC)-\d+[a-z]?)\*\*:?\s*(.*)$`)
+	// requirementRefPattern matches requirement IDs mentioned anywhere
+	requirementRefPattern = regexp.MustCompile(`\b(?:FR|SC)-\d+[a-z]?\b`)
+)
+
+// testFilePatterns are the file name patterns scanned for requirement mentions
+var testFilePatterns = []string{"*_test.go", "*.test.*", "*.spec.*", "test_*.py", "*_test.py", "*_spec.rb"}
+
+// skippedDirs are never scanned for tests
+var skippedDirs = []string{".git", "node_modules", "vendor", ".specledger"}
+
+// Requirement is a functional requirement (FR) or success criterion (SC)
+// declared in spec.md, with the tasks and tests that trace to it
+type Requirement struct {
+	ID     string   `json:"id"`
+	Text   string   `json:"text"`
+	Tasks  []string `json:"tasks"`
+	Open   int      `json:"open"`
+	Closed int      `json:"closed"`
+	Tests  []string `json:"tests,omitempty"`
+}
+
+// Functional reports whether the requirement is an FR rather than an SC
+func (r *Requirement) Functional() bool {
+	return strings.HasPrefix(r.ID, "FR-")
+}
+
+// UnknownRequirement is a task labeled with a requirement spec.md does not declare
+type UnknownRequirement struct {
+	IssueID     string `json:"issue_id"`
+	Requirement string `json:"requirement"`
+}
+
+// TraceReport is the traceability matrix of a spec
+type TraceReport struct {
+	SpecContext  string               `json:"spec_context"`
+	Requirements []*Requirement       `json:"requirements"`
+	Unknown      []UnknownRequirement `json:"unknown,omitempty"`
+	TestsScanned bool                 `json:"tests_scanned"`
+}
+
+// Untasked returns the functional requirements no task traces to. Success
+// criteria are measured rather than implemented, so they are not included.
+func (r *TraceReport) Untasked() []*Requirement {
+	var untasked []*Requirement
+	for _, req := range r.Requirements {
+		if req.Functional() && len(req.Tasks) == 0 {
+			untasked = append(untasked, req)
+		}
+	}
+	return untasked
+}
+
+// Problems counts the untasked functional requirements and unknown
+// requirement labels
+func (r *TraceReport) Problems() int {
+	return len(r.Untasked()) + len(r.Unknown)
+}
+
+// ParseRequirements returns the requirements declared in spec.md content, in
+// order. A requirement declared twice keeps its first text.
+func ParseRequirements(content string) []*Requirement {
+	var reqs []*Requirement
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		m := requirementDefPattern.FindStringSubmatch(scanner.Text())
+		if m == nil || seen[m[1]] {
+			continue
+		}
+		seen[m[1]] = true
+		reqs = append(reqs, &Requirement{ID: m[1], Text: strings.TrimSpace(m[2]), Tasks: []string{}})
+	}
+	return reqs
+}
+
+// Trace joins requirements with the requirement:<id> labels of a spec's
+// issues. Label IDs are matched case-insensitively. workflow decides which
+// statuses count as closed; nil means the default workflow.
+func Trace(specContext string, reqs []*Requirement, issueList []issues.Issue, workflow *issues.Workflow) *TraceReport {
+	if workflow == nil {
+		workflow = issues.DefaultWorkflow()
+	}
+	report := &TraceReport{SpecContext: specContext, Requirements: reqs}
+	byID := make(map[string]*Requirement, len(reqs))
+	for _, req := range reqs {
+		byID[req.ID] = req
+	}
+
+	for _, issue := range issueList {
+		for _, label := range issue.Labels {
+			id, ok := strings.CutPrefix(label, RequirementLabelPrefix)
+			if !ok {
+				continue
+			}
+			id = strings.ToUpper(strings.TrimSpace(id))
+			req, ok := byID[id]
+			if !ok {
+				report.Unknown = append(report.Unknown, UnknownRequirement{IssueID: issue.ID, Requirement: id})
+				continue
+			}
+			if slices.Contains(req.Tasks, issue.ID) {
+				continue
+			}
+			req.Tasks = append(req.Tasks, issue.ID)
+			if workflow.IsDone(issue.Status) {
+				req.Closed++
+			} else {
+				req.Open++
+			}
+		}
+	}
+	for _, req := range reqs {
+		sort.Strings(req.Tasks)
+	}
+	return report
+}
+
+// AddTestMentions scans test files under root for requirement IDs and records
+// each mention as path:line, relative to root. Requirement IDs are only
+// unique within a spec, so a test naming FR-001 is counted for every spec
+// that declares FR-001.
+func (r *TraceReport) AddTestMentions(root string) error {
+	byID := make(map[string]*Requirement, len(r.Requirements))
+	for _, req := range r.Requirements {
+		byID[req.ID] = req
+	}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != root && slices.Contains(skippedDirs, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isTestFile(d.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		return scanTestFile(path, filepath.ToSlash(rel), byID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan tests: %w", err)
+	}
+	r.TestsScanned = true
+	return nil
+}
+
+func isTestFile(name string) bool {
+	for _, pattern := range testFilePatterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func scanTestFile(path, rel string, byID map[string]*Requirement) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		for _, id := range requirementRefPattern.FindAllString(scanner.Text(), -1) {
+			req, ok := byID[id]
+			if !ok {
+				continue
+			}
+			mention := fmt.Sprintf("%s:%d", rel, line)
+			if !slices.Contains(req.Tests, mention) {
+				req.Tests = append(req.Tests, mention)
+			}
+		}
+	}
+	// Over-long lines (e.g. minified fixtures) end the scan of that file
+	return nil
+}
@@ -0,0 +1,95 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+const traceSpec = `## Requirements
+
+- **FR-001**: System MUST export issues
+- **FR-002**: System MUST import issues
+* **FR-003** System MUST log imports
+
+Mentions of FR-009 outside a definition are not requirements.
+
+## Success Criteria
+
+- **SC-001**: Exports finish in under a second
+`
+
+func TestParseRequirements(t *testing.T) {
+	reqs := ParseRequirements(traceSpec)
+	var ids []string
+	for _, req := range reqs {
+		ids = append(ids, req.ID)
+	}
+	want := []string{"FR-001", "FR-002", "FR-003", "SC-001"}
+	if len(ids) != len(want) {
+		t.Fatalf("ParseRequirements() = %v, want %v", ids, want)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("requirement %d = %s, want %s", i, ids[i], want[i])
+		}
+	}
+	if reqs[0].Text != "System MUST export issues" {
+		t.Errorf("FR-001 text = %q", reqs[0].Text)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	issueList := []issues.Issue{
+		{ID: "SL-000001", Status: issues.StatusClosed, Labels: []string{"requirement:FR-001"}},
+		{ID: "SL-000002", Status: issues.StatusOpen, Labels: []string{"requirement:fr-001", "requirement:FR-003"}},
+		{ID: "SL-000003", Status: issues.StatusOpen, Labels: []string{"requirement:FR-042", "component:cli"}},
+	}
+	report := Trace("010-test", ParseRequirements(traceSpec), issueList, nil)
+
+	fr1 := report.Requirements[0]
+	if len(fr1.Tasks) != 2 || fr1.Open != 1 || fr1.Closed != 1 {
+		t.Errorf("FR-001 = %+v, want one open and one closed task", fr1)
+	}
+	untasked := report.Untasked()
+	if len(untasked) != 1 || untasked[0].ID != "FR-002" {
+		t.Errorf("Untasked() = %v, want FR-002 only (SC-001 is not required)", untasked)
+	}
+	if len(report.Unknown) != 1 || report.Unknown[0].Requirement != "FR-042" {
+		t.Errorf("Unknown = %+v, want FR-042", report.Unknown)
+	}
+	if report.Problems() != 2 {
+		t.Errorf("Problems() = %d, want 2", report.Problems())
+	}
+
+	root := t.TempDir()
+	files := map[string]string{
+		"pkg/export/export_test.go":  "// Covers FR-001 and SC-001\nfunc TestExport() {}\n",
+		"web/import.spec.ts":         "it('imports (FR-002)', () => {})\n",
+		"pkg/export/export.go":       "// FR-003 in a non-test file\n",
+		"node_modules/x/lib.test.js": "// FR-003\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := report.AddTestMentions(root); err != nil {
+		t.Fatalf("AddTestMentions() error: %v", err)
+	}
+	if got := fr1.Tests; len(got) != 1 || got[0] != "pkg/export/export_test.go:1" {
+		t.Errorf("FR-001 tests = %v", got)
+	}
+	if got := report.Requirements[1].Tests; len(got) != 1 || got[0] != "web/import.spec.ts:1" {
+		t.Errorf("FR-002 tests = %v", got)
+	}
+	if got := report.Requirements[2].Tests; len(got) != 0 {
+		t.Errorf("FR-003 tests = %v, want none", got)
+	}
+}
@@ -0,0 +1,525 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// boardRefreshInterval is how often the board checks the issue files for
+// changes made outside the board.
+const boardRefreshInterval = time.Second
+
+// BoardSource loads the issues shown on the board and writes changes back.
+type BoardSource interface {
+	// Load returns the issues to show.
+	Load() ([]issues.Issue, error)
+
+	// Update applies a change to an issue. Implementations should go through
+	// issues.Store.Update so validation and locking apply.
+	Update(issue issues.Issue, update issues.IssueUpdate) error
+
+	// Modified returns the time the backing files last changed.
+	Modified() (time.Time, error)
+}
+
+var (
+	boardColumnStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240")).Padding(0, 1)
+	boardActiveStyle = boardColumnStyle.BorderForeground(lipgloss.Color("13"))
+	boardDetailStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("13")).Padding(0, 1)
+)
+
+// Messages used by the board
+type (
+	boardLoadedMsg struct {
+		issues   []issues.Issue
+		modified time.Time
+		err      error
+	}
+	boardTickMsg    time.Time
+	boardWrittenMsg struct {
+		status string
+		err    error
+	}
+)
+
+// BoardModel is the Bubble Tea model for sl issue board.
+type BoardModel struct {
+	source   BoardSource
+	title    string
+	workflow *issues.Workflow
+	statuses []issues.IssueStatus // Column statuses, left to right
+
+	all      []issues.Issue
+	columns  [][]issues.Issue
+	col      int
+	rows     []int
+	follow   string // Issue to put the cursor on after the next load
+	modified time.Time
+	loaded   bool
+
+	filter      *issues.Query
+	filterText  string
+	filtering   bool
+	filterInput textinput.Model
+
+	detail    bool
+	dodCursor int
+
+	status string
+	err    string
+	width  int
+	height int
+}
+
+// NewBoardModel creates a board over source with a column per workflow
+// status. filter is an initial search query (see issues.ParseQuery) applied
+// to the cards.
+func NewBoardModel(source BoardSource, workflow *issues.Workflow, title, filter string) (BoardModel, error) {
+	query, err := issues.ParseQuery(filter, workflow)
+	if err != nil {
+		return BoardModel{}, err
+	}
+
+	ti := textinput.New()
+	ti.Prompt = "/"
+	ti.Placeholder = "label:component:api assignee:me"
+	ti.CharLimit = 200
+	ti.Width = 60
+
+	statuses := workflow.Names()
+	return BoardModel{
+		source:      source,
+		title:       title,
+		workflow:    workflow,
+		statuses:    statuses,
+		columns:     make([][]issues.Issue, len(statuses)),
+		rows:        make([]int, len(statuses)),
+		filter:      query,
+		filterText:  filter,
+		filterInput: ti,
+		width:       120,
+		height:      30,
+	}, nil
+}
+
+// Init loads the issues and starts watching for changes
+func (m BoardModel) Init() tea.Cmd {
+	return tea.Batch(m.load(), boardTick())
+}
+
+// load reads the issues in the background
+func (m BoardModel) load() tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		modified, _ := source.Modified()
+		list, err := source.Load()
+		return boardLoadedMsg{issues: list, modified: modified, err: err}
+	}
+}
+
+// write applies an update in the background
+func (m BoardModel) write(issue issues.Issue, update issues.IssueUpdate, status string) tea.Cmd {
+	source := m.source
+	return func() tea.Msg {
+		if err := source.Update(issue, update); err != nil {
+			return boardWrittenMsg{err: fmt.Errorf("%s: %w", issue.ID, err)}
+		}
+		return boardWrittenMsg{status: status}
+	}
+}
+
+func boardTick() tea.Cmd {
+	return tea.Tick(boardRefreshInterval, func(t time.Time) tea.Msg { return boardTickMsg(t) })
+}
+
+// Update handles messages
+func (m BoardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case boardLoadedMsg:
+		if msg.err != nil {
+			m.err = msg.err.Error()
+			return m, nil
+		}
+		m.modified = msg.modified
+		m.loaded = true
+		m.setIssues(msg.issues)
+		return m, nil
+
+	case boardTickMsg:
+		// Reload only when the files changed, so the cursor stays put otherwise
+		modified, err := m.source.Modified()
+		if err == nil && !modified.Equal(m.modified) {
+			return m, tea.Batch(m.load(), boardTick())
+		}
+		return m, boardTick()
+
+	case boardWrittenMsg:
+		if msg.err != nil {
+			m.err = msg.err.Error()
+			m.status = ""
+		} else {
+			m.err = ""
+			m.status = msg.status
+		}
+		return m, m.load()
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// updateFilter handles keys while the filter prompt is open
+func (m BoardModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.filtering = false
+		m.filterInput.Blur()
+		return m, nil
+	case tea.KeyEnter:
+		text := strings.TrimSpace(m.filterInput.Value())
+		query, err := issues.ParseQuery(text, m.workflow)
+		if err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		m.err = ""
+		m.filter = query
+		m.filterText = text
+		m.filtering = false
+		m.filterInput.Blur()
+		m.setIssues(m.all)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	return m, cmd
+}
+
+// handleKey handles keys on the board and in the detail pane
+func (m BoardModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return m, tea.Quit
+	case "esc":
+		if m.detail {
+			m.detail = false
+			return m, nil
+		}
+		return m, tea.Quit
+	case "/", "f":
+		m.filtering = true
+		m.filterInput.SetValue(m.filterText)
+		m.filterInput.CursorEnd()
+		m.filterInput.Focus()
+		return m, textinput.Blink
+	case "r":
+		m.status = "Reloaded"
+		return m, m.load()
+	case "enter":
+		if m.selected() != nil {
+			m.detail = !m.detail
+			m.dodCursor = 0
+		}
+		return m, nil
+	case "left", "h":
+		if m.col > 0 {
+			m.col--
+			m.dodCursor = 0
+		}
+		return m, nil
+	case "right", "l":
+		if m.col < len(m.statuses)-1 {
+			m.col++
+			m.dodCursor = 0
+		}
+		return m, nil
+	case "up", "k":
+		if m.detail {
+			if m.dodCursor > 0 {
+				m.dodCursor--
+			}
+		} else if m.rows[m.col] > 0 {
+			m.rows[m.col]--
+		}
+		return m, nil
+	case "down", "j":
+		if m.detail {
+			if issue := m.selected(); issue != nil && issue.DefinitionOfDone != nil && m.dodCursor < len(issue.DefinitionOfDone.Items)-1 {
+				m.dodCursor++
+			}
+		} else if m.rows[m.col] < len(m.columns[m.col])-1 {
+			m.rows[m.col]++
+		}
+		return m, nil
+	case "H", "shift+left", "<":
+		return m.move(-1)
+	case "L", "shift+right", ">":
+		return m.move(1)
+	case " ", "x":
+		if m.detail {
+			return m.toggleDoD()
+		}
+	}
+	return m, nil
+}
+
+// move changes the selected issue's status to the neighbouring column
+func (m BoardModel) move(delta int) (tea.Model, tea.Cmd) {
+	issue := m.selected()
+	target := m.col + delta
+	if issue == nil || target < 0 || target >= len(m.statuses) {
+		return m, nil
+	}
+
+	status := m.statuses[target]
+	update := issues.IssueUpdate{Status: &status}
+	if m.workflow.IsDone(status) {
+		update.Reason = "Closed from board"
+	}
+
+	// Follow the card so repeated moves keep working on it
+	m.follow = issue.ID
+	return m, m.write(*issue, update, fmt.Sprintf("Moved %s to %s", issue.ID, status))
+}
+
+// toggleDoD checks or unchecks the DoD item under the cursor
+func (m BoardModel) toggleDoD() (tea.Model, tea.Cmd) {
+	issue := m.selected()
+	if issue == nil || issue.DefinitionOfDone == nil || m.dodCursor >= len(issue.DefinitionOfDone.Items) {
+		return m, nil
+	}
+
+	item := issue.DefinitionOfDone.Items[m.dodCursor]
+	update := issues.IssueUpdate{CheckDoDItem: item.Item}
+	status := fmt.Sprintf("Checked %q on %s", item.Item, issue.ID)
+	if item.Checked {
+		update = issues.IssueUpdate{UncheckDoDItem: item.Item}
+		status = fmt.Sprintf("Unchecked %q on %s", item.Item, issue.ID)
+	}
+	return m, m.write(*issue, update, status)
+}
+
+// setIssues sorts issues into columns, keeping the cursor on the same issue
+// where possible
+func (m *BoardModel) setIssues(list []issues.Issue) {
+	keepID := m.follow
+	m.follow = ""
+	if issue := m.selected(); keepID == "" && issue != nil {
+		keepID = issue.ID
+	}
+
+	m.all = list
+	for i := range m.columns {
+		m.columns[i] = nil
+	}
+	for _, issue := range m.filter.Filter(list) {
+		i := m.columnOf(issue.Status)
+		m.columns[i] = append(m.columns[i], issue)
+	}
+
+	keys, _ := issues.ParseSortKeys("priority,-updated")
+	for i := range m.columns {
+		issues.SortIssues(m.columns[i], keys)
+		if m.rows[i] >= len(m.columns[i]) {
+			m.rows[i] = max(len(m.columns[i])-1, 0)
+		}
+		for row, issue := range m.columns[i] {
+			if issue.ID == keepID {
+				m.col = i
+				m.rows[i] = row
+			}
+		}
+	}
+
+	if m.selected() == nil {
+		m.detail = false
+	}
+}
+
+// columnOf returns the column for a status. Statuses the workflow no longer
+// declares go to the column of their category's default status.
+func (m BoardModel) columnOf(status issues.IssueStatus) int {
+	fallback := m.workflow.StatusFor(m.workflow.Category(status))
+	col := 0
+	for i, s := range m.statuses {
+		if s == status {
+			return i
+		}
+		if s == fallback {
+			col = i
+		}
+	}
+	return col
+}
+
+// selected returns the issue under the cursor, if any
+func (m BoardModel) selected() *issues.Issue {
+	column := m.columns[m.col]
+	row := m.rows[m.col]
+	if row < 0 || row >= len(column) {
+		return nil
+	}
+	return &column[row]
+}
+
+// View renders the board
+func (m BoardModel) View() string {
+	var s strings.Builder
+
+	header := titleStyle.Render(m.title)
+	if m.filterText != "" {
+		header += colorSubtle.Render("  filter: " + m.filterText)
+	}
+	s.WriteString(header + "\n")
+
+	if !m.loaded && m.err == "" {
+		s.WriteString("Loading issues...\n")
+		return s.String()
+	}
+
+	// Columns and the detail pane share the width; borders take 2 columns
+	colWidth := max(m.width/len(m.statuses)-2, 16)
+	detailHeight := 0
+	var detail string
+	if m.detail {
+		detail = m.renderDetail(len(m.statuses)*(colWidth+2) - 2)
+		detailHeight = lipgloss.Height(detail)
+	}
+
+	// Leave room for the header, column borders and titles, and the footer
+	cardLines := max(m.height-detailHeight-7, 3)
+
+	cols := make([]string, len(m.statuses))
+	for i, status := range m.statuses {
+		cols[i] = m.renderColumn(i, status, colWidth, cardLines)
+	}
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, cols...) + "\n")
+
+	if m.detail {
+		s.WriteString(detail + "\n")
+	}
+
+	if m.filtering {
+		s.WriteString(m.filterInput.View() + "\n")
+	} else if m.err != "" {
+		s.WriteString(colorError.Render("Error: "+m.err) + "\n")
+	} else if m.status != "" {
+		s.WriteString(colorSuccess.Render(m.status) + "\n")
+	}
+
+	help := "←/→ column • ↑/↓ select • H/L move status • enter details • / filter • r reload • q quit"
+	if m.detail {
+		help = "↑/↓ item • space toggle DoD • H/L move status • enter/esc close • q quit"
+	}
+	if m.filtering {
+		help = "enter apply • esc cancel • e.g. label:component:api assignee:me"
+	}
+	s.WriteString(colorSubtle.Render(help))
+
+	return s.String()
+}
+
+// renderColumn renders one status column, scrolled to keep the cursor visible
+func (m BoardModel) renderColumn(i int, status issues.IssueStatus, width, lines int) string {
+	column := m.columns[i]
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s (%d)", status, len(column))))
+
+	start := 0
+	if m.rows[i] >= lines {
+		start = m.rows[i] - lines + 1
+	}
+	for row := start; row < len(column) && row < start+lines; row++ {
+		issue := column[row]
+		// Padding and the cursor marker take 4 columns
+		line := issues.Truncate(fmt.Sprintf("P%d %s %s", issue.Priority, issue.ID, issue.Title), width-4)
+		b.WriteString("\n")
+		if i == m.col && row == m.rows[i] {
+			b.WriteString(selectedStyle.Render("> " + line))
+		} else {
+			b.WriteString("  " + line)
+		}
+	}
+	for row := len(column) - start; row < lines; row++ {
+		b.WriteString("\n")
+	}
+
+	style := boardColumnStyle
+	if i == m.col {
+		style = boardActiveStyle
+	}
+	return style.Width(width).Render(b.String())
+}
+
+// renderDetail renders the detail pane for the selected issue
+func (m BoardModel) renderDetail(width int) string {
+	issue := m.selected()
+	if issue == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("%s  %s", issue.ID, issue.Title)) + "\n")
+	b.WriteString(fmt.Sprintf("%s • %s • P%d • %s", issue.Status, issue.IssueType, issue.Priority, issue.SpecContext))
+	if issue.Assignee != "" {
+		b.WriteString(" • @" + issue.Assignee)
+	}
+	b.WriteString("\n")
+	if len(issue.Labels) > 0 {
+		b.WriteString(colorSubtle.Render("Labels: "+strings.Join(issue.Labels, ", ")) + "\n")
+	}
+	if len(issue.BlockedBy) > 0 {
+		b.WriteString(colorSubtle.Render("Blocked by: "+strings.Join(issue.BlockedBy, ", ")) + "\n")
+	}
+	if issue.Description != "" {
+		b.WriteString("\n" + issues.Truncate(strings.ReplaceAll(issue.Description, "\n", " "), width*2) + "\n")
+	}
+
+	b.WriteString("\nDefinition of Done:")
+	if issue.DefinitionOfDone == nil || len(issue.DefinitionOfDone.Items) == 0 {
+		b.WriteString(colorSubtle.Render(" none"))
+	}
+	if issue.DefinitionOfDone != nil {
+		for i, item := range issue.DefinitionOfDone.Items {
+			checkbox := "[ ]"
+			if item.Checked {
+				checkbox = colorSuccess.Render("[x]")
+			}
+			line := fmt.Sprintf("%s %s", checkbox, item.Item)
+			if i == m.dodCursor {
+				b.WriteString("\n" + selectedStyle.Render("> ") + line)
+			} else {
+				b.WriteString("\n  " + line)
+			}
+		}
+	}
+
+	return boardDetailStyle.Width(width).Render(b.String())
+}
+
+// RunBoard runs the board until the user quits.
+func RunBoard(source BoardSource, workflow *issues.Workflow, title, filter string) error {
+	m, err := NewBoardModel(source, workflow, title, filter)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
@@ -0,0 +1,109 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/specledger/specledger/pkg/issues"
+)
+
+// fakeBoardSource keeps issues in memory and records updates
+type fakeBoardSource struct {
+	issues  []issues.Issue
+	updates []issues.IssueUpdate
+}
+
+func (s *fakeBoardSource) Load() ([]issues.Issue, error) {
+	return append([]issues.Issue(nil), s.issues...), nil
+}
+
+func (s *fakeBoardSource) Update(issue issues.Issue, update issues.IssueUpdate) error {
+	s.updates = append(s.updates, update)
+	for i := range s.issues {
+		if s.issues[i].ID != issue.ID {
+			continue
+		}
+		if update.Status != nil {
+			s.issues[i].Status = *update.Status
+		}
+		if update.CheckDoDItem != "" {
+			s.issues[i].DefinitionOfDone.CheckItem(update.CheckDoDItem)
+		}
+	}
+	return nil
+}
+
+func (s *fakeBoardSource) Modified() (time.Time, error) {
+	return time.Time{}, nil
+}
+
+// run feeds msg to the model and runs the returned load and write commands
+// until they settle, like the Bubble Tea runtime would. Commands from the
+// filter prompt are cursor blinks and are skipped.
+func run(t *testing.T, m BoardModel, msg tea.Msg) BoardModel {
+	t.Helper()
+	model, cmd := m.Update(msg)
+	m = model.(BoardModel)
+	for cmd != nil && !m.filtering {
+		next := cmd()
+		switch next.(type) {
+		case boardLoadedMsg, boardWrittenMsg:
+			model, cmd = m.Update(next)
+			m = model.(BoardModel)
+		default:
+			cmd = nil
+		}
+	}
+	return m
+}
+
+func key(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestBoardModel(t *testing.T) {
+	source := &fakeBoardSource{issues: []issues.Issue{
+		{ID: "SL-000001", Title: "API", Status: issues.StatusOpen, Priority: 1, Labels: []string{"component:api"},
+			DefinitionOfDone: &issues.DefinitionOfDone{Items: []issues.ChecklistItem{{Item: "tests pass"}}}},
+		{ID: "SL-000002", Title: "Docs", Status: issues.StatusOpen, Priority: 2},
+		{ID: "SL-000003", Title: "Auth", Status: issues.StatusInProgress, Priority: 1},
+	}}
+
+	m, err := NewBoardModel(source, issues.DefaultWorkflow(), "Board", "")
+	if err != nil {
+		t.Fatalf("NewBoardModel() error: %v", err)
+	}
+	m = run(t, m, boardLoadedMsg{issues: source.issues})
+	if len(m.columns[0]) != 2 || len(m.columns[1]) != 1 || len(m.columns[2]) != 0 {
+		t.Fatalf("unexpected columns: %d/%d/%d", len(m.columns[0]), len(m.columns[1]), len(m.columns[2]))
+	}
+
+	// Moving the selected card follows it into the next column
+	m = run(t, m, key("L"))
+	if got := source.issues[0].Status; got != issues.StatusInProgress {
+		t.Fatalf("status after move = %s, want in_progress", got)
+	}
+	if sel := m.selected(); sel == nil || sel.ID != "SL-000001" || m.col != 1 {
+		t.Fatalf("cursor did not follow the moved issue: col=%d sel=%v", m.col, sel)
+	}
+
+	// Toggling a DoD item goes through the source
+	m = run(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	m = run(t, m, key(" "))
+	if len(source.updates) != 2 || source.updates[1].CheckDoDItem != "tests pass" {
+		t.Fatalf("unexpected updates: %+v", source.updates)
+	}
+	if !m.selected().DefinitionOfDone.Items[0].Checked {
+		t.Error("DoD item not checked after reload")
+	}
+
+	// Filtering hides cards that do not match
+	m = run(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	m = run(t, m, key("/"))
+	m = run(t, m, key("label:component:api"))
+	m = run(t, m, tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.columns[0]) != 0 || len(m.columns[1]) != 1 {
+		t.Errorf("filter not applied: %d/%d", len(m.columns[0]), len(m.columns[1]))
+	}
+}
@@ -1,9 +1,55 @@
 package deps
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/gofrs/flock"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// The cache is content-addressed and shared by every project on the machine:
+//
+//	repos/<key>.git             a bare repository per remote, keyed by normalized URL
+//	worktrees/<key>/<commit>/   a read-only checkout of one commit
+//	projects.json               the projects using the cache, for garbage collection
+//	projects.json.lock          guards projects.json across concurrent runs, and
+//	                            keeps prune out while entries are being fetched
+//
+// A commit's files never change, so projects locked to the same commit share
+// a worktree and projects locked to different commits of one repository
+// never disturb each other.
+const (
+	cacheReposDir     = "repos"
+	cacheWorktreesDir = "worktrees"
+	cacheProjectsFile = "projects.json"
+	cacheLockFile     = "projects.json.lock"
+	extractPrefix     = ".extract-"
+
+	// pruneGracePeriod keeps entries a project fetched recently, since the
+	// project may not have saved the commit it locked to yet
+	pruneGracePeriod = time.Hour
+)
+
+// Cache errors
+var (
+	ErrNotCached  = errors.New("dependency is not cached")
+	ErrUnsafePath = errors.New("dependency contains an unsafe path")
 )
 
 // CacheDir returns the global cache directory for SpecLedger dependencies.
@@ -21,25 +67,902 @@ func CacheDir() (string, error) {
 	return filepath.Join(homeDir, ".specledger", "cache"), nil
 }
 
-// CachePathForDependency returns the cache path for a specific dependency.
-// Uses the alias if available, otherwise generates a directory name from the URL.
-func CachePathForDependency(alias, url string) (string, error) {
-	cacheDir, err := CacheDir()
+// Cache is the dependency cache rooted at a directory
+type Cache struct {
+	Root string
+}
+
+// OpenCache returns the cache at CacheDir()
+func OpenCache() (*Cache, error) {
+	root, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Cache{Root: root}, nil
+}
+
+// CacheKey returns the cache key of a repository: a readable name followed by
+// a hash of the normalized URL, e.g. api-spec-3fa1c2d4e5f6.
+func CacheKey(url string) string {
+	id := NormalizeURL(url)
+	sum := sha256.Sum256([]byte(id))
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(aliasFromURL(id)))
+	return name + "-" + hex.EncodeToString(sum[:6])
+}
+
+// RepoPath returns the path of a remote's bare repository
+func (c *Cache) RepoPath(url string) string {
+	return filepath.Join(c.Root, cacheReposDir, CacheKey(url)+".git")
+}
+
+// WorktreePath returns the path of a commit's worktree
+func (c *Cache) WorktreePath(url, commit string) string {
+	return filepath.Join(c.Root, cacheWorktreesDir, CacheKey(url), commit)
+}
+
+// Repository opens the bare repository of a remote, cloning it on first use.
+// Every branch is cloned; tags are fetched when needed (see EnsureCommit).
+func (c *Cache) Repository(url string) (*git.Repository, error) {
+	var repo *git.Repository
+	err := c.withSharedLock(func() error {
+		var err error
+		repo, err = c.repository(url)
+		return err
+	})
+	return repo, err
+}
+
+func (c *Cache) repository(url string) (*git.Repository, error) {
+	path := c.RepoPath(url)
+	if _, err := os.Stat(path); err == nil {
+		touch(path)
+		return OpenRepository(path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	opts := &git.CloneOptions{URL: url, Tags: git.NoTags}
+	auth, err := getAuthForURL(url)
 	if err != nil {
+		return nil, fmt.Errorf("failed to determine auth method: %w", err)
+	}
+	if auth != nil {
+		opts.Auth = auth
+	}
+
+	// Clone next to the final path and rename, so an interrupted clone never
+	// looks like a cached repository
+	tmp, err := os.MkdirTemp(filepath.Dir(path), extractPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if _, err := git.PlainClone(tmp, true, opts); err != nil {
+		os.RemoveAll(tmp)
+		return nil, fmt.Errorf("failed to clone repository: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.RemoveAll(tmp)
+		if _, statErr := os.Stat(path); statErr != nil {
+			return nil, fmt.Errorf("failed to store repository: %w", err)
+		}
+	}
+	return OpenRepository(path)
+}
+
+// Worktree returns the read-only worktree of a commit, extracting it from the
+// remote's bare repository on first use. The commit must be a full SHA and
+// already be in the repository (see EnsureCommit).
+func (c *Cache) Worktree(url, commit string) (string, error) {
+	var path string
+	err := c.withSharedLock(func() error {
+		var err error
+		path, err = c.worktree(url, commit)
+		return err
+	})
+	return path, err
+}
+
+func (c *Cache) worktree(url, commit string) (string, error) {
+	path := c.WorktreePath(url, commit)
+	if _, err := os.Stat(path); err == nil {
+		touch(path)
+		return path, nil
+	}
+	repoPath := c.RepoPath(url)
+	if _, err := os.Stat(repoPath); err != nil {
+		return "", fmt.Errorf("%w: %s", ErrNotCached, url)
+	}
+	repo, err := OpenRepository(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if err := extractCommit(repo, commit, path); err != nil {
 		return "", err
 	}
+	return path, nil
+}
+
+// CachedDependency is the commit a dependency is locked to and its worktree
+type CachedDependency struct {
+	Commit   string
+	Tag      string
+	Worktree string
+}
+
+// Fetch makes a dependency available in the cache at the commit it is locked
+// to. Without a locked commit, or with update, the commit is resolved afresh:
+// a commit pin, the highest tag matching the version constraint, or the head
+// of the branch. A locked commit whose worktree exists needs no network.
+func (c *Cache) Fetch(dep metadata.Dependency, update bool) (*CachedDependency, error) {
+	var cached *CachedDependency
+	err := c.withSharedLock(func() error {
+		var err error
+		cached, err = c.fetch(dep, update)
+		return err
+	})
+	return cached, err
+}
+
+func (c *Cache) fetch(dep metadata.Dependency, update bool) (*CachedDependency, error) {
+	if !update && len(dep.ResolvedCommit) == 40 {
+		path := c.WorktreePath(dep.URL, dep.ResolvedCommit)
+		if _, err := os.Stat(path); err == nil {
+			touch(c.RepoPath(dep.URL))
+			touch(path)
+			return &CachedDependency{Commit: dep.ResolvedCommit, Tag: dep.ResolvedTag, Worktree: path}, nil
+		}
+	}
+
+	repo, err := c.repository(dep.URL)
+	if err != nil {
+		return nil, err
+	}
+	if update {
+		dep.ResolvedCommit, dep.ResolvedTag = "", ""
+	}
+	commit, tag, err := lockedCommit(repo, dep)
+	if err != nil {
+		return nil, err
+	}
+	path, err := c.worktree(dep.URL, commit)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedDependency{Commit: commit, Tag: tag, Worktree: path}, nil
+}
+
+// lockedCommit returns the full commit and tag a dependency is locked to,
+// resolving it from its version or branch when it has no locked commit, and
+// fetches the commit into repo if it is missing.
+func lockedCommit(repo *git.Repository, dep metadata.Dependency) (string, string, error) {
+	tag, commit := dep.ResolvedTag, dep.ResolvedCommit
+	if commit == "" {
+		var err error
+		switch {
+		case IsCommitPin(dep.Version):
+			commit = dep.Version
+		case dep.Version != "":
+			tag, commit, err = ResolveVersion(dep.URL, dep.Version)
+		default:
+			commit, err = ResolveRemoteCommit(repo, dep.Branch)
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+	hash, err := EnsureCommit(repo, tag, commit)
+	if err != nil {
+		return "", "", err
+	}
+	return hash.String(), tag, nil
+}
+
+// extractCommit writes the files of a commit to path, read-only
+func extractCommit(repo *git.Repository, commit, path string) error {
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return fmt.Errorf("commit %s not found: %w", commit, err)
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return fmt.Errorf("failed to read tree of %s: %w", commit, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(path), extractPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		target, err := treePath(tmp, f.Name)
+		if err != nil {
+			return err
+		}
+		if err := checkParents(tmp, target); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		content, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		if f.Mode == filemode.Symlink {
+			if err := checkLinkTarget(tmp, target, content); err != nil {
+				return err
+			}
+			return os.Symlink(content, target)
+		}
+		perm := os.FileMode(0444)
+		if f.Mode == filemode.Executable {
+			perm = 0555
+		}
+		// O_EXCL so a duplicate entry cannot write through an earlier symlink
+		// #nosec G302 G304 -- worktrees are read-only copies of public commits
+		out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if err != nil {
+			return err
+		}
+		if _, err := out.WriteString(content); err != nil {
+			_ = out.Close()
+			return err
+		}
+		return out.Close()
+	})
+	if err == nil {
+		err = setDirsMode(tmp, 0555)
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+		if _, statErr := os.Stat(path); err != nil && statErr == nil {
+			err = nil // Extracted concurrently
+		}
+	}
+	if err != nil {
+		removeTree(tmp)
+		return fmt.Errorf("failed to extract %s: %w", commit, err)
+	}
+	return nil
+}
+
+// treePath returns where a tree entry is written under root. As in git's own
+// checkout, names that are absolute, climb out with .. or reach into .git are
+// refused.
+func treePath(root, name string) (string, error) {
+	if path.IsAbs(name) || filepath.IsAbs(filepath.FromSlash(name)) || filepath.VolumeName(filepath.FromSlash(name)) != "" {
+		return "", fmt.Errorf("%w: %q is absolute", ErrUnsafePath, name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." || strings.EqualFold(part, ".git") {
+			return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+		}
+	}
+	target := filepath.Join(root, filepath.FromSlash(name))
+	if !within(target, root) || target == root {
+		return "", fmt.Errorf("%w: %q is outside the worktree", ErrUnsafePath, name)
+	}
+	return target, nil
+}
+
+// checkParents refuses to write target when one of its parent directories is
+// already something other than a directory, so files cannot be written
+// through a symlink extracted earlier
+func checkParents(root, target string) error {
+	rel, err := filepath.Rel(root, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return err
+	}
+	dir := root
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%w: %s is beyond a symbolic link", ErrUnsafePath, target)
+		}
+	}
+	return nil
+}
+
+// checkLinkTarget refuses symlinks that point outside root. Targets must be
+// relative and may only climb with leading .. components, so resolving them
+// lexically gives the same directory the filesystem would.
+func checkLinkTarget(root, link, target string) error {
+	if target == "" || path.IsAbs(target) || filepath.IsAbs(filepath.FromSlash(target)) || filepath.VolumeName(filepath.FromSlash(target)) != "" {
+		return fmt.Errorf("%w: symlink %s points to %q", ErrUnsafePath, link, target)
+	}
+	climbing := true
+	for _, part := range strings.Split(target, "/") {
+		switch {
+		case strings.EqualFold(part, ".git"), part == ".." && !climbing:
+			return fmt.Errorf("%w: symlink %s points to %q", ErrUnsafePath, link, target)
+		case part != ".." && part != "." && part != "":
+			climbing = false
+		}
+	}
+	if !within(filepath.Join(filepath.Dir(link), filepath.FromSlash(target)), root) {
+		return fmt.Errorf("%w: symlink %s points outside the worktree", ErrUnsafePath, link)
+	}
+	return nil
+}
+
+func setDirsMode(root string, mode os.FileMode) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.Chmod(path, mode)
+		}
+		return nil
+	})
+}
+
+// removeTree removes a directory, including read-only worktrees
+func removeTree(path string) error {
+	_ = setDirsMode(path, 0755)
+	return os.RemoveAll(path)
+}
+
+// CacheProject is a project registered as a user of the cache
+type CacheProject struct {
+	Path     string    `json:"path"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+type cacheProjects struct {
+	Projects []CacheProject `json:"projects"`
+}
+
+// RegisterProject records that a project uses the cache, so that garbage
+// collection keeps the commits it is locked to
+func (c *Cache) RegisterProject(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	return c.withProjectsLock(func() error {
+		projects, err := c.loadProjects()
+		if err != nil {
+			return err
+		}
+		found := false
+		for i := range projects {
+			if projects[i].Path == abs {
+				projects[i].LastUsed = time.Now()
+				found = true
+			}
+		}
+		if !found {
+			projects = append(projects, CacheProject{Path: abs, LastUsed: time.Now()})
+		}
+		return c.saveProjects(projects)
+	})
+}
+
+// withProjectsLock runs fn while holding the flock on the project registry,
+// waiting for other runs to release it. loadProjects and saveProjects must
+// only be paired under it, or concurrent registrations get lost.
+func (c *Cache) withProjectsLock(fn func() error) error {
+	if err := os.MkdirAll(c.Root, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(c.Root, cacheLockFile))
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("failed to lock cache projects: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
+
+// withSharedLock runs fn while holding the registry flock shared, so runs can
+// fetch side by side but Prune, which holds it exclusively, never removes an
+// entry that is being cloned, extracted or handed to a project.
+func (c *Cache) withSharedLock(fn func() error) error {
+	if err := os.MkdirAll(c.Root, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(c.Root, cacheLockFile))
+	if err := lock.RLock(); err != nil {
+		return fmt.Errorf("failed to lock cache: %w", err)
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
+
+// touch marks a cache entry as just used, for pruneGracePeriod
+func touch(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// recentlyUsed reports whether a cache entry was fetched within the grace
+// period
+func recentlyUsed(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) < pruneGracePeriod
+}
+
+func (c *Cache) loadProjects() ([]CacheProject, error) {
+	data, err := os.ReadFile(filepath.Join(c.Root, cacheProjectsFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache projects: %w", err)
+	}
+	var file cacheProjects
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", cacheProjectsFile, err)
+	}
+	return file.Projects, nil
+}
+
+func (c *Cache) saveProjects(projects []CacheProject) error {
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Path < projects[j].Path })
+	data, err := json.MarshalIndent(cacheProjects{Projects: projects}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.Root, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	// #nosec G306 -- the registry only lists project paths
+	if err := os.WriteFile(filepath.Join(c.Root, cacheProjectsFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache projects: %w", err)
+	}
+	return nil
+}
+
+// CachedRepo is a cached remote with its worktrees
+type CachedRepo struct {
+	Key  string `json:"key"`
+	URL  string `json:"url,omitempty"`
+	Path string `json:"path"`
+	// Size is the size of the bare repository, without worktrees
+	Size      int64             `json:"size"`
+	Worktrees []*CachedWorktree `json:"worktrees"`
+	// Projects are the registered projects depending on the remote
+	Projects []string `json:"projects,omitempty"`
+}
+
+// CachedWorktree is the worktree of one commit
+type CachedWorktree struct {
+	Commit string `json:"commit"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	// Projects are the registered projects locked to the commit
+	Projects []string `json:"projects,omitempty"`
+}
+
+// CacheReport describes the contents of the cache
+type CacheReport struct {
+	Root     string        `json:"root"`
+	Repos    []*CachedRepo `json:"repos"`
+	Projects []string      `json:"projects"`
+	// Stale are registered projects that no longer exist
+	Stale []string `json:"stale_projects,omitempty"`
+	// Leftovers are checkouts of the old per-alias layout and interrupted clones
+	Leftovers []string `json:"leftovers,omitempty"`
+	// Linked are checkouts of the old per-alias layout that a registered
+	// project still links to; they are kept until it resolves again
+	Linked []string `json:"linked,omitempty"`
+	Size   int64    `json:"size"`
+}
+
+// List reports the cached repositories and worktrees, which registered
+// projects use them, and their sizes
+func (c *Cache) List() (*CacheReport, error) {
+	report := &CacheReport{Root: c.Root}
+	repoRefs, commitRefs, links, err := c.references(report)
+	if err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]*CachedRepo)
+	repoFor := func(key string) *CachedRepo {
+		if r, ok := byKey[key]; ok {
+			return r
+		}
+		r := &CachedRepo{Key: key, Path: filepath.Join(c.Root, cacheReposDir, key+".git"), Projects: repoRefs[key]}
+		byKey[key] = r
+		return r
+	}
+
+	repoEntries, err := readDirIfExists(filepath.Join(c.Root, cacheReposDir))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range repoEntries {
+		key, ok := strings.CutSuffix(entry.Name(), ".git")
+		if !entry.IsDir() || !ok {
+			report.Leftovers = append(report.Leftovers, filepath.Join(c.Root, cacheReposDir, entry.Name()))
+			continue
+		}
+		r := repoFor(key)
+		r.Size = dirSize(r.Path)
+		if repo, err := OpenRepository(r.Path); err == nil {
+			if remote, err := repo.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+				r.URL = remote.Config().URLs[0]
+			}
+		}
+	}
+
+	keyEntries, err := readDirIfExists(filepath.Join(c.Root, cacheWorktreesDir))
+	if err != nil {
+		return nil, err
+	}
+	for _, keyEntry := range keyEntries {
+		keyDir := filepath.Join(c.Root, cacheWorktreesDir, keyEntry.Name())
+		if !keyEntry.IsDir() {
+			report.Leftovers = append(report.Leftovers, keyDir)
+			continue
+		}
+		r := repoFor(keyEntry.Name())
+		commits, err := readDirIfExists(keyDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range commits {
+			path := filepath.Join(keyDir, entry.Name())
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), extractPrefix) {
+				report.Leftovers = append(report.Leftovers, path)
+				continue
+			}
+			r.Worktrees = append(r.Worktrees, &CachedWorktree{
+				Commit:   entry.Name(),
+				Path:     path,
+				Size:     dirSize(path),
+				Projects: commitRefs[keyEntry.Name()][entry.Name()],
+			})
+		}
+	}
+
+	rootEntries, err := readDirIfExists(c.Root)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range rootEntries {
+		switch entry.Name() {
+		case cacheReposDir, cacheWorktreesDir, cacheProjectsFile, cacheLockFile:
+		default:
+			path := filepath.Join(c.Root, entry.Name())
+			if slices.ContainsFunc(links, func(target string) bool { return within(target, path) }) {
+				report.Linked = append(report.Linked, path)
+			} else {
+				report.Leftovers = append(report.Leftovers, path)
+			}
+		}
+	}
+
+	for _, r := range byKey {
+		report.Repos = append(report.Repos, r)
+		report.Size += r.Size
+		for _, wt := range r.Worktrees {
+			report.Size += wt.Size
+		}
+	}
+	for _, path := range slices.Concat(report.Leftovers, report.Linked) {
+		report.Size += dirSize(path)
+	}
+	sort.Slice(report.Repos, func(i, j int) bool { return report.Repos[i].Key < report.Repos[j].Key })
+	return report, nil
+}
+
+// references maps cache keys to the registered projects depending on them,
+// and cache keys and commits to the projects locked to them, and returns the
+// targets of the projects' dependency links. Projects are recorded in the
+// report, split into live and stale ones.
+func (c *Cache) references(report *CacheReport) (map[string][]string, map[string]map[string][]string, []string, error) {
+	projects, err := c.loadProjects()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var links []string
+	repoRefs := make(map[string][]string)
+	commitRefs := make(map[string]map[string][]string)
+	for _, p := range projects {
+		meta, err := metadata.LoadFromProject(p.Path)
+		if errors.Is(err, fs.ErrNotExist) {
+			report.Stale = append(report.Stale, p.Path)
+			continue
+		}
+		if err != nil {
+			// Without its lock, nothing the project may use can be collected
+			return nil, nil, nil, fmt.Errorf("failed to read %s: %w", filepath.Join(p.Path, metadata.DefaultMetadataFile), err)
+		}
+		report.Projects = append(report.Projects, p.Path)
+		links = append(links, dependencyLinks(filepath.Join(p.Path, meta.GetArtifactPath(), "deps"))...)
+
+		all := append([]metadata.Dependency{}, meta.Dependencies...)
+		for _, dep := range meta.Transitive {
+			all = append(all, dep.Dependency)
+		}
+		for _, dep := range all {
+			key := CacheKey(dep.URL)
+			if !slices.Contains(repoRefs[key], p.Path) {
+				repoRefs[key] = append(repoRefs[key], p.Path)
+			}
+			if dep.ResolvedCommit == "" {
+				continue
+			}
+			if commitRefs[key] == nil {
+				commitRefs[key] = make(map[string][]string)
+			}
+			if !slices.Contains(commitRefs[key][dep.ResolvedCommit], p.Path) {
+				commitRefs[key][dep.ResolvedCommit] = append(commitRefs[key][dep.ResolvedCommit], p.Path)
+			}
+		}
+	}
+	return repoRefs, commitRefs, links, nil
+}
+
+// dependencyLinks returns the absolute targets of the symlinks in a project's
+// deps directory
+func dependencyLinks(depsDir string) []string {
+	entries, err := readDirIfExists(depsDir)
+	if err != nil {
+		return nil
+	}
+	var targets []string
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(depsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(depsDir, target)
+		}
+		targets = append(targets, filepath.Clean(target))
+	}
+	return targets
+}
+
+// within reports whether path is dir or lies below it
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// PruneResult lists what garbage collection removed, or would remove
+type PruneResult struct {
+	Worktrees []*CachedWorktree `json:"worktrees"`
+	Repos     []*CachedRepo     `json:"repos"`
+	Leftovers []string          `json:"leftovers"`
+	Stale     []string          `json:"stale_projects"`
+	Freed     int64             `json:"freed"`
+}
 
-	dirName := alias
-	if dirName == "" {
-		dirName = generateDirName(url)
+// Prune removes worktrees of commits no registered project is locked to,
+// repositories no registered project depends on, leftovers and stale
+// project registrations. With dryRun nothing is removed. The project registry
+// stays locked throughout, so no project registers or fetches while it is
+// collected. Repositories and worktrees fetched within the last hour are kept,
+// since the project that fetched them may not have saved its lock yet.
+func (c *Cache) Prune(dryRun bool) (*PruneResult, error) {
+	var result *PruneResult
+	err := c.withProjectsLock(func() error {
+		var err error
+		result, err = c.prune(dryRun)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Cache) prune(dryRun bool) (*PruneResult, error) {
+	report, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	result := &PruneResult{Leftovers: report.Leftovers, Stale: report.Stale}
+	var remove []string
+
+	for _, r := range report.Repos {
+		if len(r.Projects) == 0 && !recentlyUsed(r.Path) && !slices.ContainsFunc(r.Worktrees, func(wt *CachedWorktree) bool {
+			return recentlyUsed(wt.Path)
+		}) {
+			result.Repos = append(result.Repos, r)
+			result.Freed += r.Size
+			remove = append(remove, r.Path)
+			for _, wt := range r.Worktrees {
+				result.Freed += wt.Size
+			}
+			remove = append(remove, filepath.Join(c.Root, cacheWorktreesDir, r.Key))
+			continue
+		}
+		for _, wt := range r.Worktrees {
+			if len(wt.Projects) == 0 && !recentlyUsed(wt.Path) {
+				result.Worktrees = append(result.Worktrees, wt)
+				result.Freed += wt.Size
+				remove = append(remove, wt.Path)
+			}
+		}
 	}
+	for _, path := range report.Leftovers {
+		result.Freed += dirSize(path)
+		remove = append(remove, path)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	for _, path := range remove {
+		if err := removeTree(path); err != nil {
+			return nil, fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+	}
+	if len(report.Stale) > 0 {
+		projects, err := c.loadProjects()
+		if err != nil {
+			return nil, err
+		}
+		var live []CacheProject
+		for _, p := range projects {
+			if !slices.Contains(report.Stale, p.Path) {
+				live = append(live, p)
+			}
+		}
+		if err := c.saveProjects(live); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
 
-	return filepath.Join(cacheDir, dirName), nil
+// CacheProblem is a damaged cache entry found by Verify
+type CacheProblem struct {
+	Path    string `json:"path"`
+	Problem string `json:"problem"`
+	Fixed   bool   `json:"fixed"`
+}
+
+// Verify checks that every bare repository opens and every worktree matches
+// its commit exactly. With fix, damaged worktrees are extracted again and
+// damaged repositories removed, to be cloned again on next use.
+func (c *Cache) Verify(fix bool) ([]CacheProblem, error) {
+	report, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	var problems []CacheProblem
+	for _, r := range report.Repos {
+		repo, err := OpenRepository(r.Path)
+		if err != nil {
+			p := CacheProblem{Path: r.Path, Problem: "repository is missing or unreadable"}
+			if _, statErr := os.Stat(r.Path); statErr == nil && fix {
+				p.Fixed = removeTree(r.Path) == nil
+			}
+			problems = append(problems, p)
+		}
+		for _, wt := range r.Worktrees {
+			problem := "repository is missing"
+			if repo != nil {
+				problem = verifyWorktree(repo, wt)
+			}
+			if problem == "" {
+				continue
+			}
+			p := CacheProblem{Path: wt.Path, Problem: problem}
+			if fix {
+				p.Fixed = removeTree(wt.Path) == nil
+				if p.Fixed && repo != nil {
+					p.Fixed = extractCommit(repo, wt.Commit, wt.Path) == nil
+				}
+			}
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// verifyWorktree compares a worktree with its commit and describes the
+// differences, or returns "" when they match
+func verifyWorktree(repo *git.Repository, wt *CachedWorktree) string {
+	c, err := repo.CommitObject(plumbing.NewHash(wt.Commit))
+	if err != nil {
+		return "commit is missing from the repository"
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return "commit tree is unreadable"
+	}
+
+	var missing, modified, extra int
+	expected := make(map[string]bool)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		expected[f.Name] = true
+		path, err := treePath(wt.Path, f.Name)
+		if err != nil {
+			return err
+		}
+		var data []byte
+		if f.Mode == filemode.Symlink {
+			var target string
+			target, err = os.Readlink(path)
+			data = []byte(target)
+		} else {
+			data, err = os.ReadFile(path)
+		}
+		switch {
+		case err != nil:
+			missing++
+		case plumbing.ComputeHash(plumbing.BlobObject, data) != f.Hash:
+			modified++
+		}
+		return nil
+	})
+	if errors.Is(err, ErrUnsafePath) {
+		return "commit has unsafe paths"
+	}
+	if err != nil {
+		return "commit tree is unreadable"
+	}
+	_ = filepath.WalkDir(wt.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, _ := filepath.Rel(wt.Path, path)
+		if !expected[filepath.ToSlash(rel)] {
+			extra++
+		}
+		return nil
+	})
+
+	var parts []string
+	if modified > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", modified))
+	}
+	if missing > 0 {
+		parts = append(parts, fmt.Sprintf("%d missing", missing))
+	}
+	if extra > 0 {
+		parts = append(parts, fmt.Sprintf("%d unexpected", extra))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return strings.Join(parts, ", ") + " file(s)"
+}
+
+func readDirIfExists(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return entries, nil
 }
 
-// generateDirName generates a directory name from a Git URL.
-func generateDirName(url string) string {
-	// This is a placeholder - the actual implementation in pkg/cli/commands/deps.go
-	// has the full logic. This can be refactored later to share code.
-	return "dep"
+// dirSize returns the total size of the files under path
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
 }
@@ -0,0 +1,273 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+func TestCacheWorktrees(t *testing.T) {
+	dir := t.TempDir()
+	spec := newTestRepo(t, filepath.Join(dir, "spec"))
+	c1 := spec.commit()
+	c2 := spec.commit(metadata.Dependency{URL: "https://github.com/org/other", Alias: "other"})
+
+	cache := &Cache{Root: filepath.Join(dir, "cache")}
+	t.Cleanup(func() { _ = removeTree(cache.Root) }) // worktrees are read-only
+
+	// Two projects locked to different commits of the same repository
+	old, err := cache.Fetch(metadata.Dependency{URL: spec.dir, ResolvedCommit: c1}, false)
+	if err != nil {
+		t.Fatalf("Fetch(c1) error: %v", err)
+	}
+	latest, err := cache.Fetch(metadata.Dependency{URL: spec.dir, Branch: "master"}, false)
+	if err != nil {
+		t.Fatalf("Fetch(master) error: %v", err)
+	}
+	if latest.Commit != c2 {
+		t.Errorf("Fetch(master) = %s, want the branch head %s", latest.Commit, c2)
+	}
+	if old.Worktree == latest.Worktree {
+		t.Fatal("worktrees of different commits share a directory")
+	}
+	for _, wt := range []*CachedDependency{old, latest} {
+		if _, err := metadata.LoadFromProject(wt.Worktree); err != nil {
+			t.Errorf("worktree of %s is incomplete: %v", wt.Commit, err)
+		}
+	}
+	if meta, _ := metadata.LoadFromProject(old.Worktree); len(meta.Dependencies) != 0 {
+		t.Error("worktree of c1 has the files of c2")
+	}
+
+	file := filepath.Join(latest.Worktree, metadata.DefaultMetadataFile)
+	if info, err := os.Stat(file); err != nil || info.Mode().Perm()&0222 != 0 {
+		t.Errorf("worktree file mode = %v, %v; want read-only", info.Mode(), err)
+	}
+
+	// Verify notices a modified worktree and restores it
+	if problems, err := cache.Verify(false); err != nil || len(problems) != 0 {
+		t.Fatalf("Verify() = %+v, %v; want an intact cache", problems, err)
+	}
+	if err := os.Chmod(file, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("edited"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := cache.Verify(true)
+	if err != nil || len(problems) != 1 || !problems[0].Fixed || problems[0].Problem != "1 modified file(s)" {
+		t.Fatalf("Verify(fix) = %+v, %v; want one fixed modification", problems, err)
+	}
+	if _, err := metadata.LoadFromProject(latest.Worktree); err != nil {
+		t.Errorf("worktree not restored: %v", err)
+	}
+
+	// Prune keeps only what registered projects are locked to
+	project := filepath.Join(dir, "app")
+	meta := metadata.NewProjectMetadata("app", "app", "specledger", "1.0.0", nil, "1.0.0")
+	meta.Dependencies = []metadata.Dependency{{URL: spec.dir, Alias: "spec", ResolvedCommit: c2}}
+	if err := metadata.SaveToProject(meta, project); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.RegisterProject(project); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.RegisterProject(filepath.Join(dir, "deleted")); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(cache.Root, "spec")
+	if err := os.MkdirAll(legacy, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// An old checkout the project has not relinked yet
+	linked := filepath.Join(cache.Root, "api")
+	if err := os.MkdirAll(filepath.Join(linked, "specledger"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(project, "specledger", "deps"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(linked, "specledger"), filepath.Join(project, "specledger", "deps", "api")); err != nil {
+		t.Fatal(err)
+	}
+
+	// c1 was fetched just now, so a project may be about to lock to it
+	result, err := cache.Prune(true)
+	if err != nil {
+		t.Fatalf("Prune(dryRun) error: %v", err)
+	}
+	if len(result.Worktrees) != 0 {
+		t.Errorf("Prune(dryRun) would remove worktrees %+v fetched within the grace period", result.Worktrees)
+	}
+	stale := time.Now().Add(-2 * pruneGracePeriod)
+	if err := os.Chtimes(old.Worktree, stale, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = cache.Prune(false)
+	if err != nil {
+		t.Fatalf("Prune() error: %v", err)
+	}
+	if len(result.Worktrees) != 1 || result.Worktrees[0].Commit != c1 {
+		t.Errorf("Prune() removed worktrees %+v, want only c1", result.Worktrees)
+	}
+	if len(result.Repos) != 0 || len(result.Leftovers) != 1 || len(result.Stale) != 1 {
+		t.Errorf("Prune() = %+v, want the leftover and the stale project removed", result)
+	}
+	for path, want := range map[string]bool{old.Worktree: false, latest.Worktree: true, cache.RepoPath(spec.dir): true, legacy: false, linked: true} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", path, err == nil, want)
+		}
+	}
+
+	report, err := cache.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(report.Repos) != 1 || len(report.Projects) != 1 || len(report.Stale) != 0 || report.Size == 0 {
+		t.Errorf("List() = %+v, want one repository used by one project", report)
+	}
+	if len(report.Linked) != 1 || report.Linked[0] != linked || len(report.Leftovers) != 0 {
+		t.Errorf("List() linked = %v, leftovers = %v; want the linked old checkout kept", report.Linked, report.Leftovers)
+	}
+}
+
+func TestRegisterProjectConcurrently(t *testing.T) {
+	dir := t.TempDir()
+	cache := &Cache{Root: filepath.Join(dir, "cache")}
+
+	const n = 20
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := cache.RegisterProject(filepath.Join(dir, fmt.Sprintf("project-%d", i))); err != nil {
+				t.Errorf("RegisterProject() error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	projects, err := cache.loadProjects()
+	if err != nil {
+		t.Fatalf("loadProjects() error: %v", err)
+	}
+	if len(projects) != n {
+		t.Errorf("%d projects registered, want %d", len(projects), n)
+	}
+}
+
+// treeEntry is a file in a hand-built tree; names are written as given
+type treeEntry struct {
+	name, content string
+	mode          filemode.FileMode
+}
+
+// craftCommit stores a commit whose tree holds entries verbatim, bypassing
+// the validation a worktree checkout would apply
+func craftCommit(t *testing.T, repo *git.Repository, entries ...treeEntry) string {
+	t.Helper()
+	store := func(obj interface {
+		Encode(plumbing.EncodedObject) error
+	}) plumbing.Hash {
+		encoded := repo.Storer.NewEncodedObject()
+		if err := obj.Encode(encoded); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := repo.Storer.SetEncodedObject(encoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+	tree := &object.Tree{}
+	for _, e := range entries {
+		blob := repo.Storer.NewEncodedObject()
+		blob.SetType(plumbing.BlobObject)
+		w, err := blob.Writer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.content)); err != nil {
+			t.Fatal(err)
+		}
+		_ = w.Close()
+		hash, err := repo.Storer.SetEncodedObject(blob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree.Entries = append(tree.Entries, object.TreeEntry{Name: e.name, Mode: e.mode, Hash: hash})
+	}
+	sort.Slice(tree.Entries, func(i, j int) bool { return tree.Entries[i].Name < tree.Entries[j].Name })
+	sig := object.Signature{Name: "test", Email: "test@test.com", When: time.Now()}
+	return store(&object.Commit{Author: sig, Committer: sig, Message: "crafted", TreeHash: store(tree)}).String()
+}
+
+func TestExtractCommitRejectsUnsafePaths(t *testing.T) {
+	dir := t.TempDir()
+	t.Cleanup(func() { _ = removeTree(dir) })
+	repo, err := git.Init(memory.NewStorage(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsafe := map[string][]treeEntry{
+		"parent":        {{name: "../escaped", content: "x", mode: filemode.Regular}},
+		"git dir":       {{name: ".git/hooks/post-checkout", content: "x", mode: filemode.Executable}},
+		"absolute link": {{name: "link", content: "/etc", mode: filemode.Symlink}},
+		"escaping link": {{name: "link", content: "../../escaped", mode: filemode.Symlink}},
+		"inner climb":   {{name: "link", content: "a/../../escaped", mode: filemode.Symlink}},
+		"through link": {
+			{name: "d", content: ".", mode: filemode.Symlink},
+			{name: "d/up", content: "../escaped", mode: filemode.Symlink},
+		},
+	}
+	for name, entries := range unsafe {
+		worktree := filepath.Join(dir, "worktrees", "wt")
+		err := extractCommit(repo, craftCommit(t, repo, entries...), worktree)
+		if !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("%s: extractCommit() error = %v, want ErrUnsafePath", name, err)
+		}
+		if _, err := os.Lstat(worktree); err == nil {
+			t.Errorf("%s: worktree was created", name)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "escaped")); err == nil {
+		t.Error("a file was written outside the worktree")
+	}
+
+	// Links that stay inside the worktree are kept
+	worktree := filepath.Join(dir, "worktrees", "ok")
+	commit := craftCommit(t, repo,
+		treeEntry{name: "specledger/spec.md", content: "spec", mode: filemode.Regular},
+		treeEntry{name: "docs", content: "specledger", mode: filemode.Symlink},
+		treeEntry{name: "specledger/self", content: "../specledger/spec.md", mode: filemode.Symlink},
+	)
+	if err := extractCommit(repo, commit, worktree); err != nil {
+		t.Fatalf("extractCommit() error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(worktree, "docs", "self")); err != nil || string(data) != "spec" {
+		t.Errorf("in-tree links = %q, %v; want them followed", data, err)
+	}
+	wt := &CachedWorktree{Path: worktree, Commit: commit}
+	if problem := verifyWorktree(repo, wt); problem != "" {
+		t.Errorf("verifyWorktree() = %q, want an intact worktree", problem)
+	}
+	wt.Commit = craftCommit(t, repo, unsafe["parent"]...)
+	if problem := verifyWorktree(repo, wt); problem != "commit has unsafe paths" {
+		t.Errorf("verifyWorktree() = %q, want the unsafe path reported", problem)
+	}
+}
@@ -0,0 +1,99 @@
+package deps
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+)
+
+// FileChange is a file that changed between two commits
+type FileChange struct {
+	Path string `json:"path"`
+	// OldPath is the previous path of a renamed file
+	OldPath   string `json:"old_path,omitempty"`
+	Status    string `json:"status"` // added, modified, deleted or renamed
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary,omitempty"`
+	// Patch is the unified diff of the file
+	Patch string `json:"patch,omitempty"`
+}
+
+// DiffPath returns the changes between two commits to the files under dir,
+// or to every file when dir is empty. Both commits must be in the repository.
+func DiffPath(repo *git.Repository, from, to, dir string) ([]FileChange, error) {
+	fromTree, err := commitTree(repo, from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := commitTree(repo, to)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTreeWithOptions(context.Background(), fromTree, toTree, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff %s..%s: %w", cligit.ShortHash(from), cligit.ShortHash(to), err)
+	}
+
+	var result []FileChange
+	for _, change := range changes {
+		if !underDir(change.From.Name, dir) && !underDir(change.To.Name, dir) {
+			continue
+		}
+		action, err := change.Action()
+		if err != nil {
+			return nil, err
+		}
+		fc := FileChange{Path: change.To.Name}
+		switch {
+		case action == merkletrie.Insert:
+			fc.Status = "added"
+		case action == merkletrie.Delete:
+			fc.Status, fc.Path = "deleted", change.From.Name
+		case change.From.Name != change.To.Name:
+			fc.Status, fc.OldPath = "renamed", change.From.Name
+		default:
+			fc.Status = "modified"
+		}
+
+		patch, err := change.Patch()
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s: %w", fc.Path, err)
+		}
+		for _, stat := range patch.Stats() {
+			fc.Additions += stat.Addition
+			fc.Deletions += stat.Deletion
+		}
+		for _, fp := range patch.FilePatches() {
+			fc.Binary = fc.Binary || fp.IsBinary()
+		}
+		fc.Patch = patch.String()
+		result = append(result, fc)
+	}
+	return result, nil
+}
+
+func commitTree(repo *git.Repository, commit string) (*object.Tree, error) {
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return nil, fmt.Errorf("commit %s not found: %w", cligit.ShortHash(commit), err)
+	}
+	return c.Tree()
+}
+
+// underDir reports whether a slash-separated repository path is dir or lies
+// below it. Every path is under an empty dir.
+func underDir(name, dir string) bool {
+	dir = strings.Trim(path.Clean("/"+dir), "/")
+	if dir == "" {
+		return name != ""
+	}
+	return name == dir || strings.HasPrefix(name, dir+"/")
+}
@@ -0,0 +1,75 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestDiffPath(t *testing.T) {
+	r := newTestRepo(t, t.TempDir())
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(r.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		wt, err := r.repo.Worktree()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("specs/api.md", "one\ntwo\n")
+	write("specs/old.md", "old\n")
+	write("README.md", "readme\n")
+	from := r.commit()
+
+	write("specs/api.md", "one\n2\nthree\n")
+	write("specs/new/model.md", "model\n")
+	write("README.md", "changed\n")
+	wt, _ := r.repo.Worktree()
+	if _, err := wt.Remove("specs/old.md"); err != nil {
+		t.Fatal(err)
+	}
+	r.commit()
+	to := r.commit()
+
+	changes, err := DiffPath(r.repo, from, to, "specs/")
+	if err != nil {
+		t.Fatalf("DiffPath() error: %v", err)
+	}
+	got := make(map[string]FileChange)
+	for _, fc := range changes {
+		got[fc.Path] = fc
+	}
+	if len(changes) != 3 {
+		t.Fatalf("DiffPath() = %+v, want the three changes under specs/", changes)
+	}
+	if fc := got["specs/api.md"]; fc.Status != "modified" || fc.Additions != 2 || fc.Deletions != 1 || fc.Patch == "" {
+		t.Errorf("specs/api.md = %+v, want modified +2 -1 with a patch", fc)
+	}
+	if got["specs/new/model.md"].Status != "added" || got["specs/old.md"].Status != "deleted" {
+		t.Errorf("DiffPath() = %+v, want model.md added and old.md deleted", changes)
+	}
+
+	all, err := DiffPath(r.repo, from, to, "")
+	if err != nil {
+		t.Fatalf("DiffPath(no dir) error: %v", err)
+	}
+	if !slices.ContainsFunc(all, func(fc FileChange) bool { return fc.Path == "README.md" }) {
+		t.Errorf("DiffPath(no dir) = %+v, want README.md included", all)
+	}
+
+	commits, err := CommitsBetween(r.repo, from, to, 0)
+	if err != nil || len(commits) != 2 || commits[0].Hash.String() != to {
+		t.Errorf("CommitsBetween() = %d commits, %v; want 2 newest first", len(commits), err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
 )
@@ -237,43 +238,9 @@ func ResolveRemoteCommit(repo *git.Repository, branch string) (string, error) {
 // Log returns commit log between two revisions.
 // limit specifies the maximum number of commits to return (0 for unlimited).
 func Log(repo *git.Repository, from, to string, limit int) (string, error) {
-	// Parse the commit hashes
-	toHash := plumbing.NewHash(to)
-
-	// Get the commit iterator
-	commitIter, err := repo.Log(&git.LogOptions{
-		From:  toHash,
-		Order: git.LogOrderCommitterTime,
-	})
+	commits, err := CommitsBetween(repo, from, to, limit)
 	if err != nil {
-		return "", fmt.Errorf("failed to get commit log: %w", err)
-	}
-	defer commitIter.Close()
-
-	// Collect commits
-	var commits []string
-	count := 0
-
-	// Iterate through commits
-	for {
-		commit, err := commitIter.Next()
-		if err != nil {
-			break // No more commits
-		}
-
-		// Stop if we've reached the "from" commit
-		if commit.Hash.String() == from {
-			break
-		}
-
-		// Add commit to list
-		commits = append(commits, fmt.Sprintf("%s %s", commit.Hash.String()[:8], commit.Message))
-		count++
-
-		// Check limit
-		if limit > 0 && count >= limit {
-			break
-		}
+		return "", err
 	}
 
 	// If we didn't find any commits
@@ -287,12 +254,53 @@ func Log(repo *git.Repository, from, to string, limit int) (string, error) {
 		if i > 0 {
 			result += "\n"
 		}
-		result += commit
+		result += fmt.Sprintf("%s %s", commit.Hash.String()[:8], commit.Message)
 	}
 
 	return result, nil
 }
 
+// CommitsBetween returns the commits reachable from to but not from from,
+// newest first. limit specifies the maximum number of commits to return
+// (0 for unlimited).
+func CommitsBetween(repo *git.Repository, from, to string, limit int) ([]*object.Commit, error) {
+	// Everything from already has is not new; an unknown from excludes nothing
+	seen := make(map[plumbing.Hash]bool)
+	if from != "" {
+		if fromIter, err := repo.Log(&git.LogOptions{From: plumbing.NewHash(from)}); err == nil {
+			_ = fromIter.ForEach(func(c *object.Commit) error {
+				seen[c.Hash] = true
+				return nil
+			})
+		}
+	}
+
+	commitIter, err := repo.Log(&git.LogOptions{
+		From:  plumbing.NewHash(to),
+		Order: git.LogOrderCommitterTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+	defer commitIter.Close()
+
+	var commits []*object.Commit
+	for {
+		commit, err := commitIter.Next()
+		if err != nil {
+			break // No more commits
+		}
+		if seen[commit.Hash] {
+			continue
+		}
+		commits = append(commits, commit)
+		if limit > 0 && len(commits) >= limit {
+			break
+		}
+	}
+	return commits, nil
+}
+
 // getAuthForURL determines the appropriate authentication method for a Git URL.
 // Returns nil for public repositories, SSH auth for git@ URLs.
 func getAuthForURL(url string) (transport.AuthMethod, error) {
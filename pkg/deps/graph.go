@@ -0,0 +1,268 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// GraphNode is a single project in a spec dependency graph.
+type GraphNode struct {
+	ID             string `json:"id"`
+	Alias          string `json:"alias"`
+	URL            string `json:"url,omitempty"`
+	Branch         string `json:"branch,omitempty"`
+	ResolvedCommit string `json:"resolved_commit,omitempty"`
+	ArtifactPath   string `json:"artifact_path,omitempty"`
+	Depth          int    `json:"depth"`
+	// Cached is false when the dependency has not been resolved into the cache,
+	// in which case its own dependencies are unknown.
+	Cached bool `json:"cached"`
+	// Truncated is true when expansion stopped at this node because of the depth limit.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// GraphEdge is a "depends on" relationship between two nodes.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Graph is the spec dependency graph rooted at the current project.
+type Graph struct {
+	Root  string       `json:"root"`
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []GraphEdge  `json:"edges"`
+
+	index map[string]*GraphNode
+}
+
+// GraphOptions controls how a dependency graph is built.
+type GraphOptions struct {
+	// CacheDir is the dependency cache (defaults to CacheDir()).
+	CacheDir string
+	// MaxDepth limits transitive expansion. 1 means direct dependencies only,
+	// 0 means unlimited.
+	MaxDepth int
+}
+
+// BuildGraph builds the dependency graph for a project, starting from its
+// declared dependencies and following each cached dependency's own specledger.yaml.
+// Nodes are keyed by normalized repository URL so a dependency reached through
+// several paths appears once.
+func BuildGraph(meta *metadata.ProjectMetadata, opts GraphOptions) (*Graph, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("project metadata is required")
+	}
+
+	cacheDir := opts.CacheDir
+	if cacheDir == "" {
+		dir, err := CacheDir()
+		if err != nil {
+			return nil, err
+		}
+		cacheDir = dir
+	}
+
+	rootName := meta.Project.Name
+	if rootName == "" {
+		rootName = "project"
+	}
+
+	cache := &Cache{Root: cacheDir}
+	g := &Graph{
+		Root:  rootName,
+		index: make(map[string]*GraphNode),
+	}
+	g.addNode(&GraphNode{ID: rootName, Alias: rootName, ArtifactPath: meta.GetArtifactPath(), Cached: true})
+
+	type pending struct {
+		parent string
+		dep    metadata.Dependency
+		depth  int
+	}
+
+	queue := make([]pending, 0, len(meta.Dependencies))
+	for _, dep := range meta.Dependencies {
+		queue = append(queue, pending{parent: rootName, dep: dep, depth: 1})
+	}
+
+	// Breadth-first so each node records the shortest depth at which it is reached.
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+
+		id := NormalizeURL(item.dep.URL)
+		node, seen := g.index[id]
+		if !seen {
+			alias := item.dep.Alias
+			if alias == "" {
+				alias = aliasFromURL(item.dep.URL)
+			}
+			node = &GraphNode{
+				ID:             id,
+				Alias:          alias,
+				URL:            item.dep.URL,
+				Branch:         item.dep.Branch,
+				ResolvedCommit: item.dep.ResolvedCommit,
+				ArtifactPath:   item.dep.ArtifactPath,
+				Depth:          item.depth,
+			}
+			g.addNode(node)
+		}
+		g.addEdge(item.parent, id)
+
+		if seen {
+			continue
+		}
+
+		depMeta, ok := loadCachedMetadata(cache, node.URL, node.ResolvedCommit)
+		node.Cached = ok
+		if !ok || len(depMeta.Dependencies) == 0 {
+			continue
+		}
+
+		if opts.MaxDepth > 0 && item.depth >= opts.MaxDepth {
+			node.Truncated = true
+			continue
+		}
+
+		for _, child := range depMeta.Dependencies {
+			queue = append(queue, pending{parent: id, dep: child, depth: item.depth + 1})
+		}
+	}
+
+	return g, nil
+}
+
+// Node returns the node with the given ID, or nil.
+func (g *Graph) Node(id string) *GraphNode {
+	if g.index == nil {
+		g.reindex()
+	}
+	return g.index[id]
+}
+
+// Children returns the IDs of nodes that the given node depends on, sorted by alias.
+func (g *Graph) Children(id string) []string {
+	var children []string
+	for _, e := range g.Edges {
+		if e.From == id {
+			children = append(children, e.To)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return g.label(children[i]) < g.label(children[j])
+	})
+	return children
+}
+
+// Transitive returns every node reachable from the root, excluding the root itself,
+// ordered by depth and then alias.
+func (g *Graph) Transitive() []*GraphNode {
+	var nodes []*GraphNode
+	for _, n := range g.Nodes {
+		if n.ID != g.Root {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Depth != nodes[j].Depth {
+			return nodes[i].Depth < nodes[j].Depth
+		}
+		return nodes[i].Alias < nodes[j].Alias
+	})
+	return nodes
+}
+
+func (g *Graph) addNode(n *GraphNode) {
+	g.Nodes = append(g.Nodes, n)
+	g.index[n.ID] = n
+}
+
+func (g *Graph) addEdge(from, to string) {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to {
+			return
+		}
+	}
+	g.Edges = append(g.Edges, GraphEdge{From: from, To: to})
+}
+
+func (g *Graph) reindex() {
+	g.index = make(map[string]*GraphNode, len(g.Nodes))
+	for _, n := range g.Nodes {
+		g.index[n.ID] = n
+	}
+}
+
+func (g *Graph) label(id string) string {
+	if n := g.Node(id); n != nil {
+		return n.Alias
+	}
+	return id
+}
+
+// loadCachedMetadata reads the specledger.yaml of a dependency at its locked
+// commit, from the commit's worktree or else from the cached repository.
+func loadCachedMetadata(cache *Cache, url, commit string) (*metadata.ProjectMetadata, bool) {
+	if commit == "" {
+		return nil, false
+	}
+	if dir := cache.WorktreePath(url, commit); isDir(dir) {
+		meta, err := metadata.LoadFromProject(dir)
+		if err != nil {
+			// Cached, but not a SpecLedger repository (or unreadable metadata):
+			// treat it as a leaf.
+			return &metadata.ProjectMetadata{}, true
+		}
+		return meta, true
+	}
+	repo, err := OpenRepository(cache.RepoPath(url))
+	if err != nil {
+		return nil, false
+	}
+	if _, err := repo.CommitObject(plumbing.NewHash(commit)); err != nil {
+		return nil, false
+	}
+	if meta := readMetadataAt(repo, commit); meta != nil {
+		return meta, true
+	}
+	return &metadata.ProjectMetadata{}, true
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// NormalizeURL returns a canonical form of a git URL so the same repository
+// referenced via SSH or HTTPS, with or without ".git", maps to one key.
+func NormalizeURL(url string) string {
+	u := strings.TrimSpace(url)
+	u = strings.TrimSuffix(u, "/")
+	u = strings.TrimSuffix(u, ".git")
+
+	if strings.HasPrefix(u, "git@") {
+		u = strings.TrimPrefix(u, "git@")
+		u = strings.Replace(u, ":", "/", 1)
+	}
+	for _, prefix := range []string{"https://", "http://", "ssh://git@", "ssh://", "git://"} {
+		u = strings.TrimPrefix(u, prefix)
+	}
+
+	return strings.ToLower(u)
+}
+
+// aliasFromURL derives a short display name from a repository URL.
+func aliasFromURL(url string) string {
+	u := NormalizeURL(url)
+	if i := strings.LastIndex(u, "/"); i >= 0 {
+		return u[i+1:]
+	}
+	return u
+}
@@ -0,0 +1,310 @@
+package deps
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+)
+
+// Graph output formats supported by RenderGraph.
+const (
+	GraphFormatText    = "text"
+	GraphFormatJSON    = "json"
+	GraphFormatDOT     = "dot"
+	GraphFormatMermaid = "mermaid"
+	GraphFormatSVG     = "svg"
+)
+
+// GraphFormats lists every supported output format.
+var GraphFormats = []string{GraphFormatText, GraphFormatJSON, GraphFormatDOT, GraphFormatMermaid, GraphFormatSVG}
+
+// RenderGraph renders the graph in the requested format.
+func RenderGraph(g *Graph, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case GraphFormatText, "":
+		return RenderGraphText(g), nil
+	case GraphFormatJSON:
+		return RenderGraphJSON(g)
+	case GraphFormatDOT:
+		return RenderGraphDOT(g), nil
+	case GraphFormatMermaid:
+		return RenderGraphMermaid(g), nil
+	case GraphFormatSVG:
+		return RenderGraphSVG(g), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(GraphFormats, ", "))
+	}
+}
+
+// GraphFileExtension returns the conventional file extension for a format.
+func GraphFileExtension(format string) string {
+	switch strings.ToLower(format) {
+	case GraphFormatJSON:
+		return ".json"
+	case GraphFormatDOT:
+		return ".dot"
+	case GraphFormatMermaid:
+		return ".mmd"
+	case GraphFormatSVG:
+		return ".svg"
+	default:
+		return ".txt"
+	}
+}
+
+// RenderGraphText renders the graph as an indented tree. A dependency reached
+// through more than one path is expanded once and referenced afterwards.
+func RenderGraphText(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString(g.Root)
+	sb.WriteString("\n")
+
+	expanded := map[string]bool{g.Root: true}
+	var walk func(id, prefix string)
+	walk = func(id, prefix string) {
+		children := g.Children(id)
+		for i, child := range children {
+			last := i == len(children)-1
+			connector, nextPrefix := "├── ", prefix+"│   "
+			if last {
+				connector, nextPrefix = "└── ", prefix+"    "
+			}
+
+			node := g.Node(child)
+			sb.WriteString(prefix)
+			sb.WriteString(connector)
+			sb.WriteString(textNodeLabel(node))
+			if expanded[child] {
+				sb.WriteString(" (see above)\n")
+				continue
+			}
+			sb.WriteString("\n")
+			expanded[child] = true
+			walk(child, nextPrefix)
+		}
+	}
+	walk(g.Root, "")
+
+	if len(g.Edges) == 0 {
+		sb.WriteString("(no dependencies)\n")
+	}
+	return sb.String()
+}
+
+func textNodeLabel(n *GraphNode) string {
+	label := n.Alias
+	if n.Branch != "" {
+		label += "@" + n.Branch
+	}
+	if n.ResolvedCommit != "" {
+		label += " " + cligit.ShortHash(n.ResolvedCommit)
+	}
+	if !n.Cached {
+		label += " [not cached]"
+	}
+	if n.Truncated {
+		label += " [...]"
+	}
+	return label
+}
+
+// RenderGraphJSON renders the graph as indented JSON.
+func RenderGraphJSON(g *Graph) (string, error) {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal graph: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// RenderGraphDOT renders the graph in Graphviz DOT syntax.
+func RenderGraphDOT(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString("digraph dependencies {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=rounded];\n")
+
+	ids := nodeIDs(g)
+	for _, n := range g.Nodes {
+		attrs := "label=" + dotQuote(dotLabel(n))
+		switch {
+		case n.ID == g.Root:
+			attrs += ", style=\"rounded,bold\""
+		case !n.Cached:
+			attrs += ", style=\"rounded,dashed\""
+		}
+		fmt.Fprintf(&sb, "  %s [%s];\n", ids[n.ID], attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s;\n", ids[e.From], ids[e.To])
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func dotLabel(n *GraphNode) string {
+	label := n.Alias
+	if n.Branch != "" {
+		label += "\n" + n.Branch
+	}
+	if n.ResolvedCommit != "" {
+		label += "@" + cligit.ShortHash(n.ResolvedCommit)
+	}
+	return label
+}
+
+// dotQuote quotes a DOT string: quotes and backslashes are escaped and
+// newlines become Graphviz line breaks
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// mermaidQuote quotes a Mermaid node label: quotes and # become entity codes
+// and newlines become HTML line breaks
+func mermaidQuote(s string) string {
+	return `"` + strings.NewReplacer("#", "#35;", `"`, "#quot;", "\n", "<br/>").Replace(s) + `"`
+}
+
+// RenderGraphMermaid renders the graph as a Mermaid flowchart.
+func RenderGraphMermaid(g *Graph) string {
+	var sb strings.Builder
+	sb.WriteString("graph LR\n")
+
+	ids := nodeIDs(g)
+	for _, n := range g.Nodes {
+		label := n.Alias
+		if n.Branch != "" {
+			label += "\n" + n.Branch
+		}
+		fmt.Fprintf(&sb, "  %s[%s]\n", ids[n.ID], mermaidQuote(label))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+	for _, n := range g.Nodes {
+		if !n.Cached {
+			fmt.Fprintf(&sb, "  style %s stroke-dasharray: 5 5\n", ids[n.ID])
+		}
+	}
+	return sb.String()
+}
+
+// SVG layout constants.
+const (
+	svgNodeWidth  = 180
+	svgNodeHeight = 44
+	svgColGap     = 80
+	svgRowGap     = 24
+	svgMargin     = 20
+)
+
+// RenderGraphSVG renders the graph as a standalone SVG document. Nodes are laid
+// out in columns by depth, so the root is on the left and edges flow rightwards.
+func RenderGraphSVG(g *Graph) string {
+	columns := make(map[int][]*GraphNode)
+	maxDepth := 0
+	for _, n := range g.Nodes {
+		columns[n.Depth] = append(columns[n.Depth], n)
+		if n.Depth > maxDepth {
+			maxDepth = n.Depth
+		}
+	}
+
+	maxRows := 0
+	type point struct{ x, y int }
+	pos := make(map[string]point, len(g.Nodes))
+	for depth := 0; depth <= maxDepth; depth++ {
+		col := columns[depth]
+		sort.Slice(col, func(i, j int) bool { return col[i].Alias < col[j].Alias })
+		if len(col) > maxRows {
+			maxRows = len(col)
+		}
+		for row, n := range col {
+			pos[n.ID] = point{
+				x: svgMargin + depth*(svgNodeWidth+svgColGap),
+				y: svgMargin + row*(svgNodeHeight+svgRowGap),
+			}
+		}
+	}
+
+	width := 2*svgMargin + (maxDepth+1)*svgNodeWidth + maxDepth*svgColGap
+	height := 2*svgMargin + maxRows*svgNodeHeight + max(maxRows-1, 0)*svgRowGap
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"sans-serif\" font-size=\"12\">\n", width, height, width, height)
+	sb.WriteString("  <defs>\n")
+	sb.WriteString("    <marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"8\" markerHeight=\"8\" orient=\"auto-start-reverse\">\n")
+	sb.WriteString("      <path d=\"M 0 0 L 10 5 L 0 10 z\" fill=\"#555\"/>\n")
+	sb.WriteString("    </marker>\n")
+	sb.WriteString("  </defs>\n")
+	sb.WriteString("  <rect width=\"100%\" height=\"100%\" fill=\"#fff\"/>\n")
+
+	for _, e := range g.Edges {
+		from, to := pos[e.From], pos[e.To]
+		x1, y1 := from.x+svgNodeWidth, from.y+svgNodeHeight/2
+		x2, y2 := to.x, to.y+svgNodeHeight/2
+		if to.x <= from.x {
+			// Back or same-column edge (shared dependency reached again): route below.
+			x1, y1 = from.x+svgNodeWidth/2, from.y+svgNodeHeight
+			x2, y2 = to.x+svgNodeWidth/2, to.y+svgNodeHeight
+		}
+		fmt.Fprintf(&sb, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"#555\" stroke-width=\"1.5\" marker-end=\"url(#arrow)\"/>\n", x1, y1, x2, y2)
+	}
+
+	for _, n := range g.Nodes {
+		p := pos[n.ID]
+		fill, dash := "#eef4ff", ""
+		if n.ID == g.Root {
+			fill = "#d6e4ff"
+		}
+		if !n.Cached {
+			fill, dash = "#f5f5f5", " stroke-dasharray=\"4 3\""
+		}
+		fmt.Fprintf(&sb, "  <g>\n")
+		fmt.Fprintf(&sb, "    <title>%s</title>\n", html.EscapeString(svgTitle(n)))
+		fmt.Fprintf(&sb, "    <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"6\" fill=\"%s\" stroke=\"#3366cc\"%s/>\n", p.x, p.y, svgNodeWidth, svgNodeHeight, fill, dash)
+		fmt.Fprintf(&sb, "    <text x=\"%d\" y=\"%d\" text-anchor=\"middle\" font-weight=\"bold\">%s</text>\n", p.x+svgNodeWidth/2, p.y+18, html.EscapeString(n.Alias))
+		if sub := svgSubtitle(n); sub != "" {
+			fmt.Fprintf(&sb, "    <text x=\"%d\" y=\"%d\" text-anchor=\"middle\" fill=\"#666\" font-size=\"10\">%s</text>\n", p.x+svgNodeWidth/2, p.y+34, html.EscapeString(sub))
+		}
+		fmt.Fprintf(&sb, "  </g>\n")
+	}
+
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func svgTitle(n *GraphNode) string {
+	if n.URL == "" {
+		return n.Alias
+	}
+	return n.URL
+}
+
+func svgSubtitle(n *GraphNode) string {
+	parts := []string{}
+	if n.Branch != "" {
+		parts = append(parts, n.Branch)
+	}
+	if n.ResolvedCommit != "" {
+		parts = append(parts, cligit.ShortHash(n.ResolvedCommit))
+	}
+	if !n.Cached {
+		parts = append(parts, "not cached")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// nodeIDs assigns short, syntax-safe identifiers (n0, n1, ...) to every node
+// for formats where URLs are not valid identifiers.
+func nodeIDs(g *Graph) map[string]string {
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+	return ids
+}
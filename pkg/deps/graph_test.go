@@ -0,0 +1,162 @@
+package deps
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// writeCachedProject writes a specledger.yaml into the cached worktree of a dependency's commit.
+func writeCachedProject(t *testing.T, cacheDir, url, commit string, deps ...metadata.Dependency) {
+	t.Helper()
+	cache := &Cache{Root: cacheDir}
+	meta := metadata.NewProjectMetadata(aliasFromURL(url), "dep", "specledger", "1.0.0", nil, "1.0.0")
+	meta.Dependencies = deps
+	if err := metadata.SaveToProject(meta, cache.WorktreePath(url, commit)); err != nil {
+		t.Fatalf("failed to write cached metadata for %s: %v", url, err)
+	}
+}
+
+func setupGraphFixture(t *testing.T) (*metadata.ProjectMetadata, string) {
+	t.Helper()
+	cacheDir := t.TempDir()
+	platform, auth, shared := strings.Repeat("1", 40), strings.Repeat("2", 40), strings.Repeat("3", 40)
+
+	writeCachedProject(t, cacheDir, "git@github.com:org/platform.git", platform,
+		metadata.Dependency{URL: "https://github.com/org/shared", Alias: "shared", ResolvedCommit: shared},
+		metadata.Dependency{URL: "git@github.com:org/auth.git", Alias: "auth", ResolvedCommit: auth},
+	)
+	writeCachedProject(t, cacheDir, "https://github.com/org/auth", auth)
+	writeCachedProject(t, cacheDir, "https://github.com/org/shared", shared,
+		metadata.Dependency{URL: "https://github.com/org/core.git", Alias: "core"},
+	)
+
+	root := metadata.NewProjectMetadata("app", "app", "specledger", "1.0.0", nil, "1.0.0")
+	root.Dependencies = []metadata.Dependency{
+		{URL: "git@github.com:org/platform.git", Alias: "platform", Branch: "main", ResolvedCommit: platform},
+		{URL: "https://github.com/org/auth", Alias: "auth", ResolvedCommit: auth},
+	}
+	return root, cacheDir
+}
+
+func TestBuildGraph(t *testing.T) {
+	root, cacheDir := setupGraphFixture(t)
+
+	g, err := BuildGraph(root, GraphOptions{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	// app, platform, auth, shared, core — auth is reached twice but deduplicated.
+	if len(g.Nodes) != 5 {
+		t.Fatalf("expected 5 nodes, got %d", len(g.Nodes))
+	}
+	if len(g.Edges) != 5 {
+		t.Errorf("expected 5 edges, got %d: %v", len(g.Edges), g.Edges)
+	}
+
+	core := g.Node("github.com/org/core")
+	if core == nil {
+		t.Fatal("expected transitive dependency core in graph")
+	}
+	if core.Depth != 3 {
+		t.Errorf("expected core at depth 3, got %d", core.Depth)
+	}
+	if core.Cached {
+		t.Error("expected core to be reported as not cached")
+	}
+
+	auth := g.Node("github.com/org/auth")
+	if auth == nil || auth.Depth != 1 {
+		t.Errorf("expected auth at depth 1 via shortest path, got %+v", auth)
+	}
+}
+
+func TestBuildGraphMaxDepth(t *testing.T) {
+	root, cacheDir := setupGraphFixture(t)
+
+	g, err := BuildGraph(root, GraphOptions{CacheDir: cacheDir, MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	if got := len(g.Transitive()); got != 2 {
+		t.Errorf("expected 2 direct dependencies, got %d", got)
+	}
+	if p := g.Node("github.com/org/platform"); p == nil || !p.Truncated {
+		t.Errorf("expected platform to be marked truncated, got %+v", p)
+	}
+	if a := g.Node("github.com/org/auth"); a == nil || a.Truncated {
+		t.Errorf("auth has no dependencies and should not be truncated, got %+v", a)
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := map[string]string{
+		"git@github.com:org/spec.git":      "github.com/org/spec",
+		"https://github.com/org/spec":      "github.com/org/spec",
+		"https://github.com/Org/Spec.git/": "github.com/org/spec",
+		"ssh://git@github.com/org/spec":    "github.com/org/spec",
+	}
+	for in, want := range tests {
+		if got := NormalizeURL(in); got != want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderGraph(t *testing.T) {
+	root, cacheDir := setupGraphFixture(t)
+	g, err := BuildGraph(root, GraphOptions{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	text := RenderGraphText(g)
+	for _, want := range []string{"app\n", "platform@main", "core [not cached]", "auth 2222222 (see above)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text output missing %q:\n%s", want, text)
+		}
+	}
+
+	out, err := RenderGraph(g, GraphFormatJSON)
+	if err != nil {
+		t.Fatalf("json render failed: %v", err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("json output is not valid: %v", err)
+	}
+	if len(decoded.Nodes) != len(g.Nodes) {
+		t.Errorf("json round-trip lost nodes: %d vs %d", len(decoded.Nodes), len(g.Nodes))
+	}
+
+	dot := RenderGraphDOT(g)
+	if !strings.HasPrefix(dot, "digraph dependencies {") || strings.Count(dot, "->") != len(g.Edges) {
+		t.Errorf("unexpected dot output:\n%s", dot)
+	}
+	if strings.Contains(dot, `\\n`) || !strings.Contains(dot, `\n`) {
+		t.Errorf("dot labels should break lines with \\n:\n%s", dot)
+	}
+
+	mermaid := RenderGraphMermaid(g)
+	if !strings.HasPrefix(mermaid, "graph LR") || strings.Count(mermaid, "-->") != len(g.Edges) {
+		t.Errorf("unexpected mermaid output:\n%s", mermaid)
+	}
+
+	quoted := RenderGraphMermaid(&Graph{Nodes: []*GraphNode{{ID: "x", Alias: `say "hi"`, Branch: `fix/"quoted"#1`}}})
+	if want := `["say #quot;hi#quot;<br/>fix/#quot;quoted#quot;#35;1"]`; !strings.Contains(quoted, want) {
+		t.Errorf("mermaid label should escape quotes, want %s in:\n%s", want, quoted)
+	}
+
+	svg := RenderGraphSVG(g)
+	if !strings.HasPrefix(svg, "<svg") || strings.Count(svg, "<rect x=") != len(g.Nodes) {
+		t.Errorf("unexpected svg output:\n%s", svg)
+	}
+
+	if _, err := RenderGraph(g, "png"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
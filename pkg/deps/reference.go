@@ -31,6 +31,10 @@ import (
 // Returns:
 //   - The resolved file path (relative to project root)
 //   - An error if resolution fails
+//
+// When projectRoot is provided and the dependency is replaced by a local
+// working copy in the project's personal config (see 'sl deps replace'), the
+// artifact resolves into the working copy instead, still relative to projectRoot.
 func ResolveReference(projectArtifactPath, depAlias, artifactName, projectRoot string) (string, error) {
 	// Validate inputs
 	if projectArtifactPath == "" {
@@ -55,6 +59,10 @@ func ResolveReference(projectArtifactPath, depAlias, artifactName, projectRoot s
 
 	// If projectRoot is provided, check if the resolved file exists
 	if projectRoot != "" {
+		// A dependency replaced by a local working copy resolves there
+		if _, ok := Replacements(projectRoot)[depAlias]; ok {
+			return replacedArtifact(projectRoot, depAlias, artifactName)
+		}
 		fullPath := filepath.Join(projectRoot, resolvedPath)
 		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
 			return "", fmt.Errorf("artifact not found: %s (resolved from %s)", resolvedPath, depAlias+":"+artifactName)
@@ -132,3 +132,35 @@ func TestResolveReferenceMissingArtifact(t *testing.T) {
 		t.Error("ResolveReference() expected error for missing artifact, got nil")
 	}
 }
+
+func TestResolveReferenceReplaced(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// A local working copy replaces the platform dependency
+	localDir := filepath.Join(tempDir, "platform-local")
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		t.Fatalf("failed to create test directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "api.md"), []byte("# API Spec"), 0600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	personal := []byte("replace:\n  platform: platform-local\n")
+	if err := os.MkdirAll(filepath.Join(tempDir, "specledger"), 0755); err != nil {
+		t.Fatalf("failed to create test directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "specledger", "specledger.local.yaml"), personal, 0600); err != nil {
+		t.Fatalf("failed to write personal config: %v", err)
+	}
+
+	gotPath, err := ResolveReference("specledger/", "platform", "api.md", tempDir)
+	if err != nil {
+		t.Fatalf("ResolveReference() error = %v", err)
+	}
+	if gotPath != "platform-local/api.md" {
+		t.Errorf("ResolveReference() = %v, want platform-local/api.md", gotPath)
+	}
+
+	if _, err := ResolveReference("specledger/", "platform", "missing.md", tempDir); err == nil {
+		t.Error("ResolveReference() expected error for missing artifact, got nil")
+	}
+}
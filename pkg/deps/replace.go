@@ -0,0 +1,71 @@
+package deps
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/specledger/specledger/pkg/cli/config"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// Replacements returns the project's dependency replacements from its
+// personal config: local working copies used instead of the cache, by alias.
+// A project without personal config has none.
+func Replacements(projectRoot string) map[string]string {
+	cfg, err := config.LoadPersonal(projectRoot)
+	if err != nil {
+		return nil
+	}
+	return cfg.Replace
+}
+
+// ReplacementDir returns the directory holding a replaced dependency's
+// artifacts: the artifact path within its local working copy. ok is false
+// when the dependency is not replaced.
+func ReplacementDir(projectRoot string, dep metadata.Dependency) (dir string, ok bool) {
+	local, ok := Replacements(projectRoot)[dep.Alias]
+	if !ok {
+		return "", false
+	}
+	if !filepath.IsAbs(local) {
+		local = filepath.Join(projectRoot, local)
+	}
+	return filepath.Join(local, dep.ArtifactPath), true
+}
+
+// replacedArtifact returns the path of an artifact in a replaced dependency,
+// relative to projectRoot
+func replacedArtifact(projectRoot, depAlias, artifactName string) (string, error) {
+	dep := metadata.Dependency{Alias: depAlias}
+	if meta, err := metadata.LoadFromProject(projectRoot); err == nil {
+		for _, d := range meta.Dependencies {
+			if d.Alias == depAlias {
+				dep = d
+			}
+		}
+		for _, d := range meta.Transitive {
+			if d.Alias == depAlias {
+				dep = d.Dependency
+			}
+		}
+	}
+	dir, _ := ReplacementDir(projectRoot, dep)
+	fullPath, err := filepath.Abs(filepath.Join(dir, artifactName))
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("artifact not found: %s (%s is replaced by a local path)", fullPath, depAlias)
+	}
+
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, fullPath)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
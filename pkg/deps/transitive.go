@@ -0,0 +1,594 @@
+package deps
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// Requirement is one project's requirement on a repository, at the commit the
+// project's lock resolves it to (or, when the project never resolved it, the
+// commit its version constraint or branch resolves to on the remote).
+type Requirement struct {
+	RequiredBy string `json:"required_by"`
+	URL        string `json:"url"`
+	Branch     string `json:"branch,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Commit     string `json:"commit"`
+	Tag        string `json:"tag,omitempty"`
+
+	dep  metadata.Dependency
+	from string // version key of the requiring project, "" for the root
+}
+
+// Conflict reports requirements on one repository that no single commit satisfies
+type Conflict struct {
+	URL          string         `json:"url"`
+	Alias        string         `json:"alias"`
+	Reason       string         `json:"reason"`
+	Requirements []*Requirement `json:"requirements"`
+	// Overridden is true when the project requires the repository itself;
+	// its own requirement is then used and the others are ignored.
+	Overridden bool `json:"overridden"`
+}
+
+// Resolution is a project's fully resolved dependency graph
+type Resolution struct {
+	// Direct are the project's own dependencies with the selected commits
+	Direct []metadata.Dependency `json:"-"`
+	// Transitive are the dependencies of dependencies, with aliases made
+	// unique within the project, ordered by depth and alias
+	Transitive []metadata.LockedDependency `json:"-"`
+	Conflicts  []*Conflict                 `json:"conflicts"`
+	// Cycles are alias paths that start and end at the same project
+	Cycles [][]string `json:"cycles"`
+}
+
+// Unresolved returns the conflicts the project does not override
+func (r *Resolution) Unresolved() []*Conflict {
+	var unresolved []*Conflict
+	for _, c := range r.Conflicts {
+		if !c.Overridden {
+			unresolved = append(unresolved, c)
+		}
+	}
+	return unresolved
+}
+
+// TransitiveOptions controls how a dependency graph is resolved.
+type TransitiveOptions struct {
+	// RootURL is the project's own remote, used to detect dependencies that
+	// lead back to the project.
+	RootURL string
+	// Open returns the repository of a dependency, typically cloning it into
+	// the cache.
+	Open func(dep metadata.Dependency) (*git.Repository, error)
+}
+
+// ResolveTransitive resolves the full dependency graph of a project, following
+// the specledger.yaml of each dependency at the commit it is required at.
+//
+// Versions are chosen in the spirit of minimal version selection: every
+// project's lock names the commit it was built against, and for each
+// repository the lowest commit satisfying all of its requirers is selected.
+// For tag requirements that is the highest required tag, which must match
+// every constraint; for branch requirements it is the required commit that
+// all others are ancestors of, on a single branch. A repository reached
+// through several paths appears once. Requirements that cannot be met are
+// reported as conflicts; the project's own requirement wins over them.
+func ResolveTransitive(meta *metadata.ProjectMetadata, opts TransitiveOptions) (*Resolution, error) {
+	if meta == nil {
+		return nil, fmt.Errorf("project metadata is required")
+	}
+	if opts.Open == nil {
+		return nil, fmt.Errorf("an Open function is required")
+	}
+
+	r := &resolver{
+		opts:     opts,
+		root:     meta,
+		rootName: meta.Project.Name,
+		modules:  make(map[string]*module),
+		aliases:  make(map[string]string),
+		versions: make(map[string]*metadata.ProjectMetadata),
+		selected: make(map[string]*Requirement),
+	}
+	if r.rootName == "" {
+		r.rootName = "project"
+	}
+	if opts.RootURL != "" {
+		r.rootID = NormalizeURL(opts.RootURL)
+	}
+
+	if err := r.walk(); err != nil {
+		return nil, err
+	}
+	conflicts := r.selectVersions()
+	return r.resolution(conflicts), nil
+}
+
+type module struct {
+	id     string
+	alias  string
+	repo   *git.Repository
+	reqs   []*Requirement
+	direct bool
+}
+
+type resolver struct {
+	opts     TransitiveOptions
+	root     *metadata.ProjectMetadata
+	rootName string
+	rootID   string
+	modules  map[string]*module
+	order    []string          // module IDs in discovery order
+	aliases  map[string]string // alias -> module ID
+	// versions holds the metadata of each module version (id@commit) reached,
+	// nil when the version is not a SpecLedger project
+	versions map[string]*metadata.ProjectMetadata
+	selected map[string]*Requirement
+}
+
+// walk collects every requirement reachable from the project, through every
+// required version of every dependency.
+func (r *resolver) walk() error {
+	queue := make([]*Requirement, 0, len(r.root.Dependencies))
+	for _, dep := range r.root.Dependencies {
+		queue = append(queue, newRequirement(r.rootName, "", dep))
+	}
+
+	// Breadth-first, so the shortest path to a repository names its alias.
+	for len(queue) > 0 {
+		req := queue[0]
+		queue = queue[1:]
+
+		id := NormalizeURL(req.URL)
+		if id == r.rootID {
+			continue // A dependency on the project itself; reported as a cycle
+		}
+		m, err := r.module(id, req)
+		if err != nil {
+			return fmt.Errorf("failed to open %s (required by %s): %w", req.URL, req.RequiredBy, err)
+		}
+		if err := pinRequirement(m.repo, req); err != nil {
+			return fmt.Errorf("failed to resolve %s (required by %s): %w", req.URL, req.RequiredBy, err)
+		}
+		m.reqs = append(m.reqs, req)
+
+		key := versionKey(id, req.Commit)
+		if _, seen := r.versions[key]; seen {
+			continue
+		}
+		depMeta := readMetadataAt(m.repo, req.Commit)
+		r.versions[key] = depMeta
+		if depMeta == nil {
+			continue
+		}
+		for _, child := range depMeta.Dependencies {
+			queue = append(queue, newRequirement(m.alias, key, child))
+		}
+	}
+	return nil
+}
+
+func newRequirement(requiredBy, from string, dep metadata.Dependency) *Requirement {
+	return &Requirement{
+		RequiredBy: requiredBy,
+		URL:        dep.URL,
+		Branch:     dep.Branch,
+		Version:    dep.Version,
+		dep:        dep,
+		from:       from,
+	}
+}
+
+// module returns the module of a repository, opening it the first time it is
+// required. The project's own dependencies keep their aliases; other aliases
+// are kept unless another repository already uses them.
+func (r *resolver) module(id string, req *Requirement) (*module, error) {
+	if m, ok := r.modules[id]; ok {
+		m.direct = m.direct || req.from == ""
+		return m, nil
+	}
+
+	alias := req.dep.Alias
+	if req.from != "" || alias == "" {
+		alias = r.uniqueAlias(req.dep)
+	}
+	repo, err := r.opts.Open(req.dep)
+	if err != nil {
+		return nil, err
+	}
+	m := &module{id: id, alias: alias, repo: repo, direct: req.from == ""}
+	r.modules[id] = m
+	r.order = append(r.order, id)
+	r.aliases[alias] = id
+	return m, nil
+}
+
+func (r *resolver) uniqueAlias(dep metadata.Dependency) string {
+	base := aliasFromURL(dep.URL)
+	for _, candidate := range []string{dep.Alias, base} {
+		if candidate == "" || candidate == r.rootName {
+			continue
+		}
+		if _, taken := r.aliases[candidate]; !taken {
+			return candidate
+		}
+	}
+	for n := 2; ; n++ {
+		candidate := fmt.Sprintf("%s-%d", base, n)
+		if _, taken := r.aliases[candidate]; !taken {
+			return candidate
+		}
+	}
+}
+
+// pinRequirement sets the full commit and tag a requirement resolves to
+func pinRequirement(repo *git.Repository, req *Requirement) error {
+	commit, tag, err := lockedCommit(repo, req.dep)
+	if err != nil {
+		return err
+	}
+	req.Commit, req.Tag = commit, tag
+	return nil
+}
+
+// readMetadataAt reads the specledger.yaml of a commit. Repositories without
+// one, or with unreadable metadata, are treated as leaves.
+func readMetadataAt(repo *git.Repository, commit string) *metadata.ProjectMetadata {
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return nil
+	}
+	file, err := c.File(metadata.DefaultMetadataFile)
+	if err != nil {
+		return nil
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil
+	}
+	meta, err := metadata.Parse([]byte(content))
+	if err != nil {
+		return nil
+	}
+	return meta
+}
+
+func versionKey(id, commit string) string {
+	return id + "@" + commit
+}
+
+// selectVersions selects a version of every module. Only requirements from
+// the selected versions count, and selecting a version changes which
+// versions are selected, so selection repeats until it settles.
+func (r *resolver) selectVersions() []*Conflict {
+	for _, id := range r.order {
+		m := r.modules[id]
+		sel, _ := lowestSatisfying(m.repo, m.reqs)
+		if sel == nil {
+			sel = m.reqs[0]
+		}
+		r.selected[id] = sel
+	}
+
+	var conflicts []*Conflict
+	for round := 0; round <= len(r.order); round++ {
+		g := r.graph()
+		conflicts = nil
+		changed := false
+		for _, id := range r.order {
+			m := r.modules[id]
+			active := activeRequirements(m.reqs, g.keys)
+			if len(active) == 0 {
+				continue
+			}
+			sel, reason := lowestSatisfying(m.repo, active)
+			if sel != nil && reason == "" {
+				reason = checkRequirements(sel, active)
+			}
+			if reason != "" {
+				conflict := &Conflict{URL: active[0].URL, Alias: m.alias, Reason: reason, Requirements: active}
+				if m.direct {
+					sel = directRequirement(active)
+					conflict.Overridden = true
+				} else if sel == nil {
+					sel = active[0]
+				}
+				conflicts = append(conflicts, conflict)
+			}
+			if sel != r.selected[id] {
+				r.selected[id] = sel
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return conflicts
+}
+
+func activeRequirements(reqs []*Requirement, keys map[string]bool) []*Requirement {
+	var active []*Requirement
+	for _, req := range reqs {
+		if req.from == "" || keys[req.from] {
+			active = append(active, req)
+		}
+	}
+	return active
+}
+
+func directRequirement(reqs []*Requirement) *Requirement {
+	for _, req := range reqs {
+		if req.from == "" {
+			return req
+		}
+	}
+	return reqs[0]
+}
+
+// lowestSatisfying picks the lowest requirement at or above all others: the
+// highest tag when every requirement is a tag constraint, otherwise the
+// commit every other required commit is an ancestor of.
+func lowestSatisfying(repo *git.Repository, reqs []*Requirement) (*Requirement, string) {
+	if allTagged(reqs) {
+		best := reqs[0]
+		bestVersion, _ := ParseVersion(best.Tag)
+		for _, req := range reqs[1:] {
+			if v, _ := ParseVersion(req.Tag); v.Compare(bestVersion) > 0 {
+				best, bestVersion = req, v
+			}
+		}
+		return best, ""
+	}
+
+	for _, candidate := range reqs {
+		ok := true
+		for _, other := range reqs {
+			if other.Commit != candidate.Commit && !isAncestor(repo, other.Commit, candidate.Commit) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return candidate, ""
+		}
+	}
+	return nil, "required commits have diverged"
+}
+
+func allTagged(reqs []*Requirement) bool {
+	for _, req := range reqs {
+		if req.Version == "" || IsCommitPin(req.Version) {
+			return false
+		}
+		if _, ok := ParseVersion(req.Tag); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func isAncestor(repo *git.Repository, ancestor, commit string) bool {
+	a, err := repo.CommitObject(plumbing.NewHash(ancestor))
+	if err != nil {
+		return false
+	}
+	c, err := repo.CommitObject(plumbing.NewHash(commit))
+	if err != nil {
+		return false
+	}
+	ok, err := a.IsAncestor(c)
+	return err == nil && ok
+}
+
+// checkRequirements reports why the selected requirement does not satisfy
+// all others, or "" when it does.
+func checkRequirements(sel *Requirement, reqs []*Requirement) string {
+	var reasons []string
+	branches := make(map[string]bool)
+	for _, req := range reqs {
+		switch {
+		case req.Version == "":
+			branches[branchOrMain(req.Branch)] = true
+		case IsCommitPin(req.Version):
+			if req.Commit != sel.Commit {
+				reasons = append(reasons, fmt.Sprintf("%s pins %s", req.RequiredBy, req.Version))
+			}
+		default:
+			c, err := ParseConstraint(req.Version)
+			if err != nil {
+				reasons = append(reasons, fmt.Sprintf("%s: %v", req.RequiredBy, err))
+				continue
+			}
+			if v, ok := ParseVersion(sel.Tag); !ok || !c.Match(v) {
+				reasons = append(reasons, fmt.Sprintf("%s requires %s, not %s", req.RequiredBy, req.Version, describeRequirement(sel)))
+			}
+		}
+	}
+	if len(branches) > 1 {
+		names := make([]string, 0, len(branches))
+		for name := range branches {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		reasons = append([]string{"required on branches " + strings.Join(names, ", ")}, reasons...)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+func branchOrMain(branch string) string {
+	if branch == "" {
+		return "main"
+	}
+	return branch
+}
+
+func describeRequirement(req *Requirement) string {
+	if req.Tag != "" {
+		return req.Tag
+	}
+	return req.Commit[:8]
+}
+
+type resolvedGraph struct {
+	keys  map[string]bool     // selected version keys reachable from the project
+	depth map[string]int      // module ID -> shortest depth
+	edges map[string][]string // module ID -> required module IDs; "" is the project
+}
+
+// graph walks the selected versions from the project
+func (r *resolver) graph() *resolvedGraph {
+	g := &resolvedGraph{
+		keys:  make(map[string]bool),
+		depth: make(map[string]int),
+		edges: make(map[string][]string),
+	}
+	type pending struct {
+		from  string
+		deps  []metadata.Dependency
+		depth int
+	}
+	queue := []pending{{from: "", deps: r.root.Dependencies, depth: 1}}
+	for len(queue) > 0 {
+		item := queue[0]
+		queue = queue[1:]
+		for _, dep := range item.deps {
+			id := NormalizeURL(dep.URL)
+			if id == r.rootID {
+				g.addEdge(item.from, "")
+				continue
+			}
+			sel, ok := r.selected[id]
+			if !ok {
+				continue
+			}
+			g.addEdge(item.from, id)
+			key := versionKey(id, sel.Commit)
+			if g.keys[key] {
+				continue
+			}
+			g.keys[key] = true
+			g.depth[id] = item.depth
+			if depMeta := r.versions[key]; depMeta != nil {
+				queue = append(queue, pending{from: id, deps: depMeta.Dependencies, depth: item.depth + 1})
+			}
+		}
+	}
+	return g
+}
+
+func (g *resolvedGraph) addEdge(from, to string) {
+	for _, existing := range g.edges[from] {
+		if existing == to {
+			return
+		}
+	}
+	g.edges[from] = append(g.edges[from], to)
+}
+
+// cycles returns the cycles of the graph as module ID paths
+func (g *resolvedGraph) cycles() [][]string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, next := range g.edges[id] {
+			switch state[next] {
+			case visiting:
+				for i, onStack := range stack {
+					if onStack == next {
+						cycle := append([]string{}, stack[i:]...)
+						cycles = append(cycles, append(cycle, next))
+						break
+					}
+				}
+			case 0:
+				visit(next)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+	}
+	visit("")
+	return cycles
+}
+
+func (r *resolver) resolution(conflicts []*Conflict) *Resolution {
+	g := r.graph()
+	res := &Resolution{Conflicts: conflicts}
+
+	for _, dep := range r.root.Dependencies {
+		if sel, ok := r.selected[NormalizeURL(dep.URL)]; ok {
+			dep.ResolvedCommit = sel.Commit
+			dep.ResolvedTag = ""
+			if dep.Version != "" {
+				dep.ResolvedTag = sel.Tag
+			}
+		}
+		res.Direct = append(res.Direct, dep)
+	}
+
+	for _, id := range r.order {
+		m := r.modules[id]
+		sel := r.selected[id]
+		key := versionKey(id, sel.Commit)
+		if m.direct || !g.keys[key] {
+			continue
+		}
+		dep := sel.dep
+		dep.Alias = m.alias
+		dep.ImportPath = ""
+		dep.ResolvedCommit = sel.Commit
+		dep.ResolvedTag = sel.Tag
+		if dep.ArtifactPath == "" {
+			if depMeta := r.versions[key]; depMeta != nil {
+				dep.ArtifactPath = depMeta.GetArtifactPath()
+			}
+		}
+		var requiredBy []string
+		for _, req := range activeRequirements(m.reqs, g.keys) {
+			if !slices.Contains(requiredBy, req.RequiredBy) {
+				requiredBy = append(requiredBy, req.RequiredBy)
+			}
+		}
+		sort.Strings(requiredBy)
+		res.Transitive = append(res.Transitive, metadata.LockedDependency{Dependency: dep, RequiredBy: requiredBy})
+	}
+	sort.SliceStable(res.Transitive, func(i, j int) bool {
+		a, b := res.Transitive[i], res.Transitive[j]
+		da, db := g.depth[NormalizeURL(a.URL)], g.depth[NormalizeURL(b.URL)]
+		if da != db {
+			return da < db
+		}
+		return a.Alias < b.Alias
+	})
+
+	for _, cycle := range g.cycles() {
+		path := make([]string, len(cycle))
+		for i, id := range cycle {
+			if id == "" {
+				path[i] = r.rootName
+			} else {
+				path[i] = r.modules[id].alias
+			}
+		}
+		res.Cycles = append(res.Cycles, path)
+	}
+	return res
+}
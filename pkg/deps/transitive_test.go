@@ -0,0 +1,203 @@
+package deps
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/specledger/specledger/pkg/cli/metadata"
+)
+
+// testRepo is a local repository whose commits carry a specledger.yaml
+type testRepo struct {
+	t    *testing.T
+	dir  string
+	repo *git.Repository
+}
+
+func newTestRepo(t *testing.T, dir string) *testRepo {
+	t.Helper()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testRepo{t: t, dir: dir, repo: repo}
+}
+
+// commit commits a specledger.yaml with the given dependencies and returns the hash
+func (r *testRepo) commit(deps ...metadata.Dependency) string {
+	r.t.Helper()
+	meta := metadata.NewProjectMetadata(filepath.Base(r.dir), "dep", "specledger", "1.0.0", nil, "1.0.0")
+	meta.Dependencies = deps
+	if err := metadata.SaveToProject(meta, r.dir); err != nil {
+		r.t.Fatal(err)
+	}
+	wt, err := r.repo.Worktree()
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	if _, err := wt.Add(metadata.DefaultMetadataFile); err != nil {
+		r.t.Fatal(err)
+	}
+	hash, err := wt.Commit("update", &git.CommitOptions{
+		Author:            &object.Signature{Name: "test", Email: "test@test.com", When: time.Now()},
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		r.t.Fatal(err)
+	}
+	return hash.String()
+}
+
+func (r *testRepo) tag(name, commit string) {
+	r.t.Helper()
+	if _, err := r.repo.CreateTag(name, plumbing.NewHash(commit), nil); err != nil {
+		r.t.Fatal(err)
+	}
+}
+
+func openLocal(dep metadata.Dependency) (*git.Repository, error) {
+	return git.PlainOpen(dep.URL)
+}
+
+func TestResolveTransitive(t *testing.T) {
+	dir := t.TempDir()
+	shared := newTestRepo(t, filepath.Join(dir, "shared"))
+	models := newTestRepo(t, filepath.Join(dir, "models"))
+	api := newTestRepo(t, filepath.Join(dir, "api"))
+	docs := newTestRepo(t, filepath.Join(dir, "docs"))
+
+	s1 := shared.commit()
+	s2 := shared.commit()
+	m110 := models.commit()
+	models.tag("v1.1.0", m110)
+	m120 := models.commit()
+	models.tag("v1.2.0", m120)
+	m200 := models.commit()
+	models.tag("v2.0.0", m200)
+
+	a1 := api.commit()
+	d1 := docs.commit(
+		metadata.Dependency{URL: api.dir, Alias: "api", ResolvedCommit: a1},
+		metadata.Dependency{URL: shared.dir, Alias: "common", ResolvedCommit: s2},
+		metadata.Dependency{URL: models.dir, Alias: "models", Version: "^1.2", ResolvedCommit: m120, ResolvedTag: "v1.2.0"},
+	)
+	a2 := api.commit(
+		metadata.Dependency{URL: docs.dir, Alias: "docs", ResolvedCommit: d1},
+		metadata.Dependency{URL: shared.dir, Alias: "shared", ResolvedCommit: s1},
+		metadata.Dependency{URL: models.dir, Alias: "models", Version: "^1.1", ResolvedCommit: m110, ResolvedTag: "v1.1.0"},
+	)
+
+	root := metadata.NewProjectMetadata("app", "app", "specledger", "1.0.0", nil, "1.0.0")
+	root.Dependencies = []metadata.Dependency{
+		{URL: api.dir, Alias: "api", ResolvedCommit: a2},
+		{URL: docs.dir, Alias: "docs", ResolvedCommit: d1},
+	}
+
+	res, err := ResolveTransitive(root, TransitiveOptions{Open: openLocal})
+	if err != nil {
+		t.Fatalf("ResolveTransitive() error: %v", err)
+	}
+	if len(res.Conflicts) != 0 {
+		t.Fatalf("Conflicts = %+v, want none", res.Conflicts[0])
+	}
+	if res.Direct[0].ResolvedCommit != a2 {
+		t.Errorf("api = %s, want the newer of the required commits", res.Direct[0].ResolvedCommit)
+	}
+
+	// shared is reached twice and deduplicated; s2 descends from s1
+	if len(res.Transitive) != 2 {
+		t.Fatalf("Transitive = %+v, want models and shared", res.Transitive)
+	}
+	byAlias := make(map[string]metadata.LockedDependency)
+	for _, dep := range res.Transitive {
+		byAlias[dep.Alias] = dep
+	}
+	if got := byAlias["models"]; got.ResolvedTag != "v1.2.0" || got.ResolvedCommit != m120 {
+		t.Errorf("models = %s %s, want the highest required tag v1.2.0", got.ResolvedTag, got.ResolvedCommit)
+	}
+	if got := byAlias["shared"]; got.ResolvedCommit != s2 || !slices.Equal(got.RequiredBy, []string{"api", "docs"}) {
+		t.Errorf("shared = %s required by %v, want %s required by api and docs", got.ResolvedCommit, got.RequiredBy, s2)
+	}
+
+	if len(res.Cycles) != 1 || strings.Join(res.Cycles[0], " -> ") != "api -> docs -> api" {
+		t.Errorf("Cycles = %v, want api -> docs -> api", res.Cycles)
+	}
+
+	// A new docs requiring models ^2 cannot share models with api
+	d2 := docs.commit(
+		metadata.Dependency{URL: models.dir, Alias: "models", Version: "^2.0", ResolvedCommit: m200, ResolvedTag: "v2.0.0"},
+	)
+	root.Dependencies[1].ResolvedCommit = d2
+	res, err = ResolveTransitive(root, TransitiveOptions{Open: openLocal})
+	if err != nil {
+		t.Fatalf("ResolveTransitive() error: %v", err)
+	}
+	if len(res.Unresolved()) != 1 || res.Unresolved()[0].Alias != "models" {
+		t.Fatalf("Unresolved() = %+v, want a models conflict", res.Unresolved())
+	}
+	if reason := res.Conflicts[0].Reason; !strings.Contains(reason, "api requires ^1.1, not v2.0.0") {
+		t.Errorf("Reason = %q", reason)
+	}
+
+	// The project requiring models itself settles the conflict
+	root.Dependencies = append(root.Dependencies, metadata.Dependency{URL: models.dir, Alias: "models", Version: "^1.2", ResolvedCommit: m120, ResolvedTag: "v1.2.0"})
+	res, err = ResolveTransitive(root, TransitiveOptions{Open: openLocal})
+	if err != nil {
+		t.Fatalf("ResolveTransitive() error: %v", err)
+	}
+	if len(res.Conflicts) != 1 || !res.Conflicts[0].Overridden || len(res.Unresolved()) != 0 {
+		t.Fatalf("Conflicts = %+v, want one overridden conflict", res.Conflicts)
+	}
+	if res.Direct[2].ResolvedTag != "v1.2.0" {
+		t.Errorf("models = %s, want the project's v1.2.0", res.Direct[2].ResolvedTag)
+	}
+}
+
+func TestResolveTransitiveDivergedBranches(t *testing.T) {
+	dir := t.TempDir()
+	shared := newTestRepo(t, filepath.Join(dir, "shared"))
+	base := shared.commit()
+	mainCommit := shared.commit()
+
+	// A second line of history from base
+	wt, err := shared.repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: plumbing.NewHash(base), Branch: "refs/heads/other", Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(shared.dir, "other.md"), []byte("other"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := shared.commit()
+
+	a := newTestRepo(t, filepath.Join(dir, "a"))
+	b := newTestRepo(t, filepath.Join(dir, "b"))
+	aCommit := a.commit(metadata.Dependency{URL: shared.dir, Branch: "main", ResolvedCommit: mainCommit})
+	bCommit := b.commit(metadata.Dependency{URL: shared.dir, Branch: "other", ResolvedCommit: other})
+
+	root := metadata.NewProjectMetadata("app", "app", "specledger", "1.0.0", nil, "1.0.0")
+	root.Dependencies = []metadata.Dependency{
+		{URL: a.dir, Alias: "a", ResolvedCommit: aCommit},
+		{URL: b.dir, Alias: "b", ResolvedCommit: bCommit},
+	}
+	res, err := ResolveTransitive(root, TransitiveOptions{Open: openLocal})
+	if err != nil {
+		t.Fatalf("ResolveTransitive() error: %v", err)
+	}
+	unresolved := res.Unresolved()
+	if len(unresolved) != 1 || unresolved[0].Reason != "required commits have diverged" {
+		t.Fatalf("Unresolved() = %+v, want diverged commits", unresolved)
+	}
+	if len(res.Transitive) != 1 || res.Transitive[0].Alias != "shared" {
+		t.Errorf("Transitive = %+v, want shared once", res.Transitive)
+	}
+}
@@ -0,0 +1,417 @@
+package deps
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// Version constraint errors
+var (
+	ErrInvalidConstraint = errors.New("invalid version constraint")
+	ErrNoMatchingTag     = errors.New("no tag matches the version constraint")
+)
+
+var (
+	semverPattern = regexp.MustCompile(`^v?(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+	commitPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+)
+
+// Version is a semantic version parsed from a tag such as v1.4.0
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+// ParseVersion parses a tag name as a semantic version. The v prefix and
+// missing minor and patch numbers are allowed (v1.2 is 1.2.0).
+func ParseVersion(tag string) (Version, bool) {
+	m := semverPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return Version{}, false
+	}
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		v.Minor, _ = strconv.Atoi(m[2])
+	}
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	v.Prerelease = m[4]
+	return v, true
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than o.
+// A prerelease is lower than the release it precedes.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	}
+	return comparePrerelease(v.Prerelease, o.Prerelease)
+}
+
+// comparePrerelease orders prerelease strings by their dot-separated
+// identifiers as semver does: numeric identifiers compare as numbers and rank
+// below alphanumeric ones, and a shorter list of equal identifiers is lower
+// (rc.2 < rc.10 < rc.10.1 < rc.beta).
+func comparePrerelease(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if d := strings.Compare(as[i], bs[i]); d != 0 {
+				return d
+			}
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// IsCommitPin reports whether a constraint names a commit SHA rather than tags
+func IsCommitPin(constraint string) bool {
+	return commitPattern.MatchString(constraint)
+}
+
+// Constraint is a parsed version constraint. Terms are ANDed.
+type Constraint struct {
+	raw   string
+	terms []constraintTerm
+}
+
+type constraintTerm struct {
+	op string // =, >, >=, <, <=
+	v  Version
+}
+
+// ParseConstraint parses a version constraint, with the ranges npm and
+// composer use:
+//
+//	^1.2      >=1.2.0 <2.0.0 (^0.3 is <0.4.0, ^0.0.3 is <0.0.4, ^0 is <1.0.0)
+//	~1.4      >=1.4.0 <1.5.0 (~1 is <2.0.0)
+//	v1.4.0    exactly 1.4.0 (also =1.4.0)
+//	>=1.2 <2  comparisons, space or comma separated and ANDed
+//
+// A prerelease tag only matches if a term names a prerelease of the same
+// major.minor.patch, so ^1.2.0-rc.1 matches 1.2.0-rc.2 but not 1.3.0-rc.1.
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: strings.TrimSpace(s)}
+	fields := strings.FieldsFunc(c.raw, func(r rune) bool { return r == ' ' || r == ',' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidConstraint)
+	}
+	for _, field := range fields {
+		op, rest := splitConstraintOp(field)
+		v, ok := ParseVersion(rest)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidConstraint, field)
+		}
+		switch op {
+		case "^":
+			// The first non-zero part spelled out may not change
+			hasMinor, hasPatch := spelledOut(rest)
+			upper := Version{Major: v.Major + 1}
+			switch {
+			case v.Major != 0 || !hasMinor:
+			case v.Minor != 0 || !hasPatch:
+				upper = Version{Minor: v.Minor + 1}
+			default:
+				upper = Version{Patch: v.Patch + 1}
+			}
+			c.terms = append(c.terms, constraintTerm{">=", v}, constraintTerm{"<", upper})
+		case "~":
+			upper := Version{Major: v.Major + 1}
+			if hasMinor, _ := spelledOut(rest); hasMinor {
+				upper = Version{Major: v.Major, Minor: v.Minor + 1}
+			}
+			c.terms = append(c.terms, constraintTerm{">=", v}, constraintTerm{"<", upper})
+		case "", "=":
+			c.terms = append(c.terms, constraintTerm{"=", v})
+		default:
+			c.terms = append(c.terms, constraintTerm{op, v})
+		}
+	}
+	return c, nil
+}
+
+// spelledOut reports whether a version string spells out its minor and patch
+// numbers, so ^0.0.3 can be told apart from ^0.0 and ~1.0 from ~1
+func spelledOut(s string) (minor, patch bool) {
+	m := semverPattern.FindStringSubmatch(s)
+	return m != nil && m[2] != "", m != nil && m[3] != ""
+}
+
+func splitConstraintOp(field string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if rest, ok := strings.CutPrefix(field, op); ok {
+			return op, rest
+		}
+	}
+	return "", field
+}
+
+// Match reports whether a version satisfies the constraint
+func (c *Constraint) Match(v Version) bool {
+	if v.Prerelease != "" && !c.namesPrerelease(v) {
+		return false
+	}
+	for _, t := range c.terms {
+		cmp := v.Compare(t.v)
+		var ok bool
+		switch t.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// namesPrerelease reports whether a term names a prerelease of v's release
+func (c *Constraint) namesPrerelease(v Version) bool {
+	for _, t := range c.terms {
+		if t.v.Prerelease != "" && t.v.Major == v.Major && t.v.Minor == v.Minor && t.v.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// Exact reports whether the constraint pins a single version
+func (c *Constraint) Exact() bool {
+	return len(c.terms) == 1 && c.terms[0].op == "="
+}
+
+func (c *Constraint) String() string {
+	return c.raw
+}
+
+// Rebase returns the constraint of the same kind anchored at tag, used when
+// an update crosses the constraint: ~ stays ~, an exact version becomes the
+// tag itself and anything else becomes ^.
+func (c *Constraint) Rebase(tag string) string {
+	v, ok := ParseVersion(tag)
+	if !ok || c.Exact() {
+		return tag
+	}
+	anchor := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if strings.HasPrefix(c.raw, "~") {
+		return "~" + anchor
+	}
+	return "^" + anchor
+}
+
+// Tag is a remote tag and the commit it points to
+type Tag struct {
+	Name    string  `json:"name"`
+	Commit  string  `json:"commit"`
+	Version Version `json:"-"`
+}
+
+// ListRemoteTags lists the semantic version tags of a remote repository,
+// highest first. Annotated tags are resolved to their commit.
+func ListRemoteTags(url string) ([]Tag, error) {
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: "origin", URLs: []string{url}})
+	opts := &git.ListOptions{PeelingOption: git.AppendPeeled}
+	auth, err := getAuthForURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine auth method: %w", err)
+	}
+	if auth != nil {
+		opts.Auth = auth
+	}
+	refs, err := remote.List(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote tags: %w", err)
+	}
+
+	commits := make(map[string]string)
+	peeled := make(map[string]bool)
+	for _, ref := range refs {
+		if !ref.Name().IsTag() {
+			continue
+		}
+		name := ref.Name().Short()
+		if base, ok := strings.CutSuffix(name, "^{}"); ok {
+			// The peeled entry of an annotated tag names its commit
+			commits[base] = ref.Hash().String()
+			peeled[base] = true
+		} else if !peeled[name] {
+			commits[name] = ref.Hash().String()
+		}
+	}
+
+	var tags []Tag
+	for name, commit := range commits {
+		if v, ok := ParseVersion(name); ok {
+			tags = append(tags, Tag{Name: name, Commit: commit, Version: v})
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if c := tags[i].Version.Compare(tags[j].Version); c != 0 {
+			return c > 0
+		}
+		return tags[i].Name > tags[j].Name
+	})
+	return tags, nil
+}
+
+// SelectTag returns the highest tag matching the constraint. Tags must be
+// ordered highest first, as ListRemoteTags returns them.
+func SelectTag(tags []Tag, c *Constraint) (Tag, error) {
+	for _, tag := range tags {
+		if c.Match(tag.Version) {
+			return tag, nil
+		}
+	}
+	return Tag{}, fmt.Errorf("%w: %s", ErrNoMatchingTag, c)
+}
+
+// LatestTag returns the highest release tag, ignoring prereleases
+func LatestTag(tags []Tag) (Tag, error) {
+	for _, tag := range tags {
+		if tag.Version.Prerelease == "" {
+			return tag, nil
+		}
+	}
+	return Tag{}, fmt.Errorf("%w: no release tags", ErrNoMatchingTag)
+}
+
+// ResolveVersion resolves a dependency's version constraint against the
+// remote's tags and returns the chosen tag and commit. A commit pin resolves
+// to itself with no tag.
+func ResolveVersion(url, constraint string) (tag, commit string, err error) {
+	if IsCommitPin(constraint) {
+		return "", constraint, nil
+	}
+	c, err := ParseConstraint(constraint)
+	if err != nil {
+		return "", "", err
+	}
+	tags, err := ListRemoteTags(url)
+	if err != nil {
+		return "", "", err
+	}
+	t, err := SelectTag(tags, c)
+	if err != nil {
+		return "", "", err
+	}
+	return t.Name, t.Commit, nil
+}
+
+// FetchTag fetches a single tag into a repository
+func FetchTag(repo *git.Repository, tag string) error {
+	return fetchRefSpecs(repo, config.RefSpec(fmt.Sprintf("+refs/tags/%s:refs/tags/%[1]s", tag)))
+}
+
+// FetchBranches fetches every branch of the first remote, so that commits
+// outside the cloned branch can be checked out
+func FetchBranches(repo *git.Repository) error {
+	return fetchRefSpecs(repo, config.RefSpec("+refs/heads/*:refs/remotes/origin/*"))
+}
+
+func fetchRefSpecs(repo *git.Repository, specs ...config.RefSpec) error {
+	remotes, err := repo.Remotes()
+	if err != nil || len(remotes) == 0 {
+		return fmt.Errorf("no remotes found")
+	}
+	remote := remotes[0]
+	url := remote.Config().URLs[0]
+
+	fetchOpts := &git.FetchOptions{
+		RemoteURL: url,
+		RefSpecs:  specs,
+		Tags:      git.NoTags,
+	}
+	auth, err := getAuthForURL(url)
+	if err != nil {
+		return fmt.Errorf("failed to determine auth method: %w", err)
+	}
+	if auth != nil {
+		fetchOpts.Auth = auth
+	}
+	if err := remote.Fetch(fetchOpts); err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	return nil
+}
+
+// EnsureCommit makes a commit available in a repository, fetching its tag,
+// or every branch when there is no tag, if it is not there yet. It returns the
+// full hash of the commit, which may be given abbreviated.
+func EnsureCommit(repo *git.Repository, tag, commit string) (plumbing.Hash, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(commit))
+	if err == nil {
+		return *hash, nil
+	}
+	if tag != "" {
+		err = FetchTag(repo, tag)
+	} else {
+		err = FetchBranches(repo)
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if hash, err = repo.ResolveRevision(plumbing.Revision(commit)); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("commit %s not found: %w", commit, err)
+	}
+	return *hash, nil
+}
@@ -0,0 +1,159 @@
+package deps
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestConstraintMatch(t *testing.T) {
+	tests := []struct {
+		constraint string
+		match      []string
+		noMatch    []string
+	}{
+		{"^1.2", []string{"v1.2.0", "1.9.3", "v1.2"}, []string{"v1.1.9", "v2.0.0", "v1.3.0-rc.1"}},
+		{"^0.3", []string{"v0.3.0", "v0.3.7"}, []string{"v0.4.0", "v0.2.9"}},
+		{"^0.0.3", []string{"v0.0.3"}, []string{"v0.0.4", "v0.1.0"}},
+		{"^0.0", []string{"v0.0.0", "v0.0.9"}, []string{"v0.1.0"}},
+		{"~1.4", []string{"v1.4.0", "v1.4.9"}, []string{"v1.5.0", "v1.3.9"}},
+		{"v1.4.0", []string{"1.4.0", "v1.4"}, []string{"v1.4.1"}},
+		{">=1.2 <2", []string{"v1.2.0", "v1.99.0"}, []string{"v2.0.0", "v1.1.0"}},
+		{"=2.0.0-rc.1", []string{"v2.0.0-rc.1"}, []string{"v2.0.0"}},
+		{"^0", []string{"v0.0.0", "v0.9.9"}, []string{"v1.0.0"}},
+		{"^1", []string{"v1.0.0", "v1.9.0"}, []string{"v2.0.0"}},
+		{"~1", []string{"v1.0.0", "v1.9.9"}, []string{"v2.0.0", "v0.9.0"}},
+		{"~1.0", []string{"v1.0.0", "v1.0.9"}, []string{"v1.1.0"}},
+		{"~1.4.2", []string{"v1.4.2", "v1.4.9"}, []string{"v1.4.1", "v1.5.0"}},
+		{"^1.2.0-rc.1", []string{"v1.2.0-rc.1", "v1.2.0-rc.2", "v1.2.0", "v1.5.0"}, []string{"v1.2.0-beta", "v1.3.0-rc.1", "v2.0.0-rc.1"}},
+		{">=1.0.0-beta <2", []string{"v1.0.0-beta.2", "v1.0.0", "v1.4.0"}, []string{"v1.0.0-alpha", "v1.1.0-rc.1"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseConstraint(%q) error: %v", tt.constraint, err)
+		}
+		for _, tag := range tt.match {
+			if v, _ := ParseVersion(tag); !c.Match(v) {
+				t.Errorf("%q should match %s", tt.constraint, tag)
+			}
+		}
+		for _, tag := range tt.noMatch {
+			if v, _ := ParseVersion(tag); c.Match(v) {
+				t.Errorf("%q should not match %s", tt.constraint, tag)
+			}
+		}
+	}
+
+	for _, bad := range []string{"", "^latest", ">=1.x"} {
+		if _, err := ParseConstraint(bad); !errors.Is(err, ErrInvalidConstraint) {
+			t.Errorf("ParseConstraint(%q) error = %v, want ErrInvalidConstraint", bad, err)
+		}
+	}
+
+	rebases := map[string]string{"^1.2": "^3.1", "~1.4": "~3.1", "v1.4.0": "v3.1.2", ">=1 <2": "^3.1"}
+	for constraint, want := range rebases {
+		c, _ := ParseConstraint(constraint)
+		if got := c.Rebase("v3.1.2"); got != want {
+			t.Errorf("%q.Rebase(v3.1.2) = %s, want %s", constraint, got, want)
+		}
+	}
+
+	if !IsCommitPin("3f5d8a1") || IsCommitPin("v1.2.0") || IsCommitPin("^1.2") {
+		t.Error("IsCommitPin() misclassified a constraint")
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	ordered := []string{"v1.0.0-alpha", "v1.0.0-alpha.1", "v1.0.0-alpha.beta", "v1.0.0-rc.2", "v1.0.0-rc.10", "v1.0.0-rc.10.1", "v1.0.0", "v1.0.1"}
+	for i := 1; i < len(ordered); i++ {
+		lo, _ := ParseVersion(ordered[i-1])
+		hi, _ := ParseVersion(ordered[i])
+		if lo.Compare(hi) != -1 || hi.Compare(lo) != 1 {
+			t.Errorf("expected %s < %s", ordered[i-1], ordered[i])
+		}
+	}
+}
+
+func TestRemoteTags(t *testing.T) {
+	dir := t.TempDir()
+	origin := filepath.Join(dir, "origin")
+	repo, err := git.PlainInit(origin, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := &object.Signature{Name: "test", Email: "test@test.com", When: time.Now()}
+	commit := func(content string) plumbing.Hash {
+		if err := os.WriteFile(filepath.Join(origin, "spec.md"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add("spec.md"); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := wt.Commit(content, &git.CommitOptions{Author: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return hash
+	}
+
+	v120 := commit("1.2.0")
+	if _, err := repo.CreateTag("v1.2.0", v120, nil); err != nil {
+		t.Fatal(err)
+	}
+	v131 := commit("1.3.1")
+	if _, err := repo.CreateTag("v1.3.1", v131, &git.CreateTagOptions{Tagger: sig, Message: "1.3.1"}); err != nil {
+		t.Fatal(err)
+	}
+	v200 := commit("2.0.0")
+	if _, err := repo.CreateTag("v2.0.0", v200, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CreateTag("nightly", v200, nil); err != nil {
+		t.Fatal(err)
+	}
+	head := commit("unreleased")
+
+	tags, err := ListRemoteTags(origin)
+	if err != nil {
+		t.Fatalf("ListRemoteTags() error: %v", err)
+	}
+	if len(tags) != 3 || tags[0].Name != "v2.0.0" {
+		t.Fatalf("ListRemoteTags() = %+v, want v2.0.0, v1.3.1, v1.2.0", tags)
+	}
+
+	tag, commitSHA, err := ResolveVersion(origin, "^1.2")
+	if err != nil {
+		t.Fatalf("ResolveVersion() error: %v", err)
+	}
+	if tag != "v1.3.1" || commitSHA != v131.String() {
+		t.Errorf("ResolveVersion(^1.2) = %s %s, want v1.3.1 %s (annotated tag peeled)", tag, commitSHA, v131)
+	}
+	if _, _, err := ResolveVersion(origin, "^3"); !errors.Is(err, ErrNoMatchingTag) {
+		t.Errorf("ResolveVersion(^3) error = %v, want ErrNoMatchingTag", err)
+	}
+
+	// A clone of main finds the tagged commit
+	clone, _, err := Clone(CloneOptions{URL: origin, Branch: "master", TargetDir: filepath.Join(dir, "cache")})
+	if err != nil {
+		t.Fatalf("Clone() error: %v", err)
+	}
+	got, err := EnsureCommit(clone, tag, commitSHA)
+	if err != nil || got != v131 {
+		t.Fatalf("EnsureCommit() = %s, %v", got, err)
+	}
+	got, err = EnsureCommit(clone, "", head.String()[:8])
+	if err != nil || got != head {
+		t.Errorf("EnsureCommit(short SHA) = %s, %v; want %s", got, err, head)
+	}
+}
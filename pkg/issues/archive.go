@@ -0,0 +1,274 @@
+package issues
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveFileName is the per-spec gzip-compressed JSONL file holding archived
+// issues, stored next to issues.jsonl
+const ArchiveFileName = "issues.archive.jsonl.gz"
+
+// ErrIssueArchived is returned when changing an archived issue
+var ErrIssueArchived = errors.New("issue is archived and cannot be changed")
+
+// ArchiveSkip is a closed issue Archive left in place
+type ArchiveSkip struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// ArchiveResult reports what Archive moved into the archive
+type ArchiveResult struct {
+	SpecContext string        `json:"spec_context"`
+	Archived    []string      `json:"archived"`
+	Skipped     []ArchiveSkip `json:"skipped,omitempty"`
+}
+
+// ArchivePath returns the path to the archive of this store's spec.
+func (s *Store) ArchivePath() string {
+	return filepath.Join(s.specDir, ArchiveFileName)
+}
+
+// Archive moves issues closed before cutoff from the store into its spec's
+// archive, where Get, GetIssueAcrossSpecs and listings with IncludeArchived
+// still find them but ordinary reads no longer parse them. Issues without a
+// closed_at are judged by updated_at. Issues still referenced by an open
+// issue, in any spec, as a blocker or parent are skipped. With dryRun
+// nothing is written.
+func (s *Store) Archive(cutoff time.Time, dryRun bool) (*ArchiveResult, error) {
+	result := &ArchiveResult{SpecContext: s.specContext}
+	err := s.WithLock(func() error {
+		issues, err := s.readAllUnlocked()
+		if err != nil {
+			return err
+		}
+		referenced, err := s.openReferencesUnlocked(issues)
+		if err != nil {
+			return err
+		}
+
+		var archive []*Issue
+		for _, issue := range issues {
+			if !s.workflow.IsDone(issue.Status) {
+				continue
+			}
+			closedAt := issue.UpdatedAt
+			if issue.ClosedAt != nil {
+				closedAt = *issue.ClosedAt
+			}
+			if !closedAt.Before(cutoff) {
+				continue
+			}
+			if by, ok := referenced[issue.ID]; ok {
+				result.Skipped = append(result.Skipped, ArchiveSkip{ID: issue.ID, Reason: fmt.Sprintf("referenced by open issue %s", by)})
+				continue
+			}
+			archive = append(archive, issue)
+			result.Archived = append(result.Archived, issue.ID)
+		}
+		if dryRun || len(archive) == 0 {
+			return nil
+		}
+
+		// Write the archive first so a failure cannot lose issues, then remove
+		// them all at once so a failure cannot leave some in both places
+		if err := appendArchive(s.ArchivePath(), archive); err != nil {
+			return err
+		}
+		if err := s.backend.Delete(result.Archived...); err != nil {
+			return fmt.Errorf("failed to remove archived issues: %w", err)
+		}
+		for _, issue := range archive {
+			if err := s.recordEventUnlocked(EventArchived, issue, issue, "Moved to "+ArchiveFileName); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// openReferencesUnlocked maps the IDs that open issues of any spec are
+// blocked by or children of to one such open issue. issues are the store's
+// own, read under its lock.
+func (s *Store) openReferencesUnlocked(issues []*Issue) (map[string]string, error) {
+	all, err := ListAllSpecs(s.basePath, ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	// This spec's issues come from the locked read
+	var open []*Issue
+	for i := range all {
+		if all[i].SpecContext != s.specContext {
+			open = append(open, &all[i])
+		}
+	}
+	open = append(open, issues...)
+
+	referenced := make(map[string]string)
+	for _, issue := range open {
+		if s.workflow.IsDone(issue.Status) {
+			continue
+		}
+		refs := append([]string(nil), issue.BlockedBy...)
+		if issue.ParentID != nil && *issue.ParentID != "" {
+			refs = append(refs, *issue.ParentID)
+		}
+		for _, ref := range refs {
+			if _, ok := referenced[ref]; !ok {
+				referenced[ref] = issue.ID
+			}
+		}
+	}
+	return referenced, nil
+}
+
+// appendArchive adds issues to an archive as a new gzip member, so earlier
+// members are never rewritten
+func appendArchive(path string, issues []*Issue) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	zw := gzip.NewWriter(f)
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return fmt.Errorf("failed to marshal issue: %w", err)
+		}
+		if _, err := fmt.Fprintf(zw, "%s\n", data); err != nil {
+			return fmt.Errorf("failed to write archive: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return f.Sync()
+}
+
+// ReadArchive reads the issues of an archive. A missing archive holds no
+// issues; lines that fail to parse are skipped. An issue archived twice, after
+// a run that failed to remove it from the store, is returned once, as last
+// written.
+func ReadArchive(path string) ([]*Issue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+	}
+	defer zr.Close()
+
+	var issues []*Issue
+	index := make(map[string]int)
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var issue Issue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			continue
+		}
+		if i, ok := index[issue.ID]; ok {
+			issues[i] = &issue
+			continue
+		}
+		index[issue.ID] = len(issues)
+		issues = append(issues, &issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+	}
+	return issues, nil
+}
+
+// getArchived returns an issue from the store's archive
+func (s *Store) getArchived(id string) (*Issue, error) {
+	archived, err := ReadArchive(s.ArchivePath())
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range archived {
+		if issue.ID == id {
+			return issue, nil
+		}
+	}
+	return nil, ErrIssueNotFound
+}
+
+// listArchived returns the archived issues of every spec under basePath that
+// match filter, ordered by spec
+func listArchived(basePath string, filter ListFilter) ([]Issue, error) {
+	paths, err := filepath.Glob(filepath.Join(basePath, "*", ArchiveFileName))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var result []Issue
+	for _, path := range paths {
+		archived, err := ReadArchive(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range archived {
+			if matchesFilter(issue, filter) {
+				result = append(result, *issue)
+			}
+		}
+	}
+	return result, nil
+}
+
+// findArchived searches the archives of every spec under basePath
+func findArchived(basePath, id string) (*Issue, string, error) {
+	paths, err := filepath.Glob(filepath.Join(basePath, "*", ArchiveFileName))
+	if err != nil {
+		return nil, "", err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		archived, err := ReadArchive(path)
+		if err != nil {
+			return nil, "", err
+		}
+		for _, issue := range archived {
+			if issue.ID == id {
+				return issue, filepath.Base(filepath.Dir(path)), nil
+			}
+		}
+	}
+	return nil, "", ErrIssueNotFound
+}
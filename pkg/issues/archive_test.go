@@ -0,0 +1,139 @@
+package issues
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	NowFunc = func() time.Time { return now }
+	defer func() { NowFunc = time.Now }()
+
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	for _, spec := range []string{"010-test", "011-other"} {
+		if err := os.MkdirAll(filepath.Join(basePath, spec), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: "010-test"})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	other, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: "011-other"})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+
+	create := func(store *Store, title string) *Issue {
+		issue := NewIssue(title, "", store.specContext, TypeTask, 2)
+		if err := store.Create(issue); err != nil {
+			t.Fatalf("Create(%s) error: %v", title, err)
+		}
+		return issue
+	}
+	closeIssue := func(store *Store, issue *Issue) {
+		closed := StatusClosed
+		if _, err := store.Update(issue.ID, IssueUpdate{Status: &closed}); err != nil {
+			t.Fatalf("close %s: %v", issue.ID, err)
+		}
+	}
+
+	old := create(store, "Old and done")
+	blocker := create(store, "Done but blocking")
+	recent := create(store, "Recently done")
+	open := create(store, "Still open")
+	remote := create(other, "Open elsewhere")
+	if err := store.AddDependency(blocker.ID, open.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+	blockedBy := []string{old.ID}
+	closeIssue(store, old)
+	closeIssue(store, blocker)
+
+	// Closed issues elsewhere that were blocked by old do not keep it
+	if _, err := other.Update(remote.ID, IssueUpdate{BlockedBy: &blockedBy}); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	closeIssue(other, remote)
+
+	now = now.Add(40 * 24 * time.Hour)
+	closeIssue(store, recent)
+
+	cutoff := now.Add(-30 * 24 * time.Hour)
+	preview, err := store.Archive(cutoff, true)
+	if err != nil {
+		t.Fatalf("Archive(dry run) error: %v", err)
+	}
+	if len(preview.Archived) != 1 || preview.Archived[0] != old.ID {
+		t.Fatalf("dry run archived %v, want only %s", preview.Archived, old.ID)
+	}
+	if len(preview.Skipped) != 1 || preview.Skipped[0].ID != blocker.ID {
+		t.Errorf("dry run skipped %+v, want %s (blocks an open issue)", preview.Skipped, blocker.ID)
+	}
+	if _, err := os.Stat(store.ArchivePath()); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote the archive: %v", err)
+	}
+
+	if _, err := store.Archive(cutoff, false); err != nil {
+		t.Fatalf("Archive() error: %v", err)
+	}
+
+	list, _ := store.List(ListFilter{})
+	if len(list) != 3 {
+		t.Errorf("List() = %d issues, want 3 after archiving", len(list))
+	}
+	withArchived, _ := store.List(ListFilter{IncludeArchived: true})
+	if len(withArchived) != 4 {
+		t.Errorf("List(IncludeArchived) = %d issues, want 4", len(withArchived))
+	}
+	all, _ := ListAllSpecs(basePath, ListFilter{All: true, IncludeArchived: true})
+	if len(all) != 5 {
+		t.Errorf("ListAllSpecs(IncludeArchived) = %d issues, want 5", len(all))
+	}
+
+	got, err := store.Get(old.ID)
+	if err != nil || got.Title != old.Title {
+		t.Errorf("Get(archived) = %v, %v", got, err)
+	}
+	if _, spec, err := GetIssueAcrossSpecs(old.ID, basePath); err != nil || spec != "010-test" {
+		t.Errorf("GetIssueAcrossSpecs(archived) = %s, %v", spec, err)
+	}
+	title := "Changed"
+	if _, err := store.Update(old.ID, IssueUpdate{Title: &title}); !errors.Is(err, ErrIssueArchived) {
+		t.Errorf("Update(archived) error = %v, want ErrIssueArchived", err)
+	}
+
+	// A second run appends to the archive
+	now = now.Add(40 * 24 * time.Hour)
+	result, err := store.Archive(now.Add(-30*24*time.Hour), false)
+	if err != nil {
+		t.Fatalf("second Archive() error: %v", err)
+	}
+	if len(result.Archived) != 1 || result.Archived[0] != recent.ID {
+		t.Errorf("second run archived %v, want %s", result.Archived, recent.ID)
+	}
+	archived, err := ReadArchive(store.ArchivePath())
+	if err != nil || len(archived) != 2 {
+		t.Errorf("ReadArchive() = %d issues, %v; want 2", len(archived), err)
+	}
+
+	// An issue archived again after a failed removal is read once
+	if err := appendArchive(store.ArchivePath(), archived[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if archived, err := ReadArchive(store.ArchivePath()); err != nil || len(archived) != 2 {
+		t.Errorf("ReadArchive() after a duplicate = %d issues, %v; want 2", len(archived), err)
+	}
+
+	// Removal is all or nothing
+	if err := store.backend.Delete(open.ID, old.ID); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("Delete(with archived) error = %v, want ErrIssueNotFound", err)
+	}
+	if _, err := store.backend.Get(open.ID); err != nil {
+		t.Errorf("a failed Delete removed %s: %v", open.ID, err)
+	}
+}
@@ -0,0 +1,85 @@
+package issues
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// StorageKind selects how issues are persisted.
+type StorageKind string
+
+const (
+	// StorageJSONL keeps one issues.jsonl file per spec directory (default).
+	// It is plain text, diffable and merged record-by-record by git.
+	StorageJSONL StorageKind = "jsonl"
+	// StorageSQLite keeps all specs in a single embedded SQLite database at
+	// <base>/issues.db, so cross-spec queries do not read every spec.
+	StorageSQLite StorageKind = "sqlite"
+)
+
+// SQLiteFileName is the database file used by StorageSQLite.
+const SQLiteFileName = "issues.db"
+
+// ParseStorageKind validates a storage name. An empty name means JSONL.
+func ParseStorageKind(name string) (StorageKind, error) {
+	switch StorageKind(name) {
+	case "", StorageJSONL:
+		return StorageJSONL, nil
+	case StorageSQLite:
+		return StorageSQLite, nil
+	default:
+		return "", fmt.Errorf("unknown issue storage %q (must be jsonl or sqlite)", name)
+	}
+}
+
+// StorageFunc reports the storage configured for a base path when
+// StoreOptions.Storage is empty. The CLI points it at task_tracker.storage in
+// specledger.yaml.
+var StorageFunc = func(basePath string) StorageKind {
+	return StorageJSONL
+}
+
+// Backend persists the issues of one spec. The Store implements validation,
+// dependency bookkeeping and the event log on top of it, so backends only load
+// and save records. Callers hold the Lock while modifying.
+type Backend interface {
+	// Create stores a new issue. The Store checks for an existing ID first;
+	// backends that detect a duplicate return ErrIssueAlreadyExists.
+	Create(issue *Issue) error
+
+	// Get returns the issue with the given ID or ErrIssueNotFound.
+	Get(id string) (*Issue, error)
+
+	// List returns all issues in creation order.
+	List() ([]*Issue, error)
+
+	// Update replaces existing issues, matched by ID. It returns
+	// ErrIssueNotFound if any of them is not stored.
+	Update(issues ...*Issue) error
+
+	// Delete removes issues. It returns ErrIssueNotFound, removing none,
+	// if any of them is not stored.
+	Delete(ids ...string) error
+
+	// Lock acquires the cross-process write lock without waiting. It returns
+	// ErrStoreLocked if another process holds it.
+	Lock() (unlock func(), err error)
+}
+
+// newBackend creates the backend for a spec. An empty spec context gives a
+// backend for cross-spec bookkeeping rooted at basePath.
+func newBackend(kind StorageKind, basePath, specContext string) (Backend, string, error) {
+	switch kind {
+	case "", StorageJSONL:
+		if specContext == "" {
+			return newJSONLBackend(basePath, filepath.Join(basePath, "issues.jsonl.lock")), basePath, nil
+		}
+		path := filepath.Join(basePath, specContext, "issues.jsonl")
+		return newJSONLBackend(path, path+".lock"), path, nil
+	case StorageSQLite:
+		path := filepath.Join(basePath, SQLiteFileName)
+		return newSQLiteBackend(path, basePath, specContext), path, nil
+	default:
+		return nil, "", fmt.Errorf("unknown issue storage %q", kind)
+	}
+}
@@ -0,0 +1,293 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultLeaseDuration is how long a claim is held without a heartbeat
+const DefaultLeaseDuration = 30 * time.Minute
+
+// Claim-related errors
+var (
+	ErrIssueClaimed   = errors.New("issue is claimed by another holder")
+	ErrNoReadyIssue   = errors.New("no ready issue to claim")
+	ErrNotClaimed     = errors.New("issue is not claimed")
+	ErrNotLeaseHolder = errors.New("lease is held by another holder")
+)
+
+// Lease records who holds a claim on an issue and until when. A lease that is
+// not renewed with a heartbeat expires and the issue returns to ready.
+type Lease struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Active reports whether the lease is still held at the given time
+func (l *Lease) Active(now time.Time) bool {
+	return l != nil && now.Before(l.ExpiresAt)
+}
+
+// IsClaimed reports whether the issue has an active lease
+func (i *Issue) IsClaimed(now time.Time) bool {
+	return i.Lease.Active(now)
+}
+
+// Claim atomically assigns an issue to holder, moves it to in_progress and
+// records a lease expiring after the given duration. When id is empty the
+// highest-priority ready, unclaimed open issue is chosen. Expired leases in the
+// spec are released first so their issues can be claimed again.
+func (s *Store) Claim(id, holder string, duration time.Duration) (*Issue, error) {
+	if holder == "" {
+		return nil, fmt.Errorf("claim holder is required")
+	}
+	if duration <= 0 {
+		duration = DefaultLeaseDuration
+	}
+
+	return s.WithLockResult(func() (*Issue, error) {
+		issues, err := s.readAllUnlocked()
+		if err != nil {
+			return nil, err
+		}
+
+		now := NowFunc()
+		if err := s.reapExpiredUnlocked(issues, now); err != nil {
+			return nil, err
+		}
+
+		if IsQualifiedID(id) {
+			return nil, fmt.Errorf("%w: %s", ErrExternalIssue, id)
+		}
+		issueMap := make(map[string]*Issue, len(issues))
+		for _, issue := range issues {
+			issueMap[issue.ID] = issue
+		}
+		withExternalBlockers(s.basePath, s.workflow, issues, issueMap)
+
+		var target *Issue
+		if id == "" {
+			target = nextClaimable(s.workflow, issues, issueMap, now)
+			if target == nil {
+				return nil, ErrNoReadyIssue
+			}
+		} else {
+			target = issueMap[id]
+			if target == nil {
+				return nil, ErrIssueNotFound
+			}
+			if s.workflow.IsDone(target.Status) {
+				return nil, fmt.Errorf("cannot claim closed issue %s", id)
+			}
+			if !s.workflow.IsReady(target, issueMap) {
+				return nil, fmt.Errorf("cannot claim %s: it is blocked by open issues", id)
+			}
+			if target.IsClaimed(now) && target.Lease.Holder != holder {
+				return nil, fmt.Errorf("%w: %s holds %s until %s", ErrIssueClaimed,
+					target.Lease.Holder, id, target.Lease.ExpiresAt.Format(time.RFC3339))
+			}
+			if target.Lease == nil && s.workflow.Category(target.Status) == CategoryDoing && target.Assignee != "" && target.Assignee != holder {
+				return nil, fmt.Errorf("%w: %s is in progress by %s", ErrIssueClaimed, id, target.Assignee)
+			}
+		}
+
+		before := copyIssue(target)
+		acquired := now
+		if target.Lease != nil && target.Lease.Holder == holder {
+			// Re-claiming your own issue keeps the original acquisition time
+			acquired = target.Lease.AcquiredAt
+		}
+		// Claiming starts work, so todo issues move to the workflow's doing status
+		if s.workflow.Category(target.Status) == CategoryTodo {
+			doing := s.workflow.StatusFor(CategoryDoing)
+			if !s.workflow.CanTransition(target.Status, doing) {
+				return nil, fmt.Errorf("cannot claim %s: %w: %s → %s", target.ID, ErrTransitionNotAllowed, target.Status, doing)
+			}
+			target.Status = doing
+		}
+		target.Assignee = holder
+		target.Lease = &Lease{Holder: holder, AcquiredAt: acquired, ExpiresAt: now.Add(duration)}
+		target.UpdatedAt = now
+
+		if err := s.backend.Update(target); err != nil {
+			return nil, err
+		}
+		if err := s.recordEventUnlocked("", before, target, "claimed"); err != nil {
+			return nil, err
+		}
+		return target, nil
+	})
+}
+
+// Heartbeat extends the lease held by holder on an issue. A lease that has
+// expired but not yet been released can still be renewed by its holder.
+func (s *Store) Heartbeat(id, holder string, duration time.Duration) (*Issue, error) {
+	if duration <= 0 {
+		duration = DefaultLeaseDuration
+	}
+
+	return s.WithLockResult(func() (*Issue, error) {
+		issues, err := s.readAllUnlocked()
+		if err != nil {
+			return nil, err
+		}
+
+		target := findIssue(issues, id)
+		if target == nil {
+			return nil, ErrIssueNotFound
+		}
+		if target.Lease == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotClaimed, id)
+		}
+		if target.Lease.Holder != holder {
+			return nil, fmt.Errorf("%w: %s holds %s", ErrNotLeaseHolder, target.Lease.Holder, id)
+		}
+
+		now := NowFunc()
+		target.Lease.ExpiresAt = now.Add(duration)
+		target.UpdatedAt = now
+
+		if err := s.backend.Update(target); err != nil {
+			return nil, err
+		}
+		return target, nil
+	})
+}
+
+// Release gives up holder's claim on an issue, returning it to open and
+// clearing the assignee. With force the lease is released regardless of holder.
+func (s *Store) Release(id, holder string, force bool) (*Issue, error) {
+	return s.WithLockResult(func() (*Issue, error) {
+		issues, err := s.readAllUnlocked()
+		if err != nil {
+			return nil, err
+		}
+
+		target := findIssue(issues, id)
+		if target == nil {
+			return nil, ErrIssueNotFound
+		}
+		if target.Lease == nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotClaimed, id)
+		}
+		if !force && target.Lease.Holder != holder {
+			return nil, fmt.Errorf("%w: %s holds %s", ErrNotLeaseHolder, target.Lease.Holder, id)
+		}
+
+		before := copyIssue(target)
+		releaseLease(s.workflow, target, NowFunc())
+
+		if err := s.backend.Update(target); err != nil {
+			return nil, err
+		}
+		if err := s.recordEventUnlocked("", before, target, "released"); err != nil {
+			return nil, err
+		}
+		return target, nil
+	})
+}
+
+// ReleaseExpiredLeases returns every issue whose lease has expired to open and
+// reports the IDs that were released.
+func (s *Store) ReleaseExpiredLeases() ([]string, error) {
+	var released []string
+	err := s.WithLock(func() error {
+		issues, err := s.readAllUnlocked()
+		if err != nil {
+			return err
+		}
+
+		now := NowFunc()
+		for _, issue := range issues {
+			if issue.Lease != nil && !issue.Lease.Active(now) {
+				released = append(released, issue.ID)
+			}
+		}
+		if len(released) == 0 {
+			return nil
+		}
+		return s.reapExpiredUnlocked(issues, now)
+	})
+	return released, err
+}
+
+// reapExpiredUnlocked releases expired leases in issues, writing the file and
+// recording events if anything changed. Must be called while holding the lock.
+func (s *Store) reapExpiredUnlocked(issues []*Issue, now time.Time) error {
+	type change struct{ before, after *Issue }
+	var changes []change
+	var released []*Issue
+
+	for _, issue := range issues {
+		if issue.Lease == nil || issue.Lease.Active(now) || s.workflow.IsDone(issue.Status) {
+			continue
+		}
+		before := copyIssue(issue)
+		releaseLease(s.workflow, issue, now)
+		changes = append(changes, change{before, issue})
+		released = append(released, issue)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := s.backend.Update(released...); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if err := s.recordEventUnlocked("", c.before, c.after, "lease expired"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseLease clears a claim and returns the issue to the ready pool
+func releaseLease(workflow *Workflow, issue *Issue, now time.Time) {
+	if issue.Assignee == issue.Lease.Holder {
+		issue.Assignee = ""
+	}
+	if issue.Status == workflow.StatusFor(CategoryDoing) {
+		issue.Status = workflow.StatusFor(CategoryTodo)
+	}
+	issue.Lease = nil
+	issue.UpdatedAt = now
+}
+
+// nextClaimable picks the open, ready, unclaimed issue with the highest
+// priority, oldest first.
+func nextClaimable(workflow *Workflow, issues []*Issue, issueMap map[string]*Issue, now time.Time) *Issue {
+	var candidates []*Issue
+	for _, issue := range issues {
+		if workflow.Category(issue.Status) != CategoryTodo || issue.IssueType == TypeEpic {
+			continue
+		}
+		if issue.IsClaimed(now) || !workflow.IsReady(issue, issueMap) {
+			continue
+		}
+		candidates = append(candidates, issue)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority < candidates[j].Priority
+		}
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+	return candidates[0]
+}
+
+func findIssue(issues []*Issue, id string) *Issue {
+	for _, issue := range issues {
+		if issue.ID == id {
+			return issue
+		}
+	}
+	return nil
+}
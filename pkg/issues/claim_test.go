@@ -0,0 +1,177 @@
+package issues
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClaimLifecycle(t *testing.T) {
+	store := setupTestStore(t)
+
+	clock := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	NowFunc = func() time.Time { return clock }
+	defer func() { NowFunc = time.Now }()
+
+	create := func(id string, priority int) *Issue {
+		i := NewIssue("Issue "+id, "", "010-test", TypeTask, priority)
+		i.ID = id
+		i.CreatedAt, i.UpdatedAt = clock, clock
+		if err := store.Create(i); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+		return i
+	}
+	low := create("SL-111111", 3)
+	high := create("SL-222222", 1)
+	blocked := create("SL-333333", 0)
+	if err := store.AddDependency(low.ID, blocked.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+
+	claimed, err := store.Claim("", "agent-1", 30*time.Minute)
+	if err != nil {
+		t.Fatalf("Claim(next) error: %v", err)
+	}
+	if claimed.ID != high.ID {
+		t.Errorf("expected highest-priority unblocked issue %s, got %s", high.ID, claimed.ID)
+	}
+	if claimed.Status != StatusInProgress || claimed.Assignee != "agent-1" || claimed.Lease == nil {
+		t.Errorf("unexpected claimed issue: %+v", claimed)
+	}
+
+	if _, err := store.Claim(high.ID, "agent-2", 0); !errors.Is(err, ErrIssueClaimed) {
+		t.Errorf("expected ErrIssueClaimed, got %v", err)
+	}
+	if _, err := store.Claim(blocked.ID, "agent-2", 0); err == nil {
+		t.Error("expected claiming a blocked issue to fail")
+	}
+
+	ready, err := store.ListReady(ListFilter{})
+	if err != nil {
+		t.Fatalf("ListReady() error: %v", err)
+	}
+	for _, r := range ready {
+		if r.Issue.ID == high.ID {
+			t.Error("claimed issue should not be listed as ready")
+		}
+	}
+
+	t.Run("heartbeat extends the lease", func(t *testing.T) {
+		clock = clock.Add(20 * time.Minute)
+		if _, err := store.Heartbeat(high.ID, "agent-2", 0); !errors.Is(err, ErrNotLeaseHolder) {
+			t.Errorf("expected ErrNotLeaseHolder, got %v", err)
+		}
+		renewed, err := store.Heartbeat(high.ID, "agent-1", 30*time.Minute)
+		if err != nil {
+			t.Fatalf("Heartbeat() error: %v", err)
+		}
+		if !renewed.Lease.ExpiresAt.Equal(clock.Add(30 * time.Minute)) {
+			t.Errorf("unexpected lease expiry: %v", renewed.Lease.ExpiresAt)
+		}
+	})
+
+	t.Run("expired lease returns issue to ready", func(t *testing.T) {
+		clock = clock.Add(time.Hour)
+
+		next, err := store.Claim("", "agent-2", 0)
+		if err != nil {
+			t.Fatalf("Claim(next) error: %v", err)
+		}
+		if next.ID != high.ID || next.Assignee != "agent-2" {
+			t.Errorf("expected expired issue to be reclaimed by agent-2, got %+v", next)
+		}
+
+		events, err := store.History(high.ID)
+		if err != nil {
+			t.Fatalf("History() error: %v", err)
+		}
+		var reasons []string
+		for _, e := range events {
+			reasons = append(reasons, e.Reason)
+		}
+		if !contains(reasons, "lease expired") {
+			t.Errorf("expected lease expiry to be recorded, got %v", reasons)
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		if _, err := store.Release(high.ID, "agent-1", false); !errors.Is(err, ErrNotLeaseHolder) {
+			t.Errorf("expected ErrNotLeaseHolder, got %v", err)
+		}
+		released, err := store.Release(high.ID, "agent-2", false)
+		if err != nil {
+			t.Fatalf("Release() error: %v", err)
+		}
+		if released.Status != StatusOpen || released.Assignee != "" || released.Lease != nil {
+			t.Errorf("expected issue back to open and unassigned, got %+v", released)
+		}
+		if _, err := store.Release(high.ID, "agent-2", false); !errors.Is(err, ErrNotClaimed) {
+			t.Errorf("expected ErrNotClaimed, got %v", err)
+		}
+	})
+
+	t.Run("closing clears the lease", func(t *testing.T) {
+		if _, err := store.Claim(low.ID, "agent-1", 0); err != nil {
+			t.Fatalf("Claim() error: %v", err)
+		}
+		closed := StatusClosed
+		updated, err := store.Update(low.ID, IssueUpdate{Status: &closed})
+		if err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+		if updated.Lease != nil {
+			t.Errorf("expected lease to be cleared on close, got %+v", updated.Lease)
+		}
+	})
+}
+
+func TestClaimNextConcurrent(t *testing.T) {
+	store := setupTestStore(t)
+	for i := 0; i < 4; i++ {
+		issue := NewIssue("Task", "", "010-test", TypeTask, 2)
+		issue.ID = "SL-00000" + string(rune('a'+i))
+		if err := store.Create(issue); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan string, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(agent int) {
+			defer wg.Done()
+			// Separate stores behave like separate processes sharing the flock
+			s, _ := NewStore(StoreOptions{BasePath: filepath.Dir(filepath.Dir(store.Path())), SpecContext: "010-test"})
+			for {
+				issue, err := s.Claim("", "agent-"+string(rune('0'+agent)), 0)
+				if errors.Is(err, ErrStoreLocked) {
+					time.Sleep(time.Millisecond)
+					continue
+				}
+				if err != nil {
+					t.Errorf("Claim() error: %v", err)
+					return
+				}
+				results <- issue.ID
+				return
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	seen := make(map[string]bool)
+	for id := range results {
+		if seen[id] {
+			t.Errorf("issue %s claimed twice", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("expected 4 distinct claims, got %d", len(seen))
+	}
+}
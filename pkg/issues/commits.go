@@ -0,0 +1,263 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	cligit "github.com/specledger/specledger/pkg/cli/git"
+)
+
+// commitRefPattern matches issue IDs in commit messages, optionally preceded
+// by a closing keyword such as "fixes" or "Closes:"
+var commitRefPattern = regexp.MustCompile(`(?:\b((?i:close[sd]?|fix(?:e[sd])?|resolve[sd]?))\s*:?\s+)?\b(SL-[a-f0-9]{6})\b`)
+
+// CommitRef is an issue referenced by a commit message
+type CommitRef struct {
+	IssueID string
+	Closes  bool // Referenced with a closing keyword, e.g. "fixes SL-ab12cd"
+}
+
+// Commit is a git commit scanned for issue references
+type Commit struct {
+	Hash    string
+	Message string
+	Author  string
+	When    time.Time
+}
+
+// Subject returns the first line of the commit message
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return subject
+}
+
+// ShortHash returns the abbreviated commit hash
+func (c Commit) ShortHash() string {
+	return cligit.ShortHash(c.Hash)
+}
+
+// ParseCommitRefs returns the issues a commit message references, in order
+// of first mention. Closing keywords (close, fix, resolve and their -s/-d
+// forms) apply to the ID directly after them.
+func ParseCommitRefs(message string) []CommitRef {
+	var refs []CommitRef
+	index := make(map[string]int)
+	for _, m := range commitRefPattern.FindAllStringSubmatch(message, -1) {
+		id, closes := m[2], m[1] != ""
+		if i, ok := index[id]; ok {
+			refs[i].Closes = refs[i].Closes || closes
+			continue
+		}
+		index[id] = len(refs)
+		refs = append(refs, CommitRef{IssueID: id, Closes: closes})
+	}
+	return refs
+}
+
+// openRepo opens the git repository containing path
+func openRepo(path string) (*git.Repository, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, ErrNoGitRepo
+	}
+	return repo, nil
+}
+
+// ReadCommits returns up to limit commits reachable from rev (HEAD when
+// empty) in the repository containing repoPath, newest first. A limit of 0
+// reads the whole history.
+func ReadCommits(repoPath, rev string, limit int) ([]Commit, error) {
+	repo, err := openRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	iter, err := repo.Log(&git.LogOptions{From: *hash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	var commits []Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		commits = append(commits, commitFromObject(c))
+		if limit > 0 && len(commits) >= limit {
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	return commits, nil
+}
+
+// LookupCommit returns a commit by full or abbreviated hash
+func LookupCommit(repoPath, hash string) (*Commit, error) {
+	repo, err := openRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := repo.ResolveRevision(plumbing.Revision(hash))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", hash, err)
+	}
+	c, err := repo.CommitObject(*resolved)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s: %w", hash, err)
+	}
+	commit := commitFromObject(c)
+	return &commit, nil
+}
+
+func commitFromObject(c *object.Commit) Commit {
+	return Commit{
+		Hash:    c.Hash.String(),
+		Message: c.Message,
+		Author:  c.Author.Name,
+		When:    c.Author.When,
+	}
+}
+
+// CommitLink is an issue reference found by SyncCommits
+type CommitLink struct {
+	IssueID     string `json:"issue_id"`
+	SpecContext string `json:"spec_context"`
+	Commit      string `json:"commit"`
+	Subject     string `json:"subject"`
+	Closed      bool   `json:"closed,omitempty"`      // The commit closed the issue
+	CloseError  string `json:"close_error,omitempty"` // Why a closing reference left the issue open
+}
+
+// CommitSyncResult reports what SyncCommits linked and closed
+type CommitSyncResult struct {
+	Scanned  int          `json:"scanned"`
+	Links    []CommitLink `json:"links"`
+	Unknown  []string     `json:"unknown,omitempty"`  // Referenced IDs without an issue
+	Archived []string     `json:"archived,omitempty"` // Referenced IDs of archived issues, left unchanged
+}
+
+// SyncCommits records each commit on the issues its message references and
+// closes issues referenced with a closing keyword, with the commit as the
+// reason. Commits already recorded on an issue are skipped, so syncing is
+// idempotent and an issue reopened after a fixing commit stays open. commits
+// are newest first, as ReadCommits returns them. References to archived
+// issues are reported but not recorded, since archived issues cannot change.
+// With dryRun nothing is written.
+func SyncCommits(basePath string, commits []Commit, dryRun bool) (*CommitSyncResult, error) {
+	all, err := ListAllSpecs(basePath, ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+	byID := make(map[string]*Issue, len(all))
+	for i := range all {
+		byID[all[i].ID] = &all[i]
+	}
+	archivedIssues, err := listArchived(basePath, ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived issues: %w", err)
+	}
+	isArchived := make(map[string]bool, len(archivedIssues))
+	for _, issue := range archivedIssues {
+		isArchived[issue.ID] = true
+	}
+
+	result := &CommitSyncResult{Scanned: len(commits)}
+	stores := make(map[string]*Store)
+	unknown := make(map[string]bool)
+	archived := make(map[string]bool)
+
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		for _, ref := range ParseCommitRefs(commit.Message) {
+			issue, ok := byID[ref.IssueID]
+			if !ok {
+				if isArchived[ref.IssueID] {
+					archived[ref.IssueID] = true
+				} else {
+					unknown[ref.IssueID] = true
+				}
+				continue
+			}
+			if contains(issue.Commits, commit.Hash) {
+				continue
+			}
+
+			store, ok := stores[issue.SpecContext]
+			if !ok {
+				store, err = NewStore(StoreOptions{BasePath: basePath, SpecContext: issue.SpecContext})
+				if err != nil {
+					return nil, fmt.Errorf("failed to create store: %w", err)
+				}
+				stores[issue.SpecContext] = store
+			}
+
+			link := CommitLink{
+				IssueID:     issue.ID,
+				SpecContext: issue.SpecContext,
+				Commit:      commit.Hash,
+				Subject:     commit.Subject(),
+			}
+			closes := ref.Closes && !store.Workflow().IsDone(issue.Status)
+
+			if dryRun {
+				issue.Commits = append(issue.Commits, commit.Hash)
+				if closes {
+					issue.Status = store.Workflow().StatusFor(CategoryDone)
+				}
+				link.Closed = closes
+				result.Links = append(result.Links, link)
+				continue
+			}
+
+			updated, err := store.Update(issue.ID, IssueUpdate{AddCommits: []string{commit.Hash}})
+			if err != nil {
+				return nil, fmt.Errorf("failed to link %s to %s: %w", commit.ShortHash(), issue.ID, err)
+			}
+			if closes {
+				status := store.Workflow().StatusFor(CategoryDone)
+				closed, err := store.Update(issue.ID, IssueUpdate{
+					Status: &status,
+					Reason: fmt.Sprintf("Closed by commit %s: %s", commit.ShortHash(), commit.Subject()),
+				})
+				switch {
+				case err == nil:
+					updated, link.Closed = closed, true
+				case errors.Is(err, ErrTransitionNotAllowed), errors.Is(err, ErrDoDIncomplete):
+					link.CloseError = err.Error()
+				default:
+					return nil, fmt.Errorf("failed to close %s: %w", issue.ID, err)
+				}
+			}
+			*issue = *updated
+			result.Links = append(result.Links, link)
+		}
+	}
+
+	for id := range unknown {
+		result.Unknown = append(result.Unknown, id)
+	}
+	sort.Strings(result.Unknown)
+	for id := range archived {
+		result.Archived = append(result.Archived, id)
+	}
+	sort.Strings(result.Archived)
+	return result, nil
+}
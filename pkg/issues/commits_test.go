@@ -0,0 +1,114 @@
+package issues
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseCommitRefs(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []CommitRef
+	}{
+		{"Add parser", nil},
+		{"Add parser for SL-ab12cd", []CommitRef{{IssueID: "SL-ab12cd"}}},
+		{"Fixes SL-ab12cd", []CommitRef{{IssueID: "SL-ab12cd", Closes: true}}},
+		{"Refactor SL-ab12cd\n\ncloses: SL-ff0011, see SL-ab12cd", []CommitRef{
+			{IssueID: "SL-ab12cd"},
+			{IssueID: "SL-ff0011", Closes: true},
+		}},
+		{"prefix SL-ab12cd and resolved SL-ab12cd", []CommitRef{{IssueID: "SL-ab12cd", Closes: true}}},
+		{"Not an ID: SL-ab12cdef or XSL-ab12cd", nil},
+	}
+	for _, tt := range tests {
+		if got := ParseCommitRefs(tt.message); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseCommitRefs(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestSyncCommits(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	if err := os.MkdirAll(filepath.Join(basePath, "010-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: "010-test"})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	mentioned := NewIssue("Mentioned", "", "010-test", TypeTask, 1)
+	fixed := NewIssue("Fixed", "", "010-test", TypeTask, 1)
+	fixed.CreatedAt = fixed.CreatedAt.Add(time.Second)
+	for _, issue := range []*Issue{mentioned, fixed} {
+		if err := store.Create(issue); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+
+	commits := []Commit{ // Newest first
+		{Hash: "2222222222222222222222222222222222222222", Message: "Fix tests, fixes " + fixed.ID},
+		{Hash: "1111111111111111111111111111111111111111", Message: "Start work on " + mentioned.ID + " and " + fixed.ID + "\n\nAlso SL-000000"},
+	}
+
+	dry, err := SyncCommits(basePath, commits, true)
+	if err != nil {
+		t.Fatalf("SyncCommits(dry run) error: %v", err)
+	}
+	if len(dry.Links) != 3 || !dry.Links[2].Closed {
+		t.Errorf("dry run links = %+v, want 3 with the last closing", dry.Links)
+	}
+	if got, _ := store.Get(fixed.ID); len(got.Commits) != 0 || got.Status != StatusOpen {
+		t.Error("dry run should not write")
+	}
+
+	result, err := SyncCommits(basePath, commits, false)
+	if err != nil {
+		t.Fatalf("SyncCommits() error: %v", err)
+	}
+	if !reflect.DeepEqual(result.Unknown, []string{"SL-000000"}) {
+		t.Errorf("Unknown = %v, want [SL-000000]", result.Unknown)
+	}
+	got, _ := store.Get(fixed.ID)
+	if got.Status != StatusClosed || !reflect.DeepEqual(got.Commits, []string{commits[1].Hash, commits[0].Hash}) {
+		t.Errorf("fixed issue = %s %v, want closed with both commits oldest first", got.Status, got.Commits)
+	}
+	if got, _ := store.Get(mentioned.ID); got.Status != StatusOpen || len(got.Commits) != 1 {
+		t.Errorf("mentioned issue = %s %v, want open with one commit", got.Status, got.Commits)
+	}
+
+	// A reopened issue stays open when the same history is synced again
+	open := StatusOpen
+	if _, err := store.Update(fixed.ID, IssueUpdate{Status: &open}); err != nil {
+		t.Fatal(err)
+	}
+	again, err := SyncCommits(basePath, commits, false)
+	if err != nil {
+		t.Fatalf("SyncCommits() again error: %v", err)
+	}
+	if len(again.Links) != 0 {
+		t.Errorf("second sync links = %+v, want none", again.Links)
+	}
+	if got, _ := store.Get(fixed.ID); got.Status != StatusOpen {
+		t.Error("second sync should not close the reopened issue")
+	}
+
+	// References to archived issues are neither unknown nor recorded
+	closed := StatusClosed
+	if _, err := store.Update(mentioned.ID, IssueUpdate{Status: &closed}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Archive(time.Now().Add(time.Hour), false); err != nil {
+		t.Fatalf("Archive() error: %v", err)
+	}
+	later := []Commit{{Hash: "3333333333333333333333333333333333333333", Message: "Follow up, fixes " + mentioned.ID}}
+	archived, err := SyncCommits(basePath, later, false)
+	if err != nil {
+		t.Fatalf("SyncCommits() after archiving error: %v", err)
+	}
+	if len(archived.Links) != 0 || len(archived.Unknown) != 0 || !reflect.DeepEqual(archived.Archived, []string{mentioned.ID}) {
+		t.Errorf("sync after archiving = %+v, want %s reported as archived", archived, mentioned.ID)
+	}
+}
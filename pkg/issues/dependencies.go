@@ -57,17 +57,29 @@ func (s *Store) AddDependency(fromID, toID string, linkType LinkType) error {
 			return err
 		}
 
+		// Issues in dependencies can only block local issues, and only the
+		// local side of the link is stored
+		if IsQualifiedID(toID) {
+			return fmt.Errorf("%w: %s (link from the dependency's issue instead)", ErrExternalIssue, toID)
+		}
+		if alias, id, ok := ParseQualifiedID(fromID); ok {
+			if linkType != LinkBlocks {
+				return fmt.Errorf("only blocks links are supported for issues in dependencies")
+			}
+			if _, _, err := ExternalIssueFunc(s.basePath, alias, id); err != nil {
+				return fmt.Errorf("%w: %s: %w", ErrDependencyNotFound, fromID, err)
+			}
+			return s.setExternalBlockerUnlocked(issues, fromID, toID, true)
+		}
+
 		// Find both issues
 		var fromIssue, toIssue *Issue
-		var fromIdx, toIdx int
-		for i, issue := range issues {
+		for _, issue := range issues {
 			if issue.ID == fromID {
 				fromIssue = issue
-				fromIdx = i
 			}
 			if issue.ID == toID {
 				toIssue = issue
-				toIdx = i
 			}
 		}
 
@@ -77,6 +89,7 @@ func (s *Store) AddDependency(fromID, toID string, linkType LinkType) error {
 		if toIssue == nil {
 			return fmt.Errorf("issue %s not found", toID)
 		}
+		fromBefore, toBefore := copyIssue(fromIssue), copyIssue(toIssue)
 
 		// For blocks: fromID blocks toID means toID is blocked by fromID
 		if linkType == LinkBlocks {
@@ -115,12 +128,15 @@ func (s *Store) AddDependency(fromID, toID string, linkType LinkType) error {
 		fromIssue.UpdatedAt = NowFunc()
 		toIssue.UpdatedAt = NowFunc()
 
-		// Update in slice
-		issues[fromIdx] = fromIssue
-		issues[toIdx] = toIssue
-
 		// Write back
-		return s.writeAllUnlocked(issues)
+		if err := s.backend.Update(fromIssue, toIssue); err != nil {
+			return err
+		}
+
+		if err := s.recordEventUnlocked(EventLinked, fromBefore, fromIssue, ""); err != nil {
+			return err
+		}
+		return s.recordEventUnlocked(EventLinked, toBefore, toIssue, "")
 	})
 }
 
@@ -133,17 +149,18 @@ func (s *Store) RemoveDependency(fromID, toID string, linkType LinkType) error {
 			return err
 		}
 
+		if IsQualifiedID(fromID) {
+			return s.setExternalBlockerUnlocked(issues, fromID, toID, false)
+		}
+
 		// Find both issues
 		var fromIssue, toIssue *Issue
-		var fromIdx, toIdx int
-		for i, issue := range issues {
+		for _, issue := range issues {
 			if issue.ID == fromID {
 				fromIssue = issue
-				fromIdx = i
 			}
 			if issue.ID == toID {
 				toIssue = issue
-				toIdx = i
 			}
 		}
 
@@ -153,6 +170,7 @@ func (s *Store) RemoveDependency(fromID, toID string, linkType LinkType) error {
 		if toIssue == nil {
 			return fmt.Errorf("issue %s not found", toID)
 		}
+		fromBefore, toBefore := copyIssue(fromIssue), copyIssue(toIssue)
 
 		// Remove from fromIssue.Blocks
 		fromIssue.Blocks = removeFromSlice(fromIssue.Blocks, toID)
@@ -164,12 +182,15 @@ func (s *Store) RemoveDependency(fromID, toID string, linkType LinkType) error {
 		fromIssue.UpdatedAt = NowFunc()
 		toIssue.UpdatedAt = NowFunc()
 
-		// Update in slice
-		issues[fromIdx] = fromIssue
-		issues[toIdx] = toIssue
-
 		// Write back
-		return s.writeAllUnlocked(issues)
+		if err := s.backend.Update(fromIssue, toIssue); err != nil {
+			return err
+		}
+
+		if err := s.recordEventUnlocked(EventUnlinked, fromBefore, fromIssue, ""); err != nil {
+			return err
+		}
+		return s.recordEventUnlocked(EventUnlinked, toBefore, toIssue, "")
 	})
 }
 
@@ -287,6 +308,7 @@ func (s *Store) GetDependencyTree(id string) (*DependencyTree, error) {
 	for _, issue := range issues {
 		issueMap[issue.ID] = issue
 	}
+	withExternalBlockers(s.basePath, s.workflow, issues, issueMap)
 
 	// Find the issue
 	issue, ok := issueMap[id]
@@ -323,6 +345,38 @@ func (s *Store) buildDependencySubtree(ids []string, issueMap map[string]*Issue,
 	return trees
 }
 
+// setExternalBlockerUnlocked adds or removes an issue in a dependency, by its
+// qualified ID, from the blockers of a local issue
+func (s *Store) setExternalBlockerUnlocked(issues []*Issue, ref, toID string, add bool) error {
+	var toIssue *Issue
+	for _, issue := range issues {
+		if issue.ID == toID {
+			toIssue = issue
+		}
+	}
+	if toIssue == nil {
+		return fmt.Errorf("issue %s not found", toID)
+	}
+	if contains(toIssue.BlockedBy, ref) == add {
+		return nil
+	}
+	before := copyIssue(toIssue)
+
+	event := EventLinked
+	if add {
+		toIssue.BlockedBy = append(toIssue.BlockedBy, ref)
+	} else {
+		toIssue.BlockedBy = removeFromSlice(toIssue.BlockedBy, ref)
+		event = EventUnlinked
+	}
+	toIssue.UpdatedAt = NowFunc()
+
+	if err := s.backend.Update(toIssue); err != nil {
+		return err
+	}
+	return s.recordEventUnlocked(event, before, toIssue, "")
+}
+
 // GetBlockedIssues returns all issues that are currently blocked
 func (s *Store) GetBlockedIssues() ([]Issue, error) {
 	filter := ListFilter{Blocked: true}
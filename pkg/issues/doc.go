@@ -1,5 +1,6 @@
 // Package issues provides a built-in issue tracking system for SpecLedger.
-// Issues are stored as JSONL files per spec at specledger/<spec>/issues.jsonl.
+// Issues are stored as JSONL files per spec at specledger/<spec>/issues.jsonl,
+// or in an embedded SQLite database at specledger/issues.db (see Backend).
 //
 // Key features:
 //   - Globally unique SHA-256 based issue IDs (SL-xxxxxx format)
@@ -8,4 +9,5 @@
 //   - Migration support from Beads format
 //   - Dependency tracking with cycle detection
 //   - Definition of Done validation
+//   - Append-only event log (issues.events.jsonl) with field-level history
 package issues
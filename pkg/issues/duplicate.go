@@ -2,8 +2,6 @@ package issues
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/texttheater/golang-levenshtein/levenshtein"
@@ -93,8 +91,6 @@ func FormatDuplicateWarning(duplicates []DuplicateResult) string {
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString("Use --force to create anyway.\n")
-
 	return sb.String()
 }
 
@@ -104,10 +100,11 @@ type CheckDuplicateResult struct {
 	Duplicates    []DuplicateResult
 }
 
-// CheckDuplicatesForCreate checks for duplicates when creating a new issue
-func CheckDuplicatesForCreate(title, specContext string, threshold float64) (*CheckDuplicateResult, error) {
+// CheckDuplicatesForCreate checks for duplicates when creating a new issue in
+// the spec. An empty basePath means "specledger".
+func CheckDuplicatesForCreate(basePath, title, specContext string, threshold float64) (*CheckDuplicateResult, error) {
 	// First check within the same spec
-	store, err := NewStore(StoreOptions{SpecContext: specContext})
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: specContext})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store: %w", err)
 	}
@@ -160,26 +157,18 @@ func CheckDuplicatesAcrossAllSpecs(title string, threshold float64) (*CheckDupli
 	}, nil
 }
 
-// listSpecDirectories lists all spec directories in the base path
+// listSpecDirectories lists the spec directories with issues, excluding
+// the "migrated" holding area
 func listSpecDirectories(basePath string) ([]string, error) {
-	entries, err := os.ReadDir(basePath)
+	all, err := listSpecDirs(basePath)
 	if err != nil {
 		return nil, err
 	}
 
 	var specs []string
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
-		// Check if it matches the spec pattern (###-name)
-		if specBranchPattern.MatchString(name) {
-			// Check if issues.jsonl exists
-			issuesPath := filepath.Join(basePath, name, "issues.jsonl")
-			if _, err := os.Stat(issuesPath); err == nil {
-				specs = append(specs, name)
-			}
+	for _, spec := range all {
+		if spec != "migrated" {
+			specs = append(specs, spec)
 		}
 	}
 
@@ -0,0 +1,426 @@
+package issues
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventsFileName is the per-spec append-only event log stored next to issues.jsonl.
+const EventsFileName = "issues.events.jsonl"
+
+// EventType identifies the kind of change recorded in the event log
+type EventType string
+
+const (
+	EventCreated    EventType = "created"
+	EventUpdated    EventType = "updated"
+	EventClosed     EventType = "closed"
+	EventReopened   EventType = "reopened"
+	EventLinked     EventType = "linked"
+	EventUnlinked   EventType = "unlinked"
+	EventReparented EventType = "reparented"
+	EventDeleted    EventType = "deleted"
+	EventArchived   EventType = "archived"
+)
+
+// FieldChange is a single field-level difference. Values are stored as the
+// field's JSON encoding so history can be replayed exactly.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old,omitempty"`
+	New   json.RawMessage `json:"new,omitempty"`
+}
+
+// Event is one entry in the append-only issue event log
+type Event struct {
+	IssueID   string        `json:"issue_id"`
+	Type      EventType     `json:"type"`
+	Actor     string        `json:"actor"`
+	Timestamp time.Time     `json:"timestamp"`
+	Reason    string        `json:"reason,omitempty"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+// ActorFunc returns the identity recorded on events. It can be overridden for testing.
+var ActorFunc = defaultActor
+
+var (
+	actorOnce  sync.Once
+	actorValue string
+)
+
+// defaultActor resolves the actor from SL_ACTOR, then git user.name, then $USER.
+func defaultActor() string {
+	if actor := os.Getenv("SL_ACTOR"); actor != "" {
+		return actor
+	}
+	actorOnce.Do(func() {
+		if out, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+			actorValue = strings.TrimSpace(string(out))
+		}
+		if actorValue == "" {
+			actorValue = os.Getenv("USER")
+		}
+		if actorValue == "" {
+			actorValue = "unknown"
+		}
+	})
+	return actorValue
+}
+
+// eventSkipFields are not tracked in field-level diffs.
+var eventSkipFields = map[string]bool{
+	"ID":        true,
+	"UpdatedAt": true,
+	"Lease":     true, // Renewed on every heartbeat; claims are recorded via assignee/status
+}
+
+// EventsPath returns the path to the event log for this store's spec.
+func (s *Store) EventsPath() string {
+	return filepath.Join(s.specDir, EventsFileName)
+}
+
+// recordEventUnlocked diffs before and after and appends an event. before is nil
+// for creations and after is nil for deletions. When eventType is empty it is
+// inferred from the changed fields. Must be called while holding the lock.
+func (s *Store) recordEventUnlocked(eventType EventType, before, after *Issue, reason string) error {
+	if s.specContext == "" {
+		return nil
+	}
+
+	changes := diffIssues(before, after)
+	if len(changes) == 0 && reason == "" {
+		return nil
+	}
+	// Moves into or out of any done status of the workflow close or reopen
+	if eventType == "" && before != nil && after != nil && s.workflow.IsDone(before.Status) != s.workflow.IsDone(after.Status) {
+		eventType = EventReopened
+		if s.workflow.IsDone(after.Status) {
+			eventType = EventClosed
+		}
+	}
+	if eventType == "" {
+		eventType = classifyChanges(changes)
+	}
+
+	id := ""
+	if after != nil {
+		id = after.ID
+	} else if before != nil {
+		id = before.ID
+	}
+
+	event := Event{
+		IssueID:   id,
+		Type:      eventType,
+		Actor:     ActorFunc(),
+		Timestamp: NowFunc(),
+		Reason:    reason,
+		Changes:   changes,
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	f, err := os.OpenFile(s.EventsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s\n", data); err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	return nil
+}
+
+// diffIssues returns field-level changes between two versions of an issue.
+// Either side may be nil.
+func diffIssues(before, after *Issue) []FieldChange {
+	var changes []FieldChange
+	issueType := reflect.TypeOf(Issue{})
+
+	for i := 0; i < issueType.NumField(); i++ {
+		field := issueType.Field(i)
+		if eventSkipFields[field.Name] || !field.IsExported() {
+			continue
+		}
+
+		// Skip unset fields on creation/deletion to keep entries small
+		if before == nil && omitted(field, reflect.ValueOf(after).Elem().Field(i)) ||
+			after == nil && omitted(field, reflect.ValueOf(before).Elem().Field(i)) {
+			continue
+		}
+
+		var oldJSON, newJSON json.RawMessage
+		if before != nil {
+			oldJSON = fieldJSON(reflect.ValueOf(before).Elem().Field(i))
+		}
+		if after != nil {
+			newJSON = fieldJSON(reflect.ValueOf(after).Elem().Field(i))
+		}
+		if string(oldJSON) == string(newJSON) {
+			continue
+		}
+
+		changes = append(changes, FieldChange{Field: jsonFieldName(field), Old: oldJSON, New: newJSON})
+	}
+
+	return changes
+}
+
+func fieldJSON(v reflect.Value) json.RawMessage {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// omitted reports whether encoding/json leaves the field out of an issue:
+// its tag has omitempty and the value is zero or an empty slice or map
+func omitted(field reflect.StructField, v reflect.Value) bool {
+	_, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if !slices.Contains(strings.Split(opts, ","), "omitempty") {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// classifyChanges infers the event type of an update from the fields it touched.
+func classifyChanges(changes []FieldChange) EventType {
+	fields := make(map[string]FieldChange, len(changes))
+	for _, c := range changes {
+		fields[c.Field] = c
+	}
+
+	if c, ok := fields["status"]; ok {
+		if string(c.New) == `"`+string(StatusClosed)+`"` {
+			return EventClosed
+		}
+		if string(c.Old) == `"`+string(StatusClosed)+`"` {
+			return EventReopened
+		}
+	}
+
+	onlyFields := func(names ...string) bool {
+		for field := range fields {
+			if !contains(names, field) {
+				return false
+			}
+		}
+		return len(fields) > 0
+	}
+
+	switch {
+	case onlyFields("parentId"):
+		return EventReparented
+	case onlyFields("blocked_by", "blocks"):
+		for _, c := range fields {
+			var oldIDs, newIDs []string
+			_ = json.Unmarshal(c.Old, &oldIDs)
+			_ = json.Unmarshal(c.New, &newIDs)
+			if len(newIDs) > len(oldIDs) {
+				return EventLinked
+			}
+		}
+		return EventUnlinked
+	}
+
+	return EventUpdated
+}
+
+// ReadEvents reads an event log. A missing file yields no events.
+func ReadEvents(path string) ([]Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open event log: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			// Skip invalid lines, consistent with issues.jsonl reading
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading event log: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListAllEvents reads the event logs of every spec under basePath.
+func ListAllEvents(basePath string) ([]Event, error) {
+	if basePath == "" {
+		basePath = "specledger"
+	}
+
+	specs, err := listSpecDirs(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spec directories: %w", err)
+	}
+
+	var all []Event
+	for _, spec := range specs {
+		events, err := ReadEvents(filepath.Join(basePath, spec, EventsFileName))
+		if err != nil {
+			continue
+		}
+		all = append(all, events...)
+	}
+	return all, nil
+}
+
+// History returns the recorded events for an issue in chronological order.
+func (s *Store) History(id string) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	all, err := ReadEvents(s.EventsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, e := range all {
+		if e.IssueID == id {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// GetAsOf reconstructs an issue as it was at the given time by starting from
+// its current state and rewinding every event recorded after asOf. Issues that
+// predate the event log are rewound as far as the log reaches.
+func (s *Store) GetAsOf(id string, asOf time.Time) (*Issue, error) {
+	events, err := s.History(id)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.Get(id)
+	if err != nil && !errors.Is(err, ErrIssueNotFound) {
+		return nil, err
+	}
+	if current == nil {
+		if len(events) == 0 {
+			return nil, ErrIssueNotFound
+		}
+		// Deleted since: rewinding the deletion event restores it
+		current = &Issue{ID: id}
+	}
+
+	issue := *current
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		if !e.Timestamp.After(asOf) {
+			break
+		}
+		if e.Type == EventCreated {
+			return nil, fmt.Errorf("%w: %s did not exist at %s", ErrIssueNotFound, id, asOf.Format(time.RFC3339))
+		}
+		if err := applyChanges(&issue, e.Changes, true); err != nil {
+			return nil, fmt.Errorf("failed to replay event at %s: %w", e.Timestamp.Format(time.RFC3339), err)
+		}
+		// The previous version was last touched no later than this event.
+		issue.UpdatedAt = e.Timestamp
+	}
+
+	if issue.CreatedAt.After(asOf) {
+		return nil, fmt.Errorf("%w: %s did not exist at %s", ErrIssueNotFound, id, asOf.Format(time.RFC3339))
+	}
+
+	// Best-effort UpdatedAt: the latest event at or before asOf.
+	for i := len(events) - 1; i >= 0; i-- {
+		if !events[i].Timestamp.After(asOf) {
+			issue.UpdatedAt = events[i].Timestamp
+			break
+		}
+	}
+
+	return &issue, nil
+}
+
+// applyChanges sets fields from a change list. When rewind is true the Old
+// values are applied, otherwise the New values. A missing value resets the
+// field to its zero value.
+func applyChanges(issue *Issue, changes []FieldChange, rewind bool) error {
+	v := reflect.ValueOf(issue).Elem()
+	t := v.Type()
+
+	byName := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		byName[jsonFieldName(t.Field(i))] = i
+	}
+
+	for _, c := range changes {
+		idx, ok := byName[c.Field]
+		if !ok {
+			// Field no longer exists on Issue; ignore
+			continue
+		}
+		raw := c.New
+		if rewind {
+			raw = c.Old
+		}
+
+		field := v.Field(idx)
+		field.Set(reflect.Zero(field.Type()))
+		if len(raw) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(raw, field.Addr().Interface()); err != nil {
+			return fmt.Errorf("field %s: %w", c.Field, err)
+		}
+	}
+	return nil
+}
+
+// copyIssue returns a deep copy of an issue for before/after comparisons.
+func copyIssue(issue *Issue) *Issue {
+	data, err := json.Marshal(issue)
+	if err != nil {
+		c := *issue
+		return &c
+	}
+	var c Issue
+	if err := json.Unmarshal(data, &c); err != nil {
+		c = *issue
+	}
+	return &c
+}
@@ -0,0 +1,137 @@
+package issues
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEventLog(t *testing.T) {
+	store := setupTestStore(t)
+
+	clock := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	NowFunc = func() time.Time { return clock }
+	ActorFunc = func() string { return "alice" }
+	defer func() {
+		NowFunc = time.Now
+		ActorFunc = defaultActor
+	}()
+	tick := func() time.Time {
+		clock = clock.Add(time.Hour)
+		return clock
+	}
+
+	epic := NewIssue("Epic", "", "010-test", TypeEpic, 1)
+	epic.ID = "SL-eeeeee"
+	task := NewIssue("Task", "original description", "010-test", TypeTask, 2)
+	task.ID = "SL-111111"
+	other := NewIssue("Other", "", "010-test", TypeTask, 2)
+	other.ID = "SL-222222"
+	for _, i := range []*Issue{epic, task, other} {
+		i.CreatedAt, i.UpdatedAt = clock, clock
+		if err := store.Create(i); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+	created := clock
+
+	tick()
+	status := StatusInProgress
+	desc := "rewritten description"
+	if _, err := store.Update(task.ID, IssueUpdate{Status: &status, Description: &desc}); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	afterUpdate := clock
+
+	ActorFunc = func() string { return "bob" }
+	tick()
+	if err := store.AddDependency(other.ID, task.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+	tick()
+	parent := epic.ID
+	if _, err := store.Update(task.ID, IssueUpdate{ParentID: &parent}); err != nil {
+		t.Fatalf("Update(parent) error: %v", err)
+	}
+	tick()
+	closed := StatusClosed
+	if _, err := store.Update(task.ID, IssueUpdate{Status: &closed, Reason: "shipped"}); err != nil {
+		t.Fatalf("Update(close) error: %v", err)
+	}
+
+	events, err := store.History(task.ID)
+	if err != nil {
+		t.Fatalf("History() error: %v", err)
+	}
+
+	wantTypes := []EventType{EventCreated, EventUpdated, EventLinked, EventReparented, EventClosed}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("expected %d events, got %d: %+v", len(wantTypes), len(events), events)
+	}
+	for i, want := range wantTypes {
+		if events[i].Type != want {
+			t.Errorf("event %d: expected %s, got %s", i, want, events[i].Type)
+		}
+	}
+
+	for _, change := range events[0].Changes {
+		switch change.Field {
+		case "estimate", "labels", "assignee", "closed_at":
+			t.Errorf("create event records unset field %s: %s", change.Field, change.New)
+		}
+	}
+
+	update := events[1]
+	if update.Actor != "alice" || len(update.Changes) != 2 {
+		t.Errorf("unexpected update event: %+v", update)
+	}
+	if events[2].Actor != "bob" {
+		t.Errorf("expected link by bob, got %s", events[2].Actor)
+	}
+	if events[4].Reason != "shipped" {
+		t.Errorf("expected close reason to be recorded, got %q", events[4].Reason)
+	}
+
+	t.Run("as-of replays earlier state", func(t *testing.T) {
+		past, err := store.GetAsOf(task.ID, created)
+		if err != nil {
+			t.Fatalf("GetAsOf() error: %v", err)
+		}
+		if past.Status != StatusOpen || past.Description != "original description" {
+			t.Errorf("expected original state, got status=%s description=%q", past.Status, past.Description)
+		}
+		if past.ParentID != nil || len(past.BlockedBy) != 0 || past.ClosedAt != nil {
+			t.Errorf("expected no parent/blockers/closed_at, got %+v", past)
+		}
+
+		mid, err := store.GetAsOf(task.ID, afterUpdate)
+		if err != nil {
+			t.Fatalf("GetAsOf() error: %v", err)
+		}
+		if mid.Status != StatusInProgress || mid.Description != "rewritten description" {
+			t.Errorf("expected updated state, got status=%s description=%q", mid.Status, mid.Description)
+		}
+	})
+
+	t.Run("as-of before creation", func(t *testing.T) {
+		_, err := store.GetAsOf(task.ID, created.Add(-time.Minute))
+		if !errors.Is(err, ErrIssueNotFound) {
+			t.Errorf("expected ErrIssueNotFound, got %v", err)
+		}
+	})
+
+	t.Run("deleted issue can be replayed", func(t *testing.T) {
+		beforeDelete := clock
+		tick()
+		if err := store.Delete(epic.ID); err != nil {
+			t.Fatalf("Delete() error: %v", err)
+		}
+		restored, err := store.GetAsOf(epic.ID, beforeDelete)
+		if err != nil {
+			t.Fatalf("GetAsOf() error: %v", err)
+		}
+		if restored.Title != "Epic" || restored.IssueType != TypeEpic {
+			t.Errorf("unexpected restored issue: %+v", restored)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Cross-repository reference errors
+var (
+	ErrExternalIssue     = errors.New("issue belongs to a dependency and cannot be changed here")
+	ErrUnknownDependency = errors.New("no dependency with that alias")
+	ErrDependencyMissing = errors.New("dependency is not cached")
+)
+
+// qualifiedIDPattern matches references to issues in dependencies, e.g. @platform/SL-1a2b3c
+var qualifiedIDPattern = regexp.MustCompile(`^@([A-Za-z0-9][A-Za-z0-9._-]*)/(SL-[a-f0-9]{6})$`)
+
+// ParseQualifiedID splits a reference to an issue in a dependency, such as
+// "@platform/SL-1a2b3c", into the dependency alias and the issue ID
+func ParseQualifiedID(ref string) (alias, id string, ok bool) {
+	m := qualifiedIDPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[2], true
+}
+
+// IsQualifiedID reports whether ref refers to an issue in a dependency
+func IsQualifiedID(ref string) bool {
+	_, _, ok := ParseQualifiedID(ref)
+	return ok
+}
+
+// QualifiedID formats a reference to issue id in the dependency with alias
+func QualifiedID(alias, id string) string {
+	return "@" + alias + "/" + id
+}
+
+// ParseIssueRef validates a local issue ID or a qualified reference to an
+// issue in a dependency
+func ParseIssueRef(ref string) (string, error) {
+	if IsQualifiedID(ref) {
+		return ref, nil
+	}
+	if _, err := ParseIssueID(ref); err != nil {
+		return "", fmt.Errorf("%w (or @alias/SL-xxxxxx for an issue in a dependency)", err)
+	}
+	return ref, nil
+}
+
+// ExternalIssueFunc looks up issue id in the cached checkout of the
+// dependency with the given alias, for the project owning basePath, and
+// reports whether it is done by the dependency's own workflow. The CLI
+// replaces it to resolve aliases from specledger.yaml.
+var ExternalIssueFunc = func(basePath, alias, id string) (*Issue, bool, error) {
+	return nil, false, fmt.Errorf("%w: %s", ErrUnknownDependency, alias)
+}
+
+// withExternalBlockers adds the dependency issues that issues are blocked by
+// to issueMap under their qualified IDs, with their status mapped onto
+// workflow: its done status once the issue is done upstream, its todo status
+// otherwise. References that cannot be resolved, e.g. because the dependency
+// is not cached, keep blocking.
+func withExternalBlockers(basePath string, workflow *Workflow, issues []*Issue, issueMap map[string]*Issue) {
+	for _, issue := range issues {
+		for _, ref := range issue.BlockedBy {
+			if _, ok := issueMap[ref]; ok {
+				continue
+			}
+			alias, id, ok := ParseQualifiedID(ref)
+			if !ok {
+				continue
+			}
+
+			external := &Issue{ID: ref, Status: workflow.StatusFor(CategoryTodo), IssueType: TypeTask}
+			upstream, done, err := ExternalIssueFunc(basePath, alias, id)
+			if err != nil {
+				external.Title = fmt.Sprintf("(unavailable: %v)", err)
+			} else {
+				external.Title = upstream.Title
+				external.IssueType = upstream.IssueType
+				external.Priority = upstream.Priority
+				external.SpecContext = upstream.SpecContext
+				if done {
+					external.Status = workflow.StatusFor(CategoryDone)
+				}
+			}
+			issueMap[ref] = external
+		}
+	}
+}
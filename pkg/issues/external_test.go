@@ -0,0 +1,77 @@
+package issues
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseQualifiedID(t *testing.T) {
+	alias, id, ok := ParseQualifiedID("@platform/SL-1a2b3c")
+	if !ok || alias != "platform" || id != "SL-1a2b3c" {
+		t.Errorf("ParseQualifiedID() = %q, %q, %v", alias, id, ok)
+	}
+	for _, ref := range []string{"SL-1a2b3c", "@/SL-1a2b3c", "@platform/SL-XYZ123", "platform/SL-1a2b3c"} {
+		if IsQualifiedID(ref) {
+			t.Errorf("IsQualifiedID(%q) = true", ref)
+		}
+	}
+}
+
+func TestExternalBlockers(t *testing.T) {
+	upstreamDone := false
+	saved := ExternalIssueFunc
+	ExternalIssueFunc = func(basePath, alias, id string) (*Issue, bool, error) {
+		if alias != "platform" || id != "SL-1a2b3c" {
+			return nil, false, ErrIssueNotFound
+		}
+		return &Issue{ID: id, Title: "Upstream API", Status: "released"}, upstreamDone, nil
+	}
+	defer func() { ExternalIssueFunc = saved }()
+
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	if err := os.MkdirAll(filepath.Join(basePath, "010-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: "010-test"})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	task := NewIssue("Task", "", "010-test", TypeTask, 1)
+	if err := store.Create(task); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	if err := store.AddDependency("@platform/SL-ffffff", task.ID, LinkBlocks); !errors.Is(err, ErrDependencyNotFound) {
+		t.Errorf("unknown upstream issue: got %v, want ErrDependencyNotFound", err)
+	}
+	if err := store.AddDependency(task.ID, "@platform/SL-1a2b3c", LinkBlocks); !errors.Is(err, ErrExternalIssue) {
+		t.Errorf("blocking an upstream issue: got %v, want ErrExternalIssue", err)
+	}
+	if err := store.AddDependency("@platform/SL-1a2b3c", task.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+
+	ready, _ := store.ListReady(ListFilter{})
+	if len(ready) != 0 {
+		t.Errorf("ready = %d issues, want none while upstream is open", len(ready))
+	}
+	blocked, _ := store.GetBlockedIssuesWithBlockers()
+	if len(blocked) != 1 || len(blocked[0].BlockedBy) != 1 || blocked[0].BlockedBy[0].Title != "Upstream API" {
+		t.Errorf("blocked = %+v, want %s blocked by the upstream issue", blocked, task.ID)
+	}
+
+	upstreamDone = true
+	ready, _ = store.ListReady(ListFilter{})
+	if len(ready) != 1 {
+		t.Errorf("ready = %d issues, want the task once upstream is done", len(ready))
+	}
+
+	if err := store.RemoveDependency("@platform/SL-1a2b3c", task.ID, LinkBlocks); err != nil {
+		t.Fatalf("RemoveDependency() error: %v", err)
+	}
+	if got, _ := store.Get(task.ID); len(got.BlockedBy) != 0 {
+		t.Errorf("BlockedBy = %v after unlinking", got.BlockedBy)
+	}
+}
@@ -0,0 +1,470 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExchangeFormat is a file format for importing and exporting issues
+type ExchangeFormat string
+
+const (
+	// FormatCSV is a generic CSV with one column per issue field. Imports also
+	// accept common header names from other trackers (Summary, State, Tags, ...).
+	FormatCSV ExchangeFormat = "csv"
+	// FormatMarkdown is a nested task list, one item per issue
+	FormatMarkdown ExchangeFormat = "markdown"
+	// FormatGitHubJSON is a JSON array of GitHub issues as returned by
+	// `gh issue list --json` or the REST API
+	FormatGitHubJSON ExchangeFormat = "github-json"
+	// FormatJiraCSV is Jira's CSV export layout
+	FormatJiraCSV ExchangeFormat = "jira-csv"
+)
+
+// ExchangeFormats lists the supported formats
+var ExchangeFormats = []ExchangeFormat{FormatCSV, FormatMarkdown, FormatGitHubJSON, FormatJiraCSV}
+
+// ErrUnknownFormat is returned for unsupported exchange formats
+var ErrUnknownFormat = errors.New("unknown format")
+
+// ParseExchangeFormat validates a format name
+func ParseExchangeFormat(name string) (ExchangeFormat, error) {
+	for _, f := range ExchangeFormats {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q (must be csv, markdown, github-json or jira-csv)", ErrUnknownFormat, name)
+}
+
+// ExternalIssue is an issue read from another tracker's file, before it is
+// assigned a SpecLedger ID. Parent and BlockedBy refer to OriginalIDs.
+type ExternalIssue struct {
+	OriginalID         string
+	URL                string
+	Title              string
+	Description        string
+	Notes              string
+	Design             string
+	AcceptanceCriteria string
+	Status             IssueStatus
+	SourceStatus       string // Status as named by the source, kept if the workflow declares it
+	IssueType          IssueType
+	Priority           int
+	Labels             []string
+	Assignee           string
+	Parent             string
+	BlockedBy          []string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	ClosedAt           *time.Time
+}
+
+// ExportIssues writes issues in the given format
+func ExportIssues(w io.Writer, format ExchangeFormat, issues []Issue) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, issues)
+	case FormatMarkdown:
+		return writeMarkdown(w, issues)
+	case FormatGitHubJSON:
+		return writeGitHubJSON(w, issues)
+	case FormatJiraCSV:
+		return writeJiraCSV(w, issues)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// ParseExternalIssues reads issues in the given format
+func ParseExternalIssues(r io.Reader, format ExchangeFormat) ([]ExternalIssue, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatMarkdown:
+		return readMarkdown(r)
+	case FormatGitHubJSON:
+		return readGitHubJSON(r)
+	case FormatJiraCSV:
+		return readJiraCSV(r)
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// ImportOptions configures ImportIssues
+type ImportOptions struct {
+	BasePath    string // Path to specledger directory (default: "specledger")
+	SpecContext string // Spec receiving the issues
+	Format      ExchangeFormat
+	Force       bool // Import issues even if a similar title exists
+	DryRun      bool // Report what would be imported without writing
+}
+
+// ImportSkip describes an issue that was not imported
+type ImportSkip struct {
+	OriginalID string
+	Title      string
+	Reason     string
+}
+
+// ImportResult contains the results of an import
+type ImportResult struct {
+	TotalIssues    int
+	ImportedIssues int
+	Links          int               // Parent and blocking links created
+	IDMapping      map[string]string // original ID -> SL ID
+	Skipped        []ImportSkip
+	Warnings       []string
+}
+
+// ImportIssues reads issues from r and creates them in the target spec. Each
+// new issue records its source and original ID in Migration. Issues already
+// imported from the same source are skipped, as are titles that
+// CheckDuplicatesForCreate flags unless Force is set. Parent and blocking
+// links are recreated when both ends were imported or already exist in the spec.
+func ImportIssues(r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	basePath := opts.BasePath
+	if basePath == "" {
+		basePath = "specledger"
+	}
+
+	external, err := ParseExternalIssues(r, opts.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", opts.Format, err)
+	}
+
+	if !opts.DryRun {
+		if err := os.MkdirAll(filepath.Join(basePath, opts.SpecContext), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create spec directory: %w", err)
+		}
+	}
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: opts.SpecContext})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	existing, err := store.List(ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list issues: %w", err)
+	}
+
+	result := &ImportResult{
+		TotalIssues: len(external),
+		IDMapping:   make(map[string]string),
+	}
+
+	// Earlier imports from the same source resolve links but are not recreated
+	existingIDs := make(map[string]bool, len(existing))
+	for _, issue := range existing {
+		existingIDs[issue.ID] = true
+		if issue.Migration != nil && issue.Migration.Source == string(opts.Format) {
+			result.IDMapping[issue.Migration.OriginalID] = issue.ID
+		}
+	}
+
+	now := NowFunc()
+	created := make(map[string]bool)
+	for _, ext := range orderParentsFirst(external) {
+		skip := func(reason string) {
+			result.Skipped = append(result.Skipped, ImportSkip{OriginalID: ext.OriginalID, Title: ext.Title, Reason: reason})
+		}
+		if ext.OriginalID != "" {
+			if id, ok := result.IDMapping[ext.OriginalID]; ok {
+				skip("already imported as " + id)
+				continue
+			}
+		}
+
+		if !opts.Force {
+			dup, err := CheckDuplicatesForCreate(basePath, ext.Title, opts.SpecContext, DefaultSimilarityThreshold)
+			if err != nil {
+				return nil, err
+			}
+			if dup.HasDuplicates {
+				skip(fmt.Sprintf("similar to %s %q", dup.Duplicates[0].Issue.ID, dup.Duplicates[0].Issue.Title))
+				continue
+			}
+		}
+
+		issue := ext.toIssue(store.Workflow(), opts.SpecContext, opts.Format, now)
+		if ext.Parent != "" {
+			if parentID, ok := resolveImportRef(ext.Parent, result.IDMapping, existingIDs); ok && (created[parentID] || existingIDs[parentID]) {
+				issue.ParentID = &parentID
+			}
+		}
+
+		if !opts.DryRun {
+			if err := store.Create(issue); err != nil {
+				skip(err.Error())
+				continue
+			}
+		}
+		created[issue.ID] = true
+		if ext.OriginalID != "" {
+			result.IDMapping[ext.OriginalID] = issue.ID
+		}
+		result.ImportedIssues++
+	}
+
+	// Links are added once every issue exists
+	for _, ext := range external {
+		id, ok := result.IDMapping[ext.OriginalID]
+		if !ok || !created[id] {
+			continue
+		}
+		if ext.Parent != "" {
+			parentID, ok := resolveImportRef(ext.Parent, result.IDMapping, existingIDs)
+			switch {
+			case !ok:
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: parent %s was not imported", id, ext.Parent))
+			case !opts.DryRun:
+				if issue, err := store.Get(id); err == nil && issue.ParentID == nil {
+					if _, err := store.Update(id, IssueUpdate{ParentID: &parentID}); err != nil {
+						result.Warnings = append(result.Warnings, fmt.Sprintf("%s: parent %s: %v", id, parentID, err))
+						break
+					}
+				}
+				result.Links++
+			default:
+				result.Links++
+			}
+		}
+		for _, ref := range ext.BlockedBy {
+			blockerID, ok := resolveImportRef(ref, result.IDMapping, existingIDs)
+			if !ok {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: blocker %s was not imported", id, ref))
+				continue
+			}
+			if !opts.DryRun {
+				if err := store.AddDependency(blockerID, id, LinkBlocks); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("%s blocks %s: %v", blockerID, id, err))
+					continue
+				}
+			}
+			result.Links++
+		}
+	}
+
+	return result, nil
+}
+
+// resolveImportRef maps an original ID to its SL ID, or accepts an SL ID already in the spec.
+func resolveImportRef(ref string, mapping map[string]string, existing map[string]bool) (string, bool) {
+	if id, ok := mapping[ref]; ok {
+		return id, true
+	}
+	if existing[ref] {
+		return ref, true
+	}
+	return "", false
+}
+
+// orderParentsFirst returns issues ordered so that parents precede their
+// children, keeping the file order otherwise.
+func orderParentsFirst(external []ExternalIssue) []ExternalIssue {
+	byID := make(map[string]bool, len(external))
+	for _, ext := range external {
+		if ext.OriginalID != "" {
+			byID[ext.OriginalID] = true
+		}
+	}
+
+	ordered := make([]ExternalIssue, 0, len(external))
+	placed := make(map[string]bool, len(external))
+	remaining := external
+	for len(remaining) > 0 {
+		var next []ExternalIssue
+		for _, ext := range remaining {
+			if ext.Parent == "" || !byID[ext.Parent] || placed[ext.Parent] {
+				ordered = append(ordered, ext)
+				placed[ext.OriginalID] = true
+			} else {
+				next = append(next, ext)
+			}
+		}
+		if len(next) == len(remaining) {
+			// Parent cycle: place the rest as they are
+			return append(ordered, next...)
+		}
+		remaining = next
+	}
+	return ordered
+}
+
+// toIssue converts an external issue, keeping its timestamps when known.
+// A source status the workflow declares is kept as it is; others map to the
+// workflow's status for the same category.
+func (ext ExternalIssue) toIssue(workflow *Workflow, specContext string, format ExchangeFormat, now time.Time) *Issue {
+	issue := &Issue{
+		Title:              truncateImportTitle(ext.Title),
+		Description:        ext.Description,
+		Notes:              ext.Notes,
+		Design:             ext.Design,
+		AcceptanceCriteria: ext.AcceptanceCriteria,
+		Status:             ext.Status,
+		IssueType:          ext.IssueType,
+		Priority:           ext.Priority,
+		Labels:             ext.Labels,
+		Assignee:           ext.Assignee,
+		SpecContext:        specContext,
+		CreatedAt:          ext.CreatedAt,
+		UpdatedAt:          ext.UpdatedAt,
+		ClosedAt:           ext.ClosedAt,
+	}
+	if source := IssueStatus(strings.TrimSpace(ext.SourceStatus)); source != "" && workflow.IsValidStatus(source) {
+		issue.Status = source
+	} else if !IsValidStatus(issue.Status) {
+		issue.Status = StatusOpen
+	}
+	if !workflow.IsValidStatus(issue.Status) {
+		issue.Status = workflow.StatusFor(workflow.Category(issue.Status))
+	}
+	if !IsValidIssueType(issue.IssueType) {
+		issue.IssueType = TypeTask
+	}
+	if issue.Priority < 0 || issue.Priority > 5 {
+		issue.Priority = 2
+	}
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	if issue.UpdatedAt.IsZero() {
+		issue.UpdatedAt = issue.CreatedAt
+	}
+	if workflow.IsDone(issue.Status) && issue.ClosedAt == nil {
+		issue.ClosedAt = &issue.UpdatedAt
+	}
+	if !workflow.IsDone(issue.Status) {
+		issue.ClosedAt = nil
+	}
+
+	// Deterministic ID from the source's creation time, as for Beads
+	issue.ID = GenerateIssueID(specContext, issue.Title, issue.CreatedAt)
+	if ext.OriginalID != "" || ext.URL != "" {
+		issue.Migration = &MigrationMetadata{
+			Source:     string(format),
+			OriginalID: ext.OriginalID,
+			URL:        ext.URL,
+			MigratedAt: now,
+		}
+	}
+	return issue
+}
+
+func truncateImportTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "(untitled)"
+	}
+	if runes := []rune(title); len(runes) > 200 {
+		return string(runes[:197]) + "..."
+	}
+	return title
+}
+
+// mapExternalStatus maps status names used by common trackers
+func mapExternalStatus(status string) IssueStatus {
+	switch normalizeName(status) {
+	case "in progress", "inprogress", "in review", "review", "started", "doing", "active", "in development":
+		return StatusInProgress
+	case "closed", "done", "resolved", "completed", "complete", "canceled", "cancelled", "won't do", "wont do", "duplicate", "merged":
+		return StatusClosed
+	default:
+		return StatusOpen
+	}
+}
+
+// mapExternalType maps issue types used by common trackers
+func mapExternalType(issueType string) IssueType {
+	switch normalizeName(issueType) {
+	case "epic":
+		return TypeEpic
+	case "feature", "story", "user story", "enhancement", "improvement", "new feature":
+		return TypeFeature
+	case "bug", "defect", "incident":
+		return TypeBug
+	default:
+		return TypeTask
+	}
+}
+
+var priorityNumberPattern = regexp.MustCompile(`^p?([0-5])$`)
+
+// mapExternalPriority maps numeric (0-5, P0-P5) and named priorities; unknown
+// or empty values get the default priority 2.
+func mapExternalPriority(priority string) int {
+	name := normalizeName(priority)
+	if m := priorityNumberPattern.FindStringSubmatch(name); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	switch name {
+	case "urgent", "highest", "blocker", "critical":
+		return 0
+	case "high", "major":
+		return 1
+	case "low", "minor":
+		return 3
+	case "lowest", "trivial":
+		return 4
+	default:
+		return 2
+	}
+}
+
+// normalizeName lowercases and turns _ and - separators into spaces
+func normalizeName(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer("_", " ", "-", " ").Replace(s)
+}
+
+// externalTimeLayouts are tried in order when parsing dates from other trackers
+var externalTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000-0700", // Jira REST
+	"02/Jan/06 3:04 PM",            // Jira CSV
+	"02/Jan/06 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006",
+}
+
+func parseExternalTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range externalTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func parseExternalTimePtr(value string) *time.Time {
+	t := parseExternalTime(value)
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// splitList splits a comma or semicolon separated list, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,656 @@
+package issues
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvColumns is the generic CSV layout written by export and preferred on import
+var csvColumns = []string{
+	"id", "title", "status", "type", "priority", "labels", "assignee", "parent", "blocked_by",
+	"spec", "description", "notes", "design", "acceptance_criteria", "created_at", "updated_at", "closed_at",
+}
+
+// csvHeaderAliases maps header names used by other trackers (Linear, Asana,
+// spreadsheets) to csvColumns
+var csvHeaderAliases = map[string]string{
+	"issue id":            "id",
+	"key":                 "id",
+	"identifier":          "id",
+	"issue key":           "id",
+	"summary":             "title",
+	"name":                "title",
+	"state":               "status",
+	"issue type":          "type",
+	"kind":                "type",
+	"tags":                "labels",
+	"label":               "labels",
+	"owner":               "assignee",
+	"assigned to":         "assignee",
+	"parent id":           "parent",
+	"parent issue":        "parent",
+	"blocked by":          "blocked_by",
+	"depends on":          "blocked_by",
+	"body":                "description",
+	"acceptance criteria": "acceptance_criteria",
+	"created":             "created_at",
+	"updated":             "updated_at",
+	"closed":              "closed_at",
+	"completed":           "closed_at",
+	"resolved":            "closed_at",
+}
+
+func writeCSV(w io.Writer, issues []Issue) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		parent := ""
+		if issue.ParentID != nil {
+			parent = *issue.ParentID
+		}
+		closed := ""
+		if issue.ClosedAt != nil {
+			closed = issue.ClosedAt.Format(time.RFC3339)
+		}
+		record := []string{
+			issue.ID, issue.Title, string(issue.Status), string(issue.IssueType), strconv.Itoa(issue.Priority),
+			strings.Join(issue.Labels, ","), issue.Assignee, parent, strings.Join(issue.BlockedBy, ","),
+			issue.SpecContext, issue.Description, issue.Notes, issue.Design, issue.AcceptanceCriteria,
+			issue.CreatedAt.Format(time.RFC3339), issue.UpdatedAt.Format(time.RFC3339), closed,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func readCSV(r io.Reader) ([]ExternalIssue, error) {
+	header, rows, err := readCSVRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if alias, ok := csvHeaderAliases[name]; ok {
+			name = alias
+		}
+		if _, seen := columns[name]; !seen {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("missing title column")
+	}
+
+	var result []ExternalIssue
+	for _, row := range rows {
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		result = append(result, ExternalIssue{
+			OriginalID:         get("id"),
+			Title:              get("title"),
+			Description:        get("description"),
+			Notes:              get("notes"),
+			Design:             get("design"),
+			AcceptanceCriteria: get("acceptance_criteria"),
+			Status:             mapExternalStatus(get("status")),
+			SourceStatus:       get("status"),
+			IssueType:          mapExternalType(get("type")),
+			Priority:           mapExternalPriority(get("priority")),
+			Labels:             splitList(get("labels")),
+			Assignee:           get("assignee"),
+			Parent:             get("parent"),
+			BlockedBy:          splitList(get("blocked_by")),
+			CreatedAt:          parseExternalTime(get("created_at")),
+			UpdatedAt:          parseExternalTime(get("updated_at")),
+			ClosedAt:           parseExternalTimePtr(get("closed_at")),
+		})
+	}
+	return result, nil
+}
+
+// readCSVRows reads a header and its non-empty rows, tolerating a UTF-8 BOM
+// and ragged rows as spreadsheet exports often have.
+func readCSVRows(r io.Reader) ([]string, [][]string, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		_, _ = br.Discard(3)
+	}
+	cr := csv.NewReader(br)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil, fmt.Errorf("empty file")
+	}
+
+	var rows [][]string
+	for _, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) != "" {
+			rows = append(rows, record)
+		}
+	}
+	return records[0], rows, nil
+}
+
+// Jira CSV exports repeat columns such as Labels and issue links once per value
+const (
+	jiraInwardBlocks  = "Inward issue link (Blocks)"
+	jiraOutwardBlocks = "Outward issue link (Blocks)"
+	jiraTimeLayout    = "02/Jan/06 3:04 PM"
+)
+
+var (
+	jiraStatuses   = map[IssueStatus]string{StatusOpen: "To Do", StatusInProgress: "In Progress", StatusClosed: "Done"}
+	jiraTypes      = map[IssueType]string{TypeEpic: "Epic", TypeFeature: "Story", TypeTask: "Task", TypeBug: "Bug"}
+	jiraPriorities = []string{"Highest", "High", "Medium", "Low", "Lowest", "Lowest"}
+)
+
+func writeJiraCSV(w io.Writer, issues []Issue) error {
+	maxLabels, maxLinks := 1, 1
+	for _, issue := range issues {
+		maxLabels = max(maxLabels, len(issue.Labels))
+		maxLinks = max(maxLinks, len(issue.BlockedBy))
+	}
+
+	header := []string{"Issue key", "Summary", "Issue Type", "Status", "Priority", "Assignee", "Created", "Updated", "Resolved", "Description", "Parent"}
+	for i := 0; i < maxLabels; i++ {
+		header = append(header, "Labels")
+	}
+	for i := 0; i < maxLinks; i++ {
+		header = append(header, jiraInwardBlocks)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		parent, resolved := "", ""
+		if issue.ParentID != nil {
+			parent = *issue.ParentID
+		}
+		if issue.ClosedAt != nil {
+			resolved = issue.ClosedAt.Local().Format(jiraTimeLayout)
+		}
+		record := []string{
+			issue.ID, issue.Title, jiraTypes[issue.IssueType], jiraStatuses[issue.Status], jiraPriorities[issue.Priority],
+			issue.Assignee, issue.CreatedAt.Local().Format(jiraTimeLayout), issue.UpdatedAt.Local().Format(jiraTimeLayout),
+			resolved, issue.Description, parent,
+		}
+		record = append(record, padded(issue.Labels, maxLabels)...)
+		record = append(record, padded(issue.BlockedBy, maxLinks)...)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func padded(values []string, n int) []string {
+	out := make([]string, n)
+	copy(out, values)
+	return out
+}
+
+func readJiraCSV(r io.Reader) ([]ExternalIssue, error) {
+	header, rows, err := readCSVRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string][]int)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		columns[name] = append(columns[name], i)
+	}
+	if _, ok := columns["Summary"]; !ok {
+		return nil, fmt.Errorf("missing Summary column")
+	}
+
+	var result []ExternalIssue
+	blocks := make(map[string][]string) // key -> keys it blocks
+	idToKey := make(map[string]string)  // numeric Issue id -> key
+	for _, row := range rows {
+		all := func(name string) []string {
+			var values []string
+			for _, i := range columns[name] {
+				if i < len(row) && strings.TrimSpace(row[i]) != "" {
+					values = append(values, strings.TrimSpace(row[i]))
+				}
+			}
+			return values
+		}
+		get := func(name string) string {
+			if values := all(name); len(values) > 0 {
+				return values[0]
+			}
+			return ""
+		}
+
+		key := get("Issue key")
+		if key == "" {
+			key = get("Issue id")
+		}
+		if id := get("Issue id"); id != "" {
+			idToKey[id] = key
+		}
+		parent := get("Parent")
+		if parent == "" {
+			parent = get("Parent id")
+		}
+		blocks[key] = append(blocks[key], all(jiraOutwardBlocks)...)
+
+		var labels []string
+		for _, value := range all("Labels") {
+			labels = append(labels, strings.Fields(value)...)
+		}
+
+		result = append(result, ExternalIssue{
+			OriginalID:   key,
+			Title:        get("Summary"),
+			Description:  get("Description"),
+			Status:       mapExternalStatus(get("Status")),
+			SourceStatus: get("Status"),
+			IssueType:    mapExternalType(get("Issue Type")),
+			Priority:     mapExternalPriority(get("Priority")),
+			Labels:       labels,
+			Assignee:     get("Assignee"),
+			Parent:       parent,
+			BlockedBy:    all(jiraInwardBlocks),
+			CreatedAt:    parseExternalTime(get("Created")),
+			UpdatedAt:    parseExternalTime(get("Updated")),
+			ClosedAt:     parseExternalTimePtr(get("Resolved")),
+		})
+	}
+
+	// Parents may be given by numeric id; outward links are the other side of inward ones
+	for i := range result {
+		if key, ok := idToKey[result[i].Parent]; ok {
+			result[i].Parent = key
+		}
+	}
+	for i := range result {
+		for blocker, blocked := range blocks {
+			if contains(blocked, result[i].OriginalID) && !contains(result[i].BlockedBy, blocker) {
+				result[i].BlockedBy = append(result[i].BlockedBy, blocker)
+			}
+		}
+		sort.Strings(result[i].BlockedBy)
+	}
+	return result, nil
+}
+
+// gitHubIssue covers both `gh issue list --json` (camelCase, uppercase
+// state) and REST API (snake_case) field names
+type gitHubIssue struct {
+	Number       int           `json:"number"`
+	Title        string        `json:"title"`
+	Body         string        `json:"body"`
+	State        string        `json:"state"`
+	URL          string        `json:"url,omitempty"`
+	HTMLURL      string        `json:"html_url,omitempty"`
+	Labels       []gitHubLabel `json:"labels"`
+	Assignees    []gitHubUser  `json:"assignees"`
+	CreatedAt    string        `json:"createdAt,omitempty"`
+	UpdatedAt    string        `json:"updatedAt,omitempty"`
+	ClosedAt     string        `json:"closedAt,omitempty"`
+	CreatedAtAPI string        `json:"created_at,omitempty"`
+	UpdatedAtAPI string        `json:"updated_at,omitempty"`
+	ClosedAtAPI  string        `json:"closed_at,omitempty"`
+}
+
+type gitHubLabel struct {
+	Name string `json:"name"`
+}
+
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+var (
+	gitHubBlockedByPattern = regexp.MustCompile(`(?im)^\s*(?:blocked by|depends on)\s*:?\s*((?:#\d+[\s,]*)+)\s*$`)
+	gitHubParentPattern    = regexp.MustCompile(`(?im)^\s*parent\s*:?\s*#(\d+)\s*$`)
+	gitHubRefPattern       = regexp.MustCompile(`#(\d+)`)
+)
+
+// writeGitHubJSON numbers issues in order and expresses parents and blockers as
+// "Parent: #n" and "Blocked by: #n" lines in the body, which readGitHubJSON
+// understands. Type, priority and in-progress status become labels.
+func writeGitHubJSON(w io.Writer, issues []Issue) error {
+	numbers := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		numbers[issue.ID] = i + 1
+	}
+	ref := func(id string) string {
+		if n, ok := numbers[id]; ok {
+			return "#" + strconv.Itoa(n)
+		}
+		return id
+	}
+
+	out := make([]gitHubIssue, 0, len(issues))
+	for _, issue := range issues {
+		body := issue.Description
+		var trailer []string
+		if issue.ParentID != nil {
+			trailer = append(trailer, "Parent: "+ref(*issue.ParentID))
+		}
+		if len(issue.BlockedBy) > 0 {
+			refs := make([]string, len(issue.BlockedBy))
+			for i, id := range issue.BlockedBy {
+				refs[i] = ref(id)
+			}
+			trailer = append(trailer, "Blocked by: "+strings.Join(refs, ", "))
+		}
+		if len(trailer) > 0 {
+			if body != "" {
+				body += "\n\n"
+			}
+			body += strings.Join(trailer, "\n")
+		}
+
+		labels := []gitHubLabel{{Name: "type:" + string(issue.IssueType)}, {Name: fmt.Sprintf("priority:P%d", issue.Priority)}}
+		if issue.Status == StatusInProgress {
+			labels = append(labels, gitHubLabel{Name: "status:in_progress"})
+		}
+		for _, label := range issue.Labels {
+			labels = append(labels, gitHubLabel{Name: label})
+		}
+		assignees := []gitHubUser{}
+		if issue.Assignee != "" {
+			assignees = append(assignees, gitHubUser{Login: issue.Assignee})
+		}
+
+		state := "OPEN"
+		closedAt := ""
+		if issue.Status == StatusClosed {
+			state = "CLOSED"
+			if issue.ClosedAt != nil {
+				closedAt = issue.ClosedAt.Format(time.RFC3339)
+			}
+		}
+
+		out = append(out, gitHubIssue{
+			Number:    numbers[issue.ID],
+			Title:     issue.Title,
+			Body:      body,
+			State:     state,
+			Labels:    labels,
+			Assignees: assignees,
+			CreatedAt: issue.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: issue.UpdatedAt.Format(time.RFC3339),
+			ClosedAt:  closedAt,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func readGitHubJSON(r io.Reader) ([]ExternalIssue, error) {
+	var raw []gitHubIssue
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	result := make([]ExternalIssue, 0, len(raw))
+	for _, gh := range raw {
+		ext := ExternalIssue{
+			OriginalID: "#" + strconv.Itoa(gh.Number),
+			URL:        firstNonEmpty(gh.HTMLURL, gh.URL),
+			Title:      gh.Title,
+			Status:     mapExternalStatus(gh.State),
+			IssueType:  TypeTask,
+			Priority:   2,
+			CreatedAt:  parseExternalTime(firstNonEmpty(gh.CreatedAt, gh.CreatedAtAPI)),
+			UpdatedAt:  parseExternalTime(firstNonEmpty(gh.UpdatedAt, gh.UpdatedAtAPI)),
+			ClosedAt:   parseExternalTimePtr(firstNonEmpty(gh.ClosedAt, gh.ClosedAtAPI)),
+		}
+		if len(gh.Assignees) > 0 {
+			ext.Assignee = gh.Assignees[0].Login
+		}
+
+		// Labels carry type, priority and progress; the rest are kept as labels
+		for _, label := range gh.Labels {
+			name := label.Name
+			lower := normalizeName(name)
+			switch {
+			case strings.HasPrefix(lower, "type:"):
+				ext.IssueType = mapExternalType(strings.TrimPrefix(lower, "type:"))
+			case lower == "bug" || lower == "enhancement" || lower == "epic" || lower == "feature":
+				ext.IssueType = mapExternalType(lower)
+			case strings.HasPrefix(lower, "priority:"):
+				ext.Priority = mapExternalPriority(strings.TrimSpace(strings.TrimPrefix(lower, "priority:")))
+			case priorityNumberPattern.MatchString(lower) && strings.HasPrefix(lower, "p"):
+				ext.Priority = mapExternalPriority(lower)
+			case strings.HasPrefix(lower, "status:"):
+				if ext.Status != StatusClosed {
+					ext.Status = mapExternalStatus(strings.TrimPrefix(lower, "status:"))
+					ext.SourceStatus = strings.ReplaceAll(strings.TrimSpace(strings.TrimPrefix(lower, "status:")), " ", "_")
+				}
+			case lower == "in progress":
+				if ext.Status != StatusClosed {
+					ext.Status = StatusInProgress
+				}
+			default:
+				ext.Labels = append(ext.Labels, name)
+			}
+		}
+
+		body := gh.Body
+		if m := gitHubParentPattern.FindStringSubmatch(body); m != nil {
+			ext.Parent = "#" + m[1]
+		}
+		for _, m := range gitHubBlockedByPattern.FindAllStringSubmatch(body, -1) {
+			for _, ref := range gitHubRefPattern.FindAllStringSubmatch(m[1], -1) {
+				ext.BlockedBy = append(ext.BlockedBy, "#"+ref[1])
+			}
+		}
+		body = gitHubParentPattern.ReplaceAllString(body, "")
+		body = gitHubBlockedByPattern.ReplaceAllString(body, "")
+		ext.Description = strings.TrimSpace(body)
+
+		result = append(result, ext)
+	}
+	return result, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+var (
+	markdownItemPattern    = regexp.MustCompile(`^(\s*)[-*] \[([ xX])\] (.*)$`)
+	markdownIDPattern      = regexp.MustCompile(`^\*\*([^*]+)\*\*\s*`)
+	markdownBlockedPattern = regexp.MustCompile(`\s*\(blocked by ([^)]*)\)`)
+	markdownTagPattern     = regexp.MustCompile("\\s+(`[^`]+`|#[^\\s#]+|@[^\\s@]+)")
+)
+
+// writeMarkdown writes a task list per spec, nesting children under parents:
+//
+//   - [ ] **SL-a3f5d8** Title `task` `P1` `in_progress` #label @alice (blocked by SL-b4e6f9)
+//     > Description
+func writeMarkdown(w io.Writer, issues []Issue) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "# Issues")
+
+	var specs []string
+	bySpec := make(map[string][]Issue)
+	for _, issue := range issues {
+		if _, ok := bySpec[issue.SpecContext]; !ok {
+			specs = append(specs, issue.SpecContext)
+		}
+		bySpec[issue.SpecContext] = append(bySpec[issue.SpecContext], issue)
+	}
+
+	for _, spec := range specs {
+		list := bySpec[spec]
+		present := make(map[string]bool, len(list))
+		children := make(map[string][]Issue)
+		for _, issue := range list {
+			present[issue.ID] = true
+		}
+		var roots []Issue
+		for _, issue := range list {
+			if issue.ParentID != nil && present[*issue.ParentID] && *issue.ParentID != issue.ID {
+				children[*issue.ParentID] = append(children[*issue.ParentID], issue)
+			} else {
+				roots = append(roots, issue)
+			}
+		}
+
+		fmt.Fprintf(bw, "\n## %s\n\n", spec)
+		var write func(issue Issue, depth int)
+		write = func(issue Issue, depth int) {
+			indent := strings.Repeat("  ", depth)
+			check := " "
+			if issue.Status == StatusClosed {
+				check = "x"
+			}
+			line := fmt.Sprintf("%s- [%s] **%s** %s `%s` `P%d`", indent, check, issue.ID, issue.Title, issue.IssueType, issue.Priority)
+			if issue.Status == StatusInProgress {
+				line += " `in_progress`"
+			}
+			for _, label := range issue.Labels {
+				line += " #" + label
+			}
+			if issue.Assignee != "" {
+				line += " @" + issue.Assignee
+			}
+			if len(issue.BlockedBy) > 0 {
+				line += " (blocked by " + strings.Join(issue.BlockedBy, ", ") + ")"
+			}
+			fmt.Fprintln(bw, line)
+			for _, descLine := range strings.Split(strings.TrimSpace(issue.Description), "\n") {
+				if issue.Description != "" {
+					fmt.Fprintf(bw, "%s  > %s\n", indent, descLine)
+				}
+			}
+			for _, child := range children[issue.ID] {
+				write(child, depth+1)
+			}
+		}
+		for _, issue := range roots {
+			write(issue, 0)
+		}
+	}
+	return bw.Flush()
+}
+
+func readMarkdown(r io.Reader) ([]ExternalIssue, error) {
+	type open struct {
+		indent int
+		index  int
+	}
+	var result []ExternalIssue
+	var stack []open
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		m := markdownItemPattern.FindStringSubmatch(line)
+		if m == nil {
+			// Blockquotes under an item are its description
+			trimmed := strings.TrimSpace(line)
+			if quote, ok := strings.CutPrefix(trimmed, ">"); ok && len(result) > 0 {
+				last := &result[len(result)-1]
+				if last.Description != "" {
+					last.Description += "\n"
+				}
+				last.Description += strings.TrimPrefix(quote, " ")
+			}
+			continue
+		}
+
+		indent := len(strings.ReplaceAll(m[1], "\t", "  "))
+		ext, inProgress := parseMarkdownItem(m[3])
+		switch {
+		case m[2] != " ":
+			ext.Status = StatusClosed
+		case inProgress:
+			ext.Status = StatusInProgress
+		default:
+			ext.Status = StatusOpen
+		}
+		if ext.OriginalID == "" {
+			ext.OriginalID = fmt.Sprintf("line-%d", len(result)+1)
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) > 0 {
+			ext.Parent = result[stack[len(stack)-1].index].OriginalID
+		}
+		stack = append(stack, open{indent: indent, index: len(result)})
+		result = append(result, ext)
+	}
+	return result, scanner.Err()
+}
+
+// parseMarkdownItem reads the text after a task list checkbox and reports
+// whether it carries the `in_progress` marker.
+func parseMarkdownItem(text string) (ExternalIssue, bool) {
+	ext := ExternalIssue{IssueType: TypeTask, Priority: 2}
+	inProgress := false
+
+	if m := markdownIDPattern.FindStringSubmatch(text); m != nil {
+		ext.OriginalID = m[1]
+		text = text[len(m[0]):]
+	}
+	if m := markdownBlockedPattern.FindStringSubmatch(text); m != nil {
+		ext.BlockedBy = splitList(m[1])
+		text = markdownBlockedPattern.ReplaceAllString(text, "")
+	}
+
+	for _, m := range markdownTagPattern.FindAllStringSubmatch(text, -1) {
+		tag := m[1]
+		switch {
+		case strings.HasPrefix(tag, "#"):
+			ext.Labels = append(ext.Labels, tag[1:])
+		case strings.HasPrefix(tag, "@"):
+			ext.Assignee = tag[1:]
+		default:
+			value := strings.Trim(tag, "`")
+			switch {
+			case IsValidIssueType(IssueType(value)):
+				ext.IssueType = IssueType(value)
+			case priorityNumberPattern.MatchString(strings.ToLower(value)):
+				ext.Priority = mapExternalPriority(value)
+			case value == string(StatusInProgress):
+				inProgress = true
+			}
+		}
+	}
+	ext.Title = strings.TrimSpace(markdownTagPattern.ReplaceAllString(text, ""))
+	return ext, inProgress
+}
@@ -0,0 +1,172 @@
+package issues
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newExchangeStore creates an empty spec store under a fresh base path.
+func newExchangeStore(t *testing.T, spec string) (*Store, string) {
+	t.Helper()
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	if err := os.MkdirAll(filepath.Join(basePath, spec), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return newTestStore(t, StorageJSONL, basePath, spec), basePath
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source, _ := newExchangeStore(t, "010-source")
+
+	epic := NewIssue("Payments epic", "All payment work", "010-source", TypeEpic, 1)
+	schema := NewIssue("Design ledger schema", "", "010-source", TypeTask, 0)
+	api := NewIssue("Expose refund endpoint", "Line one\nLine two", "010-source", TypeFeature, 2)
+	bug := NewIssue("Rounding error in totals", "", "010-source", TypeBug, 3)
+	schema.ParentID, api.ParentID = &epic.ID, &epic.ID
+	schema.Labels = []string{"component:db"}
+	api.Assignee = "alice"
+	for _, issue := range []*Issue{epic, schema, api, bug} {
+		if err := source.Create(issue); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := source.AddDependency(schema.ID, api.ID, LinkBlocks); err != nil {
+		t.Fatal(err)
+	}
+	inProgress, closed := StatusInProgress, StatusClosed
+	if _, err := source.Update(api.ID, IssueUpdate{Status: &inProgress}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := source.Update(bug.ID, IssueUpdate{Status: &closed}); err != nil {
+		t.Fatal(err)
+	}
+	exported, _ := source.List(ListFilter{})
+
+	for _, format := range ExchangeFormats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := ExportIssues(&buf, format, exported); err != nil {
+				t.Fatalf("ExportIssues() error: %v", err)
+			}
+			data := buf.String()
+
+			target, basePath := newExchangeStore(t, "020-target")
+			opts := ImportOptions{BasePath: basePath, SpecContext: "020-target", Format: format}
+			result, err := ImportIssues(strings.NewReader(data), opts)
+			if err != nil {
+				t.Fatalf("ImportIssues() error: %v", err)
+			}
+			if result.ImportedIssues != 4 || result.Links != 3 || len(result.Warnings) != 0 {
+				t.Fatalf("unexpected result: %+v\n%s", result, data)
+			}
+
+			byTitle := make(map[string]Issue)
+			imported, _ := target.List(ListFilter{})
+			for _, issue := range imported {
+				byTitle[issue.Title] = issue
+				if issue.Migration == nil || issue.Migration.Source != string(format) || issue.Migration.OriginalID == "" {
+					t.Errorf("%s: missing migration metadata: %+v", issue.Title, issue.Migration)
+				}
+			}
+			gotEpic, gotSchema, gotAPI, gotBug := byTitle[epic.Title], byTitle[schema.Title], byTitle[api.Title], byTitle[bug.Title]
+			if gotSchema.ParentID == nil || *gotSchema.ParentID != gotEpic.ID || gotAPI.ParentID == nil || *gotAPI.ParentID != gotEpic.ID {
+				t.Errorf("parents not preserved: %v, %v", gotSchema.ParentID, gotAPI.ParentID)
+			}
+			if len(gotAPI.BlockedBy) != 1 || gotAPI.BlockedBy[0] != gotSchema.ID {
+				t.Errorf("blocking link not preserved: %v", gotAPI.BlockedBy)
+			}
+			if gotEpic.IssueType != TypeEpic || gotSchema.Priority != 0 || gotAPI.Status != StatusInProgress || gotBug.Status != StatusClosed || gotBug.IssueType != TypeBug {
+				t.Errorf("fields not mapped: %+v", byTitle)
+			}
+			if gotAPI.Assignee != "alice" || len(gotSchema.Labels) != 1 || gotSchema.Labels[0] != "component:db" {
+				t.Errorf("assignee/labels not preserved: %q %v", gotAPI.Assignee, gotSchema.Labels)
+			}
+
+			// Importing the same file again is a no-op
+			again, err := ImportIssues(strings.NewReader(data), opts)
+			if err != nil || again.ImportedIssues != 0 || len(again.Skipped) != 4 {
+				t.Errorf("re-import: %+v, %v", again, err)
+			}
+		})
+	}
+}
+
+func TestImportSkipsDuplicates(t *testing.T) {
+	store, basePath := newExchangeStore(t, "010-test")
+	if err := store.Create(NewIssue("Add rate limiting to the API", "", "010-test", TypeTask, 2)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Linear-style headers
+	csv := "ID,Title,Status,Labels\nLIN-1,Add rate limiting to the API!,Todo,api\nLIN-2,Write runbook,Done,\"ops, docs\"\n"
+	opts := ImportOptions{BasePath: basePath, SpecContext: "010-test", Format: FormatCSV}
+	result, err := ImportIssues(strings.NewReader(csv), opts)
+	if err != nil {
+		t.Fatalf("ImportIssues() error: %v", err)
+	}
+	if result.ImportedIssues != 1 || len(result.Skipped) != 1 || !strings.Contains(result.Skipped[0].Reason, "similar") {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	opts.Force = true
+	result, err = ImportIssues(strings.NewReader(csv), opts)
+	if err != nil || result.ImportedIssues != 1 {
+		t.Errorf("expected forced import of the similar issue, got %+v, %v", result, err)
+	}
+}
+
+func TestReadJiraCSV(t *testing.T) {
+	data := "Summary,Issue key,Issue id,Issue Type,Status,Priority,Parent id,Labels,Labels,Outward issue link (Blocks),Created\n" +
+		"Checkout epic,PAY-1,10001,Epic,In Progress,High,,payments,,,12/Mar/26 9:30 AM\n" +
+		"Card form,PAY-2,10002,Story,To Do,Highest,10001,frontend,ux,PAY-3,12/Mar/26 10:00 AM\n" +
+		"Receipt email,PAY-3,10003,Sub-task,Done,Low,10001,,,,12/Mar/26 11:15 AM\n"
+
+	external, err := ParseExternalIssues(strings.NewReader(data), FormatJiraCSV)
+	if err != nil {
+		t.Fatalf("ParseExternalIssues() error: %v", err)
+	}
+	if len(external) != 3 {
+		t.Fatalf("expected 3 issues, got %d", len(external))
+	}
+	epic, form, receipt := external[0], external[1], external[2]
+	if epic.IssueType != TypeEpic || epic.Status != StatusInProgress || epic.Priority != 1 {
+		t.Errorf("unexpected epic: %+v", epic)
+	}
+	if form.Parent != "PAY-1" || form.Priority != 0 || strings.Join(form.Labels, ",") != "frontend,ux" {
+		t.Errorf("unexpected story: %+v", form)
+	}
+	if receipt.Status != StatusClosed || len(receipt.BlockedBy) != 1 || receipt.BlockedBy[0] != "PAY-2" || receipt.CreatedAt.Hour() != 11 {
+		t.Errorf("unexpected sub-task: %+v", receipt)
+	}
+}
+
+func TestImportKeepsWorkflowStatuses(t *testing.T) {
+	defer func(orig func(string) *Workflow) { WorkflowFunc = orig }(WorkflowFunc)
+	WorkflowFunc = func(string) *Workflow {
+		return &Workflow{Statuses: []WorkflowStatus{
+			{Name: StatusOpen, Category: CategoryTodo},
+			{Name: "blocked", Category: CategoryTodo, Waiting: true},
+			{Name: StatusInProgress, Category: CategoryDoing},
+			{Name: "review", Category: CategoryDoing, Waiting: true},
+			{Name: StatusClosed, Category: CategoryDone},
+		}}
+	}
+	store, basePath := newExchangeStore(t, "010-test")
+
+	csv := "id,title,status\nA-1,Waiting on vendor,blocked\nA-2,Refund endpoint,review\nA-3,Runbook,In Review\n"
+	opts := ImportOptions{BasePath: basePath, SpecContext: "010-test", Format: FormatCSV}
+	if _, err := ImportIssues(strings.NewReader(csv), opts); err != nil {
+		t.Fatalf("ImportIssues() error: %v", err)
+	}
+
+	want := map[string]IssueStatus{"Waiting on vendor": "blocked", "Refund endpoint": "review", "Runbook": StatusInProgress}
+	imported, _ := store.List(ListFilter{})
+	for _, issue := range imported {
+		if issue.Status != want[issue.Title] {
+			t.Errorf("%s: status = %s, want %s", issue.Title, issue.Status, want[issue.Title])
+		}
+	}
+}
@@ -2,6 +2,7 @@ package issues
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"time"
 )
@@ -45,13 +46,21 @@ type Issue struct {
 	Blocks             []string          `json:"blocks,omitempty"`     // Issue IDs
 	Labels             []string          `json:"labels,omitempty"`
 	Assignee           string            `json:"assignee,omitempty"`
+	Estimate           float64           `json:"estimate,omitempty"` // Estimated effort in working days; 0 = not estimated
 	Notes              string            `json:"notes,omitempty"`
 	Design             string            `json:"design,omitempty"`
 	AcceptanceCriteria string            `json:"acceptance_criteria,omitempty"`
 	ParentID           *string           `json:"parentId,omitempty"` // Parent issue ID
+	Lease              *Lease            `json:"lease,omitempty"`    // Set while an agent holds a claim
+	Commits            []string          `json:"commits,omitempty"`  // Hashes of commits that reference the issue
+	Template           string            `json:"template,omitempty"` // Name of the template the issue was created from
+	Fields             map[string]string `json:"fields,omitempty"`   // Custom field values declared by the template
 
 	// Migration metadata (optional, for Beads migration)
 	BeadsMigration *BeadsMigration `json:"beads_migration,omitempty"`
+
+	// Migration metadata (optional, for issues imported from other trackers)
+	Migration *MigrationMetadata `json:"migration,omitempty"`
 }
 
 // BeadsMigration contains metadata for issues migrated from Beads
@@ -60,6 +69,14 @@ type BeadsMigration struct {
 	MigratedAt time.Time `json:"migrated_at"`
 }
 
+// MigrationMetadata records where an imported issue came from
+type MigrationMetadata struct {
+	Source     string    `json:"source"`        // Import format, e.g. "github-json" or "jira-csv"
+	OriginalID string    `json:"original_id"`   // ID or key in the source tracker
+	URL        string    `json:"url,omitempty"` // Link to the original, when known
+	MigratedAt time.Time `json:"migrated_at"`
+}
+
 // DefinitionOfDone represents a checklist that must be completed before closing
 type DefinitionOfDone struct {
 	Items []ChecklistItem `json:"items"`
@@ -80,30 +97,36 @@ type IssueUpdate struct {
 	Priority           *int
 	IssueType          *IssueType
 	Assignee           *string
+	Estimate           *float64 // Working days; 0 clears the estimate
 	Notes              *string
 	Design             *string
 	AcceptanceCriteria *string
 	Labels             *[]string
 	AddLabels          []string
 	RemoveLabels       []string
+	AddCommits         []string          // Commit hashes to link
+	Fields             map[string]string // Custom field values to set; an empty value removes the field
 	BlockedBy          *[]string
 	Blocks             *[]string
 	DefinitionOfDone   *DefinitionOfDone
 	CheckDoDItem       string  // Item to mark as checked
 	UncheckDoDItem     string  // Item to mark as unchecked
 	ParentID           *string // Set or clear parent
+	Reason             string  // Recorded in the event log only (e.g. close reason)
 }
 
 // ListFilter represents filtering options for listing issues
 type ListFilter struct {
-	Status      *IssueStatus
-	IssueType   *IssueType
-	Priority    *int
-	Labels      []string
-	SpecContext string // Empty = all specs
-	All         bool   // Search across all specs
-	Blocked     bool   // Only show blocked issues
-	Orphaned    bool   // Only show non-epic issues without a parent
+	Status          *IssueStatus
+	IssueType       *IssueType
+	Priority        *int
+	Labels          []string
+	SpecContext     string            // Empty = all specs
+	All             bool              // Search across all specs
+	Blocked         bool              // Only show blocked issues
+	Orphaned        bool              // Only show non-epic issues without a parent
+	Fields          map[string]string // Custom field values that must match exactly
+	IncludeArchived bool              // Also list issues moved to the archive
 }
 
 // Validation errors
@@ -114,6 +137,7 @@ var (
 	ErrInvalidPriority    = errors.New("priority must be between 0 and 5")
 	ErrInvalidIssueType   = errors.New("issue type must be one of: epic, feature, task, bug")
 	ErrInvalidSpecContext = errors.New("spec context must match pattern ###-name")
+	ErrInvalidEstimate    = errors.New("estimate must not be negative")
 )
 
 var (
@@ -121,16 +145,37 @@ var (
 	specContextPattern = regexp.MustCompile(`^\d{3,}-[a-z0-9-]+$`)
 )
 
-// Validate validates all fields of an issue
+// Validate validates all fields of an issue against the built-in statuses.
+// Custom fields must have valid names and non-empty values.
 func (i *Issue) Validate() error {
+	return i.validate(nil, nil)
+}
+
+// ValidateWorkflow validates all fields of an issue, accepting the statuses
+// declared by the workflow
+func (i *Issue) ValidateWorkflow(w *Workflow) error {
+	return i.validate(w, nil)
+}
+
+// ValidateTemplate validates all fields of an issue like ValidateWorkflow and
+// its custom fields against the declarations of template t
+func (i *Issue) ValidateTemplate(w *Workflow, t *Template) error {
+	return i.validate(w, t)
+}
+
+func (i *Issue) validate(w *Workflow, t *Template) error {
 	if !idPattern.MatchString(i.ID) {
 		return ErrInvalidID
 	}
 	if len(i.Title) == 0 || len(i.Title) > 200 {
 		return ErrInvalidTitle
 	}
-	if !IsValidStatus(i.Status) {
-		return ErrInvalidStatus
+	if w == nil {
+		if !IsValidStatus(i.Status) {
+			return ErrInvalidStatus
+		}
+	} else if !w.IsValidStatus(i.Status) {
+		return w.unknownStatus(i.Status)
 	}
 	if i.Priority < 0 || i.Priority > 5 {
 		return ErrInvalidPriority
@@ -141,6 +186,20 @@ func (i *Issue) Validate() error {
 	if i.SpecContext != "" && !isValidSpecContext(i.SpecContext) {
 		return ErrInvalidSpecContext
 	}
+	if i.Estimate < 0 {
+		return ErrInvalidEstimate
+	}
+	for _, name := range sortedFieldNames(i.Fields) {
+		if !fieldNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: name %q must be lowercase letters, digits, - or _", ErrInvalidField, name)
+		}
+		if i.Fields[name] == "" {
+			return fmt.Errorf("%w: %s has no value", ErrInvalidField, name)
+		}
+	}
+	if t != nil {
+		return t.checkFields(i.Fields)
+	}
 	return nil
 }
 
@@ -157,7 +216,7 @@ func isValidSpecContext(specContext string) bool {
 	return false
 }
 
-// IsValidStatus checks if a status is valid
+// IsValidStatus checks if a status is one of the built-in statuses
 func IsValidStatus(s IssueStatus) bool {
 	switch s {
 	case StatusOpen, StatusInProgress, StatusClosed:
@@ -253,29 +312,10 @@ type Blocker struct {
 // An issue is ready when:
 // - Status is open or in_progress (not closed)
 // - AND BlockedBy array is empty OR ALL issues in BlockedBy have status closed
+//
+// Stores apply their project's workflow instead; see Workflow.IsReady.
 func (i *Issue) IsReady(allIssues map[string]*Issue) bool {
-	// Not ready if closed
-	if i.Status == StatusClosed {
-		return false
-	}
-
-	// Ready if no blockers
-	if len(i.BlockedBy) == 0 {
-		return true
-	}
-
-	// Check if all blockers are closed
-	for _, blockerID := range i.BlockedBy {
-		blocker, exists := allIssues[blockerID]
-		if !exists {
-			// Blocker doesn't exist - treat as closed (can't block if not found)
-			continue
-		}
-		if blocker.Status != StatusClosed {
-			return false
-		}
-	}
-	return true
+	return DefaultWorkflow().IsReady(i, allIssues)
 }
 
 // GetBlockers returns details about blocking issues for display purposes.
@@ -415,3 +415,23 @@ func TestNewIssue(t *testing.T) {
 		t.Errorf("expected spec context '010-test', got %q", issue.SpecContext)
 	}
 }
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"short", 10, "short"},
+		{"exactly ten", 11, "exactly ten"},
+		{"a longer title", 10, "a longe..."},
+		{"Überprüfung der Größe", 10, "Überprü..."},
+		{"日本語のタイトル", 5, "日本..."},
+		{"abcdef", 2, "ab"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.maxLen); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package issues
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofrs/flock"
+)
+
+// jsonlBackend stores a spec's issues as one JSON object per line in
+// issues.jsonl, guarded by a flock on issues.jsonl.lock.
+type jsonlBackend struct {
+	path string
+	lock *flock.Flock
+}
+
+func newJSONLBackend(path, lockPath string) *jsonlBackend {
+	return &jsonlBackend{path: path, lock: flock.New(lockPath)}
+}
+
+// Create appends the issue to the file.
+func (b *jsonlBackend) Create(issue *Issue) error {
+	// Ensure directory exists
+	dir := filepath.Dir(b.path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Append to file
+	f, err := os.OpenFile(b.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue: %w", err)
+	}
+
+	_, err = fmt.Fprintf(f, "%s\n", data)
+	return err
+}
+
+// Get scans the file for the issue.
+func (b *jsonlBackend) Get(id string) (*Issue, error) {
+	issues, err := readJSONLFile(b.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range issues {
+		if issue.ID == id {
+			return issue, nil
+		}
+	}
+	return nil, ErrIssueNotFound
+}
+
+// List reads the whole file.
+func (b *jsonlBackend) List() ([]*Issue, error) {
+	return readJSONLFile(b.path)
+}
+
+// Update rewrites the file with the given issues replaced.
+func (b *jsonlBackend) Update(updated ...*Issue) error {
+	issues, err := readJSONLFile(b.path)
+	if err != nil {
+		return err
+	}
+
+	index := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		index[issue.ID] = i
+	}
+	for _, issue := range updated {
+		i, ok := index[issue.ID]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrIssueNotFound, issue.ID)
+		}
+		issues[i] = issue
+	}
+
+	return writeJSONLFile(b.path, issues)
+}
+
+// Delete rewrites the file once without the issues.
+func (b *jsonlBackend) Delete(ids ...string) error {
+	issues, err := readJSONLFile(b.path)
+	if err != nil {
+		return err
+	}
+
+	missing := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		missing[id] = true
+	}
+	var remaining []*Issue
+	for _, issue := range issues {
+		if _, drop := missing[issue.ID]; drop {
+			missing[issue.ID] = false
+			continue
+		}
+		remaining = append(remaining, issue)
+	}
+	for _, id := range ids {
+		if missing[id] {
+			return fmt.Errorf("%w: %s", ErrIssueNotFound, id)
+		}
+	}
+
+	return writeJSONLFile(b.path, remaining)
+}
+
+// Lock takes the flock without waiting.
+func (b *jsonlBackend) Lock() (func(), error) {
+	locked, err := b.lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return nil, ErrStoreLocked
+	}
+	return func() { _ = b.lock.Unlock() }, nil
+}
+
+// readJSONLFile reads issues from a JSONL file. A missing file holds no
+// issues; lines that fail to parse are skipped (see RepairIssuesFile).
+func readJSONLFile(path string) ([]*Issue, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Issue{}, nil
+		}
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	var issues []*Issue
+	scanner := bufio.NewScanner(f)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var issue Issue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			// Log warning but continue - skip invalid lines
+			// In production, we might want to log this
+			continue
+		}
+
+		issues = append(issues, &issue)
+	}
+
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+
+	return issues, nil
+}
+
+// writeJSONLFile atomically replaces a JSONL file with issues.
+func writeJSONLFile(path string, issues []*Issue) error {
+	// Write to temp file first
+	tmpPath := path + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	writer := bufio.NewWriter(f)
+	for _, issue := range issues {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to marshal issue: %w", err)
+		}
+		if _, err := fmt.Fprintf(writer, "%s\n", data); err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+			return fmt.Errorf("failed to write issue: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to flush writer: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package issues
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeConflict describes a field changed differently on both sides of a merge.
+type MergeConflict struct {
+	IssueID string `json:"issue_id"`
+	Field   string `json:"field"`
+	// Resolution is "ours" or "theirs" when the newer UpdatedAt decided the
+	// winner, or empty when both sides have the same UpdatedAt and the
+	// conflict could not be resolved automatically (ours is kept).
+	Resolution string `json:"resolution,omitempty"`
+}
+
+// Resolved reports whether the conflict was settled by UpdatedAt.
+func (c MergeConflict) Resolved() bool {
+	return c.Resolution != ""
+}
+
+func (c MergeConflict) String() string {
+	if c.Resolved() {
+		return fmt.Sprintf("%s: %s changed on both sides, kept %s (newer)", c.IssueID, c.Field, c.Resolution)
+	}
+	return fmt.Sprintf("%s: %s changed on both sides with identical updated_at, kept ours", c.IssueID, c.Field)
+}
+
+// MergeResult is the outcome of a three-way issue merge.
+type MergeResult struct {
+	Issues    []*Issue
+	Conflicts []MergeConflict
+}
+
+// HasUnresolved reports whether any conflict could not be decided by UpdatedAt.
+func (r *MergeResult) HasUnresolved() bool {
+	for _, c := range r.Conflicts {
+		if !c.Resolved() {
+			return true
+		}
+	}
+	return false
+}
+
+// setFields are merged as sets rather than by last-writer-wins.
+var setFields = map[string]bool{
+	"BlockedBy": true,
+	"Blocks":    true,
+	"Labels":    true,
+	"Commits":   true,
+}
+
+// mergeSkipFields are handled explicitly and never merged field-by-field.
+var mergeSkipFields = map[string]bool{
+	"ID":        true,
+	"CreatedAt": true,
+	"UpdatedAt": true,
+}
+
+// MergeIssues performs a three-way, record-level merge of issue lists keyed by ID.
+//
+// For issues present on both sides, each field is merged independently: a field
+// changed on only one side takes that side's value; a field changed on both sides
+// to different values takes the value from the side with the newer UpdatedAt and
+// is reported as a conflict. BlockedBy, Blocks, Labels and Commits are merged as sets:
+// additions from either side are kept and removals from either side are honored.
+//
+// An issue deleted on one side and modified on the other is kept (modified) and
+// reported as a conflict on the "deleted" field.
+func MergeIssues(base, ours, theirs []*Issue) *MergeResult {
+	baseByID := indexIssues(base)
+	oursByID := indexIssues(ours)
+	theirsByID := indexIssues(theirs)
+
+	result := &MergeResult{}
+
+	emit := func(id string) {
+		b, o, t := baseByID[id], oursByID[id], theirsByID[id]
+		switch {
+		case o != nil && t != nil:
+			merged, conflicts := mergeIssue(b, o, t)
+			result.Issues = append(result.Issues, merged)
+			result.Conflicts = append(result.Conflicts, conflicts...)
+		case o != nil && b == nil:
+			// Added on our side only
+			result.Issues = append(result.Issues, o)
+		case t != nil && b == nil:
+			// Added on their side only
+			result.Issues = append(result.Issues, t)
+		case o != nil:
+			// Deleted on their side
+			if !issuesEqual(b, o) {
+				result.Issues = append(result.Issues, o)
+				result.Conflicts = append(result.Conflicts, MergeConflict{IssueID: id, Field: "deleted", Resolution: "ours"})
+			}
+		case t != nil:
+			// Deleted on our side
+			if !issuesEqual(b, t) {
+				result.Issues = append(result.Issues, t)
+				result.Conflicts = append(result.Conflicts, MergeConflict{IssueID: id, Field: "deleted", Resolution: "theirs"})
+			}
+		}
+	}
+
+	// Keep our file order, then append issues only their side knows about.
+	seen := make(map[string]bool)
+	for _, list := range [][]*Issue{ours, theirs, base} {
+		for _, issue := range list {
+			if seen[issue.ID] {
+				continue
+			}
+			seen[issue.ID] = true
+			emit(issue.ID)
+		}
+	}
+
+	return result
+}
+
+// mergeIssue merges one issue present on both sides. base may be nil when both
+// sides added the same ID independently.
+func mergeIssue(base, ours, theirs *Issue) (*Issue, []MergeConflict) {
+	merged := *ours
+	var conflicts []MergeConflict
+
+	// Without a common ancestor every differing field is a two-sided change.
+	var baseVal reflect.Value
+	if base != nil {
+		baseVal = reflect.ValueOf(base).Elem()
+	}
+	oursVal := reflect.ValueOf(ours).Elem()
+	theirsVal := reflect.ValueOf(theirs).Elem()
+	mergedVal := reflect.ValueOf(&merged).Elem()
+	issueType := oursVal.Type()
+
+	theirsNewer := theirs.UpdatedAt.After(ours.UpdatedAt)
+	oursNewer := ours.UpdatedAt.After(theirs.UpdatedAt)
+
+	for i := 0; i < issueType.NumField(); i++ {
+		name := issueType.Field(i).Name
+		if mergeSkipFields[name] || !issueType.Field(i).IsExported() {
+			continue
+		}
+
+		o := oursVal.Field(i)
+		t := theirsVal.Field(i)
+
+		if setFields[name] {
+			var b []string
+			if base != nil {
+				b = baseVal.Field(i).Interface().([]string)
+			}
+			mergedVal.Field(i).Set(reflect.ValueOf(mergeStringSets(b, o.Interface().([]string), t.Interface().([]string))))
+			continue
+		}
+
+		if valuesEqual(o.Interface(), t.Interface()) {
+			continue
+		}
+
+		if base != nil {
+			b := baseVal.Field(i)
+			if valuesEqual(b.Interface(), o.Interface()) {
+				// Only theirs changed
+				mergedVal.Field(i).Set(t)
+				continue
+			}
+			if valuesEqual(b.Interface(), t.Interface()) {
+				// Only ours changed
+				continue
+			}
+		}
+
+		// Changed on both sides: newer UpdatedAt wins
+		conflict := MergeConflict{IssueID: ours.ID, Field: jsonFieldName(issueType.Field(i))}
+		switch {
+		case theirsNewer:
+			mergedVal.Field(i).Set(t)
+			conflict.Resolution = "theirs"
+		case oursNewer:
+			conflict.Resolution = "ours"
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	if theirsNewer {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+	if theirs.CreatedAt.Before(merged.CreatedAt) && !theirs.CreatedAt.IsZero() {
+		merged.CreatedAt = theirs.CreatedAt
+	}
+
+	return &merged, conflicts
+}
+
+// mergeStringSets performs a three-way set merge preserving first-seen order.
+// An element is kept if it is on both sides, or was added by either side.
+func mergeStringSets(base, ours, theirs []string) []string {
+	inBase := toSet(base)
+	inOurs := toSet(ours)
+	inTheirs := toSet(theirs)
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{ours, theirs} {
+		for _, v := range list {
+			if seen[v] {
+				continue
+			}
+			seen[v] = true
+			removed := inBase[v] && (!inOurs[v] || !inTheirs[v])
+			if !removed {
+				result = append(result, v)
+			}
+		}
+	}
+	return result
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
+
+func indexIssues(list []*Issue) map[string]*Issue {
+	index := make(map[string]*Issue, len(list))
+	for _, issue := range list {
+		index[issue.ID] = issue
+	}
+	return index
+}
+
+func issuesEqual(a, b *Issue) bool {
+	return valuesEqual(a, b)
+}
+
+// valuesEqual compares values by their JSON encoding, which is what is stored
+// on disk. reflect.DeepEqual would treat equal timestamps parsed into distinct
+// *time.Location values as different.
+func valuesEqual(a, b interface{}) bool {
+	aj, errA := json.Marshal(a)
+	bj, errB := json.Marshal(b)
+	if errA != nil || errB != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return string(aj) == string(bj)
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if tag == "" || tag == "-" {
+		return f.Name
+	}
+	return tag
+}
+
+// MergeFiles runs a three-way merge of issues.jsonl files as a git merge driver:
+// basePath (%O), oursPath (%A) and theirsPath (%B). The merged result is written
+// to oursPath, as git expects.
+func MergeFiles(basePath, oursPath, theirsPath string) (*MergeResult, error) {
+	base, err := ReadIssuesFile(basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read base: %w", err)
+	}
+	ours, err := ReadIssuesFile(oursPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ours: %w", err)
+	}
+	theirs, err := ReadIssuesFile(theirsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read theirs: %w", err)
+	}
+
+	result := MergeIssues(base, ours, theirs)
+
+	if err := WriteIssuesFile(oursPath, result.Issues); err != nil {
+		return nil, fmt.Errorf("failed to write merged issues: %w", err)
+	}
+
+	return result, nil
+}
+
+// ReadIssuesFile reads an issues JSONL file outside of a Store. A missing file
+// is treated as empty.
+func ReadIssuesFile(path string) ([]*Issue, error) {
+	return readJSONLFile(path)
+}
+
+// WriteIssuesFile atomically writes issues to a JSONL file outside of a Store.
+func WriteIssuesFile(path string, issues []*Issue) error {
+	return writeJSONLFile(path, issues)
+}
@@ -0,0 +1,173 @@
+package issues
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mergeTestIssue(id, title string, updated time.Time) *Issue {
+	return &Issue{
+		ID:          id,
+		Title:       title,
+		Status:      StatusOpen,
+		Priority:    2,
+		IssueType:   TypeTask,
+		SpecContext: "010-test",
+		CreatedAt:   updated.Add(-time.Hour),
+		UpdatedAt:   updated,
+	}
+}
+
+func cloneIssue(i *Issue, mutate func(*Issue)) *Issue {
+	c := *i
+	c.Labels = append([]string(nil), i.Labels...)
+	c.BlockedBy = append([]string(nil), i.BlockedBy...)
+	c.Blocks = append([]string(nil), i.Blocks...)
+	mutate(&c)
+	return &c
+}
+
+func TestMergeIssues(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("non-overlapping field changes merge cleanly", func(t *testing.T) {
+		base := mergeTestIssue("SL-aaaaaa", "Original", t0)
+		ours := cloneIssue(base, func(i *Issue) { i.Status = StatusInProgress; i.UpdatedAt = t0.Add(time.Minute) })
+		theirs := cloneIssue(base, func(i *Issue) { i.Title = "Renamed"; i.UpdatedAt = t0.Add(2 * time.Minute) })
+
+		result := MergeIssues([]*Issue{base}, []*Issue{ours}, []*Issue{theirs})
+		if len(result.Conflicts) != 0 {
+			t.Fatalf("expected no conflicts, got %v", result.Conflicts)
+		}
+		merged := result.Issues[0]
+		if merged.Status != StatusInProgress || merged.Title != "Renamed" {
+			t.Errorf("expected both changes, got status=%s title=%s", merged.Status, merged.Title)
+		}
+		if !merged.UpdatedAt.Equal(theirs.UpdatedAt) {
+			t.Errorf("expected newest updated_at, got %v", merged.UpdatedAt)
+		}
+	})
+
+	t.Run("same field changed on both sides uses newer updated_at", func(t *testing.T) {
+		base := mergeTestIssue("SL-aaaaaa", "Original", t0)
+		ours := cloneIssue(base, func(i *Issue) { i.Title = "Ours"; i.UpdatedAt = t0.Add(2 * time.Minute) })
+		theirs := cloneIssue(base, func(i *Issue) { i.Title = "Theirs"; i.UpdatedAt = t0.Add(time.Minute) })
+
+		result := MergeIssues([]*Issue{base}, []*Issue{ours}, []*Issue{theirs})
+		if result.Issues[0].Title != "Ours" {
+			t.Errorf("expected newer ours title, got %s", result.Issues[0].Title)
+		}
+		if len(result.Conflicts) != 1 || result.Conflicts[0].Field != "title" || result.Conflicts[0].Resolution != "ours" {
+			t.Errorf("expected a resolved title conflict, got %v", result.Conflicts)
+		}
+		if result.HasUnresolved() {
+			t.Error("conflict decided by updated_at should count as resolved")
+		}
+	})
+
+	t.Run("identical updated_at is unresolved", func(t *testing.T) {
+		base := mergeTestIssue("SL-aaaaaa", "Original", t0)
+		ours := cloneIssue(base, func(i *Issue) { i.Priority = 0; i.UpdatedAt = t0.Add(time.Minute) })
+		theirs := cloneIssue(base, func(i *Issue) { i.Priority = 4; i.UpdatedAt = t0.Add(time.Minute) })
+
+		result := MergeIssues([]*Issue{base}, []*Issue{ours}, []*Issue{theirs})
+		if !result.HasUnresolved() {
+			t.Fatalf("expected unresolved conflict, got %v", result.Conflicts)
+		}
+		if result.Issues[0].Priority != 0 {
+			t.Errorf("expected ours kept on unresolved conflict, got %d", result.Issues[0].Priority)
+		}
+	})
+
+	t.Run("set fields union additions and honor removals", func(t *testing.T) {
+		base := mergeTestIssue("SL-aaaaaa", "Original", t0)
+		base.Labels = []string{"keep", "drop"}
+		base.BlockedBy = []string{"SL-111111"}
+		ours := cloneIssue(base, func(i *Issue) { i.Labels = []string{"keep", "drop", "ours"}; i.BlockedBy = nil })
+		theirs := cloneIssue(base, func(i *Issue) {
+			i.Labels = []string{"keep", "theirs"}
+			i.BlockedBy = []string{"SL-111111", "SL-222222"}
+		})
+
+		result := MergeIssues([]*Issue{base}, []*Issue{ours}, []*Issue{theirs})
+		merged := result.Issues[0]
+		if want := []string{"keep", "ours", "theirs"}; !reflect.DeepEqual(merged.Labels, want) {
+			t.Errorf("labels: expected %v, got %v", want, merged.Labels)
+		}
+		if want := []string{"SL-222222"}; !reflect.DeepEqual(merged.BlockedBy, want) {
+			t.Errorf("blocked_by: expected %v, got %v", want, merged.BlockedBy)
+		}
+		if len(result.Conflicts) != 0 {
+			t.Errorf("set merges should not conflict, got %v", result.Conflicts)
+		}
+	})
+
+	t.Run("additions and deletions", func(t *testing.T) {
+		shared := mergeTestIssue("SL-aaaaaa", "Shared", t0)
+		deletedUnchanged := mergeTestIssue("SL-bbbbbb", "Deleted by theirs", t0)
+		deletedModified := mergeTestIssue("SL-cccccc", "Deleted by theirs, edited by ours", t0)
+		edited := cloneIssue(deletedModified, func(i *Issue) { i.Notes = "still needed" })
+		oursNew := mergeTestIssue("SL-dddddd", "Ours new", t0)
+		theirsNew := mergeTestIssue("SL-eeeeee", "Theirs new", t0)
+
+		result := MergeIssues(
+			[]*Issue{shared, deletedUnchanged, deletedModified},
+			[]*Issue{shared, deletedUnchanged, edited, oursNew},
+			[]*Issue{shared, theirsNew},
+		)
+
+		var ids []string
+		for _, i := range result.Issues {
+			ids = append(ids, i.ID)
+		}
+		if want := []string{"SL-aaaaaa", "SL-cccccc", "SL-dddddd", "SL-eeeeee"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("expected %v, got %v", want, ids)
+		}
+		if len(result.Conflicts) != 1 || result.Conflicts[0].Field != "deleted" {
+			t.Errorf("expected delete/modify conflict, got %v", result.Conflicts)
+		}
+	})
+}
+
+func TestMergeFiles(t *testing.T) {
+	dir := t.TempDir()
+	t0 := time.Date(2026, 1, 1, 10, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	base := mergeTestIssue("SL-aaaaaa", "Original", t0)
+	ours := cloneIssue(base, func(i *Issue) { i.Labels = []string{"ours"} })
+	theirs := cloneIssue(base, func(i *Issue) { i.Labels = []string{"theirs"} })
+
+	paths := map[string][]*Issue{"base": {base}, "ours": {ours}, "theirs": {theirs}}
+	for name, list := range paths {
+		if err := WriteIssuesFile(filepath.Join(dir, name), list); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	result, err := MergeFiles(filepath.Join(dir, "base"), filepath.Join(dir, "ours"), filepath.Join(dir, "theirs"))
+	if err != nil {
+		t.Fatalf("MergeFiles failed: %v", err)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Errorf("expected no conflicts after round-trip, got %v", result.Conflicts)
+	}
+
+	merged, err := ReadIssuesFile(filepath.Join(dir, "ours"))
+	if err != nil {
+		t.Fatalf("failed to read merged file: %v", err)
+	}
+	if len(merged) != 1 || !reflect.DeepEqual(merged[0].Labels, []string{"ours", "theirs"}) {
+		t.Errorf("unexpected merged content: %+v", merged)
+	}
+
+	// An empty ancestor (file added on both branches) must still merge.
+	if err := os.WriteFile(filepath.Join(dir, "empty"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MergeFiles(filepath.Join(dir, "empty"), filepath.Join(dir, "ours"), filepath.Join(dir, "theirs")); err != nil {
+		t.Errorf("MergeFiles with empty base failed: %v", err)
+	}
+}
@@ -0,0 +1,540 @@
+package issues
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RedirectsFileName is the per-spec log of issues moved out of the spec,
+// stored next to issues.jsonl. Each line maps an old ID to its new one.
+const RedirectsFileName = "issues.redirects.jsonl"
+
+// Move errors
+var (
+	ErrMoveSameSpec   = errors.New("issue is already in the target spec")
+	ErrMoveMixedSpecs = errors.New("issues to move must belong to the same spec")
+	ErrNothingToMove  = errors.New("no issues to move")
+	ErrRedirectLoop   = errors.New("redirects form a loop")
+)
+
+// maxRedirectHops bounds how many moves ResolveRedirect follows
+const maxRedirectHops = 32
+
+// Redirect is a tombstone left in a spec for an issue moved out of it
+type Redirect struct {
+	ID          string    `json:"id"`           // Old issue ID
+	MovedTo     string    `json:"moved_to"`     // New issue ID
+	SpecContext string    `json:"spec_context"` // Spec the issue was moved to
+	MovedAt     time.Time `json:"moved_at"`
+	Actor       string    `json:"actor,omitempty"`
+}
+
+// MoveResult reports what MoveIssues changed
+type MoveResult struct {
+	From      string     `json:"from"`                // Source spec
+	To        string     `json:"to"`                  // Target spec
+	Moved     []Redirect `json:"moved"`               // Moved issues, selected ones first
+	Rewritten []string   `json:"rewritten,omitempty"` // Other issues whose references were updated
+	Detached  []string   `json:"detached,omitempty"`  // New IDs of moved issues whose parent stayed behind
+	Unblocked []string   `json:"unblocked,omitempty"` // New IDs of moved issues whose blockers stayed behind
+	Released  []string   `json:"released,omitempty"`  // Issues left in the source spec whose blockers moved
+	Stale     []string   `json:"stale,omitempty"`     // Other specs whose references could not be updated
+}
+
+// NewID returns the new ID of a moved issue, or "" if it was not moved
+func (r *MoveResult) NewID(oldID string) string {
+	for _, m := range r.Moved {
+		if m.ID == oldID {
+			return m.MovedTo
+		}
+	}
+	return ""
+}
+
+// MoveIssues moves issues with all their descendants from their spec to the
+// spec toSpec. Moved issues get new IDs for the target spec, every blocks,
+// blocked_by and parent reference to them in any spec is rewritten, and a
+// redirect is recorded in the source spec so the old IDs still resolve (see
+// ResolveRedirect). A moved issue whose parent is not moved loses its parent,
+// since parents must be in the same spec. Likewise blocking links between moved
+// and unmoved issues of the source spec are removed in both directions, since
+// readiness only looks up blockers in the issue's own spec.
+//
+// Every spec is locked before anything is written, so a busy spec fails the
+// move without changing anything, and no reader sees references to the old
+// IDs once the move is recorded. A move that fails partway is rolled back.
+// If references in another spec cannot be rewritten after the issues have
+// moved, the result is returned along with the error and lists that spec in
+// Stale.
+func MoveIssues(basePath string, ids []string, toSpec string) (*MoveResult, error) {
+	if basePath == "" {
+		basePath = "specledger"
+	}
+	if len(ids) == 0 {
+		return nil, ErrNothingToMove
+	}
+	if !isValidSpecContext(toSpec) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidSpecContext, toSpec)
+	}
+	if info, err := os.Stat(filepath.Join(basePath, toSpec)); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", ErrSpecDirNotFound, toSpec)
+	}
+
+	fromSpec := ""
+	for _, id := range ids {
+		_, spec, err := GetIssueAcrossSpecs(id, basePath)
+		if err != nil {
+			if redirect, rerr := ResolveRedirect(basePath, id); rerr == nil {
+				return nil, fmt.Errorf("%w: %s (moved to %s in %s)", err, id, redirect.MovedTo, redirect.SpecContext)
+			}
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		if fromSpec != "" && spec != fromSpec {
+			return nil, fmt.Errorf("%w: %s is in %s, %s is in %s", ErrMoveMixedSpecs, ids[0], fromSpec, id, spec)
+		}
+		fromSpec = spec
+	}
+	if fromSpec == toSpec {
+		return nil, fmt.Errorf("%w: %s", ErrMoveSameSpec, toSpec)
+	}
+
+	source, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: fromSpec})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+	target, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: toSpec})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create store: %w", err)
+	}
+
+	specs, err := listSpecs(source.storage, basePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list spec directories: %w", err)
+	}
+	var others []*Store
+	for _, spec := range specs {
+		if spec == fromSpec || spec == toSpec {
+			continue
+		}
+		store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: spec})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create store: %w", err)
+		}
+		others = append(others, store)
+	}
+
+	// Lock every spec before writing anything, so a spec that is busy fails
+	// the move before any of it is applied
+	stores := append([]*Store{source, target}, others...)
+	sort.Slice(stores, func(i, j int) bool { return stores[i].specContext < stores[j].specContext })
+
+	result := &MoveResult{From: fromSpec, To: toSpec}
+	moved := false
+	err = withLocks(stores, func() error {
+		var err error
+		if moved, err = moveIssuesUnlocked(source, target, ids, result); err != nil {
+			return err
+		}
+
+		// References from other specs. The move stands even if one of them
+		// cannot be rewritten, so carry on and report the specs left behind.
+		mapping := result.mapping()
+		var errs []error
+		for _, store := range others {
+			rewritten, err := store.rewriteReferencesUnlocked(mapping, nil)
+			result.Rewritten = append(result.Rewritten, rewritten...)
+			if err != nil {
+				result.Stale = append(result.Stale, store.specContext)
+				errs = append(errs, fmt.Errorf("failed to update references in %s: %w", store.specContext, err))
+			}
+		}
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		if moved {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// withLocks runs fn while holding the locks of all stores, taken in order
+func withLocks(stores []*Store, fn func() error) error {
+	if len(stores) == 0 {
+		return fn()
+	}
+	return stores[0].WithLock(func() error {
+		return withLocks(stores[1:], fn)
+	})
+}
+
+// moveIssuesUnlocked performs a move while the caller holds both stores' locks.
+// It reports whether the issues were moved, which stays true if an error
+// occurs after that point.
+func moveIssuesUnlocked(source, target *Store, ids []string, result *MoveResult) (bool, error) {
+	sourceIssues, err := source.readAllUnlocked()
+	if err != nil {
+		return false, err
+	}
+	targetIssues, err := target.readAllUnlocked()
+	if err != nil {
+		return false, err
+	}
+
+	byID := make(map[string]*Issue, len(sourceIssues))
+	children := make(map[string][]*Issue)
+	for _, issue := range sourceIssues {
+		byID[issue.ID] = issue
+		if issue.ParentID != nil && *issue.ParentID != "" {
+			children[*issue.ParentID] = append(children[*issue.ParentID], issue)
+		}
+	}
+
+	// The selected issues, then their descendants breadth first
+	var moving []*Issue
+	selected := make(map[string]bool)
+	queue := append([]string(nil), ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if selected[id] {
+			continue
+		}
+		issue, ok := byID[id]
+		if !ok {
+			return false, fmt.Errorf("%w: %s", ErrIssueNotFound, id)
+		}
+		selected[id] = true
+		moving = append(moving, issue)
+		for _, child := range children[id] {
+			queue = append(queue, child.ID)
+		}
+	}
+
+	// Mint IDs that collide with nothing in the target spec
+	taken := make(map[string]bool, len(targetIssues))
+	for _, issue := range targetIssues {
+		taken[issue.ID] = true
+	}
+	now := NowFunc()
+	actor := ActorFunc()
+	mapping := make(map[string]string, len(moving))
+	for _, issue := range moving {
+		newID := GenerateIssueID(target.specContext, issue.Title, now)
+		for offset := 1; taken[newID]; offset++ {
+			newID = GenerateIssueID(target.specContext, issue.Title, now.Add(time.Duration(offset)))
+		}
+		taken[newID] = true
+		mapping[issue.ID] = newID
+		result.Moved = append(result.Moved, Redirect{
+			ID:          issue.ID,
+			MovedTo:     newID,
+			SpecContext: target.specContext,
+			MovedAt:     now,
+			Actor:       actor,
+		})
+	}
+
+	// Issues left in the source spec, by the moved issues they are no longer
+	// linked to by blocks or blocked_by
+	unlink := make(map[string]map[string]bool)
+	split := func(ids []string, movedID string) []string {
+		var kept []string
+		for _, id := range ids {
+			if _, stays := byID[id]; stays && !selected[id] {
+				if unlink[id] == nil {
+					unlink[id] = make(map[string]bool)
+				}
+				unlink[id][movedID] = true
+				continue
+			}
+			kept = append(kept, id)
+		}
+		return kept
+	}
+	released := make(map[string]bool)
+	moved := make([]*Issue, 0, len(moving))
+	for _, issue := range moving {
+		newIssue := copyIssue(issue)
+		newIssue.ID = mapping[issue.ID]
+		newIssue.SpecContext = target.specContext
+		blockedBy := split(issue.BlockedBy, issue.ID)
+		newIssue.BlockedBy = rewriteIDs(blockedBy, mapping)
+		if len(blockedBy) < len(issue.BlockedBy) {
+			result.Unblocked = append(result.Unblocked, newIssue.ID)
+		}
+		blocks := split(issue.Blocks, issue.ID)
+		newIssue.Blocks = rewriteIDs(blocks, mapping)
+		for _, id := range issue.Blocks {
+			if _, stays := byID[id]; stays && !selected[id] && !released[id] {
+				released[id] = true
+				result.Released = append(result.Released, id)
+			}
+		}
+		if issue.ParentID != nil && *issue.ParentID != "" {
+			if parent, ok := mapping[*issue.ParentID]; ok {
+				newIssue.ParentID = &parent
+			} else {
+				newIssue.ParentID = nil
+				result.Detached = append(result.Detached, newIssue.ID)
+			}
+		}
+		newIssue.UpdatedAt = now
+		if err := newIssue.ValidateWorkflow(target.workflow); err != nil {
+			return false, fmt.Errorf("cannot move %s: %w", issue.ID, err)
+		}
+		moved = append(moved, newIssue)
+	}
+
+	// Create in the target first so a failure cannot lose issues, and undo
+	// whatever was written if a later step fails
+	var created []string
+	removed := false
+	rollback := func(cause error) error {
+		var errs []error
+		if removed {
+			for _, issue := range moving {
+				if err := source.backend.Create(issue); err != nil {
+					errs = append(errs, fmt.Errorf("restore %s: %w", issue.ID, err))
+				}
+			}
+		}
+		if len(created) > 0 {
+			if err := target.backend.Delete(created...); err != nil {
+				errs = append(errs, fmt.Errorf("remove copies %s from %s: %w", strings.Join(created, ", "), target.specContext, err))
+			}
+		}
+		if len(errs) > 0 {
+			return fmt.Errorf("%w (rolling back also failed: %w)", cause, errors.Join(errs...))
+		}
+		return cause
+	}
+
+	for _, newIssue := range moved {
+		if err := target.backend.Create(newIssue); err != nil {
+			return false, rollback(fmt.Errorf("failed to create %s: %w", newIssue.ID, err))
+		}
+		created = append(created, newIssue.ID)
+	}
+	oldIDs := make([]string, 0, len(moving))
+	for _, issue := range moving {
+		oldIDs = append(oldIDs, issue.ID)
+	}
+	if err := source.backend.Delete(oldIDs...); err != nil {
+		return false, rollback(fmt.Errorf("failed to remove moved issues: %w", err))
+	}
+	removed = true
+	if err := source.appendRedirectsUnlocked(result.Moved); err != nil {
+		return false, rollback(err)
+	}
+
+	for i, newIssue := range moved {
+		reason := fmt.Sprintf("Moved from %s in %s", moving[i].ID, source.specContext)
+		if err := target.recordEventUnlocked(EventCreated, nil, newIssue, reason); err != nil {
+			return true, err
+		}
+	}
+	for i, issue := range moving {
+		reason := fmt.Sprintf("Moved to %s in %s", moved[i].ID, target.specContext)
+		if err := source.recordEventUnlocked(EventDeleted, issue, nil, reason); err != nil {
+			return true, err
+		}
+	}
+
+	for _, step := range []struct {
+		store  *Store
+		unlink map[string]map[string]bool
+	}{{source, unlink}, {target, nil}} {
+		rewritten, err := step.store.rewriteReferencesUnlocked(mapping, step.unlink)
+		if err != nil {
+			return true, err
+		}
+		result.Rewritten = append(result.Rewritten, rewritten...)
+	}
+	return true, nil
+}
+
+// rewriteReferencesUnlocked replaces moved IDs in the blocks, blocked_by and
+// parent references of the store's issues and returns the IDs of the issues
+// it updated. unlink maps issues to moved issues removed from their blocks and
+// blocked_by instead. Must be called while holding the lock.
+func (s *Store) rewriteReferencesUnlocked(mapping map[string]string, unlink map[string]map[string]bool) ([]string, error) {
+	issues, err := s.readAllUnlocked()
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []*Issue
+	var befores []*Issue
+	for _, issue := range issues {
+		before := copyIssue(issue)
+		if drop := unlink[issue.ID]; len(drop) > 0 {
+			issue.BlockedBy = dropIDs(issue.BlockedBy, drop)
+			issue.Blocks = dropIDs(issue.Blocks, drop)
+		}
+		issue.BlockedBy = rewriteIDs(issue.BlockedBy, mapping)
+		issue.Blocks = rewriteIDs(issue.Blocks, mapping)
+		if issue.ParentID != nil {
+			if parent, ok := mapping[*issue.ParentID]; ok {
+				issue.ParentID = &parent
+			}
+		}
+		if len(diffIssues(before, issue)) == 0 {
+			continue
+		}
+		issue.UpdatedAt = NowFunc()
+		updated = append(updated, issue)
+		befores = append(befores, before)
+	}
+	if len(updated) == 0 {
+		return nil, nil
+	}
+
+	if err := s.backend.Update(updated...); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(updated))
+	for i, issue := range updated {
+		if err := s.recordEventUnlocked(EventUpdated, befores[i], issue, "References to moved issues updated"); err != nil {
+			return nil, err
+		}
+		ids = append(ids, issue.ID)
+	}
+	return ids, nil
+}
+
+// mapping returns the old to new IDs of the moved issues
+func (r *MoveResult) mapping() map[string]string {
+	m := make(map[string]string, len(r.Moved))
+	for _, moved := range r.Moved {
+		m[moved.ID] = moved.MovedTo
+	}
+	return m
+}
+
+// rewriteIDs returns ids with moved IDs replaced, or nil for an empty list
+func rewriteIDs(ids []string, mapping map[string]string) []string {
+	if len(ids) == 0 {
+		return ids
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if newID, ok := mapping[id]; ok {
+			id = newID
+		}
+		out = append(out, id)
+	}
+	return out
+}
+
+// dropIDs returns ids without the ones in drop, or nil if none are left
+func dropIDs(ids []string, drop map[string]bool) []string {
+	var kept []string
+	for _, id := range ids {
+		if !drop[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// RedirectsPath returns the path to the redirect log for this store's spec.
+func (s *Store) RedirectsPath() string {
+	return filepath.Join(s.specDir, RedirectsFileName)
+}
+
+// appendRedirectsUnlocked records redirects in the store's spec. Must be
+// called while holding the lock.
+func (s *Store) appendRedirectsUnlocked(redirects []Redirect) error {
+	f, err := os.OpenFile(s.RedirectsPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open redirect log: %w", err)
+	}
+	defer f.Close()
+
+	// One write, so a failed move does not leave some of its redirects behind
+	var buf []byte
+	for _, r := range redirects {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("failed to marshal redirect: %w", err)
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+	if _, err := f.Write(buf); err != nil {
+		return fmt.Errorf("failed to record redirect: %w", err)
+	}
+	return nil
+}
+
+// ReadRedirects reads the redirects of every spec under basePath, keyed by
+// old ID. Missing logs hold no redirects.
+func ReadRedirects(basePath string) (map[string]Redirect, error) {
+	if basePath == "" {
+		basePath = "specledger"
+	}
+
+	paths, err := filepath.Glob(filepath.Join(basePath, "*", RedirectsFileName))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	redirects := make(map[string]Redirect)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open redirect log: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var r Redirect
+			if err := json.Unmarshal([]byte(line), &r); err != nil || r.ID == "" {
+				continue
+			}
+			redirects[r.ID] = r
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading redirect log: %w", err)
+		}
+	}
+	return redirects, nil
+}
+
+// ResolveRedirect follows the redirects left by moves from id to the issue's
+// current ID and spec. It returns ErrIssueNotFound if id was never moved.
+func ResolveRedirect(basePath, id string) (*Redirect, error) {
+	redirects, err := ReadRedirects(basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := redirects[id]
+	if !ok {
+		return nil, ErrIssueNotFound
+	}
+	for hops := 0; ; hops++ {
+		next, ok := redirects[r.MovedTo]
+		if !ok {
+			return &r, nil
+		}
+		if hops >= maxRedirectHops {
+			return nil, fmt.Errorf("%w: %s", ErrRedirectLoop, id)
+		}
+		r = Redirect{ID: id, MovedTo: next.MovedTo, SpecContext: next.SpecContext, MovedAt: next.MovedAt, Actor: next.Actor}
+	}
+}
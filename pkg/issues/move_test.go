@@ -0,0 +1,137 @@
+package issues
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMoveIssues(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	stores := make(map[string]*Store)
+	for _, spec := range []string{"001-source", "002-target", "003-other"} {
+		if err := os.MkdirAll(filepath.Join(basePath, spec), 0755); err != nil {
+			t.Fatal(err)
+		}
+		store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: spec})
+		if err != nil {
+			t.Fatalf("NewStore() error: %v", err)
+		}
+		stores[spec] = store
+	}
+	source, other := stores["001-source"], stores["003-other"]
+
+	create := func(store *Store, title string, parent *Issue) *Issue {
+		issue := NewIssue(title, "", store.specContext, TypeTask, 2)
+		if parent != nil {
+			issue.ParentID = &parent.ID
+		}
+		if err := store.Create(issue); err != nil {
+			t.Fatalf("Create(%s) error: %v", title, err)
+		}
+		return issue
+	}
+	parent := create(source, "Parent", nil)
+	feature := create(source, "Feature", parent)
+	child := create(source, "Child", feature)
+	sibling := create(source, "Sibling", nil)
+	gate := create(source, "Gate", nil)
+	remote := create(other, "Remote", nil)
+
+	if err := source.AddDependency(child.ID, sibling.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+	if err := source.AddDependency(gate.ID, feature.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+	blockedBy := []string{child.ID}
+	if _, err := other.Update(remote.ID, IssueUpdate{BlockedBy: &blockedBy}); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if _, err := MoveIssues(basePath, []string{feature.ID}, "001-source"); !errors.Is(err, ErrMoveSameSpec) {
+		t.Errorf("move within spec: got %v, want ErrMoveSameSpec", err)
+	}
+	if _, err := MoveIssues(basePath, []string{feature.ID}, "004-missing"); !errors.Is(err, ErrSpecDirNotFound) {
+		t.Errorf("move to missing spec: got %v, want ErrSpecDirNotFound", err)
+	}
+
+	// A busy spec holding references fails the move before anything is written
+	err := other.WithLock(func() error {
+		_, err := MoveIssues(basePath, []string{feature.ID}, "002-target")
+		return err
+	})
+	if !errors.Is(err, ErrStoreLocked) {
+		t.Errorf("move with a locked spec: got %v, want ErrStoreLocked", err)
+	}
+	if _, err := source.Get(feature.ID); err != nil {
+		t.Errorf("failed move should leave %s in the source spec: %v", feature.ID, err)
+	}
+	if _, err := os.Stat(source.RedirectsPath()); !os.IsNotExist(err) {
+		t.Errorf("failed move should record no redirects, stat: %v", err)
+	}
+
+	result, err := MoveIssues(basePath, []string{feature.ID}, "002-target")
+	if err != nil {
+		t.Fatalf("MoveIssues() error: %v", err)
+	}
+	if len(result.Moved) != 2 || result.Moved[0].ID != feature.ID || result.Moved[1].ID != child.ID {
+		t.Fatalf("moved = %+v, want the feature and its child", result.Moved)
+	}
+	newFeature, newChild := result.NewID(feature.ID), result.NewID(child.ID)
+
+	if _, err := source.Get(feature.ID); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("feature still in source spec: %v", err)
+	}
+	moved, spec, err := GetIssueAcrossSpecs(newChild, basePath)
+	if err != nil || spec != "002-target" {
+		t.Fatalf("GetIssueAcrossSpecs(%s) = %s, %v", newChild, spec, err)
+	}
+	if moved.SpecContext != "002-target" || moved.ParentID == nil || *moved.ParentID != newFeature {
+		t.Errorf("moved child = spec %s, parent %v; want parent %s", moved.SpecContext, moved.ParentID, newFeature)
+	}
+	if len(result.Detached) != 1 || result.Detached[0] != newFeature {
+		t.Errorf("detached = %v, want %s (its parent stayed)", result.Detached, newFeature)
+	}
+
+	gotFeature, _, _ := GetIssueAcrossSpecs(newFeature, basePath)
+	if len(gotFeature.BlockedBy) != 0 || len(result.Unblocked) != 1 || result.Unblocked[0] != newFeature {
+		t.Errorf("feature blocked by %v, unblocked = %v; want its blocker left behind", gotFeature.BlockedBy, result.Unblocked)
+	}
+	gotGate, _ := source.Get(gate.ID)
+	if len(gotGate.Blocks) != 0 {
+		t.Errorf("gate blocks %v, want nothing", gotGate.Blocks)
+	}
+
+	// The sibling stays, so the moved child no longer blocks it
+	gotSibling, _ := source.Get(sibling.ID)
+	if len(gotSibling.BlockedBy) != 0 || len(result.Released) != 1 || result.Released[0] != sibling.ID {
+		t.Errorf("sibling blocked by %v, released = %v; want it unlinked from the moved child", gotSibling.BlockedBy, result.Released)
+	}
+	if gotChild, _, _ := GetIssueAcrossSpecs(newChild, basePath); len(gotChild.Blocks) != 0 {
+		t.Errorf("moved child blocks %v, want nothing", gotChild.Blocks)
+	}
+	gotRemote, _ := other.Get(remote.ID)
+	if len(gotRemote.BlockedBy) != 1 || gotRemote.BlockedBy[0] != newChild {
+		t.Errorf("remote blocked by %v, want %s", gotRemote.BlockedBy, newChild)
+	}
+
+	redirect, err := ResolveRedirect(basePath, child.ID)
+	if err != nil || redirect.MovedTo != newChild || redirect.SpecContext != "002-target" {
+		t.Errorf("ResolveRedirect() = %+v, %v", redirect, err)
+	}
+
+	// Moving again resolves through both redirects
+	again, err := MoveIssues(basePath, []string{newChild}, "003-other")
+	if err != nil {
+		t.Fatalf("second MoveIssues() error: %v", err)
+	}
+	redirect, err = ResolveRedirect(basePath, child.ID)
+	if err != nil || redirect.MovedTo != again.NewID(newChild) || redirect.SpecContext != "003-other" {
+		t.Errorf("ResolveRedirect() after second move = %+v, %v", redirect, err)
+	}
+	if _, err := ResolveRedirect(basePath, sibling.ID); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("ResolveRedirect(unmoved) error = %v, want ErrIssueNotFound", err)
+	}
+}
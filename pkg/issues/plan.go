@@ -0,0 +1,533 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Planning errors
+var (
+	ErrNothingToPlan = errors.New("no tasks to plan")
+	ErrPlanCycle     = errors.New("blocking links form a cycle")
+)
+
+// planEpsilon absorbs floating point error when comparing day offsets
+const planEpsilon = 1e-9
+
+// PlanOptions configures BuildPlan
+type PlanOptions struct {
+	Agents          int       // Simulate a schedule with this many parallel agents (0 = critical path only)
+	DefaultEstimate float64   // Days assumed for tasks without an estimate
+	Workflow        *Workflow // Decides which statuses count as done (default: DefaultWorkflow())
+	Start           time.Time // Day the forecast starts from (default: NowFunc())
+}
+
+// PlanTask is one unit of work in a plan. Times are offsets in working days
+// from the plan start.
+type PlanTask struct {
+	ID        string      `json:"id"`
+	Title     string      `json:"title"`
+	Status    IssueStatus `json:"status"`
+	Priority  int         `json:"priority"`
+	Estimate  float64     `json:"estimate"`             // Days planned; 0 for finished work
+	Estimated bool        `json:"estimated"`            // False when PlanOptions.DefaultEstimate was assumed
+	Done      bool        `json:"done,omitempty"`       // Already finished; takes no time
+	External  bool        `json:"external,omitempty"`   // Blocker from outside the epic
+	BlockedBy []string    `json:"blocked_by,omitempty"` // Unfinished tasks that must finish first
+
+	EarliestStart  float64 `json:"earliest_start"`
+	EarliestFinish float64 `json:"earliest_finish"`
+	LatestStart    float64 `json:"latest_start"`
+	LatestFinish   float64 `json:"latest_finish"`
+	Slack          float64 `json:"slack"`
+	Critical       bool    `json:"critical"`
+
+	// Simulated schedule, when PlanOptions.Agents > 0
+	Agent  int     `json:"agent,omitempty"` // 1-based
+	Start  float64 `json:"start,omitempty"`
+	Finish float64 `json:"finish,omitempty"`
+}
+
+// Plan is the critical path analysis of the work below an epic, and
+// optionally a schedule for a number of parallel agents
+type Plan struct {
+	EpicID       string     `json:"epic_id"`
+	EpicTitle    string     `json:"epic_title"`
+	Duration     float64    `json:"duration"` // Length of the critical path in days
+	CriticalPath []string   `json:"critical_path"`
+	Agents       int        `json:"agents,omitempty"`
+	Makespan     float64    `json:"makespan,omitempty"` // Schedule length with Agents agents
+	Start        time.Time  `json:"start"`
+	Forecast     time.Time  `json:"forecast"` // Finish date, counting working days (Mon-Fri)
+	Tasks        []PlanTask `json:"tasks"`
+	Warnings     []string   `json:"warnings,omitempty"`
+}
+
+// BuildPlan computes the critical path of the work below epicID over the
+// blocking graph in all, the earliest and latest start and finish of each
+// task, and its slack. Issues with children are containers: their work is
+// their leaf descendants, which inherit their blockers. Unfinished blockers
+// from outside the epic are planned as external tasks.
+func BuildPlan(epicID string, all []Issue, opts PlanOptions) (*Plan, error) {
+	workflow := opts.Workflow
+	if workflow == nil {
+		workflow = DefaultWorkflow()
+	}
+	start := opts.Start
+	if start.IsZero() {
+		start = NowFunc()
+	}
+
+	byID := make(map[string]*Issue, len(all))
+	children := make(map[string][]string)
+	for i := range all {
+		issue := &all[i]
+		byID[issue.ID] = issue
+		if issue.ParentID != nil && *issue.ParentID != "" {
+			children[*issue.ParentID] = append(children[*issue.ParentID], issue.ID)
+		}
+	}
+	epic, ok := byID[epicID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrIssueNotFound, epicID)
+	}
+
+	// leaves expands an issue to the leaf issues that carry its work
+	var leaves func(id string, seen map[string]bool) []string
+	leaves = func(id string, seen map[string]bool) []string {
+		if seen[id] {
+			return nil
+		}
+		seen[id] = true
+		if len(children[id]) == 0 {
+			return []string{id}
+		}
+		var out []string
+		for _, child := range children[id] {
+			out = append(out, leaves(child, seen)...)
+		}
+		return out
+	}
+
+	plan := &Plan{EpicID: epic.ID, EpicTitle: epic.Title, Agents: opts.Agents, Start: start}
+	tasks := make(map[string]*PlanTask)
+	var order []string
+	var queue []string
+	var missing []string
+
+	addTask := func(id string, external bool) {
+		if _, ok := tasks[id]; ok {
+			return
+		}
+		issue := byID[id]
+		task := &PlanTask{
+			ID:        issue.ID,
+			Title:     issue.Title,
+			Status:    issue.Status,
+			Priority:  issue.Priority,
+			Estimate:  issue.Estimate,
+			Estimated: issue.Estimate > 0,
+			Done:      workflow.IsDone(issue.Status),
+			External:  external,
+		}
+		if task.Done {
+			task.Estimate = 0
+		} else if !task.Estimated {
+			task.Estimate = opts.DefaultEstimate
+		}
+		tasks[id] = task
+		order = append(order, id)
+		queue = append(queue, id)
+	}
+
+	for _, id := range leaves(epic.ID, map[string]bool{}) {
+		if id != epic.ID {
+			addTask(id, false)
+		}
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("%w: %s has no child issues", ErrNothingToPlan, epic.ID)
+	}
+
+	// Resolve blockers, inherited from containers up to the epic, to leaf
+	// tasks; unfinished blockers from outside join the plan as external work
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		task := tasks[id]
+		if task.Done {
+			continue
+		}
+
+		var blockerIDs []string
+		for cur := byID[id]; cur != nil; {
+			blockerIDs = append(blockerIDs, cur.BlockedBy...)
+			if cur.ID == epic.ID || cur.ParentID == nil {
+				break
+			}
+			cur = byID[*cur.ParentID]
+		}
+
+		seen := make(map[string]bool)
+		for _, blockerID := range blockerIDs {
+			if _, ok := byID[blockerID]; !ok {
+				missing = append(missing, blockerID)
+				continue
+			}
+			for _, leaf := range leaves(blockerID, map[string]bool{}) {
+				if leaf == id || seen[leaf] || workflow.IsDone(byID[leaf].Status) {
+					continue
+				}
+				seen[leaf] = true
+				addTask(leaf, true)
+				task.BlockedBy = append(task.BlockedBy, leaf)
+			}
+		}
+		sort.Strings(task.BlockedBy)
+	}
+
+	// Topological order of the unfinished tasks
+	var open []*PlanTask
+	indegree := make(map[string]int)
+	successors := make(map[string][]string)
+	for _, id := range order {
+		task := tasks[id]
+		if task.Done {
+			continue
+		}
+		open = append(open, task)
+		indegree[id] = len(task.BlockedBy)
+		for _, pred := range task.BlockedBy {
+			successors[pred] = append(successors[pred], id)
+		}
+	}
+	var topo []*PlanTask
+	var ready []string
+	for _, task := range open {
+		if indegree[task.ID] == 0 {
+			ready = append(ready, task.ID)
+		}
+	}
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		topo = append(topo, tasks[id])
+		for _, next := range successors[id] {
+			indegree[next]--
+			if indegree[next] == 0 {
+				ready = append(ready, next)
+			}
+		}
+	}
+	if len(topo) < len(open) {
+		var cycle []string
+		for _, task := range open {
+			if indegree[task.ID] > 0 {
+				cycle = append(cycle, task.ID)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("%w between %s", ErrPlanCycle, strings.Join(cycle, ", "))
+	}
+
+	// Forward pass: earliest start and finish
+	for _, task := range topo {
+		for _, pred := range task.BlockedBy {
+			task.EarliestStart = math.Max(task.EarliestStart, tasks[pred].EarliestFinish)
+		}
+		task.EarliestFinish = task.EarliestStart + task.Estimate
+		plan.Duration = math.Max(plan.Duration, task.EarliestFinish)
+	}
+
+	// Backward pass: latest start and finish, slack
+	for i := len(topo) - 1; i >= 0; i-- {
+		task := topo[i]
+		task.LatestFinish = plan.Duration
+		for _, next := range successors[task.ID] {
+			task.LatestFinish = math.Min(task.LatestFinish, tasks[next].LatestStart)
+		}
+		task.LatestStart = task.LatestFinish - task.Estimate
+		task.Slack = task.LatestStart - task.EarliestStart
+		if math.Abs(task.Slack) < planEpsilon {
+			task.Slack = 0
+		}
+		task.Critical = task.Slack == 0
+	}
+
+	plan.CriticalPath = criticalPath(topo, tasks, plan.Duration)
+
+	finish := plan.Duration
+	if opts.Agents > 0 {
+		plan.Makespan = simulateSchedule(topo, tasks, successors, opts.Agents)
+		finish = plan.Makespan
+	}
+	plan.Forecast = AddWorkingDays(start, finish)
+
+	for _, id := range order {
+		plan.Tasks = append(plan.Tasks, *tasks[id])
+	}
+	sort.SliceStable(plan.Tasks, func(i, j int) bool {
+		a, b := plan.Tasks[i], plan.Tasks[j]
+		if a.Done != b.Done {
+			return b.Done
+		}
+		if opts.Agents > 0 && a.Start != b.Start {
+			return a.Start < b.Start
+		}
+		if a.EarliestStart != b.EarliestStart {
+			return a.EarliestStart < b.EarliestStart
+		}
+		if a.Slack != b.Slack {
+			return a.Slack < b.Slack
+		}
+		return a.ID < b.ID
+	})
+
+	var unestimated, external int
+	for _, task := range plan.Tasks {
+		if !task.Done && !task.Estimated {
+			unestimated++
+		}
+		if task.External {
+			external++
+		}
+	}
+	if unestimated > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d task(s) have no estimate; assumed %s each", unestimated, FormatEstimate(opts.DefaultEstimate)))
+	}
+	if external > 0 {
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("%d unfinished blocker(s) from outside the epic planned as external work", external))
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		plan.Warnings = append(plan.Warnings, fmt.Sprintf("blockers not found in this spec were ignored: %s", strings.Join(dedupe(missing), ", ")))
+	}
+
+	return plan, nil
+}
+
+// criticalPath walks back from a task finishing at the plan's end through
+// critical predecessors that finish exactly when it starts
+func criticalPath(topo []*PlanTask, tasks map[string]*PlanTask, duration float64) []string {
+	var current *PlanTask
+	for _, task := range topo {
+		if task.Critical && math.Abs(task.EarliestFinish-duration) < planEpsilon {
+			if current == nil || task.Estimate > current.Estimate {
+				current = task
+			}
+		}
+	}
+
+	var path []string
+	for current != nil {
+		path = append([]string{current.ID}, path...)
+		var prev *PlanTask
+		for _, id := range current.BlockedBy {
+			pred := tasks[id]
+			if pred.Critical && math.Abs(pred.EarliestFinish-current.EarliestStart) < planEpsilon {
+				if prev == nil || pred.Estimate > prev.Estimate {
+					prev = pred
+				}
+			}
+		}
+		current = prev
+	}
+	return path
+}
+
+// simulateSchedule assigns tasks to agents as they become ready, least slack
+// first, and returns when the last task finishes
+func simulateSchedule(topo []*PlanTask, tasks map[string]*PlanTask, successors map[string][]string, agents int) float64 {
+	waiting := make(map[string]int, len(topo))
+	var ready []*PlanTask
+	for _, task := range topo {
+		waiting[task.ID] = len(task.BlockedBy)
+		if waiting[task.ID] == 0 {
+			ready = append(ready, task)
+		}
+	}
+
+	free := make([]float64, agents) // When each agent is next free
+	var running []*PlanTask
+	now, makespan := 0.0, 0.0
+	for done := 0; done < len(topo); {
+		sort.SliceStable(ready, func(i, j int) bool {
+			a, b := ready[i], ready[j]
+			if a.Slack != b.Slack {
+				return a.Slack < b.Slack
+			}
+			if a.Priority != b.Priority {
+				return a.Priority < b.Priority
+			}
+			return a.ID < b.ID
+		})
+		for agent := 0; agent < agents && len(ready) > 0; agent++ {
+			if free[agent] > now+planEpsilon {
+				continue
+			}
+			task := ready[0]
+			ready = ready[1:]
+			task.Agent = agent + 1
+			task.Start = now
+			task.Finish = now + task.Estimate
+			free[agent] = task.Finish
+			running = append(running, task)
+		}
+
+		// Advance to the next finish and release its successors
+		next := math.Inf(1)
+		for _, task := range running {
+			next = math.Min(next, task.Finish)
+		}
+		now = next
+		var still []*PlanTask
+		for _, task := range running {
+			if task.Finish > now+planEpsilon {
+				still = append(still, task)
+				continue
+			}
+			done++
+			makespan = math.Max(makespan, task.Finish)
+			for _, id := range successors[task.ID] {
+				waiting[id]--
+				if waiting[id] == 0 {
+					ready = append(ready, tasks[id])
+				}
+			}
+		}
+		running = still
+	}
+	return makespan
+}
+
+// AddWorkingDays returns the date days working days (Monday to Friday) after
+// start, rounding partial days up
+func AddWorkingDays(start time.Time, days float64) time.Time {
+	date := start
+	for n := int(math.Ceil(days - planEpsilon)); n > 0; {
+		date = date.AddDate(0, 0, 1)
+		if date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+			n--
+		}
+	}
+	return date
+}
+
+// ParseEstimate parses an estimate in working days: a number of days, or a
+// number with an h (8 per day), d or w (5 days) suffix, e.g. "2", "1.5d",
+// "4h" or "1w".
+func ParseEstimate(s string) (float64, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	unit := 1.0
+	switch {
+	case strings.HasSuffix(s, "h"):
+		unit, s = 1.0/8, strings.TrimSuffix(s, "h")
+	case strings.HasSuffix(s, "d"):
+		s = strings.TrimSuffix(s, "d")
+	case strings.HasSuffix(s, "w"):
+		unit, s = 5, strings.TrimSuffix(s, "w")
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+		return 0, fmt.Errorf("%w: use days or a duration like 4h, 1.5d or 1w", ErrInvalidEstimate)
+	}
+	return n * unit, nil
+}
+
+// FormatEstimate formats days for display, e.g. "2.5d"
+func FormatEstimate(days float64) string {
+	return strconv.FormatFloat(math.Round(days*100)/100, 'f', -1, 64) + "d"
+}
+
+// Gantt renders the plan as a text Gantt chart with bars width characters
+// wide. Critical tasks are marked with * and drawn with █, other tasks with
+// ▒ followed by their slack as ·. With a simulated schedule the bars show
+// when each agent works on a task.
+func (p *Plan) Gantt(width int) string {
+	if width < 10 {
+		width = 10
+	}
+	total := p.Duration
+	if p.Agents > 0 {
+		total = math.Max(total, p.Makespan)
+	}
+	scale := func(days float64) int {
+		if total <= 0 {
+			return 0
+		}
+		return int(math.Round(days / total * float64(width)))
+	}
+
+	const labelWidth = 36
+	var sb strings.Builder
+	axisEnd := FormatEstimate(total)
+	sb.WriteString(fmt.Sprintf("%-*s   %-*s%s\n", labelWidth, "", width-len(axisEnd), "0d", axisEnd))
+
+	for _, task := range p.Tasks {
+		label := task.ID + " " + Truncate(task.Title, labelWidth-len(task.ID)-3)
+		marker := " "
+		if task.Critical && !task.Done {
+			marker = "*"
+		}
+
+		bar := []rune(strings.Repeat(" ", width))
+		var timing string
+		switch {
+		case task.Done:
+			timing = "done"
+		default:
+			from, to := task.EarliestStart, task.EarliestFinish
+			if p.Agents > 0 {
+				from, to = task.Start, task.Finish
+			}
+			fill := '▒'
+			if task.Critical {
+				fill = '█'
+			}
+			start, end := scale(from), scale(to)
+			if p.Agents == 0 {
+				for i := end; i < scale(task.LatestFinish) && i < width; i++ {
+					bar[i] = '·'
+				}
+			}
+			if end == start {
+				// Keep short and zero-length tasks visible
+				if start >= width {
+					start = width - 1
+				}
+				bar[start] = fill
+			}
+			for i := start; i < end && i < width; i++ {
+				bar[i] = fill
+			}
+			timing = fmt.Sprintf("%s-%s", FormatEstimate(from), FormatEstimate(to))
+			if p.Agents > 0 {
+				timing = fmt.Sprintf("agent %d  %s", task.Agent, timing)
+			} else if task.Slack > 0 {
+				timing += "  slack " + FormatEstimate(task.Slack)
+			}
+		}
+		if task.External {
+			timing += "  (external)"
+		}
+
+		sb.WriteString(fmt.Sprintf("%-*s%s |%s| %s\n", labelWidth, label, marker, string(bar), timing))
+	}
+	return sb.String()
+}
+
+// dedupe removes adjacent duplicates from a sorted slice
+func dedupe(sorted []string) []string {
+	var out []string
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
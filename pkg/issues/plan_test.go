@@ -0,0 +1,99 @@
+package issues
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildPlan(t *testing.T) {
+	epic := NewIssue("Epic", "", "010-test", TypeEpic, 1)
+	task := func(title string, estimate float64, blockedBy ...*Issue) *Issue {
+		issue := NewIssue(title, "", "010-test", TypeTask, 2)
+		issue.ID = "SL-" + strings.ToLower(title)
+		issue.ParentID = &epic.ID
+		issue.Estimate = estimate
+		for _, b := range blockedBy {
+			issue.BlockedBy = append(issue.BlockedBy, b.ID)
+		}
+		return issue
+	}
+	a := task("a", 2)
+	b := task("b", 3, a)
+	c := task("c", 1)
+	d := task("d", 0, c)
+	done := task("e", 4)
+	done.Status = StatusClosed
+	all := []Issue{*epic, *a, *b, *c, *d, *done}
+
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC) // A Friday
+	plan, err := BuildPlan(epic.ID, all, PlanOptions{DefaultEstimate: 1, Start: start})
+	if err != nil {
+		t.Fatalf("BuildPlan() error: %v", err)
+	}
+	if plan.Duration != 5 {
+		t.Errorf("Duration = %v, want 5", plan.Duration)
+	}
+	if want := []string{a.ID, b.ID}; !reflect.DeepEqual(plan.CriticalPath, want) {
+		t.Errorf("CriticalPath = %v, want %v", plan.CriticalPath, want)
+	}
+	if want := time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC); !plan.Forecast.Equal(want) {
+		t.Errorf("Forecast = %v, want %v", plan.Forecast, want)
+	}
+	tasks := make(map[string]PlanTask)
+	for _, task := range plan.Tasks {
+		tasks[task.ID] = task
+	}
+	if got := tasks[d.ID]; got.EarliestStart != 1 || got.Slack != 3 || got.Estimated || got.Critical {
+		t.Errorf("task d = %+v, want start 1, slack 3, assumed estimate", got)
+	}
+	if got := tasks[done.ID]; !got.Done || got.Estimate != 0 {
+		t.Errorf("closed task = %+v, want done with no remaining estimate", got)
+	}
+	if len(plan.Warnings) != 1 {
+		t.Errorf("Warnings = %v, want one for the missing estimate", plan.Warnings)
+	}
+
+	for agents, want := range map[int]float64{1: 7, 2: 5} {
+		plan, err := BuildPlan(epic.ID, all, PlanOptions{Agents: agents, DefaultEstimate: 1, Start: start})
+		if err != nil {
+			t.Fatalf("BuildPlan(%d agents) error: %v", agents, err)
+		}
+		if plan.Makespan != want {
+			t.Errorf("%d agents: Makespan = %v, want %v", agents, plan.Makespan, want)
+		}
+		for _, task := range plan.Tasks {
+			if !task.Done && (task.Agent < 1 || task.Agent > agents) {
+				t.Errorf("%d agents: task %s on agent %d", agents, task.ID, task.Agent)
+			}
+		}
+	}
+	if !strings.Contains(plan.Gantt(40), "*") {
+		t.Error("Gantt() should mark critical tasks")
+	}
+
+	c.BlockedBy = []string{d.ID}
+	all = []Issue{*epic, *a, *b, *c, *d}
+	if _, err := BuildPlan(epic.ID, all, PlanOptions{}); !errors.Is(err, ErrPlanCycle) {
+		t.Errorf("cycle: got %v, want ErrPlanCycle", err)
+	}
+	if _, err := BuildPlan(a.ID, all, PlanOptions{}); !errors.Is(err, ErrNothingToPlan) {
+		t.Errorf("leaf issue: got %v, want ErrNothingToPlan", err)
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	for input, want := range map[string]float64{"2": 2, "1.5d": 1.5, "4h": 0.5, "1w": 5, " 3D ": 3} {
+		got, err := ParseEstimate(input)
+		if err != nil || got != want {
+			t.Errorf("ParseEstimate(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "-1", "soon", "2m"} {
+		if _, err := ParseEstimate(input); !errors.Is(err, ErrInvalidEstimate) {
+			t.Errorf("ParseEstimate(%q): got %v, want ErrInvalidEstimate", input, err)
+		}
+	}
+}
@@ -0,0 +1,532 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidQuery is returned for queries that cannot be parsed
+var ErrInvalidQuery = errors.New("invalid query")
+
+// Query is a parsed search expression. See ParseQuery for the syntax.
+type Query struct {
+	root queryNode
+}
+
+// Match reports whether an issue satisfies the query. An empty query matches everything.
+func (q *Query) Match(issue *Issue) bool {
+	if q == nil || q.root == nil {
+		return true
+	}
+	return q.root.match(issue)
+}
+
+// QueryFields lists the field qualifiers understood by ParseQuery
+var QueryFields = []string{
+	"id", "status", "type", "priority", "label", "assignee", "spec", "parent", "is",
+	"created", "updated", "closed",
+	"title", "description", "notes", "design", "acceptance",
+}
+
+// ParseQuery parses a search expression.
+//
+// Bare words and "quoted phrases" match case-insensitively against the title,
+// description, notes, design and acceptance criteria. Qualifiers narrow by field:
+//
+//	status:open,in_progress   type:bug   label:component:api   label:component:*
+//	priority:<=1              assignee:me   assignee:none       spec:010-feature
+//	parent:SL-a3f5d8          id:SL-a3f5d8   is:blocked|orphaned|claimed
+//	title:"rate limit"        description:, notes:, design:, acceptance:
+//	created:>2024-01-01       updated:<30d   closed:>=1w
+//
+// Comma-separated values match any of them. Dates accept YYYY-MM-DD, RFC3339 or
+// a duration ago (e.g. 90m, 12h, 7d, 2w): updated:>7d is "updated within the
+// last 7 days", updated:<7d "not updated for 7 days". A date with no operator
+// means that day; a duration with no operator means since then. "me" is the
+// actor recorded on events.
+//
+// Terms are ANDed; use OR, NOT (or a leading -) and parentheses to combine them.
+// Statuses are checked against workflow, usually the workflow of the project
+// being searched (see WorkflowFunc); with nil only the built-in ones are known.
+func ParseQuery(input string, workflow *Workflow) (*Query, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens, now: NowFunc(), workflow: workflow}
+	if len(tokens) == 0 {
+		return &Query{}, nil
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, p.tokens[p.pos].text)
+	}
+	return &Query{root: root}, nil
+}
+
+// ParseRelativeDuration extends time.ParseDuration with day ("d") and week ("w") units.
+func ParseRelativeDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.ParseFloat(n, 64)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			return time.Duration(count * float64(unit)), nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+type tokenKind int
+
+const (
+	tokWord tokenKind = iota
+	tokPhrase
+	tokLParen
+	tokRParen
+)
+
+type queryToken struct {
+	kind tokenKind
+	text string
+}
+
+// tokenizeQuery splits input into words, quoted phrases and parentheses. A
+// quote inside a word (title:"rate limit") extends the word to the closing quote.
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{tokLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{tokRParen, ")"})
+			i++
+		case r == '"':
+			end := indexRune(runes, i+1, '"')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidQuery)
+			}
+			tokens = append(tokens, queryToken{tokPhrase, string(runes[i+1 : end])})
+			i = end + 1
+		default:
+			var word strings.Builder
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] == '"' {
+					end := indexRune(runes, i+1, '"')
+					if end < 0 {
+						return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidQuery)
+					}
+					word.WriteString(string(runes[i+1 : end]))
+					i = end + 1
+					continue
+				}
+				word.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, queryToken{tokWord, word.String()})
+		}
+	}
+	return tokens, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+type queryParser struct {
+	tokens   []queryToken
+	pos      int
+	now      time.Time
+	workflow *Workflow
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *queryParser) isOperator(word string) bool {
+	tok, ok := p.peek()
+	return ok && tok.kind == tokWord && tok.text == word
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []queryNode{left}
+	for p.isOperator("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, right)
+	}
+	if len(nodes) == 1 {
+		return left, nil
+	}
+	return orNode(nodes), nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	var nodes []queryNode
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokRParen || p.isOperator("OR") {
+			break
+		}
+		if p.isOperator("AND") {
+			p.pos++
+			continue
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	switch len(nodes) {
+	case 0:
+		if tok, ok := p.peek(); ok {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, tok.text)
+		}
+		return nil, fmt.Errorf("%w: expected a term", ErrInvalidQuery)
+	case 1:
+		return nodes[0], nil
+	default:
+		return andNode(nodes), nil
+	}
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	tok, _ := p.peek()
+	switch {
+	case tok.kind == tokWord && tok.text == "NOT":
+		p.pos++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	case tok.kind == tokWord && len(tok.text) > 1 && tok.text[0] == '-':
+		p.tokens[p.pos].text = tok.text[1:]
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{node}, nil
+	case tok.kind == tokLParen:
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if next, ok := p.peek(); !ok || next.kind != tokRParen {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidQuery)
+		}
+		p.pos++
+		return node, nil
+	case tok.kind == tokRParen:
+		return nil, fmt.Errorf("%w: unexpected )", ErrInvalidQuery)
+	}
+
+	p.pos++
+	if tok.kind == tokPhrase {
+		return textNode{fields: fullTextFields, text: strings.ToLower(tok.text)}, nil
+	}
+	field, value, ok := strings.Cut(tok.text, ":")
+	if !ok || !isQueryField(field) {
+		// Reject likely typos like stauts:open, but let URLs through as text
+		if ok && field != "" && !strings.HasPrefix(value, "//") {
+			return nil, fmt.Errorf("%w: unknown field %q (fields: %s)", ErrInvalidQuery, field, strings.Join(QueryFields, ", "))
+		}
+		return textNode{fields: fullTextFields, text: strings.ToLower(tok.text)}, nil
+	}
+	return p.parseQualifier(strings.ToLower(field), value)
+}
+
+func isQueryField(field string) bool {
+	return contains(QueryFields, strings.ToLower(field))
+}
+
+// parseQualifier builds the predicate for field:value.
+func (p *queryParser) parseQualifier(field, value string) (queryNode, error) {
+	if value == "" {
+		return nil, fmt.Errorf("%w: %s: needs a value", ErrInvalidQuery, field)
+	}
+
+	switch field {
+	case "title", "description", "notes", "design", "acceptance":
+		return textNode{fields: []string{field}, text: strings.ToLower(value)}, nil
+	case "priority":
+		op, operand := splitOperator(value)
+		n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(operand), "p"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: priority must be a number, got %q", ErrInvalidQuery, value)
+		}
+		return predicateNode(func(issue *Issue) bool { return compareInts(issue.Priority, op, n) }), nil
+	case "created", "updated", "closed":
+		return p.parseTimeQualifier(field, value)
+	}
+
+	values := strings.Split(value, ",")
+	var match func(issue *Issue, v string) bool
+	switch field {
+	case "id":
+		match = func(issue *Issue, v string) bool { return strings.EqualFold(issue.ID, v) }
+	case "status":
+		for _, v := range values {
+			if !IsValidStatus(IssueStatus(v)) && (p.workflow == nil || !p.workflow.IsValidStatus(IssueStatus(v))) {
+				return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidQuery, v)
+			}
+		}
+		match = func(issue *Issue, v string) bool { return string(issue.Status) == v }
+	case "type":
+		for _, v := range values {
+			if !IsValidIssueType(IssueType(v)) {
+				return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidQuery, v)
+			}
+		}
+		match = func(issue *Issue, v string) bool { return string(issue.IssueType) == v }
+	case "label":
+		match = func(issue *Issue, v string) bool {
+			for _, label := range issue.Labels {
+				if prefix, ok := strings.CutSuffix(v, "*"); ok && strings.HasPrefix(label, prefix) || label == v {
+					return true
+				}
+			}
+			return false
+		}
+	case "assignee":
+		match = func(issue *Issue, v string) bool {
+			switch v {
+			case "me":
+				return issue.Assignee != "" && issue.Assignee == ActorFunc()
+			case "none":
+				return issue.Assignee == ""
+			}
+			return strings.EqualFold(issue.Assignee, v)
+		}
+	case "spec":
+		match = func(issue *Issue, v string) bool { return issue.SpecContext == v }
+	case "parent":
+		match = func(issue *Issue, v string) bool {
+			if v == "none" {
+				return issue.ParentID == nil || *issue.ParentID == ""
+			}
+			return issue.ParentID != nil && strings.EqualFold(*issue.ParentID, v)
+		}
+	case "is":
+		now := p.now
+		for _, v := range values {
+			if v != "blocked" && v != "orphaned" && v != "claimed" {
+				return nil, fmt.Errorf("%w: is: must be blocked, orphaned or claimed, got %q", ErrInvalidQuery, v)
+			}
+		}
+		match = func(issue *Issue, v string) bool {
+			switch v {
+			case "blocked":
+				return len(issue.BlockedBy) > 0
+			case "orphaned":
+				return matchesFilter(issue, ListFilter{Orphaned: true})
+			default:
+				return issue.IsClaimed(now)
+			}
+		}
+	}
+
+	return predicateNode(func(issue *Issue) bool {
+		for _, v := range values {
+			if match(issue, v) {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// parseTimeQualifier handles created/updated/closed against a date or a duration ago.
+func (p *queryParser) parseTimeQualifier(field, value string) (queryNode, error) {
+	op, operand := splitOperator(value)
+
+	// [start, end) covers a whole day for dates; durations are a single instant
+	var start, end time.Time
+	if d, err := ParseRelativeDuration(operand); err == nil {
+		start = p.now.Add(-d)
+		end = start
+		if op == "=" {
+			op = ">="
+		}
+	} else if t, err := time.ParseInLocation("2006-01-02", operand, time.Local); err == nil {
+		start, end = t, t.AddDate(0, 0, 1)
+	} else if t, err := time.Parse(time.RFC3339, operand); err == nil {
+		start, end = t, t
+	} else {
+		return nil, fmt.Errorf("%w: %s: expected YYYY-MM-DD, RFC3339 or a duration like 7d, got %q", ErrInvalidQuery, field, operand)
+	}
+
+	return predicateNode(func(issue *Issue) bool {
+		var t time.Time
+		switch field {
+		case "created":
+			t = issue.CreatedAt
+		case "updated":
+			t = issue.UpdatedAt
+		case "closed":
+			if issue.ClosedAt == nil {
+				return false
+			}
+			t = *issue.ClosedAt
+		}
+
+		instant := start.Equal(end)
+		switch op {
+		case ">":
+			if instant {
+				return t.After(start)
+			}
+			return !t.Before(end)
+		case ">=":
+			return !t.Before(start)
+		case "<":
+			return t.Before(start)
+		case "<=":
+			if instant {
+				return !t.After(start)
+			}
+			return t.Before(end)
+		default:
+			if instant {
+				return t.Equal(start)
+			}
+			return !t.Before(start) && t.Before(end)
+		}
+	}), nil
+}
+
+// splitOperator separates a leading comparison operator; none means "=".
+func splitOperator(value string) (string, string) {
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if rest, ok := strings.CutPrefix(value, op); ok {
+			return op, rest
+		}
+	}
+	return "=", value
+}
+
+func compareInts(a int, op string, b int) bool {
+	switch op {
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	default:
+		return a == b
+	}
+}
+
+// fullTextFields are searched by bare words and phrases
+var fullTextFields = []string{"title", "description", "notes", "design", "acceptance"}
+
+type queryNode interface {
+	match(issue *Issue) bool
+}
+
+type andNode []queryNode
+
+func (n andNode) match(issue *Issue) bool {
+	for _, child := range n {
+		if !child.match(issue) {
+			return false
+		}
+	}
+	return true
+}
+
+type orNode []queryNode
+
+func (n orNode) match(issue *Issue) bool {
+	for _, child := range n {
+		if child.match(issue) {
+			return true
+		}
+	}
+	return false
+}
+
+type notNode struct {
+	node queryNode
+}
+
+func (n notNode) match(issue *Issue) bool {
+	return !n.node.match(issue)
+}
+
+type predicateNode func(issue *Issue) bool
+
+func (n predicateNode) match(issue *Issue) bool {
+	return n(issue)
+}
+
+// textNode matches a lowercase substring in any of the named text fields.
+type textNode struct {
+	fields []string
+	text   string
+}
+
+func (n textNode) match(issue *Issue) bool {
+	for _, field := range n.fields {
+		var value string
+		switch field {
+		case "title":
+			value = issue.Title
+		case "description":
+			value = issue.Description
+		case "notes":
+			value = issue.Notes
+		case "design":
+			value = issue.Design
+		case "acceptance":
+			value = issue.AcceptanceCriteria
+		}
+		if strings.Contains(strings.ToLower(value), n.text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package issues
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	NowFunc = func() time.Time { return now }
+	ActorFunc = func() string { return "alice" }
+	defer func() { NowFunc = time.Now; ActorFunc = defaultActor }()
+
+	parent := "SL-epic01"
+	closedAt := now.Add(-48 * time.Hour)
+	all := []Issue{
+		{ID: "SL-000001", Title: "Rate limit the API", Status: StatusOpen, IssueType: TypeTask, Priority: 1,
+			Labels: []string{"component:api"}, Assignee: "alice", SpecContext: "010-api",
+			CreatedAt: now.Add(-10 * 24 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour), ParentID: &parent},
+		{ID: "SL-000002", Title: "Fix login", Notes: "Retry on rate limit errors", Status: StatusInProgress, IssueType: TypeBug, Priority: 0,
+			Labels: []string{"component:auth", "security"}, SpecContext: "011-auth",
+			CreatedAt: now.Add(-30 * 24 * time.Hour), UpdatedAt: now.Add(-20 * 24 * time.Hour), BlockedBy: []string{"SL-000003"}},
+		{ID: "SL-000003", Title: "Write docs", Status: StatusClosed, IssueType: TypeTask, Priority: 3,
+			Assignee: "bob", SpecContext: "010-api",
+			CreatedAt: now.Add(-5 * 24 * time.Hour), UpdatedAt: closedAt, ClosedAt: &closedAt},
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", "SL-000001,SL-000002,SL-000003"},
+		{"rate", "SL-000001,SL-000002"},
+		{`"rate limit" status:open`, "SL-000001"},
+		{`notes:"rate limit"`, "SL-000002"},
+		{"status:open,in_progress", "SL-000001,SL-000002"},
+		{"label:component:*", "SL-000001,SL-000002"},
+		{"label:security OR assignee:bob", "SL-000002,SL-000003"},
+		{"assignee:me", "SL-000001"},
+		{"assignee:none", "SL-000002"},
+		{"priority:<=1 -is:blocked", "SL-000001"},
+		{"NOT (type:bug OR status:closed)", "SL-000001"},
+		{"updated:>7d", "SL-000001,SL-000003"},
+		{"updated:<7d", "SL-000002"},
+		{"created:2026-03-05", "SL-000003"},
+		{"closed:>=1w", "SL-000003"},
+		{"parent:SL-epic01 spec:010-api", "SL-000001"},
+		{"is:orphaned type:task", "SL-000003"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query, nil)
+			if err != nil {
+				t.Fatalf("ParseQuery() error: %v", err)
+			}
+			var ids []string
+			for _, issue := range q.Filter(all) {
+				ids = append(ids, issue.ID)
+			}
+			if got := strings.Join(ids, ","); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"stauts:open", "status:done", "(type:bug", "type:bug)", `"open`, "priority:high", "updated:>soon", "OR"} {
+		if _, err := ParseQuery(bad, nil); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("ParseQuery(%q) = %v, want ErrInvalidQuery", bad, err)
+		}
+	}
+
+	// Statuses come from the workflow passed in
+	workflow := &Workflow{Statuses: []WorkflowStatus{
+		{Name: StatusOpen, Category: CategoryTodo},
+		{Name: "review", Category: CategoryDoing},
+		{Name: StatusClosed, Category: CategoryDone},
+	}}
+	if _, err := ParseQuery("status:review", workflow); err != nil {
+		t.Errorf("ParseQuery(status:review) with a review status = %v, want nil", err)
+	}
+	if _, err := ParseQuery("status:review", nil); !errors.Is(err, ErrInvalidQuery) {
+		t.Errorf("ParseQuery(status:review) without a workflow = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	list := []Issue{
+		{ID: "SL-a", Priority: 2, UpdatedAt: base},
+		{ID: "SL-b", Priority: 1, UpdatedAt: base},
+		{ID: "SL-c", Priority: 2, UpdatedAt: base.Add(time.Hour)},
+	}
+
+	keys, err := ParseSortKeys("priority,-updated")
+	if err != nil {
+		t.Fatalf("ParseSortKeys() error: %v", err)
+	}
+	SortIssues(list, keys)
+	if got := list[0].ID + list[1].ID + list[2].ID; got != "SL-bSL-cSL-a" {
+		t.Errorf("unexpected order %s", got)
+	}
+
+	if _, err := ParseSortKeys("labels"); err == nil {
+		t.Error("expected error sorting by labels")
+	}
+	if _, err := ParseFieldList("id,bogus"); err == nil {
+		t.Error("expected error for unknown field")
+	}
+}
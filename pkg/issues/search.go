@@ -0,0 +1,169 @@
+package issues
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter returns the issues that match the query, in their original order.
+func (q *Query) Filter(issues []Issue) []Issue {
+	var result []Issue
+	for i := range issues {
+		if q.Match(&issues[i]) {
+			result = append(result, issues[i])
+		}
+	}
+	return result
+}
+
+// SearchFields lists the fields accepted by ParseSortKeys and IssueField
+var SearchFields = []string{
+	"id", "title", "status", "type", "priority", "spec", "assignee", "labels",
+	"parent", "created", "updated", "closed",
+}
+
+// SortKey orders issues by one field
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSortKeys parses a comma-separated sort spec such as "priority,-updated".
+// A leading "-" sorts that field in descending order.
+func ParseSortKeys(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := SortKey{Field: part}
+		if field, ok := strings.CutPrefix(part, "-"); ok {
+			key = SortKey{Field: field, Descending: true}
+		}
+		if !contains(SearchFields, key.Field) || key.Field == "labels" {
+			return nil, fmt.Errorf("cannot sort by %q (fields: %s)", key.Field, strings.Join(SearchFields, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SortIssues sorts issues by the keys in order, keeping the original order for ties.
+func SortIssues(issues []Issue, keys []SortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareField(&issues[i], &issues[j], key.Field)
+			if c == 0 {
+				continue
+			}
+			if key.Descending {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+func compareField(a, b *Issue, field string) int {
+	switch field {
+	case "priority":
+		return a.Priority - b.Priority
+	case "created":
+		return a.CreatedAt.Compare(b.CreatedAt)
+	case "updated":
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	case "closed":
+		// Open issues sort after closed ones
+		switch {
+		case a.ClosedAt == nil && b.ClosedAt == nil:
+			return 0
+		case a.ClosedAt == nil:
+			return 1
+		case b.ClosedAt == nil:
+			return -1
+		}
+		return a.ClosedAt.Compare(*b.ClosedAt)
+	}
+	return strings.Compare(fmt.Sprint(IssueField(*a, field)), fmt.Sprint(IssueField(*b, field)))
+}
+
+// ParseFieldList parses a comma-separated list of SearchFields.
+func ParseFieldList(spec string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !contains(SearchFields, field) {
+			return nil, fmt.Errorf("unknown field %q (fields: %s)", field, strings.Join(SearchFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// IssueField returns the value of one of the SearchFields, suitable for JSON.
+// Unset optional fields are returned as nil.
+func IssueField(issue Issue, field string) interface{} {
+	switch field {
+	case "id":
+		return issue.ID
+	case "title":
+		return issue.Title
+	case "status":
+		return string(issue.Status)
+	case "type":
+		return string(issue.IssueType)
+	case "priority":
+		return issue.Priority
+	case "spec":
+		return issue.SpecContext
+	case "assignee":
+		if issue.Assignee == "" {
+			return nil
+		}
+		return issue.Assignee
+	case "labels":
+		return issue.Labels
+	case "parent":
+		if issue.ParentID == nil {
+			return nil
+		}
+		return *issue.ParentID
+	case "created":
+		return issue.CreatedAt
+	case "updated":
+		return issue.UpdatedAt
+	case "closed":
+		if issue.ClosedAt == nil {
+			return nil
+		}
+		return *issue.ClosedAt
+	}
+	return nil
+}
+
+// FormatIssueField renders one of the SearchFields for a table cell.
+func FormatIssueField(issue Issue, field string) string {
+	switch v := IssueField(issue, field).(type) {
+	case nil:
+		return "-"
+	case time.Time:
+		return v.Local().Format("2006-01-02 15:04")
+	case []string:
+		if len(v) == 0 {
+			return "-"
+		}
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
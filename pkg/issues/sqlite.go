@@ -0,0 +1,329 @@
+package issues
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/flock"
+
+	// Pure-Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteSchema stores each issue as its JSON document, with the columns
+// needed for lookups alongside. rowid keeps creation order.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS issues (
+	id         TEXT PRIMARY KEY,
+	spec       TEXT NOT NULL,
+	status     TEXT NOT NULL,
+	updated_at TEXT NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS issues_spec ON issues(spec);
+`
+
+// sqliteDBs caches open databases by path; database/sql handles are safe
+// for concurrent use and meant to be long-lived.
+var (
+	sqliteMu  sync.Mutex
+	sqliteDBs = make(map[string]*sql.DB)
+)
+
+// openSQLite returns the database at path, creating it and its schema if needed.
+func openSQLite(path string) (*sql.DB, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve database path: %w", err)
+	}
+
+	sqliteMu.Lock()
+	defer sqliteMu.Unlock()
+	if db, ok := sqliteDBs[absPath]; ok {
+		return db, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(absPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+absPath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize %s: %w", path, err)
+	}
+
+	sqliteDBs[absPath] = db
+	return db, nil
+}
+
+// closeSQLite closes and forgets a cached database, e.g. before deleting it.
+func closeSQLite(path string) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	sqliteMu.Lock()
+	defer sqliteMu.Unlock()
+	db, ok := sqliteDBs[absPath]
+	if !ok {
+		return nil
+	}
+	delete(sqliteDBs, absPath)
+	return db.Close()
+}
+
+// sqliteBackend stores one spec's rows in the shared project database. Write
+// exclusion per spec uses a flock next to the spec's other files, as JSONL does.
+type sqliteBackend struct {
+	dbPath      string
+	specContext string
+	lock        *flock.Flock
+}
+
+func newSQLiteBackend(dbPath, basePath, specContext string) *sqliteBackend {
+	lockPath := filepath.Join(basePath, "issues.db.lock")
+	if specContext != "" {
+		lockPath = filepath.Join(basePath, specContext, "issues.db.lock")
+	}
+	return &sqliteBackend{dbPath: dbPath, specContext: specContext, lock: flock.New(lockPath)}
+}
+
+// Create inserts the issue.
+func (b *sqliteBackend) Create(issue *Issue) error {
+	db, err := openSQLite(b.dbPath)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(issue)
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue: %w", err)
+	}
+
+	_, err = db.Exec(`INSERT INTO issues (id, spec, status, updated_at, data) VALUES (?, ?, ?, ?, ?)`,
+		issue.ID, b.specContext, string(issue.Status), issue.UpdatedAt.UTC().Format(time.RFC3339Nano), string(data))
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return ErrIssueAlreadyExists
+		}
+		return fmt.Errorf("failed to insert issue: %w", err)
+	}
+	return nil
+}
+
+// Get loads one issue of the spec.
+func (b *sqliteBackend) Get(id string) (*Issue, error) {
+	db, err := openSQLite(b.dbPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var data string
+	err = db.QueryRow(`SELECT data FROM issues WHERE id = ? AND spec = ?`, id, b.specContext).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrIssueNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issue: %w", err)
+	}
+	return decodeIssueRow(data)
+}
+
+// List loads all issues of the spec in creation order.
+func (b *sqliteBackend) List() ([]*Issue, error) {
+	db, err := openSQLite(b.dbPath)
+	if err != nil {
+		return nil, err
+	}
+	return queryIssues(db, `SELECT data FROM issues WHERE spec = ? ORDER BY rowid`, b.specContext)
+}
+
+// Update rewrites the given rows in one transaction.
+func (b *sqliteBackend) Update(updated ...*Issue) error {
+	db, err := openSQLite(b.dbPath)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, issue := range updated {
+		data, err := json.Marshal(issue)
+		if err != nil {
+			return fmt.Errorf("failed to marshal issue: %w", err)
+		}
+		res, err := tx.Exec(`UPDATE issues SET status = ?, updated_at = ?, data = ? WHERE id = ? AND spec = ?`,
+			string(issue.Status), issue.UpdatedAt.UTC().Format(time.RFC3339Nano), string(data), issue.ID, b.specContext)
+		if err != nil {
+			return fmt.Errorf("failed to update issue: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: %s", ErrIssueNotFound, issue.ID)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes the given rows in one transaction.
+func (b *sqliteBackend) Delete(ids ...string) error {
+	db, err := openSQLite(b.dbPath)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, id := range ids {
+		res, err := tx.Exec(`DELETE FROM issues WHERE id = ? AND spec = ?`, id, b.specContext)
+		if err != nil {
+			return fmt.Errorf("failed to delete issue: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return fmt.Errorf("%w: %s", ErrIssueNotFound, id)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Lock takes the spec's flock without waiting.
+func (b *sqliteBackend) Lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(b.lock.Path()), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	locked, err := b.lock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+	if !locked {
+		return nil, ErrStoreLocked
+	}
+	return func() { _ = b.lock.Unlock() }, nil
+}
+
+// sqliteSpecs lists the specs that have issues in the database.
+func sqliteSpecs(dbPath string) ([]string, error) {
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`SELECT DISTINCT spec FROM issues ORDER BY spec`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list specs: %w", err)
+	}
+	defer rows.Close()
+
+	var specs []string
+	for rows.Next() {
+		var spec string
+		if err := rows.Scan(&spec); err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, rows.Err()
+}
+
+// sqliteListAll loads every issue in one query, ordered by spec then creation.
+func sqliteListAll(dbPath string) ([]*Issue, error) {
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	return queryIssues(db, `SELECT data FROM issues ORDER BY spec, rowid`)
+}
+
+// sqliteListBySpec loads every issue in one query, grouped by spec.
+func sqliteListBySpec(dbPath string) (map[string][]*Issue, error) {
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.Query(`SELECT spec, data FROM issues ORDER BY spec, rowid`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	defer rows.Close()
+
+	bySpec := make(map[string][]*Issue)
+	for rows.Next() {
+		var spec, data string
+		if err := rows.Scan(&spec, &data); err != nil {
+			return nil, err
+		}
+		issue, err := decodeIssueRow(data)
+		if err != nil {
+			return nil, err
+		}
+		bySpec[spec] = append(bySpec[spec], issue)
+	}
+	return bySpec, rows.Err()
+}
+
+// sqliteFind returns an issue and its spec by ID, across specs.
+func sqliteFind(dbPath, id string) (*Issue, string, error) {
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var spec, data string
+	err = db.QueryRow(`SELECT spec, data FROM issues WHERE id = ?`, id).Scan(&spec, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, "", ErrIssueNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query issue: %w", err)
+	}
+	issue, err := decodeIssueRow(data)
+	return issue, spec, err
+}
+
+func queryIssues(db *sql.DB, query string, args ...interface{}) ([]*Issue, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := []*Issue{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		issue, err := decodeIssueRow(data)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+	return issues, rows.Err()
+}
+
+func decodeIssueRow(data string) (*Issue, error) {
+	var issue Issue
+	if err := json.Unmarshal([]byte(data), &issue); err != nil {
+		return nil, fmt.Errorf("failed to decode issue: %w", err)
+	}
+	return &issue, nil
+}
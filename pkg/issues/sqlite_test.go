@@ -0,0 +1,195 @@
+package issues
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newTestStore opens a store with explicit storage under basePath.
+func newTestStore(t *testing.T, kind StorageKind, basePath, spec string) *Store {
+	t.Helper()
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: spec, Storage: kind})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+	return store
+}
+
+// useStorage makes cross-spec functions use kind for the duration of the test.
+func useStorage(t *testing.T, kind StorageKind) {
+	t.Helper()
+	StorageFunc = func(string) StorageKind { return kind }
+	t.Cleanup(func() { StorageFunc = func(string) StorageKind { return StorageJSONL } })
+}
+
+func TestSQLiteStore(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	useStorage(t, StorageSQLite)
+
+	store := newTestStore(t, StorageSQLite, basePath, "010-test")
+	other := newTestStore(t, StorageSQLite, basePath, "011-other")
+	if store.Path() != filepath.Join(basePath, SQLiteFileName) {
+		t.Errorf("unexpected path: %s", store.Path())
+	}
+
+	blocker := NewIssue("Blocker", "", "010-test", TypeTask, 1)
+	blocked := NewIssue("Blocked", "", "010-test", TypeTask, 2)
+	elsewhere := NewIssue("Elsewhere", "", "011-other", TypeBug, 0)
+	for _, c := range []struct {
+		store *Store
+		issue *Issue
+	}{{store, blocker}, {store, blocked}, {other, elsewhere}} {
+		if err := c.store.Create(c.issue); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+	if err := store.Create(blocker); !errors.Is(err, ErrIssueAlreadyExists) {
+		t.Errorf("expected ErrIssueAlreadyExists, got %v", err)
+	}
+	if err := store.AddDependency(blocker.ID, blocked.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+
+	// Specs are isolated within the shared database
+	if _, err := other.Get(blocker.ID); !errors.Is(err, ErrIssueNotFound) {
+		t.Errorf("expected ErrIssueNotFound from another spec, got %v", err)
+	}
+	list, err := store.List(ListFilter{})
+	if err != nil || len(list) != 2 || list[0].ID != blocker.ID {
+		t.Fatalf("List() = %v, %v", list, err)
+	}
+
+	ready, err := ListReadyAcrossSpecs(basePath, ListFilter{})
+	if err != nil {
+		t.Fatalf("ListReadyAcrossSpecs() error: %v", err)
+	}
+	if len(ready) != 2 || ready[0].Issue.ID != blocker.ID || ready[1].Issue.ID != elsewhere.ID {
+		t.Errorf("unexpected ready issues: %+v", ready)
+	}
+
+	issue, spec, err := GetIssueAcrossSpecs(elsewhere.ID, basePath)
+	if err != nil || spec != "011-other" || issue.Title != "Elsewhere" {
+		t.Errorf("GetIssueAcrossSpecs() = %v, %q, %v", issue, spec, err)
+	}
+
+	all, err := ListAllSpecs(basePath, ListFilter{})
+	if err != nil || len(all) != 3 {
+		t.Errorf("ListAllSpecs() returned %d issues, %v", len(all), err)
+	}
+
+	closed := StatusClosed
+	if _, err := store.Update(blocker.ID, IssueUpdate{Status: &closed}); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	ready, _ = store.ListReady(ListFilter{})
+	if len(ready) != 1 || ready[0].Issue.ID != blocked.ID {
+		t.Errorf("expected blocked issue to become ready, got %+v", ready)
+	}
+
+	if err := other.Delete(elsewhere.ID); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if specs, _ := listSpecDirs(basePath); len(specs) != 1 || specs[0] != "010-test" {
+		t.Errorf("unexpected specs after delete: %v", specs)
+	}
+}
+
+func TestMigrateStorageRoundTrip(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	for _, spec := range []string{"010-test", "011-other"} {
+		if err := os.MkdirAll(filepath.Join(basePath, spec), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jsonl := newTestStore(t, StorageJSONL, basePath, "010-test")
+	parent := NewIssue("Epic", "", "010-test", TypeEpic, 1)
+	child := NewIssue("Task", "", "010-test", TypeTask, 2)
+	child.ParentID = &parent.ID
+	for _, issue := range []*Issue{parent, child} {
+		if err := jsonl.Create(issue); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+	if err := newTestStore(t, StorageJSONL, basePath, "011-other").Create(NewIssue("Other", "", "011-other", TypeTask, 2)); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	before, _ := jsonl.List(ListFilter{})
+
+	result, err := MigrateStorage(basePath, StorageJSONL, StorageSQLite)
+	if err != nil {
+		t.Fatalf("MigrateStorage() to sqlite error: %v", err)
+	}
+	if result.TotalIssues != 3 || result.SpecDistribution["010-test"] != 2 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if _, err := os.Stat(jsonl.Path()); !os.IsNotExist(err) {
+		t.Error("expected issues.jsonl to be removed")
+	}
+
+	after, _ := newTestStore(t, StorageSQLite, basePath, "010-test").List(ListFilter{})
+	if len(after) != 2 || after[1].ID != child.ID || *after[1].ParentID != parent.ID || !after[0].CreatedAt.Equal(before[0].CreatedAt) {
+		t.Errorf("issues not preserved: %+v", after)
+	}
+
+	// Migrating into storage that already has the spec's issues is refused
+	if err := newTestStore(t, StorageJSONL, basePath, "010-test").Create(NewIssue("Stray", "", "010-test", TypeTask, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MigrateStorage(basePath, StorageSQLite, StorageJSONL); !errors.Is(err, ErrStorageNotEmpty) {
+		t.Fatalf("expected ErrStorageNotEmpty, got %v", err)
+	}
+	if err := os.Remove(jsonl.Path()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := MigrateStorage(basePath, StorageSQLite, StorageJSONL); err != nil {
+		t.Fatalf("MigrateStorage() to jsonl error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(basePath, SQLiteFileName)); !os.IsNotExist(err) {
+		t.Error("expected issues.db to be removed")
+	}
+	back, _ := jsonl.List(ListFilter{})
+	if len(back) != 2 || back[0].ID != parent.ID || back[1].ID != child.ID {
+		t.Errorf("round trip lost issues: %+v", back)
+	}
+}
+
+func TestMigrateStorageFailureLeavesDestinationEmpty(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	for _, spec := range []string{"010-test", "011-other"} {
+		if err := os.MkdirAll(filepath.Join(basePath, spec), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := newTestStore(t, StorageJSONL, basePath, "010-test").Create(NewIssue("Copied", "", "010-test", TypeTask, 2)); err != nil {
+		t.Fatal(err)
+	}
+	// A duplicated line makes the second copy of the issue fail
+	other := newTestStore(t, StorageJSONL, basePath, "011-other")
+	dup := NewIssue("Duplicated", "", "011-other", TypeTask, 2)
+	for i := 0; i < 2; i++ {
+		if err := other.backend.Create(dup); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := MigrateStorage(basePath, StorageJSONL, StorageSQLite); err == nil {
+		t.Fatal("expected MigrateStorage() to fail on the duplicated issue")
+	}
+	for _, spec := range []string{"010-test", "011-other"} {
+		if left, _ := newTestStore(t, StorageSQLite, basePath, spec).List(ListFilter{}); len(left) != 0 {
+			t.Errorf("%s: %d issue(s) left in sqlite after the failed migration", spec, len(left))
+		}
+	}
+
+	// Once the source is fixed, the migration can be rerun
+	if err := other.backend.Delete(dup.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := MigrateStorage(basePath, StorageJSONL, StorageSQLite); err != nil {
+		t.Fatalf("rerun MigrateStorage() error: %v", err)
+	}
+}
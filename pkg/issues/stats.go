@@ -0,0 +1,401 @@
+package issues
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"time"
+)
+
+// StatsOptions controls how flow metrics are computed
+type StatsOptions struct {
+	Now      time.Time // Reference time; defaults to NowFunc()
+	Weeks    int       // Number of weekly buckets ending with the current week (default 8)
+	Workflow *Workflow // Categorizes statuses; defaults to WorkflowFunc("")
+}
+
+// DurationSummary summarizes a set of durations, expressed in hours for JSON consumers
+type DurationSummary struct {
+	Count       int     `json:"count"`
+	MeanHours   float64 `json:"mean_hours"`
+	MedianHours float64 `json:"median_hours"`
+	P85Hours    float64 `json:"p85_hours"`
+	MaxHours    float64 `json:"max_hours"`
+}
+
+// IssueFlow holds per-issue flow metrics. Lead and cycle time are nil until the
+// issue is done (cycle time also requires a recorded move to a doing status).
+type IssueFlow struct {
+	ID             string      `json:"id"`
+	Title          string      `json:"title"`
+	SpecContext    string      `json:"spec_context"`
+	Status         IssueStatus `json:"status"`
+	LeadTimeHours  *float64    `json:"lead_time_hours,omitempty"`
+	CycleTimeHours *float64    `json:"cycle_time_hours,omitempty"`
+	BlockedHours   float64     `json:"blocked_hours"`
+}
+
+// WeeklyPoint is one week of flow data. Weeks start on Monday; WIP, Open and
+// Done are sampled at the end of the week (or now, for the current week). WIP
+// counts issues in doing statuses, Open those not yet done.
+type WeeklyPoint struct {
+	WeekStart time.Time `json:"week_start"`
+	Opened    int       `json:"opened"`
+	Closed    int       `json:"closed"` // Throughput
+	WIP       int       `json:"wip"`
+	Open      int       `json:"open"` // Remaining (burndown)
+	Done      int       `json:"done"` // Cumulative closed (burnup)
+}
+
+// Stats is the result of ComputeStats. Open, InProgress and Closed count the
+// issues in todo, doing and done statuses.
+type Stats struct {
+	GeneratedAt time.Time       `json:"generated_at"`
+	Total       int             `json:"total"`
+	Open        int             `json:"open"`
+	InProgress  int             `json:"in_progress"`
+	Closed      int             `json:"closed"`
+	LeadTime    DurationSummary `json:"lead_time"`
+	CycleTime   DurationSummary `json:"cycle_time"`
+	Weeks       []WeeklyPoint   `json:"weeks"`
+	Issues      []IssueFlow     `json:"issues"`
+}
+
+// statusChange is a point on an issue's status timeline
+type statusChange struct {
+	At     time.Time
+	Status IssueStatus
+}
+
+// blockerChange is a point on an issue's blocked_by timeline
+type blockerChange struct {
+	At        time.Time
+	BlockedBy []string
+}
+
+// ComputeStats derives lead time, cycle time, WIP, weekly throughput and
+// blocked time from issues and their recorded events. Issues created before the
+// event log existed fall back to CreatedAt, UpdatedAt and ClosedAt.
+func ComputeStats(issueList []Issue, events []Event, opts StatsOptions) *Stats {
+	now := opts.Now
+	if now.IsZero() {
+		now = NowFunc()
+	}
+	weeks := opts.Weeks
+	if weeks <= 0 {
+		weeks = 8
+	}
+	workflow := opts.Workflow
+	if workflow == nil {
+		workflow = WorkflowFunc("")
+	}
+
+	byIssue := make(map[string][]Event)
+	for _, e := range events {
+		byIssue[e.IssueID] = append(byIssue[e.IssueID], e)
+	}
+	for id := range byIssue {
+		list := byIssue[id]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Timestamp.Before(list[j].Timestamp) })
+	}
+
+	known := make(map[string]bool, len(issueList))
+	closedAt := make(map[string]time.Time, len(issueList))
+	for _, issue := range issueList {
+		known[issue.ID] = true
+		if t, ok := issueClosedAt(workflow, issue); ok {
+			closedAt[issue.ID] = t
+		}
+	}
+
+	stats := &Stats{GeneratedAt: now, Total: len(issueList)}
+	timelines := make(map[string][]statusChange, len(issueList))
+	var leadTimes, cycleTimes []time.Duration
+
+	for _, issue := range issueList {
+		switch workflow.Category(issue.Status) {
+		case CategoryTodo:
+			stats.Open++
+		case CategoryDoing:
+			stats.InProgress++
+		case CategoryDone:
+			stats.Closed++
+		}
+
+		timeline := statusTimeline(workflow, issue, byIssue[issue.ID])
+		timelines[issue.ID] = timeline
+
+		flow := IssueFlow{
+			ID:          issue.ID,
+			Title:       issue.Title,
+			SpecContext: issue.SpecContext,
+			Status:      issue.Status,
+		}
+
+		end, closed := closedAt[issue.ID]
+		if closed {
+			lead := end.Sub(issue.CreatedAt)
+			leadTimes = append(leadTimes, lead)
+			flow.LeadTimeHours = hoursPtr(lead)
+
+			for _, change := range timeline {
+				if workflow.Category(change.Status) == CategoryDoing {
+					cycle := end.Sub(change.At)
+					cycleTimes = append(cycleTimes, cycle)
+					flow.CycleTimeHours = hoursPtr(cycle)
+					break
+				}
+			}
+		} else {
+			end = now
+		}
+
+		blocked := blockedDuration(issue, byIssue[issue.ID], known, closedAt, end)
+		flow.BlockedHours = roundHours(blocked)
+
+		stats.Issues = append(stats.Issues, flow)
+	}
+
+	stats.LeadTime = summarizeDurations(leadTimes)
+	stats.CycleTime = summarizeDurations(cycleTimes)
+	stats.Weeks = weeklyPoints(workflow, issueList, timelines, closedAt, now, weeks)
+
+	return stats
+}
+
+// issueClosedAt returns when an issue was closed, falling back to UpdatedAt for
+// done issues without a closed_at timestamp.
+func issueClosedAt(workflow *Workflow, issue Issue) (time.Time, bool) {
+	if !workflow.IsDone(issue.Status) {
+		return time.Time{}, false
+	}
+	if issue.ClosedAt != nil {
+		return *issue.ClosedAt, true
+	}
+	return issue.UpdatedAt, true
+}
+
+// statusTimeline reconstructs the status history of an issue from its events.
+func statusTimeline(workflow *Workflow, issue Issue, events []Event) []statusChange {
+	timeline := []statusChange{{At: issue.CreatedAt, Status: workflow.StatusFor(CategoryTodo)}}
+
+	for _, e := range events {
+		for _, c := range e.Changes {
+			if c.Field != "status" {
+				continue
+			}
+			var status IssueStatus
+			if err := json.Unmarshal(c.New, &status); err != nil || status == "" {
+				continue
+			}
+			if e.Type == EventCreated {
+				timeline[0].Status = status
+				continue
+			}
+			timeline = append(timeline, statusChange{At: e.Timestamp, Status: status})
+		}
+	}
+
+	// Transitions made before the event log existed: assume the current status
+	// was entered at the last known change.
+	if last := timeline[len(timeline)-1]; last.Status != issue.Status {
+		at := issue.UpdatedAt
+		if t, ok := issueClosedAt(workflow, issue); ok {
+			at = t
+		}
+		if at.Before(last.At) {
+			at = last.At
+		}
+		timeline = append(timeline, statusChange{At: at, Status: issue.Status})
+	}
+
+	return timeline
+}
+
+// statusAt returns the status on a timeline at time t, or "" if the issue did
+// not exist yet.
+func statusAt(timeline []statusChange, t time.Time) IssueStatus {
+	var status IssueStatus
+	for _, change := range timeline {
+		if change.At.After(t) {
+			break
+		}
+		status = change.Status
+	}
+	return status
+}
+
+// blockerTimeline reconstructs the blocked_by history of an issue from its events.
+// Without recorded blocked_by changes the current blockers are assumed to have
+// applied since creation.
+func blockerTimeline(issue Issue, events []Event) []blockerChange {
+	var timeline []blockerChange
+	for _, e := range events {
+		for _, c := range e.Changes {
+			if c.Field != "blocked_by" {
+				continue
+			}
+			var ids []string
+			_ = json.Unmarshal(c.New, &ids)
+			if e.Type == EventCreated {
+				timeline = append(timeline, blockerChange{At: issue.CreatedAt, BlockedBy: ids})
+				continue
+			}
+			if len(timeline) == 0 {
+				var old []string
+				_ = json.Unmarshal(c.Old, &old)
+				timeline = append(timeline, blockerChange{At: issue.CreatedAt, BlockedBy: old})
+			}
+			timeline = append(timeline, blockerChange{At: e.Timestamp, BlockedBy: ids})
+		}
+	}
+
+	if len(timeline) == 0 {
+		timeline = append(timeline, blockerChange{At: issue.CreatedAt, BlockedBy: issue.BlockedBy})
+	}
+	return timeline
+}
+
+// blockedDuration sums the time between creation and end during which at least
+// one blocker was still open. Blockers outside the known set are ignored,
+// matching IsReady.
+func blockedDuration(issue Issue, events []Event, known map[string]bool, closedAt map[string]time.Time, end time.Time) time.Duration {
+	timeline := blockerTimeline(issue, events)
+
+	// Candidate boundaries: blocker set changes and blocker close times
+	points := []time.Time{issue.CreatedAt, end}
+	for _, change := range timeline {
+		points = append(points, change.At)
+		for _, id := range change.BlockedBy {
+			if t, ok := closedAt[id]; ok {
+				points = append(points, t)
+			}
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Before(points[j]) })
+
+	var total time.Duration
+	for i := 0; i+1 < len(points); i++ {
+		from, to := points[i], points[i+1]
+		if from.Before(issue.CreatedAt) {
+			from = issue.CreatedAt
+		}
+		if to.After(end) {
+			to = end
+		}
+		if !to.After(from) {
+			continue
+		}
+
+		var current []string
+		for _, change := range timeline {
+			if change.At.After(from) {
+				break
+			}
+			current = change.BlockedBy
+		}
+
+		for _, id := range current {
+			if !known[id] {
+				continue
+			}
+			if t, closed := closedAt[id]; !closed || t.After(from) {
+				total += to.Sub(from)
+				break
+			}
+		}
+	}
+
+	return total
+}
+
+// weeklyPoints buckets flow data by week, ending with the week containing now.
+func weeklyPoints(workflow *Workflow, issueList []Issue, timelines map[string][]statusChange, closedAt map[string]time.Time, now time.Time, weeks int) []WeeklyPoint {
+	current := startOfWeek(now)
+	points := make([]WeeklyPoint, 0, weeks)
+
+	for w := weeks - 1; w >= 0; w-- {
+		start := current.AddDate(0, 0, -7*w)
+		end := start.AddDate(0, 0, 7)
+		sample := end.Add(-time.Nanosecond)
+		if sample.After(now) {
+			sample = now
+		}
+
+		point := WeeklyPoint{WeekStart: start}
+		for _, issue := range issueList {
+			if !issue.CreatedAt.Before(start) && issue.CreatedAt.Before(end) {
+				point.Opened++
+			}
+			if t, ok := closedAt[issue.ID]; ok {
+				if !t.Before(start) && t.Before(end) {
+					point.Closed++
+				}
+				if !t.After(sample) {
+					point.Done++
+				}
+			}
+
+			status := statusAt(timelines[issue.ID], sample)
+			if status == "" {
+				continue
+			}
+			switch workflow.Category(status) {
+			case CategoryDoing:
+				point.WIP++
+				point.Open++
+			case CategoryTodo:
+				point.Open++
+			}
+		}
+		points = append(points, point)
+	}
+
+	return points
+}
+
+// startOfWeek returns midnight on the Monday of t's week, in t's location.
+func startOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	y, m, d := t.Date()
+	return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+}
+
+func summarizeDurations(durations []time.Duration) DurationSummary {
+	summary := DurationSummary{Count: len(durations)}
+	if len(durations) == 0 {
+		return summary
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+
+	summary.MeanHours = roundHours(sum / time.Duration(len(sorted)))
+	summary.MedianHours = roundHours(percentile(sorted, 50))
+	summary.P85Hours = roundHours(percentile(sorted, 85))
+	summary.MaxHours = roundHours(sorted[len(sorted)-1])
+	return summary
+}
+
+// percentile returns the nearest-rank percentile of sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func roundHours(d time.Duration) float64 {
+	return math.Round(d.Hours()*10) / 10
+}
+
+func hoursPtr(d time.Duration) *float64 {
+	h := roundHours(d)
+	return &h
+}
@@ -0,0 +1,156 @@
+package issues
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeStats(t *testing.T) {
+	store := setupTestStore(t)
+
+	// Monday
+	clock := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	NowFunc = func() time.Time { return clock }
+	defer func() { NowFunc = time.Now }()
+
+	create := func(id, title string) *Issue {
+		i := NewIssue(title, "", "010-test", TypeTask, 2)
+		i.ID = id
+		i.CreatedAt, i.UpdatedAt = clock, clock
+		if err := store.Create(i); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+		return i
+	}
+	setStatus := func(id string, status IssueStatus) {
+		if _, err := store.Update(id, IssueUpdate{Status: &status}); err != nil {
+			t.Fatalf("Update() error: %v", err)
+		}
+	}
+
+	blocker := create("SL-111111", "Blocker")
+	task := create("SL-222222", "Task")
+	create("SL-333333", "Untouched")
+	if err := store.AddDependency(blocker.ID, task.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+
+	clock = clock.Add(2 * time.Hour)
+	setStatus(blocker.ID, StatusInProgress)
+	clock = clock.Add(4 * time.Hour)
+	setStatus(blocker.ID, StatusClosed)
+
+	// A week later the task is picked up and finished
+	clock = clock.Add(7 * 24 * time.Hour)
+	setStatus(task.ID, StatusInProgress)
+	clock = clock.Add(10 * time.Hour)
+	setStatus(task.ID, StatusClosed)
+
+	issueList, err := store.List(ListFilter{})
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	events, err := ReadEvents(store.EventsPath())
+	if err != nil {
+		t.Fatalf("ReadEvents() error: %v", err)
+	}
+
+	stats := ComputeStats(issueList, events, StatsOptions{Now: clock, Weeks: 3})
+
+	if stats.Total != 3 || stats.Closed != 2 || stats.Open != 1 {
+		t.Errorf("unexpected counts: %+v", stats)
+	}
+
+	flows := make(map[string]IssueFlow)
+	for _, f := range stats.Issues {
+		flows[f.ID] = f
+	}
+
+	if f := flows[blocker.ID]; f.LeadTimeHours == nil || *f.LeadTimeHours != 6 || f.CycleTimeHours == nil || *f.CycleTimeHours != 4 {
+		t.Errorf("blocker: expected lead 6h, cycle 4h, got %+v", f)
+	}
+	if f := flows[task.ID]; f.CycleTimeHours == nil || *f.CycleTimeHours != 10 {
+		t.Errorf("task: expected cycle 10h, got %+v", f)
+	}
+	if f := flows[task.ID]; f.BlockedHours != 6 {
+		t.Errorf("task: expected 6h blocked, got %v", f.BlockedHours)
+	}
+	if f := flows["SL-333333"]; f.LeadTimeHours != nil || f.BlockedHours != 0 {
+		t.Errorf("open issue should have no lead time, got %+v", f)
+	}
+	if stats.CycleTime.Count != 2 || stats.CycleTime.MaxHours != 10 {
+		t.Errorf("unexpected cycle time summary: %+v", stats.CycleTime)
+	}
+
+	if len(stats.Weeks) != 3 {
+		t.Fatalf("expected 3 weeks, got %d", len(stats.Weeks))
+	}
+	first, second, third := stats.Weeks[0], stats.Weeks[1], stats.Weeks[2]
+	if first.Opened != 0 || first.Closed != 0 {
+		t.Errorf("expected empty first week, got %+v", first)
+	}
+	if second.Opened != 3 || second.Closed != 1 || second.Open != 2 || second.Done != 1 {
+		t.Errorf("unexpected second week: %+v", second)
+	}
+	if third.Closed != 1 || third.Open != 1 || third.Done != 2 || third.WIP != 0 {
+		t.Errorf("unexpected third week: %+v", third)
+	}
+}
+
+func TestComputeStatsWithoutEvents(t *testing.T) {
+	created := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	closed := created.Add(48 * time.Hour)
+
+	issueList := []Issue{
+		{ID: "SL-aaaaaa", Status: StatusClosed, CreatedAt: created, UpdatedAt: closed, ClosedAt: &closed},
+		{ID: "SL-bbbbbb", Status: StatusInProgress, CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+	}
+
+	stats := ComputeStats(issueList, nil, StatsOptions{Now: closed, Weeks: 1})
+	if stats.LeadTime.Count != 1 || stats.LeadTime.MeanHours != 48 {
+		t.Errorf("unexpected lead time: %+v", stats.LeadTime)
+	}
+	if stats.CycleTime.Count != 0 {
+		t.Errorf("cycle time requires a recorded transition, got %+v", stats.CycleTime)
+	}
+	if stats.Weeks[0].WIP != 1 {
+		t.Errorf("expected the in-progress issue to count as WIP, got %+v", stats.Weeks[0])
+	}
+}
+
+func TestComputeStatsCustomWorkflow(t *testing.T) {
+	workflow := &Workflow{Statuses: []WorkflowStatus{
+		{Name: "todo", Category: CategoryTodo},
+		{Name: "doing", Category: CategoryDoing},
+		{Name: "review", Category: CategoryDoing, Waiting: true},
+		{Name: "done", Category: CategoryDone},
+	}}
+	created := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	started := created.Add(2 * time.Hour)
+	done := created.Add(8 * time.Hour)
+
+	issueList := []Issue{
+		{ID: "SL-aaaaaa", Status: "done", CreatedAt: created, UpdatedAt: done, ClosedAt: &done},
+		{ID: "SL-bbbbbb", Status: "review", CreatedAt: created, UpdatedAt: started},
+		{ID: "SL-cccccc", Status: "todo", CreatedAt: created, UpdatedAt: created},
+	}
+	events := []Event{
+		{IssueID: "SL-aaaaaa", Type: EventUpdated, Timestamp: started, Changes: []FieldChange{
+			{Field: "status", Old: []byte(`"todo"`), New: []byte(`"doing"`)},
+		}},
+	}
+
+	stats := ComputeStats(issueList, events, StatsOptions{Now: done, Weeks: 1, Workflow: workflow})
+	if stats.Open != 1 || stats.InProgress != 1 || stats.Closed != 1 {
+		t.Errorf("counts = %d open, %d in progress, %d closed; want 1 each", stats.Open, stats.InProgress, stats.Closed)
+	}
+	if stats.LeadTime.Count != 1 || stats.LeadTime.MeanHours != 8 {
+		t.Errorf("unexpected lead time: %+v", stats.LeadTime)
+	}
+	if stats.CycleTime.Count != 1 || stats.CycleTime.MeanHours != 6 {
+		t.Errorf("unexpected cycle time: %+v", stats.CycleTime)
+	}
+	if week := stats.Weeks[0]; week.WIP != 1 || week.Open != 2 || week.Done != 1 {
+		t.Errorf("expected the issue in review to count as WIP, got %+v", week)
+	}
+}
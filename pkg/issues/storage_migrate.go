@@ -0,0 +1,154 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrStorageNotEmpty is returned when migrating into storage that already
+// holds issues for a spec being migrated.
+var ErrStorageNotEmpty = errors.New("destination storage already has issues")
+
+// StorageMigrationResult reports what MigrateStorage copied.
+type StorageMigrationResult struct {
+	From             StorageKind
+	To               StorageKind
+	TotalIssues      int
+	SpecDistribution map[string]int // spec -> count
+}
+
+// MigrateStorage copies every spec's issues from one storage backend to the
+// other and removes the source once all counts match. Issues are copied
+// verbatim: IDs, timestamps and dependencies are preserved and the event log,
+// which both backends keep as JSONL in the spec directory, is left untouched.
+// If copying fails, the issues already copied are removed from the
+// destination again, so the migration can simply be rerun.
+func MigrateStorage(basePath string, from, to StorageKind) (*StorageMigrationResult, error) {
+	if basePath == "" {
+		basePath = "specledger"
+	}
+	if from == to {
+		return nil, fmt.Errorf("issues are already stored as %s", to)
+	}
+
+	specs, err := listSpecs(from, basePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list specs: %w", err)
+	}
+
+	result := &StorageMigrationResult{
+		From:             from,
+		To:               to,
+		SpecDistribution: make(map[string]int),
+	}
+
+	type pair struct {
+		source, dest Backend
+		sourcePath   string
+		unlock       func()
+	}
+	var pairs []pair
+	defer func() {
+		for _, p := range pairs {
+			p.unlock()
+		}
+	}()
+
+	// Lock every spec in both storages before copying anything
+	for _, spec := range specs {
+		source, sourcePath, err := newBackend(from, basePath, spec)
+		if err != nil {
+			return nil, err
+		}
+		dest, _, err := newBackend(to, basePath, spec)
+		if err != nil {
+			return nil, err
+		}
+		unlockSource, err := source.Lock()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec, err)
+		}
+		unlockDest, err := dest.Lock()
+		if err != nil {
+			unlockSource()
+			return nil, fmt.Errorf("%s: %w", spec, err)
+		}
+		pairs = append(pairs, pair{source, dest, sourcePath, func() { unlockDest(); unlockSource() }})
+	}
+
+	for i, p := range pairs {
+		spec := specs[i]
+		existing, err := p.dest.List()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", spec, err)
+		}
+		if len(existing) > 0 {
+			return nil, fmt.Errorf("%w: %s has %d issues in %s", ErrStorageNotEmpty, spec, len(existing), to)
+		}
+	}
+
+	// IDs copied into each destination, removed again if the copy fails
+	copiedIDs := make([][]string, len(pairs))
+	rollback := func(err error) error {
+		for i, ids := range copiedIDs {
+			for _, id := range ids {
+				if derr := pairs[i].dest.Delete(id); derr != nil && !errors.Is(derr, ErrIssueNotFound) {
+					return fmt.Errorf("%w (removing copied issues from %s failed: %v)", err, to, derr)
+				}
+			}
+		}
+		return err
+	}
+
+	for i, p := range pairs {
+		spec := specs[i]
+		issues, err := p.source.List()
+		if err != nil {
+			return nil, rollback(fmt.Errorf("%s: %w", spec, err))
+		}
+		for _, issue := range issues {
+			if err := p.dest.Create(issue); err != nil {
+				return nil, rollback(fmt.Errorf("%s: failed to copy %s: %w", spec, issue.ID, err))
+			}
+			copiedIDs[i] = append(copiedIDs[i], issue.ID)
+		}
+
+		copied, err := p.dest.List()
+		if err != nil {
+			return nil, rollback(fmt.Errorf("%s: %w", spec, err))
+		}
+		if len(copied) != len(issues) {
+			return nil, rollback(fmt.Errorf("%s: copied %d of %d issues", spec, len(copied), len(issues)))
+		}
+
+		result.SpecDistribution[spec] = len(issues)
+		result.TotalIssues += len(issues)
+	}
+
+	// Everything is copied; remove the source
+	switch from {
+	case StorageSQLite:
+		dbPath := filepath.Join(basePath, SQLiteFileName)
+		if err := closeSQLite(dbPath); err != nil {
+			return nil, fmt.Errorf("failed to close %s: %w", dbPath, err)
+		}
+		for _, suffix := range []string{"", "-wal", "-shm"} {
+			if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to remove %s: %w", dbPath+suffix, err)
+			}
+		}
+		for _, spec := range specs {
+			_ = os.Remove(filepath.Join(basePath, spec, SQLiteFileName+".lock"))
+		}
+	default:
+		for _, p := range pairs {
+			if err := os.Remove(p.sourcePath); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to remove %s: %w", p.sourcePath, err)
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -8,11 +8,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/gofrs/flock"
 )
 
 // Store-related errors
@@ -23,18 +22,27 @@ var (
 	ErrSpecDirNotFound    = errors.New("spec directory not found")
 )
 
-// Store manages JSONL file operations with file locking
+// Store manages the issues of a spec on top of a storage Backend, with
+// cross-process locking for writes
 type Store struct {
-	path        string // Path to specledger directory
+	path        string // Path to the storage file (issues.jsonl or issues.db)
+	basePath    string // Artifact directory holding the spec directories
+	specDir     string // Spec directory holding the event log
 	specContext string // Current spec context (e.g., "010-my-feature")
-	lock        *flock.Flock
+	storage     StorageKind
+	backend     Backend
+	workflow    *Workflow
+	templates   map[string]*Template
 	mu          sync.Mutex
 }
 
 // StoreOptions contains options for creating a new Store
 type StoreOptions struct {
-	BasePath    string // Base path to specledger directory (default: "specledger")
-	SpecContext string // Spec context (e.g., "010-my-feature"), empty for cross-spec mode
+	BasePath    string               // Base path to specledger directory (default: "specledger")
+	SpecContext string               // Spec context (e.g., "010-my-feature"), empty for cross-spec mode
+	Storage     StorageKind          // Storage backend (default: StorageFunc(BasePath))
+	Workflow    *Workflow            // Statuses and transition rules (default: WorkflowFunc(BasePath))
+	Templates   map[string]*Template // Issue templates by name (default: TemplatesFunc(BasePath))
 }
 
 // NewStore creates a new issue store for a specific spec context
@@ -44,35 +52,60 @@ func NewStore(opts StoreOptions) (*Store, error) {
 		basePath = "specledger"
 	}
 
-	// If no spec context, create a store for cross-spec operations
-	if opts.SpecContext == "" {
-		return &Store{
-			path:        basePath,
-			specContext: "",
-			lock:        flock.New(filepath.Join(basePath, "issues.jsonl.lock")),
-		}, nil
+	storage := opts.Storage
+	if storage == "" {
+		storage = StorageFunc(basePath)
 	}
 
-	// Construct path to issues.jsonl
-	issuesPath := filepath.Join(basePath, opts.SpecContext, "issues.jsonl")
+	workflow := opts.Workflow
+	if workflow == nil {
+		workflow = WorkflowFunc(basePath)
+	}
 
-	// Create lock file path
-	lockPath := filepath.Join(basePath, opts.SpecContext, "issues.jsonl.lock")
+	templates := opts.Templates
+	if templates == nil {
+		templates = TemplatesFunc(basePath)
+	}
 
-	store := &Store{
-		path:        issuesPath,
-		specContext: opts.SpecContext,
-		lock:        flock.New(lockPath),
+	// Without a spec context the store is rooted at basePath for cross-spec operations
+	backend, path, err := newBackend(storage, basePath, opts.SpecContext)
+	if err != nil {
+		return nil, err
 	}
 
-	return store, nil
+	return &Store{
+		path:        path,
+		basePath:    basePath,
+		specDir:     filepath.Join(basePath, opts.SpecContext),
+		specContext: opts.SpecContext,
+		storage:     storage,
+		backend:     backend,
+		workflow:    workflow,
+		templates:   templates,
+	}, nil
 }
 
-// Path returns the path to the issues.jsonl file
+// Path returns the path to the storage file: the spec's issues.jsonl, or the
+// project database for SQLite storage
 func (s *Store) Path() string {
 	return s.path
 }
 
+// Storage returns the storage kind backing the store
+func (s *Store) Storage() StorageKind {
+	return s.storage
+}
+
+// Workflow returns the workflow whose statuses and rules the store enforces
+func (s *Store) Workflow() *Workflow {
+	return s.workflow
+}
+
+// Template returns the issue template with the given name, or nil
+func (s *Store) Template(name string) *Template {
+	return s.templates[name]
+}
+
 // Create creates a new issue in the store
 func (s *Store) Create(issue *Issue) error {
 	return s.WithLock(func() error {
@@ -111,8 +144,19 @@ func (s *Store) Create(issue *Issue) error {
 			}
 		}
 
-		// Validate issue
-		if err := issue.Validate(); err != nil {
+		// New issues start in the workflow's first todo status
+		if issue.Status == "" || (issue.Status == StatusOpen && !s.workflow.IsValidStatus(StatusOpen)) {
+			issue.Status = s.workflow.StatusFor(CategoryTodo)
+		}
+
+		// Validate issue, with its custom fields against its template
+		var template *Template
+		if issue.Template != "" {
+			if template = s.templates[issue.Template]; template == nil {
+				return fmt.Errorf("%w: %s", ErrUnknownTemplate, issue.Template)
+			}
+		}
+		if err := issue.ValidateTemplate(s.workflow, template); err != nil {
 			return err
 		}
 
@@ -125,49 +169,27 @@ func (s *Store) Create(issue *Issue) error {
 			return ErrIssueAlreadyExists
 		}
 
-		// Ensure directory exists
-		dir := filepath.Dir(s.path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
-		}
-
-		// Append to file
-		f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		if err != nil {
-			return fmt.Errorf("failed to open file: %w", err)
-		}
-		defer f.Close()
-
-		data, err := json.Marshal(issue)
-		if err != nil {
-			return fmt.Errorf("failed to marshal issue: %w", err)
+		if err := s.backend.Create(issue); err != nil {
+			return err
 		}
 
-		_, err = fmt.Fprintf(f, "%s\n", data)
-		return err
+		return s.recordEventUnlocked(EventCreated, nil, issue, "")
 	})
 }
 
-// Get retrieves an issue by ID
+// Get retrieves an issue by ID, including archived issues
 func (s *Store) Get(id string) (*Issue, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.getByIDUnlocked(id)
+	issue, err := s.getByIDUnlocked(id)
+	if errors.Is(err, ErrIssueNotFound) {
+		return s.getArchived(id)
+	}
+	return issue, err
 }
 
 func (s *Store) getByIDUnlocked(id string) (*Issue, error) {
-	issues, err := s.readAllUnlocked()
-	if err != nil {
-		return nil, err
-	}
-
-	for _, issue := range issues {
-		if issue.ID == id {
-			return issue, nil
-		}
-	}
-
-	return nil, ErrIssueNotFound
+	return s.backend.Get(id)
 }
 
 // List returns issues matching the filter
@@ -180,9 +202,17 @@ func (s *Store) List(filter ListFilter) ([]Issue, error) {
 		return nil, err
 	}
 
+	if filter.IncludeArchived {
+		archived, err := ReadArchive(s.ArchivePath())
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, archived...)
+	}
+
 	var result []Issue
 	for _, issue := range issues {
-		if s.matchesFilter(issue, filter) {
+		if matchesFilter(issue, filter) {
 			result = append(result, *issue)
 		}
 	}
@@ -199,18 +229,20 @@ func (s *Store) Update(id string, update IssueUpdate) (*Issue, error) {
 		}
 
 		var found *Issue
-		var foundIdx int
-		for i, issue := range issues {
+		for _, issue := range issues {
 			if issue.ID == id {
 				found = issue
-				foundIdx = i
 				break
 			}
 		}
 
 		if found == nil {
+			if _, err := s.getArchived(id); err == nil {
+				return nil, fmt.Errorf("%w: %s", ErrIssueArchived, id)
+			}
 			return nil, ErrIssueNotFound
 		}
+		before := copyIssue(found)
 
 		// Apply updates
 		if update.Title != nil {
@@ -221,10 +253,14 @@ func (s *Store) Update(id string, update IssueUpdate) (*Issue, error) {
 		}
 		if update.Status != nil {
 			found.Status = *update.Status
-			if *update.Status == StatusClosed && found.ClosedAt == nil {
+			if s.workflow.IsDone(*update.Status) && found.ClosedAt == nil {
 				now := NowFunc()
 				found.ClosedAt = &now
 			}
+			if s.workflow.IsDone(*update.Status) {
+				// Finished work no longer needs a claim
+				found.Lease = nil
+			}
 		}
 		if update.Priority != nil {
 			found.Priority = *update.Priority
@@ -235,6 +271,9 @@ func (s *Store) Update(id string, update IssueUpdate) (*Issue, error) {
 		if update.Assignee != nil {
 			found.Assignee = *update.Assignee
 		}
+		if update.Estimate != nil {
+			found.Estimate = *update.Estimate
+		}
 		if update.Notes != nil {
 			found.Notes = *update.Notes
 		}
@@ -254,6 +293,24 @@ func (s *Store) Update(id string, update IssueUpdate) (*Issue, error) {
 				}
 			}
 		}
+		for _, hash := range update.AddCommits {
+			if !contains(found.Commits, hash) {
+				found.Commits = append(found.Commits, hash)
+			}
+		}
+		for name, value := range update.Fields {
+			if value == "" {
+				delete(found.Fields, name)
+				continue
+			}
+			if found.Fields == nil {
+				found.Fields = make(map[string]string)
+			}
+			found.Fields[name] = value
+		}
+		if len(found.Fields) == 0 {
+			found.Fields = nil
+		}
 		if len(update.RemoveLabels) > 0 {
 			var newLabels []string
 			for _, label := range found.Labels {
@@ -320,16 +377,26 @@ func (s *Store) Update(id string, update IssueUpdate) (*Issue, error) {
 		// Update timestamp
 		found.UpdatedAt = NowFunc()
 
-		// Validate
-		if err := found.Validate(); err != nil {
+		// Validate, then apply the workflow rules once the whole update
+		// (including DoD changes) is in place. A status dropped from the
+		// workflow is kept until the issue moves.
+		workflow := s.workflow
+		if found.Status == before.Status && !workflow.IsValidStatus(found.Status) {
+			workflow = workflow.withStatus(found.Status)
+		}
+		// A template removed from the project no longer constrains its issues
+		if err := found.ValidateTemplate(workflow, s.templates[found.Template]); err != nil {
+			return nil, err
+		}
+		if err := s.workflow.CheckTransition(found, before.Status, found.Status); err != nil {
 			return nil, err
 		}
 
-		// Update in slice
-		issues[foundIdx] = found
+		if err := s.backend.Update(found); err != nil {
+			return nil, err
+		}
 
-		// Write all issues back
-		if err := s.writeAllUnlocked(issues); err != nil {
+		if err := s.recordEventUnlocked("", before, found, update.Reason); err != nil {
 			return nil, err
 		}
 
@@ -379,14 +446,11 @@ func (s *Store) GetChildren(parentID string) ([]Issue, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	locked, err := s.lock.TryLock()
+	unlock, err := s.backend.Lock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	if !locked {
-		return nil, ErrStoreLocked
+		return nil, err
 	}
-	defer func() { _ = s.lock.Unlock() }()
+	defer unlock()
 
 	issues, err := s.readAllUnlocked()
 	if err != nil {
@@ -416,144 +480,52 @@ func (s *Store) GetChildren(parentID string) ([]Issue, error) {
 // Delete removes an issue from the store
 func (s *Store) Delete(id string) error {
 	return s.WithLock(func() error {
-		issues, err := s.readAllUnlocked()
+		deleted, err := s.getByIDUnlocked(id)
 		if err != nil {
 			return err
 		}
 
-		var newIssues []*Issue
-		var found bool
-		for _, issue := range issues {
-			if issue.ID == id {
-				found = true
-				continue
-			}
-			newIssues = append(newIssues, issue)
-		}
-
-		if !found {
-			return ErrIssueNotFound
+		if err := s.backend.Delete(id); err != nil {
+			return err
 		}
 
-		return s.writeAllUnlocked(newIssues)
+		return s.recordEventUnlocked(EventDeleted, deleted, nil, "")
 	})
 }
 
-// WithLock executes a function while holding the file lock
+// WithLock executes a function while holding the backend's write lock
 func (s *Store) WithLock(fn func() error) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	locked, err := s.lock.TryLock()
+	unlock, err := s.backend.Lock()
 	if err != nil {
-		return fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	if !locked {
-		return ErrStoreLocked
+		return err
 	}
-	defer func() { _ = s.lock.Unlock() }()
+	defer unlock()
 
 	return fn()
 }
 
-// WithLockResult executes a function while holding the file lock and returns a result
+// WithLockResult executes a function while holding the backend's write lock and returns a result
 func (s *Store) WithLockResult(fn func() (*Issue, error)) (*Issue, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	locked, err := s.lock.TryLock()
+	unlock, err := s.backend.Lock()
 	if err != nil {
-		return nil, fmt.Errorf("failed to acquire lock: %w", err)
-	}
-	if !locked {
-		return nil, ErrStoreLocked
+		return nil, err
 	}
-	defer func() { _ = s.lock.Unlock() }()
+	defer unlock()
 
 	return fn()
 }
 
 func (s *Store) readAllUnlocked() ([]*Issue, error) {
-	f, err := os.Open(s.path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []*Issue{}, nil
-		}
-		return nil, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer f.Close()
-
-	var issues []*Issue
-	scanner := bufio.NewScanner(f)
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
-		}
-
-		var issue Issue
-		if err := json.Unmarshal([]byte(line), &issue); err != nil {
-			// Log warning but continue - skip invalid lines
-			// In production, we might want to log this
-			continue
-		}
-
-		issues = append(issues, &issue)
-	}
-
-	if err := scanner.Err(); err != nil && err != io.EOF {
-		return nil, fmt.Errorf("error reading file: %w", err)
-	}
-
-	return issues, nil
-}
-
-func (s *Store) writeAllUnlocked(issues []*Issue) error {
-	// Write to temp file first
-	tmpPath := s.path + ".tmp"
-	f, err := os.Create(tmpPath)
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-
-	writer := bufio.NewWriter(f)
-	for _, issue := range issues {
-		data, err := json.Marshal(issue)
-		if err != nil {
-			f.Close()
-			os.Remove(tmpPath)
-			return fmt.Errorf("failed to marshal issue: %w", err)
-		}
-		if _, err := fmt.Fprintf(writer, "%s\n", data); err != nil {
-			f.Close()
-			os.Remove(tmpPath)
-			return fmt.Errorf("failed to write issue: %w", err)
-		}
-	}
-
-	if err := writer.Flush(); err != nil {
-		f.Close()
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to flush writer: %w", err)
-	}
-
-	if err := f.Close(); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to close file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, s.path); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("failed to rename file: %w", err)
-	}
-
-	return nil
+	return s.backend.List()
 }
 
-func (s *Store) matchesFilter(issue *Issue, filter ListFilter) bool {
+func matchesFilter(issue *Issue, filter ListFilter) bool {
 	if filter.Status != nil && issue.Status != *filter.Status {
 		return false
 	}
@@ -566,6 +538,11 @@ func (s *Store) matchesFilter(issue *Issue, filter ListFilter) bool {
 	if filter.Blocked && len(issue.BlockedBy) == 0 {
 		return false
 	}
+	for name, value := range filter.Fields {
+		if issue.Fields[name] != value {
+			return false
+		}
+	}
 	if filter.Orphaned {
 		// Orphaned = non-epic issue without a parent
 		if issue.IssueType == TypeEpic {
@@ -602,6 +579,28 @@ func ListAllSpecs(basePath string, filter ListFilter) ([]Issue, error) {
 		basePath = "specledger"
 	}
 
+	// SQLite answers in one query instead of opening each spec
+	if StorageFunc(basePath) == StorageSQLite {
+		issues, err := sqliteListAll(filepath.Join(basePath, SQLiteFileName))
+		if err != nil {
+			return nil, err
+		}
+		var allIssues []Issue
+		for _, issue := range issues {
+			if matchesFilter(issue, filter) {
+				allIssues = append(allIssues, *issue)
+			}
+		}
+		if filter.IncludeArchived {
+			archived, err := listArchived(basePath, filter)
+			if err != nil {
+				return nil, err
+			}
+			allIssues = append(allIssues, archived...)
+		}
+		return allIssues, nil
+	}
+
 	// Get all spec directories
 	specs, err := listSpecDirs(basePath)
 	if err != nil {
@@ -629,8 +628,32 @@ func ListAllSpecs(basePath string, filter ListFilter) ([]Issue, error) {
 	return allIssues, nil
 }
 
-// listSpecDirs lists all spec directories in the base path
+// ListSpecs returns the specs under basePath that have issues
+func ListSpecs(basePath string) ([]string, error) {
+	if basePath == "" {
+		basePath = "specledger"
+	}
+	return listSpecDirs(basePath)
+}
+
+// listSpecDirs lists all spec directories in the base path that have issues
 func listSpecDirs(basePath string) ([]string, error) {
+	return listSpecs(StorageFunc(basePath), basePath)
+}
+
+// listSpecs lists the specs that have issues in the given storage
+func listSpecs(kind StorageKind, basePath string) ([]string, error) {
+	if kind == StorageSQLite {
+		dbPath := filepath.Join(basePath, SQLiteFileName)
+		if _, err := os.Stat(dbPath); err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return sqliteSpecs(dbPath)
+	}
+
 	entries, err := os.ReadDir(basePath)
 	if err != nil {
 		return nil, err
@@ -655,12 +678,64 @@ func listSpecDirs(basePath string) ([]string, error) {
 	return specs, nil
 }
 
+// LastModified returns the latest modification time of the files backing a
+// spec's issues, or of all specs when specContext is empty. Watchers poll it
+// to notice writes from other processes. Missing files are ignored.
+func LastModified(basePath, specContext string) (time.Time, error) {
+	if basePath == "" {
+		basePath = "specledger"
+	}
+
+	var paths []string
+	if StorageFunc(basePath) == StorageSQLite {
+		dbPath := filepath.Join(basePath, SQLiteFileName)
+		paths = []string{dbPath, dbPath + "-wal"}
+	} else if specContext != "" {
+		paths = []string{filepath.Join(basePath, specContext, "issues.jsonl")}
+	} else {
+		specs, err := listSpecDirs(basePath)
+		if err != nil && !os.IsNotExist(err) {
+			return time.Time{}, err
+		}
+		for _, spec := range specs {
+			paths = append(paths, filepath.Join(basePath, spec, "issues.jsonl"))
+		}
+	}
+
+	var latest time.Time
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return time.Time{}, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
 // GetIssueAcrossSpecs searches for an issue across all specs
 func GetIssueAcrossSpecs(id, basePath string) (*Issue, string, error) {
 	if basePath == "" {
 		basePath = "specledger"
 	}
 
+	if StorageFunc(basePath) == StorageSQLite {
+		dbPath := filepath.Join(basePath, SQLiteFileName)
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			return findArchived(basePath, id)
+		}
+		issue, spec, err := sqliteFind(dbPath, id)
+		if errors.Is(err, ErrIssueNotFound) {
+			return findArchived(basePath, id)
+		}
+		return issue, spec, err
+	}
+
 	specs, err := listSpecDirs(basePath)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to list spec directories: %w", err)
@@ -691,7 +766,8 @@ type ReadyIssue struct {
 }
 
 // ListReady returns all issues that are ready to work on (not blocked by open dependencies).
-// Ready issues have status open or in_progress and all their blockers are closed.
+// Ready issues have status open or in_progress, all their blockers are closed,
+// and no active lease is held on them.
 func (s *Store) ListReady(filter ListFilter) ([]ReadyIssue, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -701,16 +777,26 @@ func (s *Store) ListReady(filter ListFilter) ([]ReadyIssue, error) {
 		return nil, err
 	}
 
+	return readyIssues(s.basePath, s.workflow, issues, filter, NowFunc()), nil
+}
+
+// readyIssues selects the ready issues of one spec.
+func readyIssues(basePath string, workflow *Workflow, issues []*Issue, filter ListFilter, now time.Time) []ReadyIssue {
 	// Build lookup map for dependency resolution
 	issueMap := make(map[string]*Issue)
 	for _, issue := range issues {
 		issueMap[issue.ID] = issue
 	}
+	withExternalBlockers(basePath, workflow, issues, issueMap)
 
 	var result []ReadyIssue
 	for _, issue := range issues {
 		// Check if ready
-		if !issue.IsReady(issueMap) {
+		if !workflow.IsReady(issue, issueMap) {
+			continue
+		}
+		// Claimed by an agent; expired leases count as ready again
+		if issue.IsClaimed(now) {
 			continue
 		}
 
@@ -736,7 +822,7 @@ func (s *Store) ListReady(filter ListFilter) ([]ReadyIssue, error) {
 		})
 	}
 
-	return result, nil
+	return result
 }
 
 // ListReadyAcrossSpecs returns ready issues across all spec directories.
@@ -745,6 +831,31 @@ func ListReadyAcrossSpecs(basePath string, filter ListFilter) ([]ReadyIssue, err
 		basePath = "specledger"
 	}
 
+	if StorageFunc(basePath) == StorageSQLite {
+		dbPath := filepath.Join(basePath, SQLiteFileName)
+		if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+			return nil, nil
+		}
+		bySpec, err := sqliteListBySpec(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		specs := make([]string, 0, len(bySpec))
+		for spec := range bySpec {
+			specs = append(specs, spec)
+		}
+		sort.Strings(specs)
+
+		// Dependencies resolve within a spec, as with per-spec stores
+		now := NowFunc()
+		workflow := WorkflowFunc(basePath)
+		var allReady []ReadyIssue
+		for _, spec := range specs {
+			allReady = append(allReady, readyIssues(basePath, workflow, bySpec[spec], filter, now)...)
+		}
+		return allReady, nil
+	}
+
 	specs, err := listSpecDirs(basePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list spec directories: %w", err)
@@ -786,16 +897,17 @@ func (s *Store) GetBlockedIssuesWithBlockers() ([]ReadyIssue, error) {
 	for _, issue := range issues {
 		issueMap[issue.ID] = issue
 	}
+	withExternalBlockers(s.basePath, s.workflow, issues, issueMap)
 
 	var result []ReadyIssue
 	for _, issue := range issues {
 		// Skip closed issues
-		if issue.Status == StatusClosed {
+		if s.workflow.IsDone(issue.Status) {
 			continue
 		}
 
 		// Check if blocked (has open blockers)
-		if issue.IsReady(issueMap) {
+		if s.workflow.IsReady(issue, issueMap) {
 			continue
 		}
 
@@ -843,6 +955,9 @@ func RepairIssuesFile(path string) (*RepairResult, error) {
 	}
 	defer f.Close()
 
+	// The file lives at <base>/<spec>/issues.jsonl
+	workflow := WorkflowFunc(filepath.Dir(filepath.Dir(path)))
+
 	var validIssues []Issue
 	scanner := bufio.NewScanner(f)
 	lineNum := 0
@@ -866,8 +981,12 @@ func RepairIssuesFile(path string) (*RepairResult, error) {
 			continue
 		}
 
-		// Validate issue
-		if err := issue.Validate(); err != nil {
+		// Validate issue, keeping built-in statuses the workflow has dropped
+		w := workflow
+		if !w.IsValidStatus(issue.Status) && IsValidStatus(issue.Status) {
+			w = w.withStatus(issue.Status)
+		}
+		if err := issue.ValidateWorkflow(w); err != nil {
 			result.InvalidLines++
 			result.SkippedLines = append(result.SkippedLines, SkippedLine{
 				LineNum: lineNum,
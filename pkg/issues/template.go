@@ -0,0 +1,258 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplatesDir is where projects keep issue templates, relative to the
+// project root. Each *.yaml file declares one template.
+const TemplatesDir = ".specledger/templates/issues"
+
+// FieldType is the type of a custom field declared by a template
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldEnum   FieldType = "enum"   // One of the field's declared values
+	FieldNumber FieldType = "number" // Decimal number
+	FieldDate   FieldType = "date"   // YYYY-MM-DD
+)
+
+// FieldDateLayout is the layout of date field values
+const FieldDateLayout = "2006-01-02"
+
+// Template errors
+var (
+	ErrInvalidTemplate = errors.New("invalid issue template")
+	ErrUnknownTemplate = errors.New("unknown issue template")
+	ErrInvalidField    = errors.New("invalid custom field")
+)
+
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// FieldDef declares a custom field of a template
+type FieldDef struct {
+	Name        string    `yaml:"name" json:"name"`
+	Type        FieldType `yaml:"type" json:"type"`
+	Values      []string  `yaml:"values,omitempty" json:"values,omitempty"` // Allowed values of an enum
+	Required    bool      `yaml:"required,omitempty" json:"required,omitempty"`
+	Default     string    `yaml:"default,omitempty" json:"default,omitempty"`
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// Template presets new issues and declares the custom fields they carry, e.g.
+//
+//	name: bug
+//	type: bug
+//	priority: 1
+//	labels: [bug]
+//	definition_of_done: [Regression test added]
+//	fields:
+//	  - {name: steps-to-reproduce, type: string, required: true}
+//	  - {name: severity, type: enum, values: [low, medium, high], default: medium}
+//	  - {name: found-on, type: date}
+type Template struct {
+	Name             string     `yaml:"name" json:"name"` // Defaults to the file name
+	Description      string     `yaml:"description,omitempty" json:"description,omitempty"`
+	IssueType        IssueType  `yaml:"type,omitempty" json:"type,omitempty"`
+	Priority         *int       `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Labels           []string   `yaml:"labels,omitempty" json:"labels,omitempty"`
+	DefinitionOfDone []string   `yaml:"definition_of_done,omitempty" json:"definition_of_done,omitempty"`
+	Fields           []FieldDef `yaml:"fields,omitempty" json:"fields,omitempty"`
+}
+
+// TemplatesFunc returns the issue templates of the project owning basePath,
+// keyed by name. The CLI replaces it to read TemplatesDir.
+var TemplatesFunc = func(basePath string) map[string]*Template {
+	return nil
+}
+
+// Field returns the declaration of a field, or nil
+func (t *Template) Field(name string) *FieldDef {
+	for i := range t.Fields {
+		if t.Fields[i].Name == name {
+			return &t.Fields[i]
+		}
+	}
+	return nil
+}
+
+// Check validates the template definition
+func (t *Template) Check() error {
+	if !fieldNamePattern.MatchString(t.Name) {
+		return fmt.Errorf("%w: name %q must be lowercase letters, digits, - or _", ErrInvalidTemplate, t.Name)
+	}
+	if t.IssueType != "" && !IsValidIssueType(t.IssueType) {
+		return fmt.Errorf("%w %s: %w", ErrInvalidTemplate, t.Name, ErrInvalidIssueType)
+	}
+	if t.Priority != nil && (*t.Priority < 0 || *t.Priority > 5) {
+		return fmt.Errorf("%w %s: %w", ErrInvalidTemplate, t.Name, ErrInvalidPriority)
+	}
+
+	seen := make(map[string]bool, len(t.Fields))
+	for _, f := range t.Fields {
+		if !fieldNamePattern.MatchString(f.Name) {
+			return fmt.Errorf("%w %s: field name %q must be lowercase letters, digits, - or _", ErrInvalidTemplate, t.Name, f.Name)
+		}
+		if seen[f.Name] {
+			return fmt.Errorf("%w %s: field %s is declared twice", ErrInvalidTemplate, t.Name, f.Name)
+		}
+		seen[f.Name] = true
+
+		switch f.Type {
+		case FieldString, FieldNumber, FieldDate:
+			if len(f.Values) > 0 {
+				return fmt.Errorf("%w %s: only enum fields take values (field %s)", ErrInvalidTemplate, t.Name, f.Name)
+			}
+		case FieldEnum:
+			if len(f.Values) == 0 {
+				return fmt.Errorf("%w %s: enum field %s declares no values", ErrInvalidTemplate, t.Name, f.Name)
+			}
+		default:
+			return fmt.Errorf("%w %s: field %s has unknown type %q (must be string, enum, number or date)", ErrInvalidTemplate, t.Name, f.Name, f.Type)
+		}
+		if f.Default != "" {
+			if err := f.CheckValue(f.Default); err != nil {
+				return fmt.Errorf("%w %s: default: %w", ErrInvalidTemplate, t.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckValue validates a value against the field's type
+func (f *FieldDef) CheckValue(value string) error {
+	switch f.Type {
+	case FieldEnum:
+		if !contains(f.Values, value) {
+			return fmt.Errorf("%w: %s must be one of %s, got %q", ErrInvalidField, f.Name, strings.Join(f.Values, ", "), value)
+		}
+	case FieldNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%w: %s must be a number, got %q", ErrInvalidField, f.Name, value)
+		}
+	case FieldDate:
+		if _, err := time.Parse(FieldDateLayout, value); err != nil {
+			return fmt.Errorf("%w: %s must be a date (YYYY-MM-DD), got %q", ErrInvalidField, f.Name, value)
+		}
+	}
+	return nil
+}
+
+// checkFields validates custom field values against the template: every
+// field must be declared, hold a value of its type, and required fields must
+// be set
+func (t *Template) checkFields(fields map[string]string) error {
+	for _, name := range sortedFieldNames(fields) {
+		f := t.Field(name)
+		if f == nil {
+			return fmt.Errorf("%w: %s is not declared by template %s", ErrInvalidField, name, t.Name)
+		}
+		if err := f.CheckValue(fields[name]); err != nil {
+			return err
+		}
+	}
+	for _, f := range t.Fields {
+		if _, ok := fields[f.Name]; f.Required && !ok {
+			return fmt.Errorf("%w: %s is required by template %s", ErrInvalidField, f.Name, t.Name)
+		}
+	}
+	return nil
+}
+
+// Apply presets a new issue from the template: its type, priority, labels,
+// definition of done and field defaults. Labels and DoD items are added to
+// the issue's own and field values already set are kept.
+func (t *Template) Apply(issue *Issue) {
+	issue.Template = t.Name
+	if t.IssueType != "" {
+		issue.IssueType = t.IssueType
+	}
+	if t.Priority != nil {
+		issue.Priority = *t.Priority
+	}
+	for _, label := range t.Labels {
+		if !contains(issue.Labels, label) {
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+	if len(t.DefinitionOfDone) > 0 {
+		if issue.DefinitionOfDone == nil {
+			issue.DefinitionOfDone = &DefinitionOfDone{}
+		}
+		for _, item := range t.DefinitionOfDone {
+			issue.DefinitionOfDone.Items = append(issue.DefinitionOfDone.Items, ChecklistItem{Item: item})
+		}
+	}
+	for _, f := range t.Fields {
+		if _, ok := issue.Fields[f.Name]; !ok && f.Default != "" {
+			if issue.Fields == nil {
+				issue.Fields = make(map[string]string)
+			}
+			issue.Fields[f.Name] = f.Default
+		}
+	}
+}
+
+// LoadTemplates reads the *.yaml templates in dir, keyed by name. A missing
+// directory holds no templates.
+func LoadTemplates(dir string) (map[string]*Template, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	templates := make(map[string]*Template, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		var t Template
+		if err := yaml.Unmarshal(data, &t); err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidTemplate, filepath.Base(path), err)
+		}
+		if t.Name == "" {
+			t.Name = strings.TrimSuffix(filepath.Base(path), ".yaml")
+		}
+		if err := t.Check(); err != nil {
+			return nil, err
+		}
+		if _, ok := templates[t.Name]; ok {
+			return nil, fmt.Errorf("%w: %s is declared twice", ErrInvalidTemplate, t.Name)
+		}
+		templates[t.Name] = &t
+	}
+	return templates, nil
+}
+
+// ParseFieldAssignment splits a "name=value" field assignment
+func ParseFieldAssignment(assignment string) (name, value string, err error) {
+	name, value, ok := strings.Cut(assignment, "=")
+	name = strings.TrimSpace(name)
+	if !ok || !fieldNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("%w: %q must be name=value", ErrInvalidField, assignment)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// sortedFieldNames returns the names of custom field values in order
+func sortedFieldNames(fields map[string]string) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
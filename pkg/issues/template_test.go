@@ -0,0 +1,133 @@
+package issues
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const bugTemplate = `type: bug
+priority: 1
+labels: [bug]
+definition_of_done: [Regression test added]
+fields:
+  - name: steps-to-reproduce
+    type: string
+    required: true
+  - name: severity
+    type: enum
+    values: [low, medium, high]
+    default: medium
+  - name: found-on
+    type: date
+  - name: affected-users
+    type: number
+`
+
+func TestLoadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bug.yaml"), []byte(bugTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates() error: %v", err)
+	}
+	bug := templates["bug"]
+	if bug == nil || bug.IssueType != TypeBug || len(bug.Fields) != 4 {
+		t.Fatalf("bug template = %+v", bug)
+	}
+
+	if templates, err := LoadTemplates(filepath.Join(dir, "missing")); err != nil || len(templates) != 0 {
+		t.Errorf("missing directory: %v, %v", templates, err)
+	}
+
+	invalid := []string{
+		"fields:\n  - {name: severity, type: enum}\n",
+		"fields:\n  - {name: size, type: color}\n",
+		"fields:\n  - {name: due, type: date, default: tomorrow}\n",
+		"fields:\n  - {name: Bad Name, type: string}\n",
+	}
+	for _, content := range invalid {
+		bad := t.TempDir()
+		if err := os.WriteFile(filepath.Join(bad, "bad.yaml"), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadTemplates(bad); !errors.Is(err, ErrInvalidTemplate) {
+			t.Errorf("LoadTemplates(%q) error = %v, want ErrInvalidTemplate", content, err)
+		}
+	}
+}
+
+func TestTemplateFields(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bug.yaml"), []byte(bugTemplate), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err := LoadTemplates(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplates() error: %v", err)
+	}
+
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	if err := os.MkdirAll(filepath.Join(basePath, "010-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: "010-test", Templates: templates})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+
+	issue := NewIssue("Login fails", "", "010-test", TypeTask, 2)
+	templates["bug"].Apply(issue)
+	if issue.IssueType != TypeBug || issue.Priority != 1 || issue.Fields["severity"] != "medium" {
+		t.Errorf("Apply() = type %s, priority %d, fields %v", issue.IssueType, issue.Priority, issue.Fields)
+	}
+	if issue.DefinitionOfDone == nil || len(issue.DefinitionOfDone.Items) != 1 {
+		t.Errorf("Apply() DoD = %+v, want the template's item", issue.DefinitionOfDone)
+	}
+
+	if err := store.Create(issue); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Create() without a required field: got %v, want ErrInvalidField", err)
+	}
+	issue.Fields["steps-to-reproduce"] = "Open /login"
+	if err := store.Create(issue); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	for _, fields := range []map[string]string{
+		{"severity": "blocker"},
+		{"found-on": "last week"},
+		{"affected-users": "many"},
+		{"browser": "firefox"},
+		{"steps-to-reproduce": ""},
+	} {
+		if _, err := store.Update(issue.ID, IssueUpdate{Fields: fields}); !errors.Is(err, ErrInvalidField) {
+			t.Errorf("Update(%v) error = %v, want ErrInvalidField", fields, err)
+		}
+	}
+	updated, err := store.Update(issue.ID, IssueUpdate{Fields: map[string]string{"severity": "high", "found-on": "2026-03-01", "affected-users": "120"}})
+	if err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if updated.Fields["severity"] != "high" || updated.Fields["found-on"] != "2026-03-01" {
+		t.Errorf("fields = %v", updated.Fields)
+	}
+
+	plain := NewIssue("Plain task", "", "010-test", TypeTask, 2)
+	if err := store.Create(plain); err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	list, err := store.List(ListFilter{Fields: map[string]string{"severity": "high"}})
+	if err != nil || len(list) != 1 || list[0].ID != issue.ID {
+		t.Errorf("List(severity=high) = %v, %v", list, err)
+	}
+
+	unknown := NewIssue("Other", "", "010-test", TypeTask, 2)
+	unknown.Template = "feature-request"
+	if err := store.Create(unknown); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("Create() with unknown template: got %v, want ErrUnknownTemplate", err)
+	}
+}
@@ -26,6 +26,8 @@ type TreeRenderOptions struct {
 	ShowType     bool // Show issue type (default: true)
 	ShowPriority bool // Show priority (default: true)
 	Color        bool // Use colors (default: true)
+
+	Workflow *Workflow // Status categories for indicators (default: DefaultWorkflow())
 }
 
 // DefaultTreeRenderOptions returns the default options
@@ -51,15 +53,16 @@ func NewTreeRenderer(opts TreeRenderOptions) *TreeRenderer {
 	return &TreeRenderer{options: opts}
 }
 
-// truncate truncates a string to the given length
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
+// Truncate shortens s to maxLen characters, ending in "..." when cut
+func Truncate(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
-		return s[:maxLen]
+		return string(runes[:maxLen])
 	}
-	return s[:maxLen-3] + "..."
+	return string(runes[:maxLen-3]) + "..."
 }
 
 // colorize applies color if enabled
@@ -122,26 +125,42 @@ func (r *TreeRenderer) formatType(issueType IssueType) string {
 	return r.colorize("["+indicator+"]", color)
 }
 
-// formatStatus returns a colored status indicator
+// formatStatus returns a colored status indicator for the status category.
+// Statuses other than the built-in ones are named after the indicator.
 func (r *TreeRenderer) formatStatus(status IssueStatus) string {
+	workflow := r.options.Workflow
+	if workflow == nil {
+		workflow = DefaultWorkflow()
+	}
+
 	var indicator string
 	var color string
 
-	switch status {
-	case StatusOpen:
-		indicator = "○"
-		color = colorGreen
-	case StatusInProgress:
-		indicator = "◐"
-		color = colorYellow
-	case StatusClosed:
-		indicator = "●"
-		color = colorGray
-	default:
+	ws, declared := workflow.Status(status)
+	switch {
+	case !declared && !IsValidStatus(status):
 		indicator = "?"
 		color = colorGray
+	case ws.Waiting:
+		indicator = "◑"
+		color = colorCyan
+	default:
+		switch workflow.Category(status) {
+		case CategoryTodo:
+			indicator = "○"
+			color = colorGreen
+		case CategoryDoing:
+			indicator = "◐"
+			color = colorYellow
+		case CategoryDone:
+			indicator = "●"
+			color = colorGray
+		}
 	}
 
+	if !IsValidStatus(status) {
+		indicator += " " + string(status)
+	}
 	return r.colorize(indicator, color)
 }
 
@@ -165,7 +184,7 @@ func (r *TreeRenderer) formatIssue(issue Issue) string {
 
 	// Title
 	sb.WriteString(" ")
-	sb.WriteString(truncate(issue.Title, r.options.TitleWidth))
+	sb.WriteString(Truncate(issue.Title, r.options.TitleWidth))
 
 	// Status indicator
 	if r.options.ShowStatus {
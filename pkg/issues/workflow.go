@@ -0,0 +1,271 @@
+package issues
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StatusCategory groups workflow statuses by how far along the work is
+type StatusCategory string
+
+const (
+	CategoryTodo  StatusCategory = "todo"  // Not started
+	CategoryDoing StatusCategory = "doing" // Started but not finished
+	CategoryDone  StatusCategory = "done"  // Finished; resolves blockers and sets closed_at
+)
+
+// WorkflowStatus is one status of a workflow
+type WorkflowStatus struct {
+	Name     IssueStatus
+	Category StatusCategory
+	Waiting  bool // Waiting on something outside the tracker (e.g. review); not listed as ready
+}
+
+// Workflow declares the statuses issues can have and how they may move
+// between them. Projects configure it under task_tracker.workflow in
+// specledger.yaml; DefaultWorkflow is used otherwise.
+type Workflow struct {
+	Statuses []WorkflowStatus
+
+	// Transitions lists the statuses each status may move to. A status
+	// without an entry may move to any status.
+	Transitions map[IssueStatus][]IssueStatus
+
+	// RequireCompleteDoD refuses moves into a done status while the
+	// definition of done has unchecked items.
+	RequireCompleteDoD bool
+}
+
+// Workflow errors
+var (
+	ErrInvalidWorkflow      = errors.New("invalid workflow")
+	ErrUnknownStatus        = errors.New("status is not defined by the workflow")
+	ErrTransitionNotAllowed = errors.New("status transition not allowed")
+	ErrDoDIncomplete        = errors.New("definition of done is incomplete")
+)
+
+// WorkflowFunc returns the workflow for the project owning basePath. The CLI
+// replaces it to read task_tracker.workflow from specledger.yaml.
+var WorkflowFunc = func(basePath string) *Workflow {
+	return DefaultWorkflow()
+}
+
+var statusNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// DefaultWorkflow returns the built-in open → in_progress → closed workflow
+// with unrestricted transitions
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Name: StatusOpen, Category: CategoryTodo},
+			{Name: StatusInProgress, Category: CategoryDoing},
+			{Name: StatusClosed, Category: CategoryDone},
+		},
+	}
+}
+
+// Check validates the workflow definition: unique lowercase status names,
+// known categories with at least one todo and one done status, and
+// transitions between declared statuses.
+func (w *Workflow) Check() error {
+	if len(w.Statuses) == 0 {
+		return fmt.Errorf("%w: no statuses declared", ErrInvalidWorkflow)
+	}
+
+	seen := make(map[IssueStatus]bool, len(w.Statuses))
+	categories := make(map[StatusCategory]bool)
+	for _, s := range w.Statuses {
+		if !statusNamePattern.MatchString(string(s.Name)) {
+			return fmt.Errorf("%w: status name %q must be lowercase letters, digits and underscores", ErrInvalidWorkflow, s.Name)
+		}
+		if seen[s.Name] {
+			return fmt.Errorf("%w: status %q declared twice", ErrInvalidWorkflow, s.Name)
+		}
+		seen[s.Name] = true
+
+		switch s.Category {
+		case CategoryTodo, CategoryDoing, CategoryDone:
+			categories[s.Category] = true
+		default:
+			return fmt.Errorf("%w: status %q has unknown category %q (want todo, doing or done)", ErrInvalidWorkflow, s.Name, s.Category)
+		}
+	}
+	if !categories[CategoryTodo] || !categories[CategoryDone] {
+		return fmt.Errorf("%w: at least one todo and one done status are required", ErrInvalidWorkflow)
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("%w: transition from undeclared status %q", ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("%w: transition %s → %s to undeclared status", ErrInvalidWorkflow, from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// Names returns the status names in declaration order
+func (w *Workflow) Names() []IssueStatus {
+	names := make([]IssueStatus, len(w.Statuses))
+	for i, s := range w.Statuses {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Status looks up a status by name
+func (w *Workflow) Status(name IssueStatus) (WorkflowStatus, bool) {
+	for _, s := range w.Statuses {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return WorkflowStatus{}, false
+}
+
+// IsValidStatus reports whether the workflow declares the status
+func (w *Workflow) IsValidStatus(name IssueStatus) bool {
+	_, ok := w.Status(name)
+	return ok
+}
+
+// Category returns the category of a status. Statuses the workflow does not
+// declare, e.g. left over from an earlier configuration, fall back to the
+// category of the built-in status of that name, or todo.
+func (w *Workflow) Category(name IssueStatus) StatusCategory {
+	if s, ok := w.Status(name); ok {
+		return s.Category
+	}
+	switch name {
+	case StatusInProgress:
+		return CategoryDoing
+	case StatusClosed:
+		return CategoryDone
+	default:
+		return CategoryTodo
+	}
+}
+
+// IsDone reports whether a status is in the done category
+func (w *Workflow) IsDone(name IssueStatus) bool {
+	return w.Category(name) == CategoryDone
+}
+
+// StatusFor returns the status used when work enters a category: the
+// built-in status of that category if declared, otherwise the first status
+// declared in it. For example, claiming moves issues to StatusFor(CategoryDoing).
+func (w *Workflow) StatusFor(category StatusCategory) IssueStatus {
+	builtin := map[StatusCategory]IssueStatus{
+		CategoryTodo:  StatusOpen,
+		CategoryDoing: StatusInProgress,
+		CategoryDone:  StatusClosed,
+	}[category]
+	if s, ok := w.Status(builtin); ok && s.Category == category {
+		return builtin
+	}
+	for _, s := range w.Statuses {
+		if s.Category == category {
+			return s.Name
+		}
+	}
+	return builtin
+}
+
+// AllowedTransitions returns the statuses an issue in from may move to
+func (w *Workflow) AllowedTransitions(from IssueStatus) []IssueStatus {
+	if targets, ok := w.Transitions[from]; ok {
+		return targets
+	}
+	var all []IssueStatus
+	for _, s := range w.Statuses {
+		if s.Name != from {
+			all = append(all, s.Name)
+		}
+	}
+	return all
+}
+
+// CanTransition reports whether an issue may move from one status to another
+func (w *Workflow) CanTransition(from, to IssueStatus) bool {
+	if from == to {
+		return true
+	}
+	targets, ok := w.Transitions[from]
+	if !ok {
+		return true
+	}
+	for _, t := range targets {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTransition applies the workflow rules to an issue moving from one
+// status to another. issue holds the state after the move.
+func (w *Workflow) CheckTransition(issue *Issue, from, to IssueStatus) error {
+	if from == to {
+		return nil
+	}
+	if !w.CanTransition(from, to) {
+		allowed := w.AllowedTransitions(from)
+		if len(allowed) == 0 {
+			return fmt.Errorf("%w: %s → %s (%s is final)", ErrTransitionNotAllowed, from, to, from)
+		}
+		return fmt.Errorf("%w: %s → %s (allowed: %s)", ErrTransitionNotAllowed, from, to, joinStatuses(allowed))
+	}
+	if w.RequireCompleteDoD && w.IsDone(to) && !issue.DefinitionOfDone.IsComplete() {
+		return fmt.Errorf("%w: %s has unchecked items: %s", ErrDoDIncomplete, issue.ID,
+			strings.Join(issue.DefinitionOfDone.GetUncheckedItems(), ", "))
+	}
+	return nil
+}
+
+// IsReady reports whether an issue can be worked on: it is not done, not in
+// a waiting status, and every blocker it references is done.
+func (w *Workflow) IsReady(issue *Issue, allIssues map[string]*Issue) bool {
+	if w.IsDone(issue.Status) {
+		return false
+	}
+	if s, ok := w.Status(issue.Status); ok && s.Waiting {
+		return false
+	}
+	for _, blockerID := range issue.BlockedBy {
+		blocker, exists := allIssues[blockerID]
+		if !exists {
+			// Blocker doesn't exist - treat as closed (can't block if not found)
+			continue
+		}
+		if !w.IsDone(blocker.Status) {
+			return false
+		}
+	}
+	return true
+}
+
+// withStatus returns a copy of the workflow that also declares name, in the
+// category Category reports for it
+func (w *Workflow) withStatus(name IssueStatus) *Workflow {
+	copied := *w
+	copied.Statuses = append(append([]WorkflowStatus(nil), w.Statuses...), WorkflowStatus{Name: name, Category: w.Category(name)})
+	return &copied
+}
+
+// unknownStatus describes a status the workflow does not declare
+func (w *Workflow) unknownStatus(name IssueStatus) error {
+	return fmt.Errorf("%w: %q (statuses: %s)", ErrUnknownStatus, name, joinStatuses(w.Names()))
+}
+
+func joinStatuses(statuses []IssueStatus) string {
+	names := make([]string, len(statuses))
+	for i, s := range statuses {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,144 @@
+package issues
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// reviewWorkflow is a team workflow with review and blocked states where only
+// review may move to closed
+func reviewWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Name: "todo", Category: CategoryTodo},
+			{Name: StatusInProgress, Category: CategoryDoing},
+			{Name: "blocked", Category: CategoryDoing, Waiting: true},
+			{Name: "review", Category: CategoryDoing, Waiting: true},
+			{Name: "done", Category: CategoryDone},
+		},
+		Transitions: map[IssueStatus][]IssueStatus{
+			"todo":           {StatusInProgress, "blocked"},
+			StatusInProgress: {"review", "blocked", "todo"},
+			"review":         {"done", StatusInProgress},
+			"done":           {},
+		},
+		RequireCompleteDoD: true,
+	}
+}
+
+func TestWorkflowStore(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), "specledger")
+	if err := os.MkdirAll(filepath.Join(basePath, "010-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewStore(StoreOptions{BasePath: basePath, SpecContext: "010-test", Workflow: reviewWorkflow()})
+	if err != nil {
+		t.Fatalf("NewStore() error: %v", err)
+	}
+
+	blocker := NewIssue("Blocker", "", "010-test", TypeTask, 1)
+	task := NewIssue("Task", "", "010-test", TypeTask, 1)
+	task.DefinitionOfDone = &DefinitionOfDone{Items: []ChecklistItem{{Item: "tests pass"}}}
+	task.CreatedAt = task.CreatedAt.Add(time.Second)
+	for _, issue := range []*Issue{blocker, task} {
+		if err := store.Create(issue); err != nil {
+			t.Fatalf("Create() error: %v", err)
+		}
+	}
+	if task.Status != "todo" {
+		t.Fatalf("new issue status = %s, want todo", task.Status)
+	}
+	if err := store.AddDependency(blocker.ID, task.ID, LinkBlocks); err != nil {
+		t.Fatalf("AddDependency() error: %v", err)
+	}
+
+	move := func(id string, status IssueStatus) error {
+		_, err := store.Update(id, IssueUpdate{Status: &status})
+		return err
+	}
+
+	if err := move(task.ID, "done"); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Errorf("todo → done: got %v, want ErrTransitionNotAllowed", err)
+	}
+	if err := move(task.ID, "shipped"); !errors.Is(err, ErrUnknownStatus) {
+		t.Errorf("unknown status: got %v, want ErrUnknownStatus", err)
+	}
+
+	// The blocker sits in review: waiting, and not done, so task is blocked too
+	if err := move(blocker.ID, StatusInProgress); err != nil {
+		t.Fatal(err)
+	}
+	if err := move(blocker.ID, "review"); err != nil {
+		t.Fatal(err)
+	}
+	ready, err := store.ListReady(ListFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ready) != 0 {
+		t.Errorf("ready = %d issues, want none while the blocker is in review", len(ready))
+	}
+	if err := move(blocker.ID, "done"); err != nil {
+		t.Fatal(err)
+	}
+	ready, _ = store.ListReady(ListFilter{})
+	if len(ready) != 1 || ready[0].Issue.ID != task.ID {
+		t.Errorf("ready = %v, want only %s once the blocker is done", ready, task.ID)
+	}
+	if got, _ := store.Get(blocker.ID); got.ClosedAt == nil {
+		t.Error("moving to a done status should set closed_at")
+	}
+
+	// Claiming moves a todo issue to the doing status
+	claimed, err := store.Claim(task.ID, "agent-1", time.Minute)
+	if err != nil {
+		t.Fatalf("Claim() error: %v", err)
+	}
+	if claimed.Status != StatusInProgress {
+		t.Errorf("claimed status = %s, want in_progress", claimed.Status)
+	}
+
+	// Closing requires a complete DoD, checked in the same update too
+	if err := move(task.ID, "review"); err != nil {
+		t.Fatal(err)
+	}
+	if err := move(task.ID, "done"); !errors.Is(err, ErrDoDIncomplete) {
+		t.Errorf("close with open DoD: got %v, want ErrDoDIncomplete", err)
+	}
+	done := IssueStatus("done")
+	if _, err := store.Update(task.ID, IssueUpdate{Status: &done, CheckDoDItem: "tests pass"}); err != nil {
+		t.Errorf("close with DoD checked: %v", err)
+	}
+	if err := move(task.ID, "todo"); !errors.Is(err, ErrTransitionNotAllowed) {
+		t.Errorf("done is final: got %v, want ErrTransitionNotAllowed", err)
+	}
+}
+
+func TestWorkflowCheck(t *testing.T) {
+	if err := DefaultWorkflow().Check(); err != nil {
+		t.Errorf("default workflow: %v", err)
+	}
+	if err := reviewWorkflow().Check(); err != nil {
+		t.Errorf("review workflow: %v", err)
+	}
+
+	bad := []*Workflow{
+		{},
+		{Statuses: []WorkflowStatus{{Name: "open", Category: CategoryTodo}}},
+		{Statuses: []WorkflowStatus{{Name: "open", Category: CategoryTodo}, {Name: "Done", Category: CategoryDone}}},
+		{Statuses: []WorkflowStatus{{Name: "open", Category: CategoryTodo}, {Name: "done", Category: "finished"}}},
+		{Statuses: []WorkflowStatus{{Name: "open", Category: CategoryTodo}, {Name: "open", Category: CategoryDone}}},
+		{
+			Statuses:    []WorkflowStatus{{Name: "open", Category: CategoryTodo}, {Name: "done", Category: CategoryDone}},
+			Transitions: map[IssueStatus][]IssueStatus{"open": {"review"}},
+		},
+	}
+	for i, w := range bad {
+		if err := w.Check(); !errors.Is(err, ErrInvalidWorkflow) {
+			t.Errorf("case %d: got %v, want ErrInvalidWorkflow", i, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// savedView mirrors one entry of `sl issue view list --json`.
+type savedView struct {
+	Name     string `json:"name"`
+	Scope    string `json:"scope"`
+	Shadowed bool   `json:"shadowed"`
+}
+
+// setupViewProject initializes a project with one bug and one task in spec 001-views.
+// HOME is pointed at a temp dir so private views never touch the real user config.
+func setupViewProject(t *testing.T) (slBinary, projectDir string) {
+	t.Helper()
+	slBinary = buildBinary(t)
+	t.Setenv("HOME", t.TempDir())
+
+	projectDir = filepath.Join(t.TempDir(), "views")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("Failed to create project dir: %v", err)
+	}
+	initProject(t, slBinary, projectDir)
+	if err := os.MkdirAll(filepath.Join(projectDir, "specledger", "001-views"), 0755); err != nil {
+		t.Fatalf("Failed to create spec dir: %v", err)
+	}
+
+	runSL(t, slBinary, projectDir, "issue", "create", "--title", "Crash on save", "--type", "bug", "-p", "1", "--spec", "001-views")
+	runSL(t, slBinary, projectDir, "issue", "create", "--title", "Write docs", "--type", "task", "-p", "2", "--spec", "001-views")
+	return slBinary, projectDir
+}
+
+// runSL runs sl in dir and fails the test if it exits non-zero.
+func runSL(t *testing.T, slBinary, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command(slBinary, args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("sl %s failed: %v\nOutput: %s", strings.Join(args, " "), err, string(output))
+	}
+	return string(output)
+}
+
+// listViewTitles runs `sl issue list @name --json` and returns the issue titles.
+func listViewTitles(t *testing.T, slBinary, dir, name string) []string {
+	t.Helper()
+	cmd := exec.Command(slBinary, "issue", "list", "@"+name, "--json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("sl issue list @%s failed: %v", name, err)
+	}
+	var list []struct {
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		t.Fatalf("Failed to parse issue list: %v\nOutput: %s", err, string(output))
+	}
+	titles := make([]string, 0, len(list))
+	for _, issue := range list {
+		titles = append(titles, issue.Title)
+	}
+	return titles
+}
+
+// listSavedViews runs `sl issue view list --json`.
+func listSavedViews(t *testing.T, slBinary, dir string) []savedView {
+	t.Helper()
+	cmd := exec.Command(slBinary, "issue", "view", "list", "--json")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("sl issue view list failed: %v", err)
+	}
+	var views []savedView
+	if err := json.Unmarshal(output, &views); err != nil {
+		t.Fatalf("Failed to parse view list: %v\nOutput: %s", err, string(output))
+	}
+	return views
+}
+
+// TestIssueViewSaveListDelete tests the save, list, run and delete round trip of a private view.
+func TestIssueViewSaveListDelete(t *testing.T) {
+	slBinary, projectDir := setupViewProject(t)
+
+	runSL(t, slBinary, projectDir, "issue", "view", "save", "bugs", "--type", "bug", "--all")
+
+	views := listSavedViews(t, slBinary, projectDir)
+	if len(views) != 1 || views[0].Name != "bugs" || views[0].Scope != "private" {
+		t.Fatalf("Expected one private view 'bugs', got %+v", views)
+	}
+
+	titles := listViewTitles(t, slBinary, projectDir, "bugs")
+	if len(titles) != 1 || titles[0] != "Crash on save" {
+		t.Errorf("Expected @bugs to list only the bug, got %v", titles)
+	}
+
+	// Private views must stay out of the committed project config
+	projectConfig, err := os.ReadFile(filepath.Join(projectDir, "specledger", "specledger.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read specledger.yaml: %v", err)
+	}
+	if strings.Contains(string(projectConfig), "bugs") {
+		t.Errorf("Private view leaked into specledger.yaml:\n%s", string(projectConfig))
+	}
+
+	runSL(t, slBinary, projectDir, "issue", "view", "delete", "bugs")
+	if views := listSavedViews(t, slBinary, projectDir); len(views) != 0 {
+		t.Errorf("Expected no views after delete, got %+v", views)
+	}
+}
+
+// TestIssueViewPrivateOverridesTeam tests that a private view shadows a team view of the same name.
+func TestIssueViewPrivateOverridesTeam(t *testing.T) {
+	slBinary, projectDir := setupViewProject(t)
+
+	runSL(t, slBinary, projectDir, "issue", "view", "save", "triage", "--team", "--type", "task", "--all")
+	runSL(t, slBinary, projectDir, "issue", "view", "save", "triage", "--type", "bug", "--all")
+
+	views := listSavedViews(t, slBinary, projectDir)
+	if len(views) != 2 {
+		t.Fatalf("Expected a private and a team view, got %+v", views)
+	}
+	for _, v := range views {
+		if v.Scope == "team" && !v.Shadowed {
+			t.Errorf("Expected team view to be marked shadowed, got %+v", v)
+		}
+		if v.Scope == "private" && v.Shadowed {
+			t.Errorf("Expected private view not to be shadowed, got %+v", v)
+		}
+	}
+
+	titles := listViewTitles(t, slBinary, projectDir, "triage")
+	if len(titles) != 1 || titles[0] != "Crash on save" {
+		t.Errorf("Expected private @triage to list only the bug, got %v", titles)
+	}
+
+	// Deleting the private view falls back to the team view
+	runSL(t, slBinary, projectDir, "issue", "view", "delete", "triage")
+	titles = listViewTitles(t, slBinary, projectDir, "triage")
+	if len(titles) != 1 || titles[0] != "Write docs" {
+		t.Errorf("Expected team @triage to list only the task, got %v", titles)
+	}
+
+	runSL(t, slBinary, projectDir, "issue", "view", "delete", "triage", "--team")
+	if views := listSavedViews(t, slBinary, projectDir); len(views) != 0 {
+		t.Errorf("Expected no views after deleting the team view, got %+v", views)
+	}
+}
+
+// TestIssueViewUnknownName tests that running an unknown @name fails and lists the saved views.
+func TestIssueViewUnknownName(t *testing.T) {
+	slBinary, projectDir := setupViewProject(t)
+
+	cmd := exec.Command(slBinary, "issue", "list", "@missing")
+	cmd.Dir = projectDir
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected sl issue list @missing to fail, got: %s", string(output))
+	}
+	if !strings.Contains(string(output), `unknown view "missing" (no saved views`) {
+		t.Errorf("Expected no-saved-views error, got: %s", string(output))
+	}
+
+	runSL(t, slBinary, projectDir, "issue", "view", "save", "bugs", "--type", "bug", "--all")
+
+	cmd = exec.Command(slBinary, "issue", "list", "@missing")
+	cmd.Dir = projectDir
+	output, err = cmd.CombinedOutput()
+	if err == nil {
+		t.Fatalf("Expected sl issue list @missing to fail, got: %s", string(output))
+	}
+	if !strings.Contains(string(output), `unknown view "missing" (available: bugs)`) {
+		t.Errorf("Expected error listing available views, got: %s", string(output))
+	}
+}